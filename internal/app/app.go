@@ -1,10 +1,14 @@
 package app
 
 import (
+	"context"
 	"database/sql"
 	"net/http"
+	"sync"
+	"time"
 	"uttc-hackathon-backend/internal/handler"
 	"uttc-hackathon-backend/internal/middleware"
+	"uttc-hackathon-backend/internal/models"
 	"uttc-hackathon-backend/internal/repository"
 	"uttc-hackathon-backend/internal/service"
 
@@ -13,50 +17,142 @@ import (
 )
 
 type App struct {
-	UserHandler        *handler.UserHandler
-	listingHandler     *handler.ListingHandler
-	orderHandler       *handler.OrderHandler
-	MessageHandler     *handler.MessageHandler
-	SuggestionHandler  *handler.SuggestionHandler  // Exported
-	TranslationHandler *handler.TranslationHandler // Added this
-	authMiddleware     func(http.Handler) http.Handler
-	VertexRepo         *repository.VertexRepository // Added this
+	UserHandler           *handler.UserHandler
+	listingHandler        *handler.ListingHandler
+	orderHandler          *handler.OrderHandler
+	MessageHandler        *handler.MessageHandler
+	SuggestionHandler     *handler.SuggestionHandler  // Exported
+	TranslationHandler    *handler.TranslationHandler // Added this
+	moderationHandler     *handler.ModerationHandler
+	discoveryHandler      *handler.DiscoveryHandler
+	savedSearchHandler    *handler.SavedSearchHandler
+	notificationHandler   *handler.NotificationHandler
+	disputeHandler        *handler.DisputeHandler
+	reviewHandler         *handler.ReviewHandler
+	refundHandler         *handler.RefundHandler
+	paymentHandler        *handler.PaymentHandler
+	ledgerHandler         *handler.LedgerHandler
+	fraudHandler          *handler.FraudHandler
+	salesReportHandler    *handler.SalesReportHandler
+	orderSvc              *service.OrderService
+	savedSearchSvc        *service.SavedSearchService
+	disputeSvc            *service.DisputeService
+	idempotencySvc        *service.IdempotencyService
+	shipmentSvc           *service.ShipmentService
+	autoCompleteSvc       *service.AutoCompleteService
+	refundSvc             *service.RefundService
+	authMiddleware        func(http.Handler) http.Handler
+	adminMiddleware       func(http.Handler) http.Handler
+	idempotencyMiddleware func(http.Handler) http.Handler
+	VertexRepo            *repository.VertexRepository // Added this
+
+	jobs sync.WaitGroup // background jobs started by RunBackgroundJobs
 }
 
-func NewApp(db *sql.DB, fbAuth *auth.Client, vertexClient *genai.Client) *App {
+func NewApp(db *sql.DB, fbAuth *auth.Client, vertexClient *genai.Client, siteURL string, paymentProvider service.PaymentProvider, autoCompleteAfter time.Duration) *App {
 	userRepo := repository.NewUserRepo(db)
 	listingRepo := repository.NewListingRepo(db)
 	orderRepo := repository.NewOrderRepo(db)
 	messageRepo := repository.NewMessageRepository(db)
 	fbRepo := repository.NewFirebaseAuthRepo(fbAuth)
 	vertexRepo := repository.NewVertexRepository(vertexClient)
+	reportRepo := repository.NewReportRepo(db)
+	translationRepo := repository.NewTranslationRepo(db)
+	discoveryRepo := repository.NewDiscoveryRepo(db)
+	savedSearchRepo := repository.NewSavedSearchRepo(db)
+	notificationRepo := repository.NewNotificationRepo(db)
+	disputeRepo := repository.NewDisputeRepo(db)
+	idempotencyRepo := repository.NewIdempotencyRepo(db)
+	ledgerRepo := repository.NewLedgerRepo(db)
+	salesReportRepo := repository.NewSalesReportRepo(db)
+	feePolicyRepo := repository.NewFeePolicyRepo(db)
+	fraudRepo := repository.NewFraudRepo(db)
+	shipmentRepo := repository.NewShipmentRepo(db)
+	leaseRepo := repository.NewLeaseRepo(db)
+	reviewRepo := repository.NewReviewRepo(db)
+	refundRepo := repository.NewRefundRepo(db)
+	// No carrier APIs are integrated yet; the fake reports every package in transit
+	carriers := map[models.Carrier]service.Carrier{
+		models.CarrierYamato:    repository.NewFakeCarrier(),
+		models.CarrierJapanPost: repository.NewFakeCarrier(),
+		models.CarrierSagawa:    repository.NewFakeCarrier(),
+	}
 
 	userSvc := service.NewUserService(userRepo, fbRepo)
-	listingSvc := service.NewListingService(listingRepo)
-	orderSvc := service.NewOrderService(orderRepo)
-	messageSvc := service.NewMessageService(messageRepo, userRepo)
+	screeningSvc := service.NewScreeningService(vertexRepo)
+	listingSvc := service.NewListingService(listingRepo, screeningSvc)
+	feeSvc := service.NewFeeService(feePolicyRepo)
+	fraudSvc := service.NewFraudService(fraudRepo)
+	orderSvc := service.NewOrderService(orderRepo, paymentProvider, feeSvc, fraudSvc, refundRepo)
+	messageSvc := service.NewMessageService(messageRepo, userRepo, orderSvc)
 	suggestionSvc := service.NewSuggestionService(vertexRepo)
+	moderationSvc := service.NewModerationService(reportRepo, listingRepo, userRepo)
+	translationSvc := service.NewTranslationService(vertexRepo, translationRepo)
+	discoverySvc := service.NewDiscoveryService(discoveryRepo)
+	savedSearchSvc := service.NewSavedSearchService(savedSearchRepo, listingRepo)
+	notificationSvc := service.NewNotificationService(notificationRepo)
+	disputeSvc := service.NewDisputeService(disputeRepo, paymentProvider, refundRepo)
+	idempotencySvc := service.NewIdempotencyService(idempotencyRepo)
+	ledgerSvc := service.NewLedgerService(ledgerRepo)
+	salesReportSvc := service.NewSalesReportService(salesReportRepo)
+	invoiceSvc := service.NewInvoiceService(orderSvc, userSvc)
+	shipmentSvc := service.NewShipmentService(shipmentRepo, orderSvc, carriers)
+	autoCompleteSvc := service.NewAutoCompleteService(orderRepo, leaseRepo, autoCompleteAfter)
+	reviewSvc := service.NewReviewService(reviewRepo)
+	refundSvc := service.NewRefundService(refundRepo, orderSvc, paymentProvider)
 
-	userHandler := handler.NewUserHandler(userSvc)
-	listingHandler := handler.NewListingHandler(listingSvc, userSvc)
-	orderHandler := handler.NewOrderHandler(orderSvc, userSvc)
+	userHandler := handler.NewUserHandler(userSvc, reviewSvc)
+	listingHandler := handler.NewListingHandler(listingSvc, userSvc, translationSvc, savedSearchSvc)
+	orderHandler := handler.NewOrderHandler(orderSvc, userSvc, invoiceSvc, shipmentSvc)
 	messageHandler := handler.NewMessageHandler(messageSvc, userSvc)
 	suggestionHandler := handler.NewSuggestionHandler(suggestionSvc)
+	moderationHandler := handler.NewModerationHandler(moderationSvc, savedSearchSvc)
+	discoveryHandler := handler.NewDiscoveryHandler(discoverySvc, siteURL)
+	savedSearchHandler := handler.NewSavedSearchHandler(savedSearchSvc)
+	notificationHandler := handler.NewNotificationHandler(notificationSvc)
+	disputeHandler := handler.NewDisputeHandler(disputeSvc)
+	reviewHandler := handler.NewReviewHandler(reviewSvc)
+	refundHandler := handler.NewRefundHandler(refundSvc)
+	paymentHandler := handler.NewPaymentHandler(orderSvc)
+	ledgerHandler := handler.NewLedgerHandler(ledgerSvc)
+	fraudHandler := handler.NewFraudHandler(fraudSvc)
+	salesReportHandler := handler.NewSalesReportHandler(salesReportSvc)
 
-	translationSvc := service.NewTranslationService(vertexRepo)
 	translationHandler := handler.NewTranslationHandler(translationSvc)
 
 	authMW := middleware.AuthMiddleware(userSvc)
+	adminMW := middleware.AdminMiddleware(userSvc)
+	idempotencyMW := middleware.IdempotencyMiddleware(idempotencySvc)
 
 	return &App{
-		UserHandler:        userHandler,
-		listingHandler:     listingHandler,
-		orderHandler:       orderHandler,
-		MessageHandler:     messageHandler,
-		SuggestionHandler:  suggestionHandler,
-		TranslationHandler: translationHandler,
-		authMiddleware:     authMW,
-		VertexRepo:         vertexRepo,
+		UserHandler:           userHandler,
+		listingHandler:        listingHandler,
+		orderHandler:          orderHandler,
+		MessageHandler:        messageHandler,
+		SuggestionHandler:     suggestionHandler,
+		TranslationHandler:    translationHandler,
+		moderationHandler:     moderationHandler,
+		discoveryHandler:      discoveryHandler,
+		savedSearchHandler:    savedSearchHandler,
+		notificationHandler:   notificationHandler,
+		disputeHandler:        disputeHandler,
+		reviewHandler:         reviewHandler,
+		refundHandler:         refundHandler,
+		paymentHandler:        paymentHandler,
+		ledgerHandler:         ledgerHandler,
+		fraudHandler:          fraudHandler,
+		salesReportHandler:    salesReportHandler,
+		orderSvc:              orderSvc,
+		savedSearchSvc:        savedSearchSvc,
+		disputeSvc:            disputeSvc,
+		idempotencySvc:        idempotencySvc,
+		shipmentSvc:           shipmentSvc,
+		autoCompleteSvc:       autoCompleteSvc,
+		refundSvc:             refundSvc,
+		authMiddleware:        authMW,
+		adminMiddleware:       adminMW,
+		idempotencyMiddleware: idempotencyMW,
+		VertexRepo:            vertexRepo,
 	}
 }
 
@@ -66,20 +162,52 @@ func (a *App) Routes() http.Handler {
 	// Users
 	mux.HandleFunc("POST /users", a.UserHandler.HandleCreate)
 	mux.Handle("GET /me", a.authMiddleware(http.HandlerFunc(a.UserHandler.HandleMe)))
+	mux.Handle("PUT /me/invoice-registration", a.authMiddleware(http.HandlerFunc(a.UserHandler.HandlePutInvoiceRegistration)))
 	mux.HandleFunc("GET /users/{userId}/profile", a.UserHandler.HandleGetProfile)
+	mux.HandleFunc("GET /users/{userId}/reviews", a.reviewHandler.HandleList)
+	mux.Handle("POST /users/{userId}/report", a.authMiddleware(http.HandlerFunc(a.moderationHandler.HandleReportUser)))
 
 	// Listings
 	mux.HandleFunc("GET /listings/feed", a.listingHandler.HandleFeed)
-	mux.Handle("POST /listings", a.authMiddleware(http.HandlerFunc(a.listingHandler.HandleCreate)))
+	mux.Handle("POST /listings", a.idempotent(a.listingHandler.HandleCreate))
 	mux.HandleFunc("GET /listings/{id}", a.listingHandler.HandleGetListing)
+	mux.Handle("POST /listings/{id}/report", a.authMiddleware(http.HandlerFunc(a.moderationHandler.HandleReportListing)))
 
 	// Orders
-	mux.Handle("POST /orders", a.authMiddleware(http.HandlerFunc(a.orderHandler.HandleCreate)))
+	mux.Handle("POST /orders", a.idempotent(a.orderHandler.HandleCreate))
 	mux.Handle("GET /orders/my", a.authMiddleware(http.HandlerFunc(a.orderHandler.HandleGetMyOrders)))
+	mux.Handle("GET /orders/my/summary", a.authMiddleware(http.HandlerFunc(a.orderHandler.HandleGetMyOrderSummary)))
 	mux.Handle("GET /orders/{orderId}", a.authMiddleware(http.HandlerFunc(a.orderHandler.HandleGet)))
+	mux.Handle("GET /orders/{orderId}/shipment", a.authMiddleware(http.HandlerFunc(a.orderHandler.HandleGetShipment)))
+	mux.Handle("GET /orders/{orderId}/events", a.authMiddleware(http.HandlerFunc(a.orderHandler.HandleGetEvents)))
+	mux.Handle("GET /orders/{orderId}/invoice", a.authMiddleware(http.HandlerFunc(a.orderHandler.HandleGetInvoice)))
+	mux.Handle("POST /orders/{orderId}/ship", a.authMiddleware(http.HandlerFunc(a.orderHandler.HandleShip)))
+	mux.Handle("POST /orders/{orderId}/deliver", a.authMiddleware(http.HandlerFunc(a.orderHandler.HandleDeliver)))
+	mux.Handle("POST /orders/{orderId}/complete", a.authMiddleware(http.HandlerFunc(a.orderHandler.HandleComplete)))
+	mux.Handle("POST /orders/{orderId}/cancel", a.authMiddleware(http.HandlerFunc(a.orderHandler.HandleCancel)))
+	mux.Handle("POST /orders/{orderId}/dispute", a.authMiddleware(http.HandlerFunc(a.disputeHandler.HandleOpen)))
+	mux.Handle("GET /orders/{orderId}/dispute", a.authMiddleware(http.HandlerFunc(a.disputeHandler.HandleGet)))
+	mux.Handle("POST /orders/{orderId}/dispute/respond", a.authMiddleware(http.HandlerFunc(a.disputeHandler.HandleRespond)))
+	mux.Handle("POST /orders/{orderId}/review", a.authMiddleware(http.HandlerFunc(a.reviewHandler.HandleSubmit)))
+	mux.Handle("POST /orders/{orderId}/refunds", a.idempotent(a.refundHandler.HandleCreate))
+	mux.Handle("GET /orders/{orderId}/refunds", a.authMiddleware(http.HandlerFunc(a.refundHandler.HandleList)))
+	mux.Handle("POST /orders/{orderId}/messages", a.idempotent(a.MessageHandler.HandleCreateOrderMessage))
+	mux.Handle("GET /orders/{orderId}/messages", a.authMiddleware(http.HandlerFunc(a.MessageHandler.HandleGetOrderMessages)))
+
+	// Payments
+	mux.HandleFunc("POST /webhooks/payments", a.paymentHandler.HandleWebhook)
+
+	// Balance & Payouts
+	mux.Handle("GET /me/balance", a.authMiddleware(http.HandlerFunc(a.ledgerHandler.HandleGetBalance)))
+	mux.Handle("GET /me/ledger", a.authMiddleware(http.HandlerFunc(a.ledgerHandler.HandleGetLedger)))
+	mux.Handle("GET /me/bank-account", a.authMiddleware(http.HandlerFunc(a.ledgerHandler.HandleGetBankAccount)))
+	mux.Handle("PUT /me/bank-account", a.authMiddleware(http.HandlerFunc(a.ledgerHandler.HandlePutBankAccount)))
+	mux.Handle("POST /me/payouts", a.idempotent(a.ledgerHandler.HandleRequestPayout))
+	mux.Handle("GET /me/payouts", a.authMiddleware(http.HandlerFunc(a.ledgerHandler.HandleGetMyPayouts)))
+	mux.Handle("GET /me/reports/sales", a.authMiddleware(http.HandlerFunc(a.salesReportHandler.HandleGetMySalesReport)))
 
 	// Messages
-	mux.Handle("POST /messages", a.authMiddleware(http.HandlerFunc(a.MessageHandler.HandleCreate)))
+	mux.Handle("POST /messages", a.idempotent(a.MessageHandler.HandleCreate))
 	mux.Handle("GET /messages/conversations", a.authMiddleware(http.HandlerFunc(a.MessageHandler.HandleGetConversations)))
 	mux.Handle("GET /messages/with/{userid}", a.authMiddleware(http.HandlerFunc(a.MessageHandler.HandleGetMessages)))
 
@@ -89,5 +217,79 @@ func (a *App) Routes() http.Handler {
 	// Translation
 	mux.HandleFunc("POST /translate", a.TranslationHandler.HandleTranslate)
 
+	// Saved Searches & Notifications
+	mux.Handle("POST /saved-searches", a.authMiddleware(http.HandlerFunc(a.savedSearchHandler.HandleCreate)))
+	mux.Handle("GET /saved-searches", a.authMiddleware(http.HandlerFunc(a.savedSearchHandler.HandleGetMine)))
+	mux.Handle("DELETE /saved-searches/{id}", a.authMiddleware(http.HandlerFunc(a.savedSearchHandler.HandleDelete)))
+	mux.Handle("GET /notifications", a.authMiddleware(http.HandlerFunc(a.notificationHandler.HandleGetMine)))
+	mux.Handle("POST /notifications/{id}/read", a.authMiddleware(http.HandlerFunc(a.notificationHandler.HandleMarkRead)))
+
+	// Feeds & Sitemaps
+	mux.HandleFunc("GET /feeds/listings.atom", a.discoveryHandler.HandleListingsAtom)
+	mux.HandleFunc("GET /sitemap.xml", a.discoveryHandler.HandleSitemapIndex)
+	mux.HandleFunc("GET /sitemaps/{kind}/{page}", a.discoveryHandler.HandleSitemap)
+
+	// Admin
+	mux.Handle("GET /admin/reports", a.admin(a.moderationHandler.HandleGetReports))
+	mux.Handle("POST /admin/reports/{reportId}/status", a.admin(a.moderationHandler.HandleUpdateReportStatus))
+	mux.Handle("GET /admin/listings/pending", a.admin(a.moderationHandler.HandleGetPendingListings))
+	mux.Handle("POST /admin/listings/{id}/approve", a.admin(a.moderationHandler.HandleApproveListing))
+	mux.Handle("POST /admin/listings/{id}/takedown", a.admin(a.moderationHandler.HandleTakeDownListing))
+	mux.Handle("POST /admin/users/{userId}/suspend", a.admin(a.moderationHandler.HandleSuspendUser))
+	mux.Handle("GET /admin/disputes", a.admin(a.disputeHandler.HandleGetDisputes))
+	mux.Handle("GET /admin/disputes/{disputeId}", a.admin(a.disputeHandler.HandleGetDispute))
+	mux.Handle("POST /admin/disputes/{disputeId}/resolve", a.admin(a.disputeHandler.HandleResolve))
+	mux.Handle("POST /admin/orders/{orderId}/refunds", a.admin(a.refundHandler.HandleAdminCreate))
+	mux.Handle("GET /admin/payouts", a.admin(a.ledgerHandler.HandleGetPayouts))
+	mux.Handle("POST /admin/payouts/{payoutId}/paid", a.admin(a.ledgerHandler.HandleMarkPayoutPaid))
+	mux.Handle("POST /admin/payouts/{payoutId}/failed", a.admin(a.ledgerHandler.HandleMarkPayoutFailed))
+	mux.Handle("GET /admin/fraud-reviews", a.admin(a.fraudHandler.HandleGetReviews))
+	mux.Handle("POST /admin/fraud-reviews/{reviewId}/resolve", a.admin(a.fraudHandler.HandleResolveReview))
+
 	return mux
 }
+
+// RunBackgroundJobs starts periodic work and returns immediately. The jobs stop when ctx is
+// cancelled; WaitBackgroundJobs waits for them to finish.
+func (a *App) RunBackgroundJobs(ctx context.Context) {
+	a.startJob(func() { a.savedSearchSvc.RunDigestLoop(ctx, 5*time.Minute) })
+	a.startJob(func() { a.disputeSvc.RunEscalationLoop(ctx, 10*time.Minute) })
+	a.startJob(func() { a.orderSvc.RunPaymentExpiryLoop(ctx, time.Minute) })
+	a.startJob(func() { a.idempotencySvc.RunPurgeLoop(ctx, time.Hour) })
+	a.startJob(func() { a.shipmentSvc.RunTrackingLoop(ctx, 5*time.Minute) })
+	a.startJob(func() { a.autoCompleteSvc.RunAutoCompleteLoop(ctx, 10*time.Minute) })
+	a.startJob(func() { a.refundSvc.RunRefundRetryLoop(ctx, time.Minute) })
+}
+
+func (a *App) startJob(run func()) {
+	a.jobs.Add(1)
+	go func() {
+		defer a.jobs.Done()
+		run()
+	}()
+}
+
+// WaitBackgroundJobs blocks until every background job has returned, or until ctx is done.
+func (a *App) WaitBackgroundJobs(ctx context.Context) error {
+	done := make(chan struct{})
+	go func() {
+		a.jobs.Wait()
+		close(done)
+	}()
+	select {
+	case <-done:
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
+
+// idempotent wraps a handler with authentication and Idempotency-Key handling.
+func (a *App) idempotent(h http.HandlerFunc) http.Handler {
+	return a.authMiddleware(a.idempotencyMiddleware(h))
+}
+
+// admin wraps a handler with authentication and the admin role check.
+func (a *App) admin(h http.HandlerFunc) http.Handler {
+	return a.authMiddleware(a.adminMiddleware(h))
+}
@@ -11,10 +11,14 @@ import (
 func InitDB(mysqlUser, mysqlUserPwd, mysqlDatabase, mysqlHost, connectionParms string) *sql.DB {
 	connStr := fmt.Sprintf("%s:%s@%s/%s", mysqlUser, mysqlUserPwd, mysqlHost, mysqlDatabase)
 
+	// Pin the session to UTC so that TIMESTAMP columns, time parameters (sent in the
+	// driver's default UTC loc) and CONVERT_TZ(..., '+00:00', ...) all agree, whatever
+	// the server's default time zone is.
+	const sessionParms = "parseTime=true&loc=UTC&time_zone=%27%2B00%3A00%27"
 	if len(connectionParms) > 0 {
-		connStr += "?" + connectionParms + "&parseTime=true"
+		connStr += "?" + connectionParms + "&" + sessionParms
 	} else {
-		connStr += "?parseTime=true"
+		connStr += "?" + sessionParms
 	}
 
 	db, err := sql.Open("mysql", connStr)
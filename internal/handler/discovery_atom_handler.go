@@ -0,0 +1,136 @@
+package handler
+
+import (
+	"bytes"
+	"encoding/xml"
+	"log"
+	"net/http"
+	"strconv"
+	"time"
+	"unicode/utf8"
+	"uttc-hackathon-backend/internal/models"
+)
+
+// atomSummaryLength caps the description excerpt in each entry, in characters.
+const atomSummaryLength = 300
+
+type atomFeed struct {
+	XMLName xml.Name    `xml:"http://www.w3.org/2005/Atom feed"`
+	ID      string      `xml:"id"`
+	Title   string      `xml:"title"`
+	Updated string      `xml:"updated"`
+	Author  atomPerson  `xml:"author"`
+	Links   []atomLink  `xml:"link"`
+	Entries []atomEntry `xml:"entry"`
+}
+
+type atomPerson struct {
+	Name string `xml:"name"`
+}
+
+type atomLink struct {
+	Rel  string `xml:"rel,attr,omitempty"`
+	Type string `xml:"type,attr,omitempty"`
+	Href string `xml:"href,attr"`
+}
+
+type atomEntry struct {
+	ID        string   `xml:"id"`
+	Title     string   `xml:"title"`
+	Updated   string   `xml:"updated"`
+	Published string   `xml:"published"`
+	Link      atomLink `xml:"link"`
+	Summary   string   `xml:"summary"`
+}
+
+// HandleListingsAtom returns the newest active listings as an Atom feed.
+//
+// Route
+//   - GET /feeds/listings.atom
+//
+// Query Parameters
+//   - category: string (optional, category id)
+//   - seller: string (optional, seller user id)
+//   - limit: int (optional, default 50, max 100)
+//
+// Success Response
+//   - 200 OK
+//   - Content-Type: application/atom+xml; charset=utf-8
+//   - Body: Atom 1.0 feed, newest listing first, entries linking to the listing page
+//   - ETag (hash of the body), Cache-Control
+//   - 304 Not Modified: If-None-Match matches the current feed
+//
+// Error Responses
+//   - 500 Internal Server Error
+func (h *DiscoveryHandler) HandleListingsAtom(w http.ResponseWriter, r *http.Request) {
+	q := r.URL.Query()
+	filter := models.ListingFeedFilter{
+		CategoryID: q.Get("category"),
+		SellerID:   q.Get("seller"),
+	}
+	if v, err := strconv.Atoi(q.Get("limit")); err == nil {
+		filter.Limit = v
+	}
+
+	listings, err := h.svc.GetFeedListings(r.Context(), filter)
+	if err != nil {
+		log.Printf("get atom feed listings error: %v", err)
+		http.Error(w, "internal server error", http.StatusInternalServerError)
+		return
+	}
+
+	site := h.siteBaseURL(r)
+	self := requestBaseURL(r) + r.URL.RequestURI()
+
+	feed := atomFeed{
+		ID:     self,
+		Title:  "New listings",
+		Author: atomPerson{Name: "UTTC Market"},
+		Links: []atomLink{
+			{Rel: "self", Type: "application/atom+xml", Href: self},
+			{Rel: "alternate", Type: "text/html", Href: site + "/"},
+		},
+	}
+	if filter.SellerID != "" {
+		feed.Links[1].Href = site + siteProfilePath + filter.SellerID
+	}
+
+	// An empty feed still needs a valid <updated>; the Unix epoch keeps the body stable.
+	var updated time.Time
+	for _, l := range listings {
+		if l.UpdatedAt.After(updated) {
+			updated = l.UpdatedAt
+		}
+		link := site + siteListingPath + l.ID
+		feed.Entries = append(feed.Entries, atomEntry{
+			ID:        link,
+			Title:     l.Title,
+			Updated:   l.UpdatedAt.UTC().Format(time.RFC3339),
+			Published: l.CreatedAt.UTC().Format(time.RFC3339),
+			Link:      atomLink{Rel: "alternate", Type: "text/html", Href: link},
+			Summary:   "¥" + strconv.Itoa(l.Price) + " " + truncateRunes(l.Description, atomSummaryLength),
+		})
+	}
+	if updated.IsZero() {
+		updated = time.Unix(0, 0)
+	}
+	feed.Updated = updated.UTC().Format(time.RFC3339)
+
+	var buf bytes.Buffer
+	buf.WriteString(xml.Header)
+	if err := xml.NewEncoder(&buf).Encode(feed); err != nil {
+		log.Printf("encode atom feed error: %v", err)
+		http.Error(w, "internal server error", http.StatusInternalServerError)
+		return
+	}
+	buf.WriteByte('\n')
+
+	writeCached(w, r, buf.Bytes(), "application/atom+xml; charset=utf-8", time.Time{}, cachePolicyAtom)
+}
+
+func truncateRunes(s string, n int) string {
+	if utf8.RuneCountInString(s) <= n {
+		return s
+	}
+	return string([]rune(s)[:n]) + "…"
+}
@@ -0,0 +1,41 @@
+package handler
+
+import (
+	"net/http"
+	"strings"
+	"uttc-hackathon-backend/internal/service"
+)
+
+// Paths of the public pages on the frontend that sitemaps and feeds link to.
+const (
+	siteListingPath = "/listings/"
+	siteProfilePath = "/users/"
+)
+
+type DiscoveryHandler struct {
+	svc *service.DiscoveryService
+	// siteURL is the frontend origin, e.g. https://example.com. When empty, links use this API's origin.
+	siteURL string
+}
+
+func NewDiscoveryHandler(svc *service.DiscoveryService, siteURL string) *DiscoveryHandler {
+	return &DiscoveryHandler{svc: svc, siteURL: strings.TrimSuffix(siteURL, "/")}
+}
+
+// siteBaseURL returns the origin for links to frontend pages.
+func (h *DiscoveryHandler) siteBaseURL(r *http.Request) string {
+	if h.siteURL != "" {
+		return h.siteURL
+	}
+	return requestBaseURL(r)
+}
+
+// requestBaseURL returns the origin the client used to reach this API. Cloud Run terminates TLS
+// and passes the original scheme in X-Forwarded-Proto.
+func requestBaseURL(r *http.Request) string {
+	scheme := "http"
+	if r.TLS != nil || r.Header.Get("X-Forwarded-Proto") == "https" {
+		scheme = "https"
+	}
+	return scheme + "://" + r.Host
+}
@@ -0,0 +1,160 @@
+package handler
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/xml"
+	"errors"
+	"fmt"
+	"log"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+	"uttc-hackathon-backend/internal/models"
+	"uttc-hackathon-backend/internal/service"
+)
+
+const sitemapNamespace = "http://www.sitemaps.org/schemas/sitemap/0.9"
+
+type sitemapIndexEntry struct {
+	XMLName xml.Name `xml:"sitemap"`
+	Loc     string   `xml:"loc"`
+	LastMod string   `xml:"lastmod,omitempty"`
+}
+
+type sitemapURL struct {
+	XMLName xml.Name `xml:"url"`
+	Loc     string   `xml:"loc"`
+	LastMod string   `xml:"lastmod,omitempty"`
+}
+
+// HandleSitemapIndex returns the sitemap index that points to the paginated child sitemaps.
+//
+// Route
+//   - GET /sitemap.xml
+//
+// Success Response
+//   - 200 OK
+//   - Content-Type: application/xml; charset=utf-8
+//   - Body: <sitemapindex> with one <sitemap> per page of /sitemaps/{listings|users}/{page}.xml;
+//     listing pages carry lastmod (newest updated_at on the page)
+//   - ETag (hash of the body), Cache-Control
+//   - 304 Not Modified: If-None-Match matches the current index
+//
+// Error Responses
+//   - 500 Internal Server Error
+func (h *DiscoveryHandler) HandleSitemapIndex(w http.ResponseWriter, r *http.Request) {
+	pages, err := h.svc.GetSitemapIndex(r.Context())
+	if err != nil {
+		log.Printf("get sitemap index error: %v", err)
+		http.Error(w, "internal server error", http.StatusInternalServerError)
+		return
+	}
+
+	base := requestBaseURL(r)
+
+	var buf bytes.Buffer
+	buf.WriteString(xml.Header)
+	buf.WriteString(`<sitemapindex xmlns="` + sitemapNamespace + `">` + "\n")
+	enc := xml.NewEncoder(&buf)
+	for _, p := range pages {
+		entry := sitemapIndexEntry{Loc: fmt.Sprintf("%s/sitemaps/%s/%d.xml", base, p.Kind, p.Page)}
+		if !p.LastModified.IsZero() {
+			entry.LastMod = p.LastModified.UTC().Format(time.RFC3339)
+		}
+		if err := enc.Encode(entry); err != nil {
+			log.Printf("encode sitemap index error: %v", err)
+			http.Error(w, "internal server error", http.StatusInternalServerError)
+			return
+		}
+	}
+	buf.WriteString("\n</sitemapindex>\n")
+
+	writeCached(w, r, buf.Bytes(), "application/xml; charset=utf-8", time.Time{}, cachePolicySitemap)
+}
+
+// HandleSitemap streams one page of a child sitemap straight from the database, so large
+// catalogs are never held in memory.
+//
+// Route
+//   - GET /sitemaps/{kind}/{page}.xml
+//
+// Path Parameters
+//   - kind: "listings" or "users"
+//   - page: int (1-based, as listed in /sitemap.xml)
+//
+// Success Response
+//   - 200 OK
+//   - Content-Type: application/xml; charset=utf-8
+//   - Body: <urlset> of listing pages (with lastmod from updated_at) or public profile pages
+//   - Cache-Control
+//
+// Error Responses
+//   - 404 Not Found: unknown kind or page past the end
+//   - 500 Internal Server Error: only if the failure happens before any output is written;
+//     later failures truncate the document and are logged
+func (h *DiscoveryHandler) HandleSitemap(w http.ResponseWriter, r *http.Request) {
+	kind := models.SitemapKind(r.PathValue("kind"))
+	pageStr, ok := strings.CutSuffix(r.PathValue("page"), ".xml")
+	page, err := strconv.Atoi(pageStr)
+	if !ok || err != nil {
+		http.NotFound(w, r)
+		return
+	}
+
+	prefix := siteListingPath
+	if kind == models.SitemapKindUsers {
+		prefix = siteProfilePath
+	}
+	site := h.siteBaseURL(r)
+
+	bw := bufio.NewWriter(w)
+	enc := xml.NewEncoder(bw)
+	started := false
+	start := func() {
+		started = true
+		w.Header().Set("Content-Type", "application/xml; charset=utf-8")
+		w.Header().Set("Cache-Control", cachePolicySitemap)
+		bw.WriteString(xml.Header)
+		bw.WriteString(`<urlset xmlns="` + sitemapNamespace + `">` + "\n")
+	}
+
+	err = h.svc.StreamSitemap(r.Context(), kind, page, func(e models.SitemapEntry) error {
+		if !started {
+			start()
+		}
+		u := sitemapURL{Loc: site + prefix + e.ID}
+		if !e.LastModified.IsZero() {
+			u.LastMod = e.LastModified.UTC().Format(time.RFC3339)
+		}
+		return enc.Encode(u)
+	})
+	if err != nil {
+		if errors.Is(err, service.ErrSitemapNotFound) {
+			http.NotFound(w, r)
+			return
+		}
+		log.Printf("stream sitemap %s/%d error: %v", kind, page, err)
+		if !started {
+			http.Error(w, "internal server error", http.StatusInternalServerError)
+			return
+		}
+		// Headers are already sent; flush what we have so the client sees a truncated document.
+		bw.Flush()
+		return
+	}
+
+	if !started {
+		// Only the first page may be empty (no listings or users yet).
+		if page > 1 {
+			http.NotFound(w, r)
+			return
+		}
+		start()
+	}
+	bw.WriteString("\n</urlset>\n")
+	if err := bw.Flush(); err != nil {
+		log.Printf("write sitemap error: %v", err)
+	}
+}
@@ -0,0 +1,146 @@
+package handler
+
+import (
+	"encoding/json"
+	"log"
+	"net/http"
+	"strconv"
+	"uttc-hackathon-backend/internal/middleware"
+	"uttc-hackathon-backend/internal/models"
+)
+
+// HandleGetDisputes returns the dispute queue.
+//
+// Route
+//   - GET /admin/disputes
+//
+// Required Headers
+//   - Authorization: Bearer <Firebase ID token of an admin>
+//
+// Query Parameters
+//   - status: string (optional, awaiting_seller, awaiting_admin, resolved; default all)
+//   - limit: int (optional, default 50, max 100)
+//   - offset: int (optional, default 0)
+//
+// Success Response
+//   - 200 OK
+//   - Body: []Dispute (escalated first, then oldest first; without events)
+//
+// Error Responses
+//   - 400 Bad Request: invalid status
+//   - 403 Forbidden: not an admin
+func (h *DisputeHandler) HandleGetDisputes(w http.ResponseWriter, r *http.Request) {
+	q := r.URL.Query()
+	status := models.DisputeStatus(q.Get("status"))
+
+	limit := 0
+	if v, err := strconv.Atoi(q.Get("limit")); err == nil {
+		limit = v
+	}
+	offset := 0
+	if v, err := strconv.Atoi(q.Get("offset")); err == nil {
+		offset = v
+	}
+
+	disputes, err := h.svc.GetDisputes(r.Context(), status, limit, offset)
+	if err != nil {
+		writeDisputeError(w, err, "get disputes")
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	if err := json.NewEncoder(w).Encode(disputes); err != nil {
+		log.Printf("encode disputes response error: %v", err)
+	}
+}
+
+// HandleGetDispute returns a dispute and its timeline.
+//
+// Route
+//   - GET /admin/disputes/{disputeId}
+//
+// Required Headers
+//   - Authorization: Bearer <Firebase ID token of an admin>
+//
+// Success Response
+//   - 200 OK
+//   - Body: Dispute (with events)
+//
+// Error Responses
+//   - 403 Forbidden: not an admin
+//   - 404 Not Found: dispute not found
+func (h *DisputeHandler) HandleGetDispute(w http.ResponseWriter, r *http.Request) {
+	disputeID := r.PathValue("disputeId")
+	if disputeID == "" {
+		http.Error(w, "missing dispute id", http.StatusBadRequest)
+		return
+	}
+
+	dispute, err := h.svc.GetDispute(r.Context(), disputeID)
+	if err != nil {
+		writeDisputeError(w, err, "get dispute")
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	if err := json.NewEncoder(w).Encode(dispute); err != nil {
+		log.Printf("encode dispute response error: %v", err)
+	}
+}
+
+// HandleResolve closes a dispute with an outcome.
+//
+// Route
+//   - POST /admin/disputes/{disputeId}/resolve
+//
+// Required Headers
+//   - Authorization: Bearer <Firebase ID token of an admin>
+//   - Content-Type: application/json
+//
+// Request Body
+//   - outcome: string (required, full_refund, partial_refund, release_to_seller)
+//   - refund_amount: int (required for partial_refund; more than 0 and less than the unrefunded order total)
+//   - note: string (optional, max 1000 characters)
+//
+// A full refund returns the unrefunded order total and cancels the order; the other outcomes
+// complete it. Refunds are recorded on the order like seller and admin refunds.
+//
+// Success Response
+//   - 200 OK
+//   - Body: Dispute (status "resolved")
+//
+// Error Responses
+//   - 400 Bad Request: invalid outcome, refund amount or note
+//   - 403 Forbidden: not an admin
+//   - 404 Not Found: dispute not found
+//   - 409 Conflict: already resolved
+func (h *DisputeHandler) HandleResolve(w http.ResponseWriter, r *http.Request) {
+	adminID := middleware.GetUserIDFromContext(r.Context())
+
+	disputeID := r.PathValue("disputeId")
+	if disputeID == "" {
+		http.Error(w, "missing dispute id", http.StatusBadRequest)
+		return
+	}
+
+	var req struct {
+		Outcome      models.DisputeOutcome `json:"outcome"`
+		RefundAmount int                   `json:"refund_amount"`
+		Note         string                `json:"note"`
+	}
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		http.Error(w, "invalid request body", http.StatusBadRequest)
+		return
+	}
+
+	dispute, err := h.svc.ResolveDispute(r.Context(), adminID, disputeID, req.Outcome, req.RefundAmount, req.Note)
+	if err != nil {
+		writeDisputeError(w, err, "resolve dispute")
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	if err := json.NewEncoder(w).Encode(dispute); err != nil {
+		log.Printf("encode resolve dispute response error: %v", err)
+	}
+}
@@ -0,0 +1,47 @@
+package handler
+
+import (
+	"errors"
+	"log"
+	"net/http"
+	"uttc-hackathon-backend/internal/repository"
+	"uttc-hackathon-backend/internal/service"
+)
+
+type DisputeHandler struct {
+	svc *service.DisputeService
+}
+
+func NewDisputeHandler(svc *service.DisputeService) *DisputeHandler {
+	return &DisputeHandler{svc: svc}
+}
+
+// writeDisputeError maps dispute errors to responses; op names the request in the log.
+func writeDisputeError(w http.ResponseWriter, err error, op string) {
+	switch {
+	case errors.Is(err, service.ErrInvalidDisputeReason),
+		errors.Is(err, service.ErrInvalidDisputeStatus),
+		errors.Is(err, service.ErrDisputeMessageRequired),
+		errors.Is(err, service.ErrDisputeMessageLong),
+		errors.Is(err, service.ErrTooManyDisputeImages),
+		errors.Is(err, service.ErrInvalidImageURL),
+		errors.Is(err, service.ErrInvalidDisputeOutcome),
+		errors.Is(err, service.ErrInvalidRefundAmount),
+		errors.Is(err, service.ErrResolutionNoteLong):
+		http.Error(w, err.Error(), http.StatusBadRequest)
+	case errors.Is(err, service.ErrOrderActionForbidden):
+		http.Error(w, err.Error(), http.StatusForbidden)
+	case errors.Is(err, repository.ErrOrderNotFound), errors.Is(err, service.ErrUnauthorized):
+		http.Error(w, "order not found", http.StatusNotFound)
+	case errors.Is(err, repository.ErrDisputeNotFound):
+		http.Error(w, "dispute not found", http.StatusNotFound)
+	case errors.Is(err, repository.ErrDisputeExists),
+		errors.Is(err, service.ErrInvalidOrderTransition),
+		errors.Is(err, service.ErrDisputeNotAwaitingSeller),
+		errors.Is(err, service.ErrDisputeResolved):
+		http.Error(w, err.Error(), http.StatusConflict)
+	default:
+		log.Printf("%s error: %v", op, err)
+		http.Error(w, "internal server error", http.StatusInternalServerError)
+	}
+}
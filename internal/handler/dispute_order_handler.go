@@ -0,0 +1,155 @@
+package handler
+
+import (
+	"encoding/json"
+	"log"
+	"net/http"
+	"uttc-hackathon-backend/internal/middleware"
+	"uttc-hackathon-backend/internal/models"
+)
+
+// HandleOpen opens a dispute on a shipped or delivered order. Buyer only.
+//
+// Route
+//   - POST /orders/{orderId}/dispute
+//
+// Required Headers
+//   - Authorization: Bearer <Firebase ID token>
+//   - Content-Type: application/json
+//
+// Request Body
+//   - reason: string (required, not_received, not_as_described, damaged, counterfeit, other)
+//   - message: string (required, max 2000 characters)
+//   - images: []string (optional, max 5 uploaded image URLs)
+//
+// The order moves to "disputed". The seller has 72 hours to respond before the dispute is
+// escalated to the admins automatically.
+//
+// Success Response
+//   - 201 Created
+//   - Body: Dispute (with events)
+//
+// Error Responses
+//   - 400 Bad Request: invalid reason, missing or long message, or invalid images
+//   - 403 Forbidden: the seller cannot open a dispute
+//   - 404 Not Found: order not found or the user is not part of it
+//   - 409 Conflict: the order is not shipped or delivered, or already has a dispute
+func (h *DisputeHandler) HandleOpen(w http.ResponseWriter, r *http.Request) {
+	userID := middleware.GetUserIDFromContext(r.Context())
+
+	orderID := r.PathValue("orderId")
+	if orderID == "" {
+		http.Error(w, "missing order id", http.StatusBadRequest)
+		return
+	}
+
+	var req struct {
+		Reason  models.DisputeReason `json:"reason"`
+		Message string               `json:"message"`
+		Images  []string             `json:"images"`
+	}
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		http.Error(w, "invalid request body", http.StatusBadRequest)
+		return
+	}
+
+	dispute, err := h.svc.OpenDispute(r.Context(), userID, orderID, req.Reason, req.Message, req.Images)
+	if err != nil {
+		writeDisputeError(w, err, "open dispute")
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusCreated)
+	if err := json.NewEncoder(w).Encode(dispute); err != nil {
+		log.Printf("encode open dispute response error: %v", err)
+	}
+}
+
+// HandleGet returns the order's dispute and its timeline to the buyer or seller.
+//
+// Route
+//   - GET /orders/{orderId}/dispute
+//
+// Required Headers
+//   - Authorization: Bearer <Firebase ID token>
+//
+// Success Response
+//   - 200 OK
+//   - Body: Dispute (with events)
+//
+// Error Responses
+//   - 404 Not Found: no dispute, or the user is not part of the order
+func (h *DisputeHandler) HandleGet(w http.ResponseWriter, r *http.Request) {
+	userID := middleware.GetUserIDFromContext(r.Context())
+
+	orderID := r.PathValue("orderId")
+	if orderID == "" {
+		http.Error(w, "missing order id", http.StatusBadRequest)
+		return
+	}
+
+	dispute, err := h.svc.GetOrderDispute(r.Context(), userID, orderID)
+	if err != nil {
+		writeDisputeError(w, err, "get dispute")
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	if err := json.NewEncoder(w).Encode(dispute); err != nil {
+		log.Printf("encode dispute response error: %v", err)
+	}
+}
+
+// HandleRespond records the seller's response and hands the dispute to the admins. Seller
+// only, once, before the response deadline.
+//
+// Route
+//   - POST /orders/{orderId}/dispute/respond
+//
+// Required Headers
+//   - Authorization: Bearer <Firebase ID token>
+//   - Content-Type: application/json
+//
+// Request Body
+//   - message: string (required, max 2000 characters)
+//   - images: []string (optional, max 5 uploaded image URLs)
+//
+// Success Response
+//   - 200 OK
+//   - Body: Dispute (status "awaiting_admin", with events)
+//
+// Error Responses
+//   - 400 Bad Request: missing or long message, or invalid images
+//   - 403 Forbidden: the buyer cannot respond
+//   - 404 Not Found: no dispute, or the user is not part of the order
+//   - 409 Conflict: already responded, escalated or resolved
+func (h *DisputeHandler) HandleRespond(w http.ResponseWriter, r *http.Request) {
+	userID := middleware.GetUserIDFromContext(r.Context())
+
+	orderID := r.PathValue("orderId")
+	if orderID == "" {
+		http.Error(w, "missing order id", http.StatusBadRequest)
+		return
+	}
+
+	var req struct {
+		Message string   `json:"message"`
+		Images  []string `json:"images"`
+	}
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		http.Error(w, "invalid request body", http.StatusBadRequest)
+		return
+	}
+
+	dispute, err := h.svc.RespondToDispute(r.Context(), userID, orderID, req.Message, req.Images)
+	if err != nil {
+		writeDisputeError(w, err, "respond to dispute")
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	if err := json.NewEncoder(w).Encode(dispute); err != nil {
+		log.Printf("encode dispute response error: %v", err)
+	}
+}
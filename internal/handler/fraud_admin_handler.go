@@ -0,0 +1,109 @@
+package handler
+
+import (
+	"encoding/json"
+	"log"
+	"net/http"
+	"strconv"
+	"uttc-hackathon-backend/internal/middleware"
+	"uttc-hackathon-backend/internal/models"
+)
+
+// HandleGetReviews returns the orders blocked by fraud rules.
+//
+// Route
+//   - GET /admin/fraud-reviews
+//
+// Required Headers
+//   - Authorization: Bearer <Firebase ID token of an admin>
+//
+// Query Parameters
+//   - status: string (optional, open, cleared, confirmed; default all)
+//   - limit: int (optional, default 50, max 100)
+//   - offset: int (optional, default 0)
+//
+// Success Response
+//   - 200 OK
+//   - Body: []FraudReview (oldest first; one per buyer and rule while open, with attempts)
+//
+// Error Responses
+//   - 400 Bad Request: invalid status
+//   - 403 Forbidden: not an admin
+func (h *FraudHandler) HandleGetReviews(w http.ResponseWriter, r *http.Request) {
+	q := r.URL.Query()
+	status := models.FraudReviewStatus(q.Get("status"))
+
+	limit := 0
+	if v, err := strconv.Atoi(q.Get("limit")); err == nil {
+		limit = v
+	}
+	offset := 0
+	if v, err := strconv.Atoi(q.Get("offset")); err == nil {
+		offset = v
+	}
+
+	reviews, err := h.svc.GetReviews(r.Context(), status, limit, offset)
+	if err != nil {
+		writeFraudError(w, err, "get fraud reviews")
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	if err := json.NewEncoder(w).Encode(reviews); err != nil {
+		log.Printf("encode fraud reviews response error: %v", err)
+	}
+}
+
+// HandleResolveReview records the decision on a blocked order.
+//
+// Route
+//   - POST /admin/fraud-reviews/{reviewId}/resolve
+//
+// Required Headers
+//   - Authorization: Bearer <Firebase ID token of an admin>
+//   - Content-Type: application/json
+//
+// Request Body
+//   - status: string (required, cleared or confirmed)
+//   - note: string (optional, max 1000 characters)
+//
+// Confirming does not suspend the buyer; use POST /admin/users/{userId}/suspend.
+//
+// Success Response
+//   - 200 OK
+//   - Body: FraudReview
+//
+// Error Responses
+//   - 400 Bad Request: invalid status or note
+//   - 403 Forbidden: not an admin
+//   - 404 Not Found: review not found
+//   - 409 Conflict: already resolved
+func (h *FraudHandler) HandleResolveReview(w http.ResponseWriter, r *http.Request) {
+	adminID := middleware.GetUserIDFromContext(r.Context())
+
+	reviewID := r.PathValue("reviewId")
+	if reviewID == "" {
+		http.Error(w, "missing review id", http.StatusBadRequest)
+		return
+	}
+
+	var req struct {
+		Status models.FraudReviewStatus `json:"status"`
+		Note   string                   `json:"note"`
+	}
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		http.Error(w, "invalid request body", http.StatusBadRequest)
+		return
+	}
+
+	review, err := h.svc.ResolveReview(r.Context(), adminID, reviewID, req.Status, req.Note)
+	if err != nil {
+		writeFraudError(w, err, "resolve fraud review")
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	if err := json.NewEncoder(w).Encode(review); err != nil {
+		log.Printf("encode resolve fraud review response error: %v", err)
+	}
+}
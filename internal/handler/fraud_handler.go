@@ -0,0 +1,33 @@
+package handler
+
+import (
+	"errors"
+	"log"
+	"net/http"
+	"uttc-hackathon-backend/internal/repository"
+	"uttc-hackathon-backend/internal/service"
+)
+
+type FraudHandler struct {
+	svc *service.FraudService
+}
+
+func NewFraudHandler(svc *service.FraudService) *FraudHandler {
+	return &FraudHandler{svc: svc}
+}
+
+// writeFraudError maps fraud review errors to responses; op names the request in the log.
+func writeFraudError(w http.ResponseWriter, err error, op string) {
+	switch {
+	case errors.Is(err, service.ErrInvalidFraudReviewStatus),
+		errors.Is(err, service.ErrFraudReviewNoteLong):
+		http.Error(w, err.Error(), http.StatusBadRequest)
+	case errors.Is(err, repository.ErrFraudReviewNotFound):
+		http.Error(w, err.Error(), http.StatusNotFound)
+	case errors.Is(err, service.ErrFraudReviewResolved):
+		http.Error(w, err.Error(), http.StatusConflict)
+	default:
+		log.Printf("%s error: %v", op, err)
+		http.Error(w, "internal server error", http.StatusInternalServerError)
+	}
+}
@@ -0,0 +1,123 @@
+package handler
+
+import (
+	"encoding/json"
+	"log"
+	"net/http"
+	"strconv"
+	"uttc-hackathon-backend/internal/middleware"
+	"uttc-hackathon-backend/internal/models"
+)
+
+// HandleGetPayouts returns payouts of all sellers, newest first.
+//
+// Route
+//   - GET /admin/payouts
+//
+// Required Headers
+//   - Authorization: Bearer <Firebase ID token of an admin>
+//
+// Query Parameters
+//   - status: string (optional, requested, paid, failed; default all)
+//   - limit: int (optional, default 50, max 100)
+//   - offset: int (optional, default 0)
+//
+// Success Response
+//   - 200 OK
+//   - Body: []Payout (with full account numbers)
+//
+// Error Responses
+//   - 400 Bad Request: invalid status
+//   - 403 Forbidden: not an admin
+func (h *LedgerHandler) HandleGetPayouts(w http.ResponseWriter, r *http.Request) {
+	q := r.URL.Query()
+	status := models.PayoutStatus(q.Get("status"))
+
+	limit := 0
+	if v, err := strconv.Atoi(q.Get("limit")); err == nil {
+		limit = v
+	}
+	offset := 0
+	if v, err := strconv.Atoi(q.Get("offset")); err == nil {
+		offset = v
+	}
+
+	payouts, err := h.svc.GetPayouts(r.Context(), status, limit, offset)
+	if err != nil {
+		writeLedgerError(w, err, "get payouts")
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	if err := json.NewEncoder(w).Encode(payouts); err != nil {
+		log.Printf("encode payouts response error: %v", err)
+	}
+}
+
+// HandleMarkPayoutPaid records that the payout was transferred to the seller's bank.
+//
+// Route
+//   - POST /admin/payouts/{payoutId}/paid
+//
+// Required Headers
+//   - Authorization: Bearer <Firebase ID token of an admin>
+//
+// Success Response
+//   - 200 OK
+//   - Body: Payout (status "paid")
+//
+// Error Responses
+//   - 403 Forbidden: not an admin
+//   - 404 Not Found: payout not found
+//   - 409 Conflict: already paid or failed
+func (h *LedgerHandler) HandleMarkPayoutPaid(w http.ResponseWriter, r *http.Request) {
+	h.processPayout(w, r, true)
+}
+
+// HandleMarkPayoutFailed records that the bank rejected the transfer. The amount returns to
+// the seller's available balance.
+//
+// Route
+//   - POST /admin/payouts/{payoutId}/failed
+//
+// Required Headers
+//   - Authorization: Bearer <Firebase ID token of an admin>
+//
+// Success Response
+//   - 200 OK
+//   - Body: Payout (status "failed")
+//
+// Error Responses
+//   - 403 Forbidden: not an admin
+//   - 404 Not Found: payout not found
+//   - 409 Conflict: already paid or failed
+func (h *LedgerHandler) HandleMarkPayoutFailed(w http.ResponseWriter, r *http.Request) {
+	h.processPayout(w, r, false)
+}
+
+func (h *LedgerHandler) processPayout(w http.ResponseWriter, r *http.Request, paid bool) {
+	adminID := middleware.GetUserIDFromContext(r.Context())
+
+	payoutID := r.PathValue("payoutId")
+	if payoutID == "" {
+		http.Error(w, "missing payout id", http.StatusBadRequest)
+		return
+	}
+
+	var payout *models.Payout
+	var err error
+	if paid {
+		payout, err = h.svc.MarkPayoutPaid(r.Context(), adminID, payoutID)
+	} else {
+		payout, err = h.svc.MarkPayoutFailed(r.Context(), adminID, payoutID)
+	}
+	if err != nil {
+		writeLedgerError(w, err, "process payout")
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	if err := json.NewEncoder(w).Encode(payout); err != nil {
+		log.Printf("encode payout response error: %v", err)
+	}
+}
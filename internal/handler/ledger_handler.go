@@ -0,0 +1,41 @@
+package handler
+
+import (
+	"errors"
+	"log"
+	"net/http"
+	"uttc-hackathon-backend/internal/repository"
+	"uttc-hackathon-backend/internal/service"
+)
+
+type LedgerHandler struct {
+	svc *service.LedgerService
+}
+
+func NewLedgerHandler(svc *service.LedgerService) *LedgerHandler {
+	return &LedgerHandler{svc: svc}
+}
+
+// writeLedgerError maps balance and payout errors to responses; op names the request in the log.
+func writeLedgerError(w http.ResponseWriter, err error, op string) {
+	switch {
+	case errors.Is(err, service.ErrInvalidBankCode),
+		errors.Is(err, service.ErrInvalidBranchCode),
+		errors.Is(err, service.ErrInvalidAccountType),
+		errors.Is(err, service.ErrInvalidAccountNumber),
+		errors.Is(err, service.ErrInvalidAccountHolder),
+		errors.Is(err, service.ErrPayoutTooSmall),
+		errors.Is(err, service.ErrInvalidPayoutStatus):
+		http.Error(w, err.Error(), http.StatusBadRequest)
+	case errors.Is(err, repository.ErrBankAccountNotFound):
+		http.Error(w, err.Error(), http.StatusNotFound)
+	case errors.Is(err, repository.ErrPayoutNotFound):
+		http.Error(w, "payout not found", http.StatusNotFound)
+	case errors.Is(err, service.ErrInsufficientBalance),
+		errors.Is(err, service.ErrPayoutProcessed):
+		http.Error(w, err.Error(), http.StatusConflict)
+	default:
+		log.Printf("%s error: %v", op, err)
+		http.Error(w, "internal server error", http.StatusInternalServerError)
+	}
+}
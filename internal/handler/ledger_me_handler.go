@@ -0,0 +1,243 @@
+package handler
+
+import (
+	"encoding/json"
+	"log"
+	"net/http"
+	"strconv"
+	"uttc-hackathon-backend/internal/middleware"
+	"uttc-hackathon-backend/internal/models"
+)
+
+// HandleGetBalance returns the current user's seller balance.
+//
+// Route
+//   - GET /me/balance
+//
+// Required Headers
+//   - Authorization: Bearer <Firebase ID token>
+//
+// Success Response
+//   - 200 OK
+//   - Body: {pending, available, in_payout, paid_out} in yen
+//   - pending: sales whose orders are not completed yet
+//   - available: can be paid out
+//   - in_payout: requested payouts not transferred yet
+//   - paid_out: transferred to the bank account so far
+func (h *LedgerHandler) HandleGetBalance(w http.ResponseWriter, r *http.Request) {
+	userID := middleware.GetUserIDFromContext(r.Context())
+
+	balance, err := h.svc.GetBalance(r.Context(), userID)
+	if err != nil {
+		writeLedgerError(w, err, "get balance")
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	if err := json.NewEncoder(w).Encode(balance); err != nil {
+		log.Printf("encode balance response error: %v", err)
+	}
+}
+
+// HandleGetLedger returns the entries behind the current user's balance, newest first.
+//
+// Route
+//   - GET /me/ledger
+//
+// Required Headers
+//   - Authorization: Bearer <Firebase ID token>
+//
+// Query Parameters
+//   - limit: int (optional, default 50, max 100)
+//   - offset: int (optional, default 0)
+//
+// Success Response
+//   - 200 OK
+//   - Body: []LedgerEntry {id, transaction_id, account, amount, type, order_id, payout_id, created_at}
+//   - amount is signed: positive entries increase the account's balance
+func (h *LedgerHandler) HandleGetLedger(w http.ResponseWriter, r *http.Request) {
+	userID := middleware.GetUserIDFromContext(r.Context())
+
+	q := r.URL.Query()
+	limit := 0
+	if v, err := strconv.Atoi(q.Get("limit")); err == nil {
+		limit = v
+	}
+	offset := 0
+	if v, err := strconv.Atoi(q.Get("offset")); err == nil {
+		offset = v
+	}
+
+	entries, err := h.svc.GetLedger(r.Context(), userID, limit, offset)
+	if err != nil {
+		writeLedgerError(w, err, "get ledger")
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	if err := json.NewEncoder(w).Encode(entries); err != nil {
+		log.Printf("encode ledger response error: %v", err)
+	}
+}
+
+// HandleGetBankAccount returns the current user's payout bank account.
+//
+// Route
+//   - GET /me/bank-account
+//
+// Required Headers
+//   - Authorization: Bearer <Firebase ID token>
+//
+// Success Response
+//   - 200 OK
+//   - Body: BankAccount (account_number masked except the last 3 digits)
+//
+// Error Responses
+//   - 404 Not Found: no bank account registered
+func (h *LedgerHandler) HandleGetBankAccount(w http.ResponseWriter, r *http.Request) {
+	userID := middleware.GetUserIDFromContext(r.Context())
+
+	account, err := h.svc.GetBankAccount(r.Context(), userID)
+	if err != nil {
+		writeLedgerError(w, err, "get bank account")
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	if err := json.NewEncoder(w).Encode(account); err != nil {
+		log.Printf("encode bank account response error: %v", err)
+	}
+}
+
+// HandlePutBankAccount registers the current user's payout bank account, replacing any
+// previous one.
+//
+// Route
+//   - PUT /me/bank-account
+//
+// Required Headers
+//   - Authorization: Bearer <Firebase ID token>
+//   - Content-Type: application/json
+//
+// Request Body
+//   - bank_code: string (required, 4 digits)
+//   - branch_code: string (required, 3 digits)
+//   - account_type: string (required, ordinary, current)
+//   - account_number: string (required, 7 digits)
+//   - account_holder: string (required, full-width katakana, max 100 characters)
+//
+// Success Response
+//   - 200 OK
+//   - Body: BankAccount (account_number masked except the last 3 digits)
+//
+// Error Responses
+//   - 400 Bad Request: invalid field
+func (h *LedgerHandler) HandlePutBankAccount(w http.ResponseWriter, r *http.Request) {
+	userID := middleware.GetUserIDFromContext(r.Context())
+
+	var req models.BankAccount
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		http.Error(w, "invalid request body", http.StatusBadRequest)
+		return
+	}
+
+	account, err := h.svc.SaveBankAccount(r.Context(), userID, &req)
+	if err != nil {
+		writeLedgerError(w, err, "save bank account")
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	if err := json.NewEncoder(w).Encode(account); err != nil {
+		log.Printf("encode bank account response error: %v", err)
+	}
+}
+
+// HandleRequestPayout transfers part of the available balance to the registered bank account.
+//
+// Route
+//   - POST /me/payouts
+//
+// Required Headers
+//   - Authorization: Bearer <Firebase ID token>
+//   - Content-Type: application/json
+//
+// Optional Headers
+//   - Idempotency-Key: string (retries with the same key and body replay the first response for 24h)
+//
+// Request Body
+//   - amount: int (required, at least 1000, at most the available balance)
+//
+// The amount moves from available to in_payout until an admin transfers it.
+//
+// Success Response
+//   - 201 Created
+//   - Body: Payout (status "requested", account_number masked)
+//
+// Error Responses
+//   - 400 Bad Request: amount below the minimum
+//   - 404 Not Found: no bank account registered
+//   - 409 Conflict: amount exceeds the available balance
+func (h *LedgerHandler) HandleRequestPayout(w http.ResponseWriter, r *http.Request) {
+	userID := middleware.GetUserIDFromContext(r.Context())
+
+	var req struct {
+		Amount int `json:"amount"`
+	}
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		http.Error(w, "invalid request body", http.StatusBadRequest)
+		return
+	}
+
+	payout, err := h.svc.RequestPayout(r.Context(), userID, req.Amount)
+	if err != nil {
+		writeLedgerError(w, err, "request payout")
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusCreated)
+	if err := json.NewEncoder(w).Encode(payout); err != nil {
+		log.Printf("encode payout response error: %v", err)
+	}
+}
+
+// HandleGetMyPayouts returns the current user's payouts, newest first.
+//
+// Route
+//   - GET /me/payouts
+//
+// Required Headers
+//   - Authorization: Bearer <Firebase ID token>
+//
+// Query Parameters
+//   - limit: int (optional, default 50, max 100)
+//   - offset: int (optional, default 0)
+//
+// Success Response
+//   - 200 OK
+//   - Body: []Payout (account_number masked)
+func (h *LedgerHandler) HandleGetMyPayouts(w http.ResponseWriter, r *http.Request) {
+	userID := middleware.GetUserIDFromContext(r.Context())
+
+	q := r.URL.Query()
+	limit := 0
+	if v, err := strconv.Atoi(q.Get("limit")); err == nil {
+		limit = v
+	}
+	offset := 0
+	if v, err := strconv.Atoi(q.Get("offset")); err == nil {
+		offset = v
+	}
+
+	payouts, err := h.svc.GetMyPayouts(r.Context(), userID, limit, offset)
+	if err != nil {
+		writeLedgerError(w, err, "get my payouts")
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	if err := json.NewEncoder(w).Encode(payouts); err != nil {
+		log.Printf("encode payouts response error: %v", err)
+	}
+}
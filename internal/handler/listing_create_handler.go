@@ -1,6 +1,7 @@
 package handler
 
 import (
+	"context"
 	"encoding/json"
 	"errors"
 	"log"
@@ -19,15 +20,31 @@ import (
 //   - Authorization: Bearer <Firebase ID token>
 //   - Content-Type: application/json
 //
+// Optional Headers
+//   - Idempotency-Key: string (retries with the same key and body replay the first response for 24h)
+//
 // Request Body
 //   - title: string (required)
 //   - description: string
 //   - images: []{url: string} (required)
-//   - price: int (required)
+//   - price: int (required, tax-inclusive)
+//   - tax_rate: string (optional, standard (10%) or reduced (8%, food and beverages), default standard)
 //   - quantity: int
 //   - item_condition: string (new, excellent, good, not_good, bad)
+//   - shipping_payer: string (optional, seller or buyer, default seller)
+//   - shipping_method: string (nekopos, yu_packet, takkyubin, yu_pack; required if buyer pays)
+//   - shipping_size: string (small, 60, 80, 100, 120, 140, 160; required if buyer pays)
+//   - ship_from_prefecture: int (optional, JIS prefecture code 1-47)
+//   - days_to_ship: string (optional, 1_2, 2_3, 4_7)
+//   - variants: []{label: string, price: int (optional override), quantity: int} (optional, max 20;
+//     quantity is then the sum of the variant quantities)
 //   - is_active: bool (optional, default false/draft)
 //
+// Listings created with is_active are screened for prohibited content.
+// Flagged listings are created with status "pending_review" until an admin approves them.
+// Published listings are translated into every supported language and matched against saved
+// searches in the background.
+//
 // Success Response
 //   - 201 Created
 //   - Content-Type: application/json
@@ -36,13 +53,20 @@ func (h *ListingHandler) HandleCreate(w http.ResponseWriter, r *http.Request) {
 	userID := middleware.GetUserIDFromContext(r.Context())
 
 	var req struct {
-		Title         string                `json:"title"`
-		Description   string                `json:"description"`
-		Images        []models.ListingImage `json:"images"`
-		Price         int                   `json:"price"`
-		Quantity      int                   `json:"quantity"`
-		ItemCondition models.ItemCondition  `json:"item_condition"`
-		IsActive      bool                  `json:"is_active"`
+		Title              string                  `json:"title"`
+		Description        string                  `json:"description"`
+		Images             []models.ListingImage   `json:"images"`
+		Price              int                     `json:"price"`
+		TaxRate            models.TaxRate          `json:"tax_rate"`
+		Quantity           int                     `json:"quantity"`
+		ItemCondition      models.ItemCondition    `json:"item_condition"`
+		ShippingPayer      models.ShippingPayer    `json:"shipping_payer"`
+		ShippingMethod     models.ShippingMethod   `json:"shipping_method"`
+		ShippingSize       models.ShippingSize     `json:"shipping_size"`
+		ShipFromPrefecture int                     `json:"ship_from_prefecture"`
+		DaysToShip         models.DaysToShip       `json:"days_to_ship"`
+		Variants           []models.ListingVariant `json:"variants"`
+		IsActive           bool                    `json:"is_active"`
 	}
 	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
 		http.Error(w, "invalid request body", http.StatusBadRequest)
@@ -55,21 +79,40 @@ func (h *ListingHandler) HandleCreate(w http.ResponseWriter, r *http.Request) {
 	}
 
 	listing := &models.Listing{
-		SellerID:      userID,
-		Title:         req.Title,
-		Description:   req.Description,
-		Images:        req.Images,
-		Price:         req.Price,
-		Quantity:      req.Quantity,
-		ItemCondition: req.ItemCondition,
-		Status:        status,
+		SellerID:           userID,
+		Title:              req.Title,
+		Description:        req.Description,
+		Images:             req.Images,
+		Price:              req.Price,
+		TaxRate:            req.TaxRate,
+		Quantity:           req.Quantity,
+		ItemCondition:      req.ItemCondition,
+		ShippingPayer:      req.ShippingPayer,
+		ShippingMethod:     req.ShippingMethod,
+		ShippingSize:       req.ShippingSize,
+		ShipFromPrefecture: req.ShipFromPrefecture,
+		DaysToShip:         req.DaysToShip,
+		Variants:           req.Variants,
+		Status:             status,
 	}
 
 	createdListing, err := h.svc.CreateListing(r.Context(), listing)
 	if err != nil {
 		if errors.Is(err, service.ErrTitleRequired) ||
 			errors.Is(err, service.ErrPriceInvalid) ||
-			errors.Is(err, service.ErrNoImages) {
+			errors.Is(err, service.ErrNoImages) ||
+			errors.Is(err, service.ErrInvalidTaxRate) ||
+			errors.Is(err, service.ErrInvalidShippingPayer) ||
+			errors.Is(err, service.ErrInvalidShippingMethod) ||
+			errors.Is(err, service.ErrShippingMethodRequired) ||
+			errors.Is(err, service.ErrInvalidPrefecture) ||
+			errors.Is(err, service.ErrInvalidDaysToShip) ||
+			errors.Is(err, service.ErrTooManyVariants) ||
+			errors.Is(err, service.ErrVariantLabelRequired) ||
+			errors.Is(err, service.ErrVariantLabelLong) ||
+			errors.Is(err, service.ErrDuplicateVariantLabel) ||
+			errors.Is(err, service.ErrVariantPriceInvalid) ||
+			errors.Is(err, service.ErrVariantQuantityInvalid) {
 			http.Error(w, err.Error(), http.StatusBadRequest)
 			return
 		}
@@ -78,6 +121,11 @@ func (h *ListingHandler) HandleCreate(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
+	if createdListing.Status == models.ListingStatusActive {
+		go h.translationSvc.PrefetchListingTranslations(context.WithoutCancel(r.Context()), createdListing)
+		go matchSavedSearches(context.WithoutCancel(r.Context()), h.savedSearchSvc, createdListing.ID)
+	}
+
 	w.WriteHeader(http.StatusCreated)
 	w.Header().Set("Content-Type", "application/json")
 	if err := json.NewEncoder(w).Encode(createdListing); err != nil {
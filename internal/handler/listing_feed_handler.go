@@ -1,10 +1,10 @@
 package handler
 
 import (
-	"encoding/json"
 	"log"
 	"net/http"
 	"strconv"
+	"time"
 )
 
 // HandleFeed returns a list of active listings.
@@ -15,11 +15,19 @@ import (
 // Query Parameters
 //   - limit: int (optional, default 20, max 100)
 //   - offset: int (optional, default 0)
+//   - lang: string (optional, "ja" or "en"; takes precedence over Accept-Language)
+//
+// Only already cached translations are applied, so untranslated listings keep their original text.
+//
+// The feed carries an ETag but no Last-Modified: a listing leaving the feed (sold, removed) does not
+// raise the newest updated_at of the page, so If-Modified-Since would wrongly answer 304.
 //
 // Success Response
 //   - 200 OK
 //   - Content-Type: application/json
-//   - Body: []Listing
+//   - Body: []Listing with optional language and original {title, description}
+//   - ETag (hash of the body), Cache-Control
+//   - 304 Not Modified: If-None-Match matches the current page
 func (h *ListingHandler) HandleFeed(w http.ResponseWriter, r *http.Request) {
 	limitStr := r.URL.Query().Get("limit")
 	offsetStr := r.URL.Query().Get("offset")
@@ -45,8 +53,8 @@ func (h *ListingHandler) HandleFeed(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	w.Header().Set("Content-Type", "application/json")
-	if err := json.NewEncoder(w).Encode(listings); err != nil {
-		log.Printf("encode listings response error: %v", err)
-	}
+	localized := h.translationSvc.LocalizeListings(r.Context(), listings, requestLanguage(r))
+
+	w.Header().Set("Vary", "Accept-Language")
+	writeCachedJSON(w, r, localized, time.Time{}, cachePolicyFeed)
 }
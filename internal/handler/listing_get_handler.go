@@ -1,7 +1,7 @@
 package handler
 
 import (
-	"encoding/json"
+	"context"
 	"errors"
 	"log"
 	"net/http"
@@ -13,10 +13,23 @@ import (
 // Route
 //   - GET /listings/{id}
 //
+// Query Parameters
+//   - lang: string (optional, "ja" or "en"; takes precedence over Accept-Language)
+//
+// Optional Headers
+//   - Accept-Language: preferred languages for the title and description
+//   - If-None-Match / If-Modified-Since: conditional request against ETag / Last-Modified
+//
+// When a supported language is requested and a translation is cached, the translated title and
+// description are returned with the seller's text in "original". On a cache miss the original
+// text is returned and the translation is generated in the background for later requests.
+//
 // Success Response
 //   - 200 OK
 //   - Content-Type: application/json
-//   - Body: Listing
+//   - Body: Listing with optional language and original {title, description}
+//   - ETag (hash of the body), Last-Modified (the later of the listing and translation updated_at), Cache-Control
+//   - 304 Not Modified: the client's cached copy is still current
 func (h *ListingHandler) HandleGetListing(w http.ResponseWriter, r *http.Request) {
 	id := r.PathValue("id")
 	if id == "" {
@@ -35,8 +48,21 @@ func (h *ListingHandler) HandleGetListing(w http.ResponseWriter, r *http.Request
 		return
 	}
 
-	w.Header().Set("Content-Type", "application/json")
-	if err := json.NewEncoder(w).Encode(listing); err != nil {
-		log.Printf("encode listing response error: %v", err)
+	lang := requestLanguage(r)
+	localized, pending := h.translationSvc.LocalizeListing(r.Context(), listing, lang)
+	if pending {
+		go h.translationSvc.TranslateListing(context.WithoutCancel(r.Context()), listing, lang)
+	}
+
+	w.Header().Set("Vary", "Accept-Language")
+	if localized.Language != "" {
+		w.Header().Set("Content-Language", localized.Language)
+	}
+	// A translation cached after the last listing edit changes the body, so it must also
+	// move Last-Modified or If-Modified-Since would keep serving the untranslated copy.
+	lastModified := listing.UpdatedAt
+	if localized.TranslatedAt.After(lastModified) {
+		lastModified = localized.TranslatedAt
 	}
+	writeCachedJSON(w, r, localized, lastModified, cachePolicyListing)
 }
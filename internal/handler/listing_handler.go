@@ -1,17 +1,64 @@
 package handler
 
 import (
+	"net/http"
+	"sort"
+	"strconv"
+	"strings"
 	"uttc-hackathon-backend/internal/service"
 )
 
 type ListingHandler struct {
-	svc     *service.ListingService
-	userSvc *service.UserService
+	svc            *service.ListingService
+	userSvc        *service.UserService
+	translationSvc *service.TranslationService
+	savedSearchSvc *service.SavedSearchService
 }
 
-func NewListingHandler(svc *service.ListingService, userSvc *service.UserService) *ListingHandler {
+func NewListingHandler(svc *service.ListingService, userSvc *service.UserService, translationSvc *service.TranslationService, savedSearchSvc *service.SavedSearchService) *ListingHandler {
 	return &ListingHandler{
-		svc:     svc,
-		userSvc: userSvc,
+		svc:            svc,
+		userSvc:        userSvc,
+		translationSvc: translationSvc,
+		savedSearchSvc: savedSearchSvc,
 	}
 }
+
+// requestLanguage returns the supported language requested by ?lang= or the Accept-Language header,
+// or an empty string when the client did not ask for a supported language.
+func requestLanguage(r *http.Request) string {
+	if lang := strings.ToLower(r.URL.Query().Get("lang")); lang != "" {
+		if service.IsSupportedLanguage(lang) {
+			return lang
+		}
+		return ""
+	}
+
+	type langQ struct {
+		lang string
+		q    float64
+	}
+	var prefs []langQ
+	for _, part := range strings.Split(r.Header.Get("Accept-Language"), ",") {
+		tag, params, _ := strings.Cut(strings.TrimSpace(part), ";")
+		if tag == "" {
+			continue
+		}
+		q := 1.0
+		if v, ok := strings.CutPrefix(strings.TrimSpace(params), "q="); ok {
+			if f, err := strconv.ParseFloat(v, 64); err == nil {
+				q = f
+			}
+		}
+		primary, _, _ := strings.Cut(tag, "-")
+		prefs = append(prefs, langQ{lang: strings.ToLower(primary), q: q})
+	}
+	sort.SliceStable(prefs, func(i, j int) bool { return prefs[i].q > prefs[j].q })
+
+	for _, p := range prefs {
+		if p.q > 0 && service.IsSupportedLanguage(p.lang) {
+			return p.lang
+		}
+	}
+	return ""
+}
@@ -7,7 +7,8 @@ import (
 	"uttc-hackathon-backend/internal/middleware"
 )
 
-// HandleGetConversations returns a list of conversations (latest message per user).
+// HandleGetConversations returns a list of conversations (latest message per user, and per
+// order or listing the messages were about), newest first.
 //
 // Route:
 //   - GET /messages/conversations
@@ -17,7 +18,7 @@ import (
 //
 // Success Response:
 //   - 200 OK
-//   - Body: []Conversation
+//   - Body: []Conversation {message, user, order_id, listing_id}
 //
 // Error Responses:
 //   - 401 Unauthorized
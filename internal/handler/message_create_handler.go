@@ -6,11 +6,13 @@ import (
 	"log"
 	"net/http"
 	"uttc-hackathon-backend/internal/middleware"
+	"uttc-hackathon-backend/internal/repository"
 	"uttc-hackathon-backend/internal/service"
 )
 
 type createMessageRequest struct {
 	ReceiverID string `json:"receiver_id"`
+	ListingID  string `json:"listing_id"`
 	Content    string `json:"content"`
 }
 
@@ -22,10 +24,16 @@ type createMessageRequest struct {
 // Required Headers:
 //   - Authorization: Bearer <Firebase ID token>
 //
+// Optional Headers:
+//   - Idempotency-Key: string (retries with the same key and body replay the first response for 24h)
+//
 // Request Body:
 //   - receiver_id: string (required)
+//   - listing_id: string (optional, puts the message in a thread about the listing)
 //   - content: string (required)
 //
+// Messages about an order go through POST /orders/{orderId}/messages instead.
+//
 // Success Response:
 //   - 201 Created
 //   - Body: Message
@@ -33,6 +41,7 @@ type createMessageRequest struct {
 // Error Responses:
 //   - 400 Bad Request: Invalid body or missing content
 //   - 401 Unauthorized: Missing or invalid token
+//   - 404 Not Found: listing not found
 //   - 500 Internal Server Error: Database error
 func (h *MessageHandler) HandleCreate(w http.ResponseWriter, r *http.Request) {
 	userID := middleware.GetUserIDFromContext(r.Context())
@@ -43,12 +52,16 @@ func (h *MessageHandler) HandleCreate(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	msg, err := h.svc.CreateMessage(r.Context(), userID, req.ReceiverID, req.Content)
+	msg, err := h.svc.CreateMessage(r.Context(), userID, req.ReceiverID, req.ListingID, req.Content)
 	if err != nil {
 		if errors.Is(err, service.ErrContentRequired) || errors.Is(err, service.ErrSelfMessage) {
 			http.Error(w, err.Error(), http.StatusBadRequest)
 			return
 		}
+		if errors.Is(err, repository.ErrListingNotFound) {
+			http.Error(w, "listing not found", http.StatusNotFound)
+			return
+		}
 		// FIXME: handle FK violation
 		log.Printf("create message error: %v", err)
 		http.Error(w, "internal server error", http.StatusInternalServerError)
@@ -7,7 +7,7 @@ import (
 	"uttc-hackathon-backend/internal/middleware"
 )
 
-// HandleGetMessages fetches all messages between the current user and the specified user.
+// HandleGetMessages fetches the direct messages between the current user and the specified user.
 //
 // Route:
 //   - GET /messages/with/{userid}
@@ -15,6 +15,9 @@ import (
 // Required Headers:
 //   - Authorization: Bearer <Firebase ID token>
 //
+// Query Parameters:
+//   - listing_id: string (optional, the thread about the listing instead)
+//
 // Success Response:
 //   - 200 OK
 //   - Body: []Message
@@ -31,7 +34,7 @@ func (h *MessageHandler) HandleGetMessages(w http.ResponseWriter, r *http.Reques
 		return
 	}
 
-	messages, err := h.svc.GetMessages(r.Context(), userID, otherUserID)
+	messages, err := h.svc.GetMessages(r.Context(), userID, otherUserID, r.URL.Query().Get("listing_id"))
 	if err != nil {
 		log.Printf("get messages error: %v", err)
 		http.Error(w, "internal server error", http.StatusInternalServerError)
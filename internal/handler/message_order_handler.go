@@ -0,0 +1,114 @@
+package handler
+
+import (
+	"encoding/json"
+	"errors"
+	"log"
+	"net/http"
+	"uttc-hackathon-backend/internal/middleware"
+	"uttc-hackathon-backend/internal/repository"
+	"uttc-hackathon-backend/internal/service"
+)
+
+// writeOrderMessageError maps order thread errors to responses; op names the request in the log.
+func writeOrderMessageError(w http.ResponseWriter, err error, op string) {
+	switch {
+	case errors.Is(err, service.ErrContentRequired):
+		http.Error(w, err.Error(), http.StatusBadRequest)
+	case errors.Is(err, repository.ErrOrderNotFound), errors.Is(err, service.ErrUnauthorized):
+		http.Error(w, "order not found", http.StatusNotFound)
+	default:
+		log.Printf("%s error: %v", op, err)
+		http.Error(w, "internal server error", http.StatusInternalServerError)
+	}
+}
+
+// HandleCreateOrderMessage sends a message about an order to its other party.
+//
+// Route:
+//   - POST /orders/{orderId}/messages
+//
+// Required Headers:
+//   - Authorization: Bearer <Firebase ID token>
+//
+// Optional Headers:
+//   - Idempotency-Key: string (retries with the same key and body replay the first response for 24h)
+//
+// Request Body:
+//   - content: string (required)
+//
+// Success Response:
+//   - 201 Created
+//   - Body: Message (receiver_id is the other party, order_id is set)
+//
+// Error Responses:
+//   - 400 Bad Request: Invalid body or missing content
+//   - 401 Unauthorized: Missing or invalid token
+//   - 404 Not Found: order not found or the user is not its buyer or seller
+//   - 500 Internal Server Error: Database error
+func (h *MessageHandler) HandleCreateOrderMessage(w http.ResponseWriter, r *http.Request) {
+	userID := middleware.GetUserIDFromContext(r.Context())
+
+	orderID := r.PathValue("orderId")
+	if orderID == "" {
+		http.Error(w, "missing order id", http.StatusBadRequest)
+		return
+	}
+
+	var req struct {
+		Content string `json:"content"`
+	}
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		http.Error(w, "invalid request body", http.StatusBadRequest)
+		return
+	}
+
+	msg, err := h.svc.CreateOrderMessage(r.Context(), userID, orderID, req.Content)
+	if err != nil {
+		writeOrderMessageError(w, err, "create order message")
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusCreated)
+	if err := json.NewEncoder(w).Encode(msg); err != nil {
+		log.Printf("encode create order message response error: %v", err)
+	}
+}
+
+// HandleGetOrderMessages returns the messages about an order, oldest first.
+//
+// Route:
+//   - GET /orders/{orderId}/messages
+//
+// Required Headers:
+//   - Authorization: Bearer <Firebase ID token>
+//
+// Success Response:
+//   - 200 OK
+//   - Body: []Message
+//
+// Error Responses:
+//   - 401 Unauthorized: Missing or invalid token
+//   - 404 Not Found: order not found or the user is not its buyer or seller
+//   - 500 Internal Server Error: Database error
+func (h *MessageHandler) HandleGetOrderMessages(w http.ResponseWriter, r *http.Request) {
+	userID := middleware.GetUserIDFromContext(r.Context())
+
+	orderID := r.PathValue("orderId")
+	if orderID == "" {
+		http.Error(w, "missing order id", http.StatusBadRequest)
+		return
+	}
+
+	messages, err := h.svc.GetOrderMessages(r.Context(), userID, orderID)
+	if err != nil {
+		writeOrderMessageError(w, err, "get order messages")
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	if err := json.NewEncoder(w).Encode(messages); err != nil {
+		log.Printf("encode get order messages response error: %v", err)
+	}
+}
@@ -0,0 +1,175 @@
+package handler
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"log"
+	"net/http"
+	"strconv"
+	"uttc-hackathon-backend/internal/middleware"
+	"uttc-hackathon-backend/internal/repository"
+	"uttc-hackathon-backend/internal/service"
+)
+
+// HandleTakeDownListing removes a listing from the marketplace.
+// Open reports against the listing are marked as actioned.
+//
+// Route
+//   - POST /admin/listings/{id}/takedown
+//
+// Required Headers
+//   - Authorization: Bearer <Firebase ID token of an admin>
+//
+// Success Response
+//   - 204 No Content
+//
+// Error Responses
+//   - 403 Forbidden: not an admin
+//   - 404 Not Found: listing not found
+func (h *ModerationHandler) HandleTakeDownListing(w http.ResponseWriter, r *http.Request) {
+	adminID := middleware.GetUserIDFromContext(r.Context())
+
+	listingID := r.PathValue("id")
+	if listingID == "" {
+		http.Error(w, "missing listing id", http.StatusBadRequest)
+		return
+	}
+
+	if err := h.svc.TakeDownListing(r.Context(), adminID, listingID); err != nil {
+		if errors.Is(err, service.ErrListingNotFound) {
+			http.Error(w, "listing not found", http.StatusNotFound)
+			return
+		}
+		log.Printf("take down listing error: %v", err)
+		http.Error(w, "internal server error", http.StatusInternalServerError)
+		return
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// HandleGetPendingListings returns listings held by content screening.
+//
+// Route
+//   - GET /admin/listings/pending
+//
+// Required Headers
+//   - Authorization: Bearer <Firebase ID token of an admin>
+//
+// Query Parameters
+//   - limit: int (optional, default 50, max 100)
+//   - offset: int (optional, default 0)
+//
+// Success Response
+//   - 200 OK
+//   - Body: []Listing (oldest first)
+//
+// Error Responses
+//   - 403 Forbidden: not an admin
+func (h *ModerationHandler) HandleGetPendingListings(w http.ResponseWriter, r *http.Request) {
+	q := r.URL.Query()
+	limit := 0
+	if v, err := strconv.Atoi(q.Get("limit")); err == nil {
+		limit = v
+	}
+	offset := 0
+	if v, err := strconv.Atoi(q.Get("offset")); err == nil {
+		offset = v
+	}
+
+	listings, err := h.svc.GetPendingListings(r.Context(), limit, offset)
+	if err != nil {
+		log.Printf("get pending listings error: %v", err)
+		http.Error(w, "internal server error", http.StatusInternalServerError)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	if err := json.NewEncoder(w).Encode(listings); err != nil {
+		log.Printf("encode pending listings response error: %v", err)
+	}
+}
+
+// HandleApproveListing publishes a listing held by content screening.
+//
+// Route
+//   - POST /admin/listings/{id}/approve
+//
+// Required Headers
+//   - Authorization: Bearer <Firebase ID token of an admin>
+//
+// Success Response
+//   - 204 No Content
+//
+// Error Responses
+//   - 403 Forbidden: not an admin
+//   - 404 Not Found: listing not found
+//   - 409 Conflict: listing is not pending review
+func (h *ModerationHandler) HandleApproveListing(w http.ResponseWriter, r *http.Request) {
+	adminID := middleware.GetUserIDFromContext(r.Context())
+
+	listingID := r.PathValue("id")
+	if listingID == "" {
+		http.Error(w, "missing listing id", http.StatusBadRequest)
+		return
+	}
+
+	if err := h.svc.ApproveListing(r.Context(), adminID, listingID); err != nil {
+		switch {
+		case errors.Is(err, service.ErrListingNotFound):
+			http.Error(w, "listing not found", http.StatusNotFound)
+		case errors.Is(err, service.ErrListingNotPending):
+			http.Error(w, err.Error(), http.StatusConflict)
+		default:
+			log.Printf("approve listing error: %v", err)
+			http.Error(w, "internal server error", http.StatusInternalServerError)
+		}
+		return
+	}
+
+	go matchSavedSearches(context.WithoutCancel(r.Context()), h.savedSearchSvc, listingID)
+
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// HandleSuspendUser suspends a user account.
+// Open reports against the user are marked as actioned.
+//
+// Route
+//   - POST /admin/users/{userId}/suspend
+//
+// Required Headers
+//   - Authorization: Bearer <Firebase ID token of an admin>
+//
+// Success Response
+//   - 204 No Content
+//
+// Error Responses
+//   - 400 Bad Request: suspending yourself
+//   - 403 Forbidden: not an admin
+//   - 404 Not Found: user not found
+func (h *ModerationHandler) HandleSuspendUser(w http.ResponseWriter, r *http.Request) {
+	adminID := middleware.GetUserIDFromContext(r.Context())
+
+	userID := r.PathValue("userId")
+	if userID == "" {
+		http.Error(w, "missing user id", http.StatusBadRequest)
+		return
+	}
+
+	if err := h.svc.SuspendUser(r.Context(), adminID, userID); err != nil {
+		switch {
+		case errors.Is(err, service.ErrSelfSuspend):
+			http.Error(w, err.Error(), http.StatusBadRequest)
+		case errors.Is(err, service.ErrUserNotFound), errors.Is(err, repository.ErrUserNotFound):
+			http.Error(w, "user not found", http.StatusNotFound)
+		default:
+			log.Printf("suspend user error: %v", err)
+			http.Error(w, "internal server error", http.StatusInternalServerError)
+		}
+		return
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+}
@@ -0,0 +1,12 @@
+package handler
+
+import "uttc-hackathon-backend/internal/service"
+
+type ModerationHandler struct {
+	svc            *service.ModerationService
+	savedSearchSvc *service.SavedSearchService
+}
+
+func NewModerationHandler(svc *service.ModerationService, savedSearchSvc *service.SavedSearchService) *ModerationHandler {
+	return &ModerationHandler{svc: svc, savedSearchSvc: savedSearchSvc}
+}
@@ -0,0 +1,122 @@
+package handler
+
+import (
+	"encoding/json"
+	"errors"
+	"log"
+	"net/http"
+	"strconv"
+	"uttc-hackathon-backend/internal/middleware"
+	"uttc-hackathon-backend/internal/models"
+	"uttc-hackathon-backend/internal/repository"
+	"uttc-hackathon-backend/internal/service"
+)
+
+// HandleGetReports returns the moderation queue.
+//
+// Route
+//   - GET /admin/reports
+//
+// Required Headers
+//   - Authorization: Bearer <Firebase ID token of an admin>
+//
+// Query Parameters
+//   - status: string (optional, open, in_review, actioned, dismissed; default all)
+//   - limit: int (optional, default 50, max 100)
+//   - offset: int (optional, default 0)
+//
+// Success Response
+//   - 200 OK
+//   - Body: []Report (oldest first)
+//
+// Error Responses
+//   - 400 Bad Request: invalid status
+//   - 403 Forbidden: not an admin
+func (h *ModerationHandler) HandleGetReports(w http.ResponseWriter, r *http.Request) {
+	q := r.URL.Query()
+	status := models.ReportStatus(q.Get("status"))
+
+	limit := 0
+	if v, err := strconv.Atoi(q.Get("limit")); err == nil {
+		limit = v
+	}
+	offset := 0
+	if v, err := strconv.Atoi(q.Get("offset")); err == nil {
+		offset = v
+	}
+
+	reports, err := h.svc.GetReports(r.Context(), status, limit, offset)
+	if err != nil {
+		if errors.Is(err, service.ErrInvalidReportStatus) {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+		log.Printf("get reports error: %v", err)
+		http.Error(w, "internal server error", http.StatusInternalServerError)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	if err := json.NewEncoder(w).Encode(reports); err != nil {
+		log.Printf("encode reports response error: %v", err)
+	}
+}
+
+// HandleUpdateReportStatus moves a report through the moderation queue.
+//
+// Route
+//   - POST /admin/reports/{reportId}/status
+//
+// Required Headers
+//   - Authorization: Bearer <Firebase ID token of an admin>
+//
+// Request Body
+//   - status: string (required, in_review, actioned, dismissed)
+//
+// Success Response
+//   - 200 OK
+//   - Body: Report
+//
+// Error Responses
+//   - 400 Bad Request: invalid status
+//   - 403 Forbidden: not an admin
+//   - 404 Not Found: report not found
+//   - 409 Conflict: report already actioned or dismissed
+func (h *ModerationHandler) HandleUpdateReportStatus(w http.ResponseWriter, r *http.Request) {
+	adminID := middleware.GetUserIDFromContext(r.Context())
+
+	reportID := r.PathValue("reportId")
+	if reportID == "" {
+		http.Error(w, "missing report id", http.StatusBadRequest)
+		return
+	}
+
+	var req struct {
+		Status models.ReportStatus `json:"status"`
+	}
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		http.Error(w, "invalid request body", http.StatusBadRequest)
+		return
+	}
+
+	report, err := h.svc.UpdateReportStatus(r.Context(), adminID, reportID, req.Status)
+	if err != nil {
+		switch {
+		case errors.Is(err, service.ErrInvalidReportStatus):
+			http.Error(w, err.Error(), http.StatusBadRequest)
+		case errors.Is(err, repository.ErrReportNotFound):
+			http.Error(w, "report not found", http.StatusNotFound)
+		case errors.Is(err, service.ErrReportClosed):
+			http.Error(w, err.Error(), http.StatusConflict)
+		default:
+			log.Printf("update report status error: %v", err)
+			http.Error(w, "internal server error", http.StatusInternalServerError)
+		}
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	if err := json.NewEncoder(w).Encode(report); err != nil {
+		log.Printf("encode report response error: %v", err)
+	}
+}
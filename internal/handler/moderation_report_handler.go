@@ -0,0 +1,135 @@
+package handler
+
+import (
+	"encoding/json"
+	"errors"
+	"log"
+	"net/http"
+	"uttc-hackathon-backend/internal/middleware"
+	"uttc-hackathon-backend/internal/models"
+	"uttc-hackathon-backend/internal/repository"
+	"uttc-hackathon-backend/internal/service"
+)
+
+type createReportRequest struct {
+	Reason  models.ReportReason `json:"reason"`
+	Comment string              `json:"comment"`
+}
+
+// HandleReportListing reports a listing to the moderators.
+//
+// Route
+//   - POST /listings/{id}/report
+//
+// Required Headers
+//   - Authorization: Bearer <Firebase ID token>
+//
+// Request Body
+//   - reason: string (required, scam, prohibited_item, counterfeit, inappropriate, spam, harassment, other)
+//   - comment: string (optional, max 1000 characters)
+//
+// Success Response
+//   - 201 Created
+//   - Body: Report
+//
+// Error Responses
+//   - 400 Bad Request: invalid reason or reporting your own listing
+//   - 404 Not Found: listing not found
+//   - 409 Conflict: already reported
+//   - 429 Too Many Requests: report rate limit exceeded
+func (h *ModerationHandler) HandleReportListing(w http.ResponseWriter, r *http.Request) {
+	userID := middleware.GetUserIDFromContext(r.Context())
+
+	listingID := r.PathValue("id")
+	if listingID == "" {
+		http.Error(w, "missing listing id", http.StatusBadRequest)
+		return
+	}
+
+	var req createReportRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		http.Error(w, "invalid request body", http.StatusBadRequest)
+		return
+	}
+
+	report, err := h.svc.ReportListing(r.Context(), userID, listingID, req.Reason, req.Comment)
+	if err != nil {
+		writeReportError(w, err)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusCreated)
+	if err := json.NewEncoder(w).Encode(report); err != nil {
+		log.Printf("encode report listing response error: %v", err)
+	}
+}
+
+// HandleReportUser reports a user to the moderators.
+//
+// Route
+//   - POST /users/{userId}/report
+//
+// Required Headers
+//   - Authorization: Bearer <Firebase ID token>
+//
+// Request Body
+//   - reason: string (required, scam, prohibited_item, counterfeit, inappropriate, spam, harassment, other)
+//   - comment: string (optional, max 1000 characters)
+//
+// Success Response
+//   - 201 Created
+//   - Body: Report
+//
+// Error Responses
+//   - 400 Bad Request: invalid reason or reporting yourself
+//   - 404 Not Found: user not found
+//   - 409 Conflict: already reported
+//   - 429 Too Many Requests: report rate limit exceeded
+func (h *ModerationHandler) HandleReportUser(w http.ResponseWriter, r *http.Request) {
+	userID := middleware.GetUserIDFromContext(r.Context())
+
+	targetID := r.PathValue("userId")
+	if targetID == "" {
+		http.Error(w, "missing user id", http.StatusBadRequest)
+		return
+	}
+
+	var req createReportRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		http.Error(w, "invalid request body", http.StatusBadRequest)
+		return
+	}
+
+	report, err := h.svc.ReportUser(r.Context(), userID, targetID, req.Reason, req.Comment)
+	if err != nil {
+		writeReportError(w, err)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusCreated)
+	if err := json.NewEncoder(w).Encode(report); err != nil {
+		log.Printf("encode report user response error: %v", err)
+	}
+}
+
+func writeReportError(w http.ResponseWriter, err error) {
+	switch {
+	case errors.Is(err, service.ErrInvalidReportReason),
+		errors.Is(err, service.ErrReportCommentLong),
+		errors.Is(err, service.ErrSelfReport):
+		http.Error(w, err.Error(), http.StatusBadRequest)
+	case errors.Is(err, service.ErrListingNotFound):
+		http.Error(w, "listing not found", http.StatusNotFound)
+	case errors.Is(err, service.ErrUserNotFound):
+		http.Error(w, "user not found", http.StatusNotFound)
+	case errors.Is(err, repository.ErrDuplicateReport):
+		http.Error(w, err.Error(), http.StatusConflict)
+	case errors.Is(err, service.ErrReportRateLimited):
+		http.Error(w, err.Error(), http.StatusTooManyRequests)
+	default:
+		log.Printf("create report error: %v", err)
+		http.Error(w, "internal server error", http.StatusInternalServerError)
+	}
+}
@@ -0,0 +1,11 @@
+package handler
+
+import "uttc-hackathon-backend/internal/service"
+
+type NotificationHandler struct {
+	svc *service.NotificationService
+}
+
+func NewNotificationHandler(svc *service.NotificationService) *NotificationHandler {
+	return &NotificationHandler{svc: svc}
+}
@@ -0,0 +1,82 @@
+package handler
+
+import (
+	"encoding/json"
+	"errors"
+	"log"
+	"net/http"
+	"strconv"
+	"uttc-hackathon-backend/internal/middleware"
+	"uttc-hackathon-backend/internal/repository"
+)
+
+// HandleGetMine returns the current user's notifications, newest first.
+//
+// Route
+//   - GET /notifications
+//
+// Required Headers
+//   - Authorization: Bearer <Firebase ID token>
+//
+// Query Parameters
+//   - limit: int (optional, default 20, max 100)
+//   - offset: int (optional, default 0)
+//
+// Success Response
+//   - 200 OK
+//   - Body: []Notification {id, type, payload, read_at, created_at}
+//   - saved_search_digest payload: {count, matches: []{saved_search_id, listing_id}}
+func (h *NotificationHandler) HandleGetMine(w http.ResponseWriter, r *http.Request) {
+	userID := middleware.GetUserIDFromContext(r.Context())
+
+	q := r.URL.Query()
+	limit := 0
+	if v, err := strconv.Atoi(q.Get("limit")); err == nil {
+		limit = v
+	}
+	offset := 0
+	if v, err := strconv.Atoi(q.Get("offset")); err == nil {
+		offset = v
+	}
+
+	notifications, err := h.svc.GetNotifications(r.Context(), userID, limit, offset)
+	if err != nil {
+		log.Printf("get notifications error: %v", err)
+		http.Error(w, "internal server error", http.StatusInternalServerError)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	if err := json.NewEncoder(w).Encode(notifications); err != nil {
+		log.Printf("encode notifications response error: %v", err)
+	}
+}
+
+// HandleMarkRead marks a notification as read.
+//
+// Route
+//   - POST /notifications/{id}/read
+//
+// Required Headers
+//   - Authorization: Bearer <Firebase ID token>
+//
+// Success Response
+//   - 204 No Content
+//
+// Error Responses
+//   - 404 Not Found: no such notification for this user
+func (h *NotificationHandler) HandleMarkRead(w http.ResponseWriter, r *http.Request) {
+	userID := middleware.GetUserIDFromContext(r.Context())
+
+	if err := h.svc.MarkRead(r.Context(), userID, r.PathValue("id")); err != nil {
+		if errors.Is(err, repository.ErrNotificationNotFound) {
+			http.Error(w, "notification not found", http.StatusNotFound)
+			return
+		}
+		log.Printf("mark notification read error: %v", err)
+		http.Error(w, "internal server error", http.StatusInternalServerError)
+		return
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+}
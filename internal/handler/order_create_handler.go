@@ -19,13 +19,28 @@ import (
 // Required Headers
 //   - Authorization: Bearer <Firebase ID token>
 //
+// Optional Headers
+//   - Idempotency-Key: string (retries with the same key and body replay the first response for 24h)
+//
 // Request Body
 //   - listing_id: string (required)
 //   - quantity: int (required)
+//   - variant_id: string (required when the listing has variants)
+//
+// The order is created as "pending_payment" and holds its stock for 30 minutes while the
+// buyer completes the payment on the client with payment_client_secret. The payment webhook
+// then marks it paid; otherwise it is cancelled and the stock released.
 //
 // Success Response
 //   - 201 Created
-//   - Body: Order
+//   - Body: Order (with payment_intent_id, payment_client_secret and payment_expires_at)
+//
+// Error Responses
+//   - 400 Bad Request: invalid quantity or variant, own or inactive listing
+//   - 403 Forbidden: blocked by a fraud rule; Body: {error, code} where code is order_velocity,
+//     spend_limit, new_account_limit or same_seller_velocity
+//   - 409 Conflict: insufficient stock
+//   - 503 Service Unavailable: the payment could not be started
 func (h *OrderHandler) HandleCreate(w http.ResponseWriter, r *http.Request) {
 	userID := middleware.GetUserIDFromContext(r.Context())
 
@@ -37,7 +52,8 @@ func (h *OrderHandler) HandleCreate(w http.ResponseWriter, r *http.Request) {
 
 	createdOrder, err := h.svc.CreateOrder(r.Context(), userID, &req)
 	if err != nil {
-		if errors.Is(err, service.ErrQuantityInvalid) || errors.Is(err, service.ErrBuyOwnListing) {
+		if errors.Is(err, service.ErrQuantityInvalid) || errors.Is(err, service.ErrBuyOwnListing) ||
+			errors.Is(err, service.ErrVariantRequired) || errors.Is(err, service.ErrVariantNotFound) {
 			http.Error(w, err.Error(), http.StatusBadRequest)
 			return
 		}
@@ -45,10 +61,24 @@ func (h *OrderHandler) HandleCreate(w http.ResponseWriter, r *http.Request) {
 			http.Error(w, err.Error(), http.StatusBadRequest)
 			return
 		}
+		var violation *service.FraudViolationError
+		if errors.As(err, &violation) {
+			w.Header().Set("Content-Type", "application/json")
+			w.WriteHeader(http.StatusForbidden)
+			resp := map[string]string{"error": service.ErrOrderBlocked.Error(), "code": string(violation.Rule)}
+			if err := json.NewEncoder(w).Encode(resp); err != nil {
+				log.Printf("encode order blocked response error: %v", err)
+			}
+			return
+		}
 		if errors.Is(err, service.ErrInsufficientStock) {
 			http.Error(w, err.Error(), http.StatusConflict)
 			return
 		}
+		if errors.Is(err, service.ErrPaymentUnavailable) {
+			http.Error(w, err.Error(), http.StatusServiceUnavailable)
+			return
+		}
 
 		log.Printf("create order error: %v", err)
 		http.Error(w, "internal server error", http.StatusInternalServerError)
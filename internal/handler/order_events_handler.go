@@ -0,0 +1,53 @@
+package handler
+
+import (
+	"encoding/json"
+	"errors"
+	"log"
+	"net/http"
+	"uttc-hackathon-backend/internal/middleware"
+	"uttc-hackathon-backend/internal/repository"
+	"uttc-hackathon-backend/internal/service"
+)
+
+// HandleGetEvents returns the order's timeline to the buyer or seller.
+//
+// Route
+//   - GET /orders/{orderId}/events
+//
+// Required Headers
+//   - Authorization: Bearer <Firebase ID token>
+//
+// Success Response
+//   - 200 OK
+//   - Body: []OrderEvent {id, order_id, type, actor_id, actor_role, from_status, to_status, metadata, created_at}, oldest first
+//   - actor_role is buyer, seller, admin or system
+//
+// Error Responses
+//   - 401 Unauthorized
+//   - 404 Not Found: order not found or the user is not part of it
+func (h *OrderHandler) HandleGetEvents(w http.ResponseWriter, r *http.Request) {
+	userID := middleware.GetUserIDFromContext(r.Context())
+
+	orderID := r.PathValue("orderId")
+	if orderID == "" {
+		http.Error(w, "missing order id", http.StatusBadRequest)
+		return
+	}
+
+	events, err := h.svc.GetOrderEvents(r.Context(), userID, orderID)
+	if err != nil {
+		if errors.Is(err, repository.ErrOrderNotFound) || errors.Is(err, service.ErrUnauthorized) {
+			http.Error(w, "order not found", http.StatusNotFound)
+			return
+		}
+		log.Printf("get order events error: %v", err)
+		http.Error(w, "internal server error", http.StatusInternalServerError)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	if err := json.NewEncoder(w).Encode(events); err != nil {
+		log.Printf("encode order events response error: %v", err)
+	}
+}
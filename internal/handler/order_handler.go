@@ -3,13 +3,17 @@ package handler
 import "uttc-hackathon-backend/internal/service"
 
 type OrderHandler struct {
-	svc     *service.OrderService
-	userSvc *service.UserService
+	svc         *service.OrderService
+	userSvc     *service.UserService
+	invoiceSvc  *service.InvoiceService
+	shipmentSvc *service.ShipmentService
 }
 
-func NewOrderHandler(svc *service.OrderService, userSvc *service.UserService) *OrderHandler {
+func NewOrderHandler(svc *service.OrderService, userSvc *service.UserService, invoiceSvc *service.InvoiceService, shipmentSvc *service.ShipmentService) *OrderHandler {
 	return &OrderHandler{
-		svc:     svc,
-		userSvc: userSvc,
+		svc:         svc,
+		userSvc:     userSvc,
+		invoiceSvc:  invoiceSvc,
+		shipmentSvc: shipmentSvc,
 	}
 }
@@ -0,0 +1,135 @@
+package handler
+
+import (
+	"encoding/json"
+	"errors"
+	"html/template"
+	"log"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+	"uttc-hackathon-backend/internal/middleware"
+	"uttc-hackathon-backend/internal/models"
+	"uttc-hackathon-backend/internal/repository"
+	"uttc-hackathon-backend/internal/service"
+)
+
+var jst = time.FixedZone("JST", 9*60*60)
+
+var invoiceTemplate = template.Must(template.New("invoice").Funcs(template.FuncMap{
+	"yen":     formatYen,
+	"date":    func(t time.Time) string { return t.In(jst).Format("2006年1月2日") },
+	"reduced": func(r models.TaxRate) bool { return r == models.TaxRateReduced },
+}).Parse(`<!DOCTYPE html>
+<html lang="ja">
+<head>
+<meta charset="utf-8">
+<title>{{if .Qualified}}適格請求書{{else}}領収書{{end}} {{.OrderID}}</title>
+<style>
+body { font-family: sans-serif; max-width: 720px; margin: 2em auto; }
+table { width: 100%; border-collapse: collapse; }
+th, td { border: 1px solid #999; padding: 4px 8px; }
+td.num { text-align: right; }
+</style>
+</head>
+<body>
+<h1>{{if .Qualified}}適格請求書{{else}}領収書{{end}}</h1>
+<p>{{.BuyerName}} 様</p>
+<p>注文番号: {{.OrderID}}<br>
+取引年月日: {{date .TransactionDate}}<br>
+発行日: {{date .IssuedAt}}</p>
+<p>発行者: {{.SellerName}}{{if .Qualified}}<br>
+登録番号: {{.SellerRegistrationNumber}}{{end}}</p>
+<table>
+<tr><th>品名</th><th>単価（税込）</th><th>数量</th><th>金額（税込）</th></tr>
+{{range .Lines}}<tr><td>{{.Description}}{{if reduced .TaxRate}} ※{{end}}</td><td class="num">{{yen .UnitPrice}}</td><td class="num">{{.Quantity}}</td><td class="num">{{yen .Amount}}</td></tr>
+{{end}}<tr><th colspan="3">合計</th><td class="num">{{yen .Total}}</td></tr>
+</table>
+<table>
+<tr><th>税率</th><th>対象金額（税込）</th><th>消費税額</th></tr>
+{{if .Tax.StandardRateTotal}}<tr><td>10%</td><td class="num">{{yen .Tax.StandardRateTotal}}</td><td class="num">{{yen .Tax.StandardRateTax}}</td></tr>
+{{end}}{{if .Tax.ReducedRateTotal}}<tr><td>8%</td><td class="num">{{yen .Tax.ReducedRateTotal}}</td><td class="num">{{yen .Tax.ReducedRateTax}}</td></tr>
+{{end}}</table>
+{{if .Tax.ReducedRateTotal}}<p>※ は軽減税率（8%）対象品目です。</p>
+{{end}}</body>
+</html>
+`))
+
+// formatYen renders an amount as ¥1,234.
+func formatYen(n int) string {
+	s := strconv.Itoa(n)
+	var b strings.Builder
+	b.WriteString("¥")
+	for i, c := range s {
+		if i > 0 && (len(s)-i)%3 == 0 {
+			b.WriteByte(',')
+		}
+		b.WriteRune(c)
+	}
+	return b.String()
+}
+
+// wantsHTML reports whether the client asked for the printable form.
+func wantsHTML(r *http.Request) bool {
+	if f := r.URL.Query().Get("format"); f != "" {
+		return f == "html"
+	}
+	return strings.Contains(r.Header.Get("Accept"), "text/html")
+}
+
+// HandleGetInvoice returns the invoice for an order to its buyer or seller.
+//
+// Route
+//   - GET /orders/{orderId}/invoice
+//
+// Required Headers
+//   - Authorization: Bearer <Firebase ID token>
+//
+// Optional Headers
+//   - Accept: text/html for the printable form
+//
+// Query Parameters
+//   - format: string (optional, json or html; overrides Accept)
+//
+// The invoice is a qualified invoice (適格請求書) when the seller was registered at the time
+// of purchase. Prices are tax-inclusive; tax is shown per rate and reduced-rate items are marked.
+//
+// Success Response
+//   - 200 OK
+//   - Body: Invoice {order_id, qualified, seller_name, seller_registration_number, buyer_name,
+//     transaction_date, issued_at, lines, tax, total}, or an HTML document
+//
+// Error Responses
+//   - 404 Not Found: order not found or not a party to it
+//   - 409 Conflict: the order is not paid or was cancelled
+func (h *OrderHandler) HandleGetInvoice(w http.ResponseWriter, r *http.Request) {
+	userID := middleware.GetUserIDFromContext(r.Context())
+
+	invoice, err := h.invoiceSvc.GetInvoice(r.Context(), userID, r.PathValue("orderId"))
+	if err != nil {
+		switch {
+		case errors.Is(err, repository.ErrOrderNotFound), errors.Is(err, service.ErrUnauthorized):
+			http.Error(w, "order not found", http.StatusNotFound)
+		case errors.Is(err, service.ErrInvoiceNotAvailable):
+			http.Error(w, err.Error(), http.StatusConflict)
+		default:
+			log.Printf("get invoice error: %v", err)
+			http.Error(w, "internal server error", http.StatusInternalServerError)
+		}
+		return
+	}
+
+	if wantsHTML(r) {
+		w.Header().Set("Content-Type", "text/html; charset=utf-8")
+		if err := invoiceTemplate.Execute(w, invoice); err != nil {
+			log.Printf("render invoice error: %v", err)
+		}
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	if err := json.NewEncoder(w).Encode(invoice); err != nil {
+		log.Printf("encode invoice response error: %v", err)
+	}
+}
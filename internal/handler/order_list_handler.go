@@ -2,12 +2,18 @@ package handler
 
 import (
 	"encoding/json"
+	"errors"
 	"log"
 	"net/http"
+	"strconv"
+	"strings"
+	"time"
 	"uttc-hackathon-backend/internal/middleware"
+	"uttc-hackathon-backend/internal/models"
+	"uttc-hackathon-backend/internal/service"
 )
 
-// HandleGetMyOrders returns all orders for the current user (buyer or seller).
+// HandleGetMyOrders returns the current user's orders, newest first.
 //
 // Route
 //   - GET /orders/my
@@ -15,21 +21,71 @@ import (
 // Required Headers
 //   - Authorization: Bearer <Firebase ID token>
 //
+// Query Parameters
+//   - role: "buyer" | "seller" (optional, default both)
+//   - status: order status (optional, repeatable or comma separated, e.g. status=paid,shipped)
+//   - from: YYYY-MM-DD (optional, orders created on or after this day, JST)
+//   - to: YYYY-MM-DD (optional, orders created on or before this day, JST)
+//   - limit: int (optional, default 50, max 100)
+//   - offset: int (optional, default 0)
+//
 // Success Response
 //   - 200 OK
 //   - Body: []Order
 //
 // Error Responses
+//   - 400 Bad Request: invalid role, status or date range
 //   - 401 Unauthorized
 //   - 500 Internal Server Error
 func (h *OrderHandler) HandleGetMyOrders(w http.ResponseWriter, r *http.Request) {
 	userID := middleware.GetUserIDFromContext(r.Context())
 
+	// Parse Query
+	q := r.URL.Query()
+	f := models.OrderFilter{Role: models.OrderRole(q.Get("role"))}
+	for _, v := range q["status"] {
+		for _, st := range strings.Split(v, ",") {
+			if st = strings.TrimSpace(st); st != "" {
+				f.Statuses = append(f.Statuses, models.OrderStatus(st))
+			}
+		}
+	}
+	if v := q.Get("from"); v != "" {
+		day, err := time.ParseInLocation(time.DateOnly, v, jst)
+		if err != nil {
+			http.Error(w, "from must be a date (YYYY-MM-DD)", http.StatusBadRequest)
+			return
+		}
+		f.CreatedFrom = day
+	}
+	if v := q.Get("to"); v != "" {
+		day, err := time.ParseInLocation(time.DateOnly, v, jst)
+		if err != nil {
+			http.Error(w, "to must be a date (YYYY-MM-DD)", http.StatusBadRequest)
+			return
+		}
+		// The whole day is included
+		f.CreatedTo = day.AddDate(0, 0, 1)
+	}
+	if v, err := strconv.Atoi(q.Get("limit")); err == nil {
+		f.Limit = v
+	}
+	if v, err := strconv.Atoi(q.Get("offset")); err == nil {
+		f.Offset = v
+	}
+
 	// Call Service
-	orders, err := h.svc.GetOrdersByUser(r.Context(), userID)
+	orders, err := h.svc.GetOrdersByUser(r.Context(), userID, f)
 	if err != nil {
-		log.Printf("get my orders error: %v", err)
-		http.Error(w, "internal server error", http.StatusInternalServerError)
+		switch {
+		case errors.Is(err, service.ErrInvalidOrderRole),
+			errors.Is(err, service.ErrInvalidOrderStatus),
+			errors.Is(err, service.ErrInvalidDateRange):
+			http.Error(w, err.Error(), http.StatusBadRequest)
+		default:
+			log.Printf("get my orders error: %v", err)
+			http.Error(w, "internal server error", http.StatusInternalServerError)
+		}
 		return
 	}
 
@@ -39,3 +95,44 @@ func (h *OrderHandler) HandleGetMyOrders(w http.ResponseWriter, r *http.Request)
 		log.Printf("encode get my orders response error: %v", err)
 	}
 }
+
+// HandleGetMyOrderSummary counts the current user's orders per status, e.g. for the seller
+// dashboard's "3 to ship".
+//
+// Route
+//   - GET /orders/my/summary
+//
+// Required Headers
+//   - Authorization: Bearer <Firebase ID token>
+//
+// Query Parameters
+//   - role: "buyer" | "seller" (optional, default both)
+//
+// Success Response
+//   - 200 OK
+//   - Body: OrderSummary {role, counts: {status: count}, total}
+//
+// Error Responses
+//   - 400 Bad Request: invalid role
+//   - 401 Unauthorized
+//   - 500 Internal Server Error
+func (h *OrderHandler) HandleGetMyOrderSummary(w http.ResponseWriter, r *http.Request) {
+	userID := middleware.GetUserIDFromContext(r.Context())
+
+	role := models.OrderRole(r.URL.Query().Get("role"))
+	summary, err := h.svc.GetOrderSummary(r.Context(), userID, role)
+	if err != nil {
+		if errors.Is(err, service.ErrInvalidOrderRole) {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+		log.Printf("get my order summary error: %v", err)
+		http.Error(w, "internal server error", http.StatusInternalServerError)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	if err := json.NewEncoder(w).Encode(summary); err != nil {
+		log.Printf("encode order summary response error: %v", err)
+	}
+}
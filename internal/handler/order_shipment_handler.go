@@ -0,0 +1,50 @@
+package handler
+
+import (
+	"encoding/json"
+	"errors"
+	"log"
+	"net/http"
+	"uttc-hackathon-backend/internal/middleware"
+	"uttc-hackathon-backend/internal/repository"
+	"uttc-hackathon-backend/internal/service"
+)
+
+// HandleGetShipment returns the package sent for an order to its buyer or seller.
+//
+// Route
+//   - GET /orders/{orderId}/shipment
+//
+// Required Headers
+//   - Authorization: Bearer <Firebase ID token>
+//
+// Success Response
+//   - 200 OK
+//   - Body: Shipment {order_id, carrier, tracking_number, tracking_status, shipped_at,
+//     delivered_at, last_checked_at}
+//   - tracking_status: in_transit, delivered, returned
+//
+// Error Responses
+//   - 404 Not Found: order not found, not a party to it, or shipped without a tracking number
+func (h *OrderHandler) HandleGetShipment(w http.ResponseWriter, r *http.Request) {
+	userID := middleware.GetUserIDFromContext(r.Context())
+
+	shipment, err := h.shipmentSvc.GetShipment(r.Context(), userID, r.PathValue("orderId"))
+	if err != nil {
+		switch {
+		case errors.Is(err, repository.ErrOrderNotFound), errors.Is(err, service.ErrUnauthorized):
+			http.Error(w, "order not found", http.StatusNotFound)
+		case errors.Is(err, repository.ErrShipmentNotFound):
+			http.Error(w, err.Error(), http.StatusNotFound)
+		default:
+			log.Printf("get shipment error: %v", err)
+			http.Error(w, "internal server error", http.StatusInternalServerError)
+		}
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	if err := json.NewEncoder(w).Encode(shipment); err != nil {
+		log.Printf("encode shipment response error: %v", err)
+	}
+}
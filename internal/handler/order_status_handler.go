@@ -0,0 +1,224 @@
+package handler
+
+import (
+	"encoding/json"
+	"errors"
+	"io"
+	"log"
+	"net/http"
+	"uttc-hackathon-backend/internal/middleware"
+	"uttc-hackathon-backend/internal/models"
+	"uttc-hackathon-backend/internal/repository"
+	"uttc-hackathon-backend/internal/service"
+)
+
+// HandleShip marks a paid order as shipped. Seller only.
+//
+// Route
+//   - POST /orders/{orderId}/ship
+//
+// Required Headers
+//   - Authorization: Bearer <Firebase ID token>
+//
+// Request Body (optional)
+//   - carrier: string (yamato, japan_post, sagawa; required with tracking_number)
+//   - tracking_number: string (12 digits, hyphens allowed; required with carrier)
+//
+// The carrier must run the order's shipping method (yamato for nekopos and takkyubin,
+// japan_post for yu_packet and yu_pack). Tracked orders move to delivered automatically when
+// the carrier reports delivery.
+//
+// Success Response
+//   - 200 OK
+//   - Body: Order (status "shipped")
+//
+// Error Responses
+//   - 400 Bad Request: invalid carrier or tracking number, only one of them given, or the
+//     carrier does not match the shipping method
+//   - 409 Conflict: the tracking number is already used by another order
+//   - see transitionOrder for the rest
+func (h *OrderHandler) HandleShip(w http.ResponseWriter, r *http.Request) {
+	userID := middleware.GetUserIDFromContext(r.Context())
+
+	orderID := r.PathValue("orderId")
+	if orderID == "" {
+		http.Error(w, "missing order id", http.StatusBadRequest)
+		return
+	}
+
+	var req struct {
+		Carrier        models.Carrier `json:"carrier"`
+		TrackingNumber string         `json:"tracking_number"`
+	}
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil && !errors.Is(err, io.EOF) {
+		http.Error(w, "invalid request body", http.StatusBadRequest)
+		return
+	}
+
+	order, err := h.shipmentSvc.ShipOrder(r.Context(), userID, orderID, req.Carrier, req.TrackingNumber)
+	if err != nil {
+		switch {
+		case errors.Is(err, repository.ErrOrderNotFound), errors.Is(err, service.ErrUnauthorized):
+			http.Error(w, "order not found", http.StatusNotFound)
+		case errors.Is(err, service.ErrInvalidCarrier), errors.Is(err, service.ErrInvalidTrackingNumber),
+			errors.Is(err, service.ErrTrackingIncomplete), errors.Is(err, service.ErrCarrierMismatch):
+			http.Error(w, err.Error(), http.StatusBadRequest)
+		case errors.Is(err, service.ErrOrderActionForbidden):
+			http.Error(w, err.Error(), http.StatusForbidden)
+		case errors.Is(err, service.ErrInvalidOrderTransition), errors.Is(err, repository.ErrTrackingNumberUsed):
+			http.Error(w, err.Error(), http.StatusConflict)
+		default:
+			log.Printf("ship order error: %v", err)
+			http.Error(w, "internal server error", http.StatusInternalServerError)
+		}
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	if err := json.NewEncoder(w).Encode(order); err != nil {
+		log.Printf("encode ship order response error: %v", err)
+	}
+}
+
+// HandleDeliver marks a shipped order as delivered. Buyer or seller.
+//
+// Route
+//   - POST /orders/{orderId}/deliver
+//
+// Required Headers
+//   - Authorization: Bearer <Firebase ID token>
+//
+// Success Response
+//   - 200 OK
+//   - Body: Order (status "delivered")
+//
+// Error Responses: see transitionOrder
+func (h *OrderHandler) HandleDeliver(w http.ResponseWriter, r *http.Request) {
+	h.transitionOrder(w, r, service.OrderActionDeliver)
+}
+
+// HandleComplete completes a delivered order. Buyer only.
+//
+// Route
+//   - POST /orders/{orderId}/complete
+//
+// Required Headers
+//   - Authorization: Bearer <Firebase ID token>
+//
+// Success Response
+//   - 200 OK
+//   - Body: Order (status "completed")
+//
+// Error Responses: see transitionOrder
+func (h *OrderHandler) HandleComplete(w http.ResponseWriter, r *http.Request) {
+	h.transitionOrder(w, r, service.OrderActionComplete)
+}
+
+// HandleCancel cancels an order and puts the item back on sale when appropriate.
+//
+// Route
+//   - POST /orders/{orderId}/cancel
+//
+// Required Headers
+//   - Authorization: Bearer <Firebase ID token>
+//   - Content-Type: application/json
+//
+// Request Body
+//   - reason: string (required)
+//   - before shipping (buyer or seller): changed_mind, out_of_stock, buyer_unresponsive, other
+//   - after shipping (seller only): returned_to_sender, lost_in_transit
+//   - note: string (optional, max 500 characters)
+//
+// Stock is restored unless the reason is out_of_stock or lost_in_transit. A sold-out listing
+// goes back on sale; an unpublished or removed listing keeps its status. The part of the
+// payment not refunded yet goes back to the buyer as an order_cancelled refund
+// (see GET /orders/{orderId}/refunds).
+//
+// Success Response
+//   - 200 OK
+//   - Body: Order (status "cancelled", with cancelled_by, cancel_reason, cancel_note, cancelled_at)
+//
+// Error Responses
+//   - 400 Bad Request: invalid reason for the order's status, or note too long
+//   - 403 Forbidden: buyer cancelling after shipping
+//   - 404 Not Found: order not found or the user is not part of it
+//   - 409 Conflict: the order is already delivered, completed, cancelled or disputed
+func (h *OrderHandler) HandleCancel(w http.ResponseWriter, r *http.Request) {
+	userID := middleware.GetUserIDFromContext(r.Context())
+
+	orderID := r.PathValue("orderId")
+	if orderID == "" {
+		http.Error(w, "missing order id", http.StatusBadRequest)
+		return
+	}
+
+	var req struct {
+		Reason models.CancelReason `json:"reason"`
+		Note   string              `json:"note"`
+	}
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		http.Error(w, "invalid request body", http.StatusBadRequest)
+		return
+	}
+
+	order, err := h.svc.CancelOrder(r.Context(), userID, orderID, req.Reason, req.Note)
+	if err != nil {
+		switch {
+		case errors.Is(err, repository.ErrOrderNotFound), errors.Is(err, service.ErrUnauthorized):
+			http.Error(w, "order not found", http.StatusNotFound)
+		case errors.Is(err, service.ErrInvalidCancelReason), errors.Is(err, service.ErrCancelNoteLong):
+			http.Error(w, err.Error(), http.StatusBadRequest)
+		case errors.Is(err, service.ErrCancelAfterShipping), errors.Is(err, service.ErrOrderActionForbidden):
+			http.Error(w, err.Error(), http.StatusForbidden)
+		case errors.Is(err, service.ErrInvalidOrderTransition):
+			http.Error(w, err.Error(), http.StatusConflict)
+		default:
+			log.Printf("cancel order error: %v", err)
+			http.Error(w, "internal server error", http.StatusInternalServerError)
+		}
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	if err := json.NewEncoder(w).Encode(order); err != nil {
+		log.Printf("encode cancel order response error: %v", err)
+	}
+}
+
+// transitionOrder runs an order status change for the current user.
+//
+// Error Responses
+//   - 401 Unauthorized
+//   - 403 Forbidden: the user's role may not perform this action
+//   - 404 Not Found: order not found or the user is not part of it
+//   - 409 Conflict: the order is not in a status this action applies to, or it changed concurrently
+func (h *OrderHandler) transitionOrder(w http.ResponseWriter, r *http.Request, action service.OrderAction) {
+	userID := middleware.GetUserIDFromContext(r.Context())
+
+	orderID := r.PathValue("orderId")
+	if orderID == "" {
+		http.Error(w, "missing order id", http.StatusBadRequest)
+		return
+	}
+
+	order, err := h.svc.TransitionOrder(r.Context(), userID, orderID, action)
+	if err != nil {
+		switch {
+		case errors.Is(err, repository.ErrOrderNotFound), errors.Is(err, service.ErrUnauthorized):
+			http.Error(w, "order not found", http.StatusNotFound)
+		case errors.Is(err, service.ErrOrderActionForbidden):
+			http.Error(w, err.Error(), http.StatusForbidden)
+		case errors.Is(err, service.ErrInvalidOrderTransition), errors.Is(err, repository.ErrOrderVersionConflict):
+			http.Error(w, err.Error(), http.StatusConflict)
+		default:
+			log.Printf("%s order error: %v", action, err)
+			http.Error(w, "internal server error", http.StatusInternalServerError)
+		}
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	if err := json.NewEncoder(w).Encode(order); err != nil {
+		log.Printf("encode %s order response error: %v", action, err)
+	}
+}
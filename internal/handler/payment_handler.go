@@ -0,0 +1,65 @@
+package handler
+
+import (
+	"errors"
+	"io"
+	"log"
+	"net/http"
+	"uttc-hackathon-backend/internal/service"
+)
+
+// maxWebhookBody bounds the size of a payment provider callback.
+const maxWebhookBody = 64 << 10
+
+type PaymentHandler struct {
+	orderSvc *service.OrderService
+}
+
+func NewPaymentHandler(orderSvc *service.OrderService) *PaymentHandler {
+	return &PaymentHandler{orderSvc: orderSvc}
+}
+
+// HandleWebhook receives payment provider callbacks.
+//
+// Route
+//   - POST /webhooks/payments
+//
+// Required Headers
+//   - X-Payment-Signature: signature of the raw body (hex HMAC-SHA256 with the webhook secret
+//     for the development provider)
+//
+// Request Body
+//   - id: string
+//   - type: string (payment.authorized, payment.failed)
+//   - intent_id: string
+//   - amount: int
+//
+// An authorized payment moves the order from pending_payment to paid and captures it; a
+// failed payment cancels the order and puts its stock back on sale.
+//
+// Success Response
+//   - 204 No Content
+//
+// Error Responses
+//   - 400 Bad Request: unreadable body or invalid signature
+//   - 500 Internal Server Error: the provider should retry
+func (h *PaymentHandler) HandleWebhook(w http.ResponseWriter, r *http.Request) {
+	payload, err := io.ReadAll(http.MaxBytesReader(w, r.Body, maxWebhookBody))
+	if err != nil {
+		http.Error(w, "invalid request body", http.StatusBadRequest)
+		return
+	}
+
+	err = h.orderSvc.HandlePaymentWebhook(r.Context(), payload, r.Header.Get("X-Payment-Signature"))
+	if err != nil {
+		if errors.Is(err, service.ErrInvalidPaymentWebhook) {
+			http.Error(w, "invalid signature", http.StatusBadRequest)
+			return
+		}
+		log.Printf("payment webhook error: %v", err)
+		http.Error(w, "internal server error", http.StatusInternalServerError)
+		return
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+}
@@ -0,0 +1,38 @@
+package handler
+
+import (
+	"errors"
+	"log"
+	"net/http"
+	"uttc-hackathon-backend/internal/repository"
+	"uttc-hackathon-backend/internal/service"
+)
+
+type RefundHandler struct {
+	svc *service.RefundService
+}
+
+func NewRefundHandler(svc *service.RefundService) *RefundHandler {
+	return &RefundHandler{svc: svc}
+}
+
+// writeRefundError maps refund errors to responses; op names the request in the log.
+func writeRefundError(w http.ResponseWriter, err error, op string) {
+	switch {
+	case errors.Is(err, service.ErrInvalidRefundReason),
+		errors.Is(err, service.ErrRefundAmountRequired),
+		errors.Is(err, service.ErrRefundExceedsTotal),
+		errors.Is(err, service.ErrRefundQuantityExceeded),
+		errors.Is(err, service.ErrRefundNoteLong):
+		http.Error(w, err.Error(), http.StatusBadRequest)
+	case errors.Is(err, service.ErrOrderActionForbidden):
+		http.Error(w, err.Error(), http.StatusForbidden)
+	case errors.Is(err, repository.ErrOrderNotFound), errors.Is(err, service.ErrUnauthorized):
+		http.Error(w, "order not found", http.StatusNotFound)
+	case errors.Is(err, service.ErrOrderNotRefundable):
+		http.Error(w, err.Error(), http.StatusConflict)
+	default:
+		log.Printf("%s error: %v", op, err)
+		http.Error(w, "internal server error", http.StatusInternalServerError)
+	}
+}
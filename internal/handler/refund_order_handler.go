@@ -0,0 +1,130 @@
+package handler
+
+import (
+	"encoding/json"
+	"log"
+	"net/http"
+	"uttc-hackathon-backend/internal/middleware"
+	"uttc-hackathon-backend/internal/models"
+)
+
+type refundRequest struct {
+	Amount   int                 `json:"amount"`
+	Quantity int                 `json:"quantity"`
+	Reason   models.RefundReason `json:"reason"`
+	Note     string              `json:"note"`
+}
+
+// HandleCreate refunds part or all of an order to the buyer. Seller only.
+//
+// Route
+//   - POST /orders/{orderId}/refunds
+//
+// Required Headers
+//   - Authorization: Bearer <Firebase ID token>
+//   - Content-Type: application/json
+//   - Idempotency-Key: string (optional, recommended)
+//
+// Request Body
+//   - amount: int (optional, defaults to the item price times quantity)
+//   - quantity: int (optional, items refunded; at least one of amount and quantity is required)
+//   - reason: string (required, damaged, not_as_described, missing_item, goodwill, other)
+//   - note: string (optional, max 1000 characters)
+//
+// The platform fee is reduced in proportion to the refund and the rest comes out of the
+// seller's payout. All refunds together cannot exceed the order total. The refund is
+// recorded first and then sent to the payment provider; it stays "pending" until the
+// provider confirms it, which is retried in the background.
+//
+// Success Response
+//   - 201 Created
+//   - Body: Refund (status "completed", or "pending" if the provider has not confirmed it yet)
+//
+// Error Responses
+//   - 400 Bad Request: invalid reason, amount, quantity or note
+//   - 403 Forbidden: the buyer cannot refund
+//   - 404 Not Found: order not found or the user is not part of it
+//   - 409 Conflict: the order is unpaid or cancelled
+func (h *RefundHandler) HandleCreate(w http.ResponseWriter, r *http.Request) {
+	userID := middleware.GetUserIDFromContext(r.Context())
+	h.create(w, r, userID, false)
+}
+
+// HandleAdminCreate refunds part or all of any order to the buyer, with the same body and
+// responses as HandleCreate.
+//
+// Route
+//   - POST /admin/orders/{orderId}/refunds
+//
+// Required Headers
+//   - Authorization: Bearer <Firebase ID token of an admin>
+//   - Content-Type: application/json
+func (h *RefundHandler) HandleAdminCreate(w http.ResponseWriter, r *http.Request) {
+	adminID := middleware.GetUserIDFromContext(r.Context())
+	h.create(w, r, adminID, true)
+}
+
+func (h *RefundHandler) create(w http.ResponseWriter, r *http.Request, userID string, admin bool) {
+	orderID := r.PathValue("orderId")
+	if orderID == "" {
+		http.Error(w, "missing order id", http.StatusBadRequest)
+		return
+	}
+
+	var req refundRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		http.Error(w, "invalid request body", http.StatusBadRequest)
+		return
+	}
+
+	refund := h.svc.RefundOrder
+	if admin {
+		refund = h.svc.AdminRefundOrder
+	}
+	rf, err := refund(r.Context(), userID, orderID, req.Amount, req.Quantity, req.Reason, req.Note)
+	if err != nil {
+		writeRefundError(w, err, "refund order")
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusCreated)
+	if err := json.NewEncoder(w).Encode(rf); err != nil {
+		log.Printf("encode refund order response error: %v", err)
+	}
+}
+
+// HandleList returns the order's refunds to the buyer or seller, oldest first.
+//
+// Route
+//   - GET /orders/{orderId}/refunds
+//
+// Required Headers
+//   - Authorization: Bearer <Firebase ID token>
+//
+// Success Response
+//   - 200 OK
+//   - Body: []Refund
+//
+// Error Responses
+//   - 404 Not Found: order not found or the user is not part of it
+func (h *RefundHandler) HandleList(w http.ResponseWriter, r *http.Request) {
+	userID := middleware.GetUserIDFromContext(r.Context())
+
+	orderID := r.PathValue("orderId")
+	if orderID == "" {
+		http.Error(w, "missing order id", http.StatusBadRequest)
+		return
+	}
+
+	refunds, err := h.svc.GetRefunds(r.Context(), userID, orderID)
+	if err != nil {
+		writeRefundError(w, err, "get refunds")
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	if err := json.NewEncoder(w).Encode(refunds); err != nil {
+		log.Printf("encode get refunds response error: %v", err)
+	}
+}
@@ -0,0 +1,35 @@
+package handler
+
+import (
+	"errors"
+	"log"
+	"net/http"
+	"uttc-hackathon-backend/internal/repository"
+	"uttc-hackathon-backend/internal/service"
+)
+
+type ReviewHandler struct {
+	svc *service.ReviewService
+}
+
+func NewReviewHandler(svc *service.ReviewService) *ReviewHandler {
+	return &ReviewHandler{svc: svc}
+}
+
+// writeReviewError maps review errors to responses; op names the request in the log.
+func writeReviewError(w http.ResponseWriter, err error, op string) {
+	switch {
+	case errors.Is(err, service.ErrInvalidReviewRating),
+		errors.Is(err, service.ErrReviewCommentLong):
+		http.Error(w, err.Error(), http.StatusBadRequest)
+	case errors.Is(err, repository.ErrOrderNotFound), errors.Is(err, service.ErrUnauthorized):
+		http.Error(w, "order not found", http.StatusNotFound)
+	case errors.Is(err, repository.ErrAlreadyReviewed),
+		errors.Is(err, service.ErrOrderNotReviewable),
+		errors.Is(err, service.ErrReviewWindowClosed):
+		http.Error(w, err.Error(), http.StatusConflict)
+	default:
+		log.Printf("%s error: %v", op, err)
+		http.Error(w, "internal server error", http.StatusInternalServerError)
+	}
+}
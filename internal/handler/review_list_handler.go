@@ -0,0 +1,53 @@
+package handler
+
+import (
+	"encoding/json"
+	"log"
+	"net/http"
+	"strconv"
+)
+
+// HandleList returns the visible reviews a user received, newest first (public).
+//
+// Route
+//   - GET /users/{userId}/reviews
+//
+// Query Parameters
+//   - limit: int (optional, default 20, max 100)
+//   - offset: int (optional, default 0)
+//
+// Success Response
+//   - 200 OK
+//   - Body: []Review (with reviewer_name and reviewer_avatar_url)
+//
+// Error Responses
+//   - 400 Bad Request: missing user id
+//   - 500 Internal Server Error
+func (h *ReviewHandler) HandleList(w http.ResponseWriter, r *http.Request) {
+	userID := r.PathValue("userId")
+	if userID == "" {
+		http.Error(w, "missing user id", http.StatusBadRequest)
+		return
+	}
+
+	q := r.URL.Query()
+	limit := 0
+	if v, err := strconv.Atoi(q.Get("limit")); err == nil {
+		limit = v
+	}
+	offset := 0
+	if v, err := strconv.Atoi(q.Get("offset")); err == nil {
+		offset = v
+	}
+
+	reviews, err := h.svc.GetReviews(r.Context(), userID, limit, offset)
+	if err != nil {
+		writeReviewError(w, err, "list reviews")
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	if err := json.NewEncoder(w).Encode(reviews); err != nil {
+		log.Printf("encode reviews response error: %v", err)
+	}
+}
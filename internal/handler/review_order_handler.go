@@ -0,0 +1,65 @@
+package handler
+
+import (
+	"encoding/json"
+	"log"
+	"net/http"
+	"uttc-hackathon-backend/internal/middleware"
+	"uttc-hackathon-backend/internal/models"
+)
+
+// HandleSubmit rates the other side of a completed order. Buyer and seller review each other
+// once per order.
+//
+// Route
+//   - POST /orders/{orderId}/review
+//
+// Required Headers
+//   - Authorization: Bearer <Firebase ID token>
+//   - Content-Type: application/json
+//
+// Request Body
+//   - rating: string (required, good, normal, bad)
+//   - comment: string (optional, max 1000 characters)
+//
+// The review is hidden until the other side has reviewed too, or until 14 days after the
+// order was completed, when reviewing closes.
+//
+// Success Response
+//   - 201 Created
+//   - Body: Review (visible_at tells when it will be shown)
+//
+// Error Responses
+//   - 400 Bad Request: invalid rating or long comment
+//   - 404 Not Found: order not found or the user is not part of it
+//   - 409 Conflict: the order is not completed, the review period ended, or already reviewed
+func (h *ReviewHandler) HandleSubmit(w http.ResponseWriter, r *http.Request) {
+	userID := middleware.GetUserIDFromContext(r.Context())
+
+	orderID := r.PathValue("orderId")
+	if orderID == "" {
+		http.Error(w, "missing order id", http.StatusBadRequest)
+		return
+	}
+
+	var req struct {
+		Rating  models.ReviewRating `json:"rating"`
+		Comment string              `json:"comment"`
+	}
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		http.Error(w, "invalid request body", http.StatusBadRequest)
+		return
+	}
+
+	review, err := h.svc.SubmitReview(r.Context(), userID, orderID, req.Rating, req.Comment)
+	if err != nil {
+		writeReviewError(w, err, "submit review")
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusCreated)
+	if err := json.NewEncoder(w).Encode(review); err != nil {
+		log.Printf("encode submit review response error: %v", err)
+	}
+}
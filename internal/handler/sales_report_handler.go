@@ -0,0 +1,90 @@
+package handler
+
+import (
+	"encoding/json"
+	"errors"
+	"log"
+	"net/http"
+	"time"
+	"uttc-hackathon-backend/internal/middleware"
+	"uttc-hackathon-backend/internal/models"
+	"uttc-hackathon-backend/internal/service"
+)
+
+type SalesReportHandler struct {
+	svc *service.SalesReportService
+}
+
+func NewSalesReportHandler(svc *service.SalesReportService) *SalesReportHandler {
+	return &SalesReportHandler{svc: svc}
+}
+
+// HandleGetMySalesReport returns the current user's sales over time as a seller.
+//
+// Route
+//   - GET /me/reports/sales
+//
+// Required Headers
+//   - Authorization: Bearer <Firebase ID token>
+//
+// Query Parameters
+//   - granularity: "day" | "week" | "month" (optional, default month; weeks start on Monday)
+//   - from: YYYY-MM-DD (optional, JST; default 30 days, 12 weeks or 12 months before to)
+//   - to: YYYY-MM-DD (optional, JST, inclusive; default today)
+//
+// Orders count on the day they were placed once paid, unless cancelled. Fees and payouts
+// are after refunds. At most 366 periods are returned.
+//
+// Success Response
+//   - 200 OK
+//   - Body: SalesReport {granularity, from, to, totals, buckets, top_listings}
+//   - totals and each bucket: {orders, quantity, gross_sales, refunds, platform_fees, net_payouts}
+//   - buckets: [{start, ...totals}], oldest first, including periods without sales
+//   - top_listings: [{listing_id, listing_title, listing_main_image, ...totals}], top 5
+//
+// Error Responses
+//   - 400 Bad Request: invalid granularity or dates, or too many periods
+//   - 401 Unauthorized
+//   - 500 Internal Server Error
+func (h *SalesReportHandler) HandleGetMySalesReport(w http.ResponseWriter, r *http.Request) {
+	userID := middleware.GetUserIDFromContext(r.Context())
+
+	q := r.URL.Query()
+	var from, to time.Time
+	if v := q.Get("from"); v != "" {
+		day, err := time.ParseInLocation(time.DateOnly, v, jst)
+		if err != nil {
+			http.Error(w, "from must be a date (YYYY-MM-DD)", http.StatusBadRequest)
+			return
+		}
+		from = day
+	}
+	if v := q.Get("to"); v != "" {
+		day, err := time.ParseInLocation(time.DateOnly, v, jst)
+		if err != nil {
+			http.Error(w, "to must be a date (YYYY-MM-DD)", http.StatusBadRequest)
+			return
+		}
+		// The whole day is included
+		to = day.AddDate(0, 0, 1)
+	}
+
+	report, err := h.svc.GetSalesReport(r.Context(), userID, models.SalesGranularity(q.Get("granularity")), from, to)
+	if err != nil {
+		switch {
+		case errors.Is(err, service.ErrInvalidGranularity),
+			errors.Is(err, service.ErrInvalidDateRange),
+			errors.Is(err, service.ErrSalesRangeTooLong):
+			http.Error(w, err.Error(), http.StatusBadRequest)
+		default:
+			log.Printf("get sales report error: %v", err)
+			http.Error(w, "internal server error", http.StatusInternalServerError)
+		}
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	if err := json.NewEncoder(w).Encode(report); err != nil {
+		log.Printf("encode sales report response error: %v", err)
+	}
+}
@@ -0,0 +1,79 @@
+package handler
+
+import (
+	"encoding/json"
+	"errors"
+	"log"
+	"net/http"
+	"uttc-hackathon-backend/internal/middleware"
+	"uttc-hackathon-backend/internal/models"
+	"uttc-hackathon-backend/internal/service"
+)
+
+// HandleCreate saves a search the user wants to be notified about.
+//
+// Route
+//   - POST /saved-searches
+//
+// Required Headers
+//   - Authorization: Bearer <Firebase ID token>
+//   - Content-Type: application/json
+//
+// Request Body
+//   - query: string (required, max 100 characters; every word must appear in the listing)
+//   - min_price: int (optional)
+//   - max_price: int (optional)
+//   - condition: string (optional, new, excellent, good, not_good, bad)
+//
+// When a matching listing goes live the user gets a saved_search_digest notification.
+// Digests are sent at most once an hour; matches in between are bundled into the next one.
+//
+// Success Response
+//   - 201 Created
+//   - Body: SavedSearch
+//
+// Error Responses
+//   - 400 Bad Request: empty or too long query, invalid price range or condition
+//   - 409 Conflict: saved search limit (20) reached
+func (h *SavedSearchHandler) HandleCreate(w http.ResponseWriter, r *http.Request) {
+	userID := middleware.GetUserIDFromContext(r.Context())
+
+	var req struct {
+		Query     string               `json:"query"`
+		MinPrice  int                  `json:"min_price"`
+		MaxPrice  int                  `json:"max_price"`
+		Condition models.ItemCondition `json:"condition"`
+	}
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		http.Error(w, "invalid request body", http.StatusBadRequest)
+		return
+	}
+
+	ss, err := h.svc.CreateSavedSearch(r.Context(), userID, &models.SavedSearch{
+		Query:     req.Query,
+		MinPrice:  req.MinPrice,
+		MaxPrice:  req.MaxPrice,
+		Condition: req.Condition,
+	})
+	if err != nil {
+		switch {
+		case errors.Is(err, service.ErrSavedSearchQueryRequired),
+			errors.Is(err, service.ErrSavedSearchQueryLong),
+			errors.Is(err, service.ErrSavedSearchPriceRange),
+			errors.Is(err, service.ErrInvalidItemCondition):
+			http.Error(w, err.Error(), http.StatusBadRequest)
+		case errors.Is(err, service.ErrTooManySavedSearches):
+			http.Error(w, err.Error(), http.StatusConflict)
+		default:
+			log.Printf("create saved search error: %v", err)
+			http.Error(w, "internal server error", http.StatusInternalServerError)
+		}
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusCreated)
+	if err := json.NewEncoder(w).Encode(ss); err != nil {
+		log.Printf("encode saved search response error: %v", err)
+	}
+}
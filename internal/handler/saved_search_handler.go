@@ -0,0 +1,23 @@
+package handler
+
+import (
+	"context"
+	"log"
+	"uttc-hackathon-backend/internal/service"
+)
+
+type SavedSearchHandler struct {
+	svc *service.SavedSearchService
+}
+
+func NewSavedSearchHandler(svc *service.SavedSearchService) *SavedSearchHandler {
+	return &SavedSearchHandler{svc: svc}
+}
+
+// matchSavedSearches notifies saved-search owners about a newly active listing. It is meant
+// to run in its own goroutine after the request that activated the listing has finished.
+func matchSavedSearches(ctx context.Context, svc *service.SavedSearchService, listingID string) {
+	if err := svc.MatchListing(ctx, listingID); err != nil {
+		log.Printf("match saved searches for listing %s: %v", listingID, err)
+	}
+}
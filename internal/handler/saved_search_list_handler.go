@@ -0,0 +1,66 @@
+package handler
+
+import (
+	"encoding/json"
+	"errors"
+	"log"
+	"net/http"
+	"uttc-hackathon-backend/internal/middleware"
+	"uttc-hackathon-backend/internal/repository"
+)
+
+// HandleGetMine returns the current user's saved searches, newest first.
+//
+// Route
+//   - GET /saved-searches
+//
+// Required Headers
+//   - Authorization: Bearer <Firebase ID token>
+//
+// Success Response
+//   - 200 OK
+//   - Body: []SavedSearch
+func (h *SavedSearchHandler) HandleGetMine(w http.ResponseWriter, r *http.Request) {
+	userID := middleware.GetUserIDFromContext(r.Context())
+
+	searches, err := h.svc.GetSavedSearches(r.Context(), userID)
+	if err != nil {
+		log.Printf("get saved searches error: %v", err)
+		http.Error(w, "internal server error", http.StatusInternalServerError)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	if err := json.NewEncoder(w).Encode(searches); err != nil {
+		log.Printf("encode saved searches response error: %v", err)
+	}
+}
+
+// HandleDelete deletes one of the current user's saved searches.
+//
+// Route
+//   - DELETE /saved-searches/{id}
+//
+// Required Headers
+//   - Authorization: Bearer <Firebase ID token>
+//
+// Success Response
+//   - 204 No Content
+//
+// Error Responses
+//   - 404 Not Found: no such saved search for this user
+func (h *SavedSearchHandler) HandleDelete(w http.ResponseWriter, r *http.Request) {
+	userID := middleware.GetUserIDFromContext(r.Context())
+
+	if err := h.svc.DeleteSavedSearch(r.Context(), userID, r.PathValue("id")); err != nil {
+		if errors.Is(err, repository.ErrSavedSearchNotFound) {
+			http.Error(w, "saved search not found", http.StatusNotFound)
+			return
+		}
+		log.Printf("delete saved search error: %v", err)
+		http.Error(w, "internal server error", http.StatusInternalServerError)
+		return
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+}
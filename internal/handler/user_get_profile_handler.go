@@ -1,10 +1,10 @@
 package handler
 
 import (
-	"encoding/json"
 	"errors"
 	"log"
 	"net/http"
+	"time"
 	"uttc-hackathon-backend/internal/service"
 )
 
@@ -15,7 +15,9 @@ import (
 // Success Response:
 //   - 200 OK
 //   - Content-Type: application/json
-//   - Body: UserProfile (id, name, avatar_url)
+//   - Body: UserProfile (id, name, avatar_url, ratings {good, normal, bad})
+//   - ETag (hash of the body), Cache-Control
+//   - 304 Not Modified: If-None-Match matches the current profile
 //
 // Error Responses:
 //   - 400 Bad Request: missing user id
@@ -39,8 +41,13 @@ func (h *UserHandler) HandleGetProfile(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	w.Header().Set("Content-Type", "application/json")
-	if err := json.NewEncoder(w).Encode(profile); err != nil {
+	profile.Ratings, err = h.reviewSvc.GetRatingSummary(r.Context(), id)
+	if err != nil {
+		log.Printf("failed to get user ratings: %v", err)
 		http.Error(w, "internal server error", http.StatusInternalServerError)
+		return
 	}
+
+	// users has no updated_at column, so only the ETag is available for revalidation.
+	writeCachedJSON(w, r, profile, time.Time{}, cachePolicyProfile)
 }
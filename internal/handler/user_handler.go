@@ -3,9 +3,10 @@ package handler
 import "uttc-hackathon-backend/internal/service"
 
 type UserHandler struct {
-	svc *service.UserService
+	svc       *service.UserService
+	reviewSvc *service.ReviewService
 }
 
-func NewUserHandler(svc *service.UserService) *UserHandler {
-	return &UserHandler{svc: svc}
+func NewUserHandler(svc *service.UserService, reviewSvc *service.ReviewService) *UserHandler {
+	return &UserHandler{svc: svc, reviewSvc: reviewSvc}
 }
@@ -0,0 +1,59 @@
+package handler
+
+import (
+	"encoding/json"
+	"errors"
+	"log"
+	"net/http"
+	"uttc-hackathon-backend/internal/middleware"
+	"uttc-hackathon-backend/internal/service"
+)
+
+// HandlePutInvoiceRegistration registers the current user as a qualified invoice issuer
+// (適格請求書発行事業者), or clears the registration.
+//
+// Route
+//   - PUT /me/invoice-registration
+//
+// Required Headers
+//   - Authorization: Bearer <Firebase ID token>
+//   - Content-Type: application/json
+//
+// Request Body
+//   - invoice_registration_number: string ("T" + 13 digits with a valid check digit; empty to clear)
+//
+// Orders placed after the change carry the new number; earlier invoices keep the old one.
+//
+// Success Response
+//   - 200 OK
+//   - Body: User
+//
+// Error Responses
+//   - 400 Bad Request: invalid registration number
+func (h *UserHandler) HandlePutInvoiceRegistration(w http.ResponseWriter, r *http.Request) {
+	userID := middleware.GetUserIDFromContext(r.Context())
+
+	var req struct {
+		InvoiceRegistrationNumber string `json:"invoice_registration_number"`
+	}
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		http.Error(w, "invalid request body", http.StatusBadRequest)
+		return
+	}
+
+	user, err := h.svc.SetInvoiceRegistrationNumber(r.Context(), userID, req.InvoiceRegistrationNumber)
+	if err != nil {
+		if errors.Is(err, service.ErrInvalidInvoiceRegistrationNumber) {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+		log.Printf("set invoice registration error: %v", err)
+		http.Error(w, "internal server error", http.StatusInternalServerError)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	if err := json.NewEncoder(w).Encode(user); err != nil {
+		log.Printf("encode invoice registration response error: %v", err)
+	}
+}
@@ -26,7 +26,8 @@ import (
 //     "id": string,
 //     "name": string,
 //     "email": string,
-//     "avatar_url": string
+//     "avatar_url": string,
+//     "invoice_registration_number": string (only when registered)
 //     }
 //
 // Errors
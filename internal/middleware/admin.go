@@ -0,0 +1,39 @@
+package middleware
+
+import (
+	"context"
+	"net/http"
+
+	"github.com/google/martian/v3/log"
+)
+
+type AdminProvider interface {
+	IsAdmin(ctx context.Context, userID string) (bool, error)
+}
+
+// AdminMiddleware rejects requests from users without the admin role.
+// It must be applied inside AuthMiddleware so the user ID is in the context.
+func AdminMiddleware(provider AdminProvider) func(http.Handler) http.Handler {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			userID := GetUserIDFromContext(r.Context())
+			if userID == "" {
+				http.Error(w, "unauthorized", http.StatusUnauthorized)
+				return
+			}
+
+			ok, err := provider.IsAdmin(r.Context(), userID)
+			if err != nil {
+				log.Errorf("admin check error: %v", err)
+				http.Error(w, "internal server error", http.StatusInternalServerError)
+				return
+			}
+			if !ok {
+				http.Error(w, "forbidden", http.StatusForbidden)
+				return
+			}
+
+			next.ServeHTTP(w, r)
+		})
+	}
+}
@@ -10,6 +10,7 @@ import (
 
 type UserProvider interface {
 	VerifyToken(ctx context.Context, idToken string) (string, error)
+	IsSuspended(ctx context.Context, userID string) (bool, error)
 }
 
 type ctxKey string
@@ -38,6 +39,18 @@ func AuthMiddleware(provider UserProvider) func(http.Handler) http.Handler {
 				return
 			}
 
+			// Suspended users are rejected even with a valid token
+			suspended, err := provider.IsSuspended(r.Context(), userID)
+			if err != nil {
+				log.Errorf("suspension check error: %v", err)
+				http.Error(w, "internal server error", http.StatusInternalServerError)
+				return
+			}
+			if suspended {
+				http.Error(w, "account suspended", http.StatusForbidden)
+				return
+			}
+
 			// Store userID in context
 			ctx := context.WithValue(r.Context(), userIDCtxKey, userID)
 			next.ServeHTTP(w, r.WithContext(ctx))
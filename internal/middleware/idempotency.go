@@ -0,0 +1,124 @@
+package middleware
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"io"
+	"net/http"
+	"uttc-hackathon-backend/internal/models"
+	"uttc-hackathon-backend/internal/service"
+
+	"github.com/google/martian/v3/log"
+)
+
+// maxIdempotentBody bounds the request bodies buffered for fingerprinting.
+const maxIdempotentBody = 1 << 20
+
+type IdempotencyProvider interface {
+	Begin(ctx context.Context, userID, key, fingerprint string) (*models.IdempotencyRecord, error)
+	Finish(ctx context.Context, userID, key string, status int, contentType string, body []byte) error
+	Abandon(ctx context.Context, userID, key string) error
+}
+
+// IdempotencyMiddleware makes requests carrying an Idempotency-Key header safe to retry.
+// The first request with a key runs and its response is stored; retries with the same body
+// get the stored response back with Idempotent-Replayed: true. A retry while the first
+// request is still running gets 409, and reusing the key for a different body gets 422.
+// Requests without the header pass through.
+//
+// It must be applied inside AuthMiddleware, since keys are scoped per user.
+func IdempotencyMiddleware(provider IdempotencyProvider) func(http.Handler) http.Handler {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			key := r.Header.Get("Idempotency-Key")
+			if key == "" {
+				next.ServeHTTP(w, r)
+				return
+			}
+			userID := GetUserIDFromContext(r.Context())
+			if userID == "" {
+				http.Error(w, "unauthorized", http.StatusUnauthorized)
+				return
+			}
+
+			body, err := io.ReadAll(http.MaxBytesReader(w, r.Body, maxIdempotentBody))
+			if err != nil {
+				http.Error(w, "request body too large", http.StatusRequestEntityTooLarge)
+				return
+			}
+			r.Body = io.NopCloser(bytes.NewReader(body))
+
+			fingerprint := service.RequestFingerprint(r.Method, r.URL.Path, body)
+			rec, err := provider.Begin(r.Context(), userID, key, fingerprint)
+			if err != nil {
+				switch {
+				case errors.Is(err, service.ErrIdempotencyKeyInvalid):
+					http.Error(w, err.Error(), http.StatusBadRequest)
+				case errors.Is(err, service.ErrIdempotencyKeyInFlight):
+					http.Error(w, err.Error(), http.StatusConflict)
+				case errors.Is(err, service.ErrIdempotencyKeyReused):
+					http.Error(w, err.Error(), http.StatusUnprocessableEntity)
+				default:
+					log.Errorf("idempotency key error: %v", err)
+					http.Error(w, "internal server error", http.StatusInternalServerError)
+				}
+				return
+			}
+
+			if rec != nil {
+				if rec.ResponseContentType != "" {
+					w.Header().Set("Content-Type", rec.ResponseContentType)
+				}
+				w.Header().Set("Idempotent-Replayed", "true")
+				w.WriteHeader(rec.ResponseStatus)
+				w.Write(rec.ResponseBody)
+				return
+			}
+
+			rw := &recordingResponseWriter{ResponseWriter: w}
+			finished := false
+			defer func() {
+				// The handler panicked; free the key so the client can retry
+				if !finished {
+					if err := provider.Abandon(context.WithoutCancel(r.Context()), userID, key); err != nil {
+						log.Errorf("abandon idempotency key error: %v", err)
+					}
+				}
+			}()
+
+			next.ServeHTTP(rw, r)
+
+			finished = true
+			if rw.status == 0 {
+				rw.status = http.StatusOK
+			}
+			err = provider.Finish(context.WithoutCancel(r.Context()), userID, key, rw.status, rw.Header().Get("Content-Type"), rw.body.Bytes())
+			if err != nil {
+				log.Errorf("store idempotent response error: %v", err)
+			}
+		})
+	}
+}
+
+// recordingResponseWriter passes the response through and keeps a copy of it.
+type recordingResponseWriter struct {
+	http.ResponseWriter
+	status int
+	body   bytes.Buffer
+}
+
+func (rw *recordingResponseWriter) WriteHeader(status int) {
+	if rw.status == 0 {
+		rw.status = status
+	}
+	rw.ResponseWriter.WriteHeader(status)
+}
+
+func (rw *recordingResponseWriter) Write(b []byte) (int, error) {
+	if rw.status == 0 {
+		rw.WriteHeader(http.StatusOK)
+	}
+	rw.body.Write(b)
+	return rw.ResponseWriter.Write(b)
+}
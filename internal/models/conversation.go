@@ -1,6 +1,10 @@
 package models
 
+// Conversation is a thread with one other user: their direct messages, or the messages about
+// one order or one listing.
 type Conversation struct {
-	Message *Message     `json:"message"`
-	User    *UserProfile `json:"user"`
+	Message   *Message     `json:"message"` // the latest
+	User      *UserProfile `json:"user"`
+	OrderID   string       `json:"order_id,omitempty"`
+	ListingID string       `json:"listing_id,omitempty"`
 }
@@ -0,0 +1,31 @@
+package models
+
+import "time"
+
+type SitemapKind string
+
+const (
+	SitemapKindListings SitemapKind = "listings"
+	SitemapKindUsers    SitemapKind = "users"
+)
+
+// SitemapPage is one child sitemap referenced from the sitemap index.
+// LastModified is zero when the pages' rows have no update time (users).
+type SitemapPage struct {
+	Kind         SitemapKind
+	Page         int
+	LastModified time.Time
+}
+
+// SitemapEntry is one URL in a child sitemap.
+type SitemapEntry struct {
+	ID           string
+	LastModified time.Time
+}
+
+// ListingFeedFilter narrows the syndication feed. Empty fields do not filter.
+type ListingFeedFilter struct {
+	CategoryID string
+	SellerID   string
+	Limit      int
+}
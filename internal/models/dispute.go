@@ -0,0 +1,59 @@
+package models
+
+import "time"
+
+type DisputeReason string
+type DisputeStatus string
+type DisputeOutcome string
+type DisputeEventType string
+
+const (
+	DisputeReasonNotReceived    DisputeReason = "not_received"
+	DisputeReasonNotAsDescribed DisputeReason = "not_as_described"
+	DisputeReasonDamaged        DisputeReason = "damaged"
+	DisputeReasonCounterfeit    DisputeReason = "counterfeit"
+	DisputeReasonOther          DisputeReason = "other"
+
+	DisputeStatusAwaitingSeller DisputeStatus = "awaiting_seller"
+	DisputeStatusAwaitingAdmin  DisputeStatus = "awaiting_admin"
+	DisputeStatusResolved       DisputeStatus = "resolved"
+
+	DisputeOutcomeFullRefund      DisputeOutcome = "full_refund"
+	DisputeOutcomePartialRefund   DisputeOutcome = "partial_refund"
+	DisputeOutcomeReleaseToSeller DisputeOutcome = "release_to_seller"
+
+	DisputeEventOpened          DisputeEventType = "opened"
+	DisputeEventSellerResponded DisputeEventType = "seller_responded"
+	DisputeEventEscalated       DisputeEventType = "escalated"
+	DisputeEventResolved        DisputeEventType = "resolved"
+)
+
+type Dispute struct {
+	ID             string         `json:"id"`
+	OrderID        string         `json:"order_id"`
+	BuyerID        string         `json:"buyer_id"`
+	SellerID       string         `json:"seller_id"`
+	Reason         DisputeReason  `json:"reason"`
+	Status         DisputeStatus  `json:"status"`
+	Escalated      bool           `json:"escalated"`            // the seller missed RespondBy
+	RespondBy      *time.Time     `json:"respond_by,omitempty"` // seller deadline while awaiting_seller
+	Outcome        DisputeOutcome `json:"outcome,omitempty"`
+	RefundAmount   int            `json:"refund_amount,omitempty"`
+	ResolutionNote string         `json:"resolution_note,omitempty"`
+	ResolvedBy     string         `json:"resolved_by,omitempty"`
+	ResolvedAt     *time.Time     `json:"resolved_at,omitempty"`
+	Events         []DisputeEvent `json:"events,omitempty"`
+	CreatedAt      time.Time      `json:"created_at"`
+	UpdatedAt      time.Time      `json:"updated_at"`
+}
+
+// DisputeEvent is one step in a dispute's timeline. ActorID is empty for automatic steps.
+type DisputeEvent struct {
+	ID        string           `json:"id"`
+	DisputeID string           `json:"dispute_id"`
+	ActorID   string           `json:"actor_id,omitempty"`
+	Type      DisputeEventType `json:"type"`
+	Message   string           `json:"message"`
+	Images    []string         `json:"images"`
+	CreatedAt time.Time        `json:"created_at"`
+}
@@ -0,0 +1,38 @@
+package models
+
+import "time"
+
+type SellerTier string
+type FeeRounding string
+
+const (
+	SellerTierStandard SellerTier = "standard"
+	SellerTierSilver   SellerTier = "silver"
+	SellerTierGold     SellerTier = "gold"
+
+	FeeRoundingUp     FeeRounding = "up"
+	FeeRoundingDown   FeeRounding = "down"
+	FeeRoundingHalfUp FeeRounding = "half_up"
+)
+
+// FeePolicy is one row of the platform fee schedule. Empty CategoryID and SellerTier, and
+// nil StartsAt and EndsAt, match every sale.
+type FeePolicy struct {
+	ID         string      `json:"id"`
+	Name       string      `json:"name"`
+	CategoryID string      `json:"category_id,omitempty"`
+	SellerTier SellerTier  `json:"seller_tier,omitempty"`
+	RateBps    int         `json:"rate_bps"` // basis points of the item subtotal
+	MinFee     int         `json:"min_fee"`
+	MaxFee     int         `json:"max_fee,omitempty"` // 0 means no cap
+	Rounding   FeeRounding `json:"rounding"`
+	Priority   int         `json:"priority"`
+	StartsAt   *time.Time  `json:"starts_at,omitempty"`
+	EndsAt     *time.Time  `json:"ends_at,omitempty"`
+}
+
+// FeeSubject is what fee policies are matched against for a listing.
+type FeeSubject struct {
+	SellerTier  SellerTier
+	CategoryIDs []string // the listing's categories and all their ancestors
+}
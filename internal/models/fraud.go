@@ -0,0 +1,53 @@
+package models
+
+import "time"
+
+// FraudRuleKind names a velocity check on new orders. It is also the error code a blocked
+// buyer receives.
+type FraudRuleKind string
+type FraudReviewStatus string
+
+const (
+	FraudRuleOrderVelocity      FraudRuleKind = "order_velocity"       // orders by the buyer
+	FraudRuleSpendLimit         FraudRuleKind = "spend_limit"          // yen spent by the buyer
+	FraudRuleNewAccountLimit    FraudRuleKind = "new_account_limit"    // yen spent by a young account
+	FraudRuleSameSellerVelocity FraudRuleKind = "same_seller_velocity" // orders by the buyer from one seller
+
+	FraudReviewStatusOpen      FraudReviewStatus = "open"
+	FraudReviewStatusCleared   FraudReviewStatus = "cleared"   // the buyer was legitimate
+	FraudReviewStatusConfirmed FraudReviewStatus = "confirmed" // fraud; act on the account separately
+)
+
+// FraudRule limits what a buyer can order within a rolling window, counting the new order.
+type FraudRule struct {
+	Rule          FraudRuleKind
+	Limit         int // orders, or yen for the spend rules
+	Window        time.Duration
+	MaxAccountAge time.Duration // new_account_limit only
+}
+
+// FraudActivity is what a fraud rule measures for a buyer before their new order.
+type FraudActivity struct {
+	AccountCreatedAt time.Time
+	Orders           int // orders placed in the window, paid or not
+	Spend            int // yen in orders placed in the window whose payment did not fail or expire
+	SameSellerOrders int // orders placed in the window from the seller of the new order
+}
+
+// FraudReview is an order blocked by a fraud rule, waiting for an admin.
+type FraudReview struct {
+	ID            string            `json:"id"`
+	BuyerID       string            `json:"buyer_id"`
+	SellerID      string            `json:"seller_id"` // of the latest attempt
+	ListingID     string            `json:"listing_id"`
+	Rule          FraudRuleKind     `json:"rule"`
+	Limit         int               `json:"limit"`
+	Observed      int               `json:"observed"` // including the blocked order
+	Attempts      int               `json:"attempts"`
+	Status        FraudReviewStatus `json:"status"`
+	ReviewedBy    string            `json:"reviewed_by,omitempty"`
+	Note          string            `json:"note,omitempty"`
+	CreatedAt     time.Time         `json:"created_at"`
+	LastAttemptAt time.Time         `json:"last_attempt_at"`
+	ReviewedAt    *time.Time        `json:"reviewed_at,omitempty"`
+}
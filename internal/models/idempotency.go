@@ -0,0 +1,23 @@
+package models
+
+import "time"
+
+type IdempotencyStatus string
+
+const (
+	IdempotencyStatusInFlight  IdempotencyStatus = "in_flight"
+	IdempotencyStatusCompleted IdempotencyStatus = "completed"
+)
+
+// IdempotencyRecord is the first request made with an Idempotency-Key and, once it finished,
+// its response.
+type IdempotencyRecord struct {
+	UserID              string
+	Key                 string
+	Fingerprint         string
+	Status              IdempotencyStatus
+	ResponseStatus      int
+	ResponseContentType string
+	ResponseBody        []byte
+	CreatedAt           time.Time
+}
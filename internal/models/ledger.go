@@ -0,0 +1,91 @@
+package models
+
+import "time"
+
+type LedgerAccount string
+type LedgerTransactionType string
+type BankAccountType string
+type PayoutStatus string
+
+// Seller accounts hold what the platform owes a seller, as it moves pending → available →
+// payout → paid_out. The platform clearing account is the other side of every sale.
+const (
+	LedgerAccountSellerPending    LedgerAccount = "seller_pending"   // sales not yet completed
+	LedgerAccountSellerAvailable  LedgerAccount = "seller_available" // can be paid out
+	LedgerAccountSellerPayout     LedgerAccount = "seller_payout"    // requested, not yet transferred
+	LedgerAccountSellerPaidOut    LedgerAccount = "seller_paid_out"  // transferred to the bank
+	LedgerAccountPlatformClearing LedgerAccount = "platform_clearing"
+
+	LedgerTxOrderPaid       LedgerTransactionType = "order_paid"
+	LedgerTxOrderCompleted  LedgerTransactionType = "order_completed"
+	LedgerTxOrderCancelled  LedgerTransactionType = "order_cancelled"
+	LedgerTxOrderRefunded   LedgerTransactionType = "order_refunded"
+	LedgerTxPayoutRequested LedgerTransactionType = "payout_requested"
+	LedgerTxPayoutPaid      LedgerTransactionType = "payout_paid"
+	LedgerTxPayoutFailed    LedgerTransactionType = "payout_failed"
+
+	BankAccountTypeOrdinary BankAccountType = "ordinary" // 普通
+	BankAccountTypeCurrent  BankAccountType = "current"  // 当座
+
+	PayoutStatusRequested PayoutStatus = "requested"
+	PayoutStatusPaid      PayoutStatus = "paid"
+	PayoutStatusFailed    PayoutStatus = "failed"
+)
+
+// LedgerTransaction is a balanced set of entries: their amounts sum to zero.
+type LedgerTransaction struct {
+	ID        int64                 `json:"id"`
+	Type      LedgerTransactionType `json:"type"`
+	OrderID   string                `json:"order_id,omitempty"`
+	PayoutID  string                `json:"payout_id,omitempty"`
+	RefundID  string                `json:"refund_id,omitempty"`
+	Entries   []LedgerEntry         `json:"entries"`
+	CreatedAt time.Time             `json:"created_at"`
+}
+
+// LedgerEntry changes one account's balance. UserID is empty for platform accounts.
+type LedgerEntry struct {
+	ID            int64                 `json:"id"`
+	TransactionID int64                 `json:"transaction_id"`
+	Account       LedgerAccount         `json:"account"`
+	UserID        string                `json:"user_id,omitempty"`
+	Amount        int                   `json:"amount"`
+	Type          LedgerTransactionType `json:"type,omitempty"` // of the transaction, when listed
+	OrderID       string                `json:"order_id,omitempty"`
+	PayoutID      string                `json:"payout_id,omitempty"`
+	RefundID      string                `json:"refund_id,omitempty"`
+	CreatedAt     time.Time             `json:"created_at"`
+}
+
+// SellerBalance is the sum of a seller's ledger entries per account.
+type SellerBalance struct {
+	Pending   int `json:"pending"`
+	Available int `json:"available"`
+	InPayout  int `json:"in_payout"`
+	PaidOut   int `json:"paid_out"`
+}
+
+type BankAccount struct {
+	UserID        string          `json:"-"`
+	BankCode      string          `json:"bank_code"`   // 4 digits
+	BranchCode    string          `json:"branch_code"` // 3 digits
+	AccountType   BankAccountType `json:"account_type"`
+	AccountNumber string          `json:"account_number"` // 7 digits
+	AccountHolder string          `json:"account_holder"` // full-width katakana, as registered with the bank
+	UpdatedAt     time.Time       `json:"updated_at"`
+}
+
+type Payout struct {
+	ID            string          `json:"id"`
+	UserID        string          `json:"user_id"`
+	Amount        int             `json:"amount"`
+	Status        PayoutStatus    `json:"status"`
+	BankCode      string          `json:"bank_code"` // snapshot of the bank account at request time
+	BranchCode    string          `json:"branch_code"`
+	AccountType   BankAccountType `json:"account_type"`
+	AccountNumber string          `json:"account_number"`
+	AccountHolder string          `json:"account_holder"`
+	ProcessedBy   string          `json:"processed_by,omitempty"`
+	ProcessedAt   *time.Time      `json:"processed_at,omitempty"`
+	CreatedAt     time.Time       `json:"created_at"`
+}
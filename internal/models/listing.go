@@ -4,33 +4,75 @@ import "time"
 
 type ListingStatus string
 type ItemCondition string
+type ShippingPayer string
+type ShippingMethod string
+type ShippingSize string
+type DaysToShip string
 
 const (
-	ListingStatusDraft  ListingStatus = "draft"
-	ListingStatusActive ListingStatus = "active"
-	ListingStatusSold   ListingStatus = "sold"
+	ListingStatusDraft         ListingStatus = "draft"
+	ListingStatusActive        ListingStatus = "active"
+	ListingStatusSold          ListingStatus = "sold"
+	ListingStatusRemoved       ListingStatus = "removed"        // taken down by an admin
+	ListingStatusPendingReview ListingStatus = "pending_review" // held by content screening
 
 	ItemConditionNew       ItemCondition = "new"
 	ItemConditionExcellent ItemCondition = "excellent"
 	ItemConditionGood      ItemCondition = "good"
 	ItemConditionNotGood   ItemCondition = "not_good"
 	ItemConditionBad       ItemCondition = "bad"
+
+	ShippingPayerSeller ShippingPayer = "seller" // 送料込み
+	ShippingPayerBuyer  ShippingPayer = "buyer"  // 送料購入者負担 (added to the order total at checkout)
+
+	ShippingMethodNekopos   ShippingMethod = "nekopos"
+	ShippingMethodYuPacket  ShippingMethod = "yu_packet"
+	ShippingMethodTakkyubin ShippingMethod = "takkyubin"
+	ShippingMethodYuPack    ShippingMethod = "yu_pack"
+
+	ShippingSizeSmall ShippingSize = "small" // fits in a post box (A4, up to 3cm thick)
+	ShippingSize60    ShippingSize = "60"
+	ShippingSize80    ShippingSize = "80"
+	ShippingSize100   ShippingSize = "100"
+	ShippingSize120   ShippingSize = "120"
+	ShippingSize140   ShippingSize = "140"
+	ShippingSize160   ShippingSize = "160"
+
+	DaysToShip1To2 DaysToShip = "1_2"
+	DaysToShip2To3 DaysToShip = "2_3"
+	DaysToShip4To7 DaysToShip = "4_7"
 )
 
 type ListingImage struct {
 	URL string `json:"url"`
 }
 
+// ListingVariant is one purchasable option (size, color) of a listing with its own stock.
+type ListingVariant struct {
+	ID       string `json:"id"`
+	Label    string `json:"label"`
+	Price    int    `json:"price,omitempty"` // overrides the listing price when set
+	Quantity int    `json:"quantity"`
+}
+
 type Listing struct {
-	ID            string         `json:"id"`
-	SellerID      string         `json:"seller_id"`
-	Title         string         `json:"title"`
-	Description   string         `json:"description"`
-	Images        []ListingImage `json:"images"`
-	Price         int            `json:"price"`
-	Quantity      int            `json:"quantity"`
-	Status        ListingStatus  `json:"status"`
-	ItemCondition ItemCondition  `json:"item_condition"`
-	CreatedAt     time.Time      `json:"created_at"`
-	UpdatedAt     time.Time      `json:"updated_at"`
+	ID                  string           `json:"id"`
+	SellerID            string           `json:"seller_id"`
+	SellerInvoiceNumber string           `json:"seller_invoice_number,omitempty"` // set when the seller issues qualified invoices
+	Title               string           `json:"title"`
+	Description         string           `json:"description"`
+	Images              []ListingImage   `json:"images"`
+	Price               int              `json:"price"`
+	TaxRate             TaxRate          `json:"tax_rate"`
+	Quantity            int              `json:"quantity"`
+	Status              ListingStatus    `json:"status"`
+	ItemCondition       ItemCondition    `json:"item_condition"`
+	ShippingPayer       ShippingPayer    `json:"shipping_payer"`
+	ShippingMethod      ShippingMethod   `json:"shipping_method,omitempty"` // optional when the seller pays
+	ShippingSize        ShippingSize     `json:"shipping_size,omitempty"`
+	ShipFromPrefecture  int              `json:"ship_from_prefecture,omitempty"` // JIS X 0401 code, 1-47
+	DaysToShip          DaysToShip       `json:"days_to_ship,omitempty"`
+	Variants            []ListingVariant `json:"variants,omitempty"` // optional; Quantity is their sum when present
+	CreatedAt           time.Time        `json:"created_at"`
+	UpdatedAt           time.Time        `json:"updated_at"`
 }
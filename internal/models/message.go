@@ -6,6 +6,8 @@ type Message struct {
 	ID         string    `json:"id"`
 	SenderID   string    `json:"sender_id"`
 	ReceiverID string    `json:"receiver_id"`
+	OrderID    string    `json:"order_id,omitempty"`   // set for messages about an order
+	ListingID  string    `json:"listing_id,omitempty"` // set for messages about a listing
 	Content    string    `json:"content"`
 	CreatedAt  time.Time `json:"created_at"`
 }
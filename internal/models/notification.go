@@ -0,0 +1,29 @@
+package models
+
+import (
+	"encoding/json"
+	"time"
+)
+
+type NotificationType string
+
+const (
+	NotificationTypeSavedSearchDigest       NotificationType = "saved_search_digest"
+	NotificationTypeOrderCompletionReminder NotificationType = "order_completion_reminder"
+)
+
+type Notification struct {
+	ID        string           `json:"id"`
+	UserID    string           `json:"user_id"`
+	Type      NotificationType `json:"type"`
+	Payload   json.RawMessage  `json:"payload"`
+	ReadAt    *time.Time       `json:"read_at,omitempty"`
+	CreatedAt time.Time        `json:"created_at"`
+}
+
+// OrderCompletionReminder is the payload of an order_completion_reminder notification.
+type OrderCompletionReminder struct {
+	OrderID        string    `json:"order_id"`
+	ListingTitle   string    `json:"listing_title"`
+	AutoCompleteAt time.Time `json:"auto_complete_at"`
+}
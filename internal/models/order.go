@@ -5,27 +5,93 @@ import "time"
 type OrderStatus string
 
 const (
-	OrderStatusPaid      OrderStatus = "paid"
-	OrderStatusShipped   OrderStatus = "shipped"
-	OrderStatusDelivered OrderStatus = "delivered"
-	OrderStatusCompleted OrderStatus = "completed"
-	OrderStatusCancelled OrderStatus = "cancelled"
-	OrderStatusDisputed  OrderStatus = "disputed"
+	OrderStatusPendingPayment OrderStatus = "pending_payment" // stock is held until the payment completes or expires
+	OrderStatusPaid           OrderStatus = "paid"
+	OrderStatusShipped        OrderStatus = "shipped"
+	OrderStatusDelivered      OrderStatus = "delivered"
+	OrderStatusCompleted      OrderStatus = "completed"
+	OrderStatusCancelled      OrderStatus = "cancelled"
+	OrderStatusDisputed       OrderStatus = "disputed"
 )
 
+type CancelReason string
+
+const (
+	CancelReasonChangedMind       CancelReason = "changed_mind"
+	CancelReasonOutOfStock        CancelReason = "out_of_stock"
+	CancelReasonBuyerUnresponsive CancelReason = "buyer_unresponsive"
+	CancelReasonReturnedToSender  CancelReason = "returned_to_sender" // after shipping; the item is back with the seller
+	CancelReasonLostInTransit     CancelReason = "lost_in_transit"    // after shipping; the item is gone
+	CancelReasonOther             CancelReason = "other"
+	CancelReasonDisputeRefunded   CancelReason = "dispute_refunded" // set by dispute resolution, not accepted from users
+	CancelReasonPaymentFailed     CancelReason = "payment_failed"   // set by the payment webhook
+	CancelReasonPaymentExpired    CancelReason = "payment_expired"  // set when the payment window closes
+)
+
+// CancelledBySystem is recorded as the canceller when the platform cancels an order itself.
+const CancelledBySystem = "system"
+
 type Order struct {
-	ID               string      `json:"id"`
-	BuyerID          string      `json:"buyer_id"`
-	SellerID         string      `json:"seller_id"`
-	ListingID        string      `json:"listing_id"`
-	ListingTitle     string      `json:"listing_title"`
-	ListingMainImage string      `json:"listing_main_image"`
-	ListingPrice     int         `json:"listing_price"`
-	Quantity         int         `json:"quantity"`
-	TotalPrice       int         `json:"total_price"`
-	PlatformFee      int         `json:"platform_fee"`
-	NetPayout        int         `json:"net_payout"`
-	Status           OrderStatus `json:"status"`
-	CreatedAt        time.Time   `json:"created_at"`
-	UpdatedAt        time.Time   `json:"updated_at"`
+	ID                  string         `json:"id"`
+	BuyerID             string         `json:"buyer_id"`
+	SellerID            string         `json:"seller_id"`
+	SellerInvoiceNumber string         `json:"seller_invoice_number,omitempty"` // snapshot at purchase time
+	ListingID           string         `json:"listing_id"`
+	VariantID           string         `json:"variant_id,omitempty"`
+	VariantLabel        string         `json:"variant_label,omitempty"` // snapshot at purchase time
+	ListingTitle        string         `json:"listing_title"`
+	ListingMainImage    string         `json:"listing_main_image"`
+	ListingPrice        int            `json:"listing_price"`
+	ItemTaxRate         TaxRate        `json:"item_tax_rate"`
+	Quantity            int            `json:"quantity"`
+	ShippingPayer       ShippingPayer  `json:"shipping_payer"`
+	ShippingMethod      ShippingMethod `json:"shipping_method,omitempty"`
+	ShippingSize        ShippingSize   `json:"shipping_size,omitempty"`
+	ShippingFee         int            `json:"shipping_fee"` // charged to the buyer, 0 when the seller pays
+	TotalPrice          int            `json:"total_price"`
+	Tax                 TaxBreakdown   `json:"tax"`          // consumption tax included in TotalPrice
+	PlatformFee         int            `json:"platform_fee"` // less the part given back by refunds
+	NetPayout           int            `json:"net_payout"`   // less the part taken by refunds
+	RefundedAmount      int            `json:"refunded_amount"`
+	RefundedQuantity    int            `json:"refunded_quantity"`
+	FeePolicyID         string         `json:"fee_policy_id,omitempty"` // the fee policy applied
+	Status              OrderStatus    `json:"status"`
+	Version             int            `json:"version"` // incremented on every status change
+	PaymentIntentID     string         `json:"payment_intent_id,omitempty"`
+	PaymentExpiresAt    *time.Time     `json:"payment_expires_at,omitempty"` // while pending_payment
+	DeliveredAt         *time.Time     `json:"delivered_at,omitempty"`
+	CompletedAt         *time.Time     `json:"completed_at,omitempty"`
+	PaymentSecret       string         `json:"payment_client_secret,omitempty"` // only in the create response
+	CancelledBy         string         `json:"cancelled_by,omitempty"`
+	CancelReason        CancelReason   `json:"cancel_reason,omitempty"`
+	CancelNote          string         `json:"cancel_note,omitempty"`
+	CancelledAt         *time.Time     `json:"cancelled_at,omitempty"`
+	CreatedAt           time.Time      `json:"created_at"`
+	UpdatedAt           time.Time      `json:"updated_at"`
+}
+
+// OrderRole is the part a user plays in an order.
+type OrderRole string
+
+const (
+	OrderRoleBuyer  OrderRole = "buyer"
+	OrderRoleSeller OrderRole = "seller"
+)
+
+// OrderFilter narrows a user's order list. Empty fields do not filter. CreatedFrom is
+// inclusive and CreatedTo exclusive.
+type OrderFilter struct {
+	Role        OrderRole
+	Statuses    []OrderStatus
+	CreatedFrom time.Time
+	CreatedTo   time.Time
+	Limit       int
+	Offset      int
+}
+
+// OrderSummary counts a user's orders per status, e.g. paid orders a seller still has to ship.
+type OrderSummary struct {
+	Role   OrderRole           `json:"role,omitempty"` // empty for both sides
+	Counts map[OrderStatus]int `json:"counts"`         // every status, zero when there are none
+	Total  int                 `json:"total"`
 }
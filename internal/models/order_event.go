@@ -0,0 +1,35 @@
+package models
+
+import "time"
+
+type OrderEventType string
+
+const (
+	OrderEventCreated   OrderEventType = "created"
+	OrderEventPaid      OrderEventType = "paid"
+	OrderEventShipped   OrderEventType = "shipped"
+	OrderEventDelivered OrderEventType = "delivered"
+	OrderEventCompleted OrderEventType = "completed"
+	OrderEventCancelled OrderEventType = "cancelled"
+	OrderEventDisputed  OrderEventType = "disputed"
+	OrderEventRefunded  OrderEventType = "refunded" // the status does not change
+)
+
+// ActorRoleSystem and ActorRoleAdmin describe event actors who are neither buyer nor seller.
+const (
+	ActorRoleSystem = "system"
+	ActorRoleAdmin  = "admin"
+)
+
+// OrderEvent is one entry in an order's timeline.
+type OrderEvent struct {
+	ID         int64          `json:"id"`
+	OrderID    string         `json:"order_id"`
+	Type       OrderEventType `json:"type"`
+	ActorID    string         `json:"actor_id,omitempty"`   // empty when the platform acted
+	ActorRole  string         `json:"actor_role,omitempty"` // buyer, seller, admin or system; set when read
+	FromStatus OrderStatus    `json:"from_status,omitempty"`
+	ToStatus   OrderStatus    `json:"to_status"`
+	Metadata   map[string]any `json:"metadata,omitempty"` // e.g. carrier, cancel reason, dispute id
+	CreatedAt  time.Time      `json:"created_at"`
+}
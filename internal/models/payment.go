@@ -0,0 +1,23 @@
+package models
+
+type PaymentEventType string
+
+const (
+	PaymentEventAuthorized PaymentEventType = "payment.authorized" // the buyer's payment can be captured
+	PaymentEventFailed     PaymentEventType = "payment.failed"
+)
+
+// PaymentIntent is a payment started with the provider for one order.
+type PaymentIntent struct {
+	ID           string `json:"id"`
+	Amount       int    `json:"amount"` // JPY
+	ClientSecret string `json:"client_secret"`
+}
+
+// PaymentEvent is a verified webhook callback from the payment provider.
+type PaymentEvent struct {
+	ID       string           `json:"id"`
+	Type     PaymentEventType `json:"type"`
+	IntentID string           `json:"intent_id"`
+	Amount   int              `json:"amount"`
+}
@@ -0,0 +1,45 @@
+package models
+
+import "time"
+
+type RefundReason string
+
+const (
+	RefundReasonDamaged        RefundReason = "damaged"
+	RefundReasonNotAsDescribed RefundReason = "not_as_described"
+	RefundReasonMissingItem    RefundReason = "missing_item"
+	RefundReasonGoodwill       RefundReason = "goodwill"
+	RefundReasonOther          RefundReason = "other"
+	// RefundReasonDispute refunds are made by resolving a dispute and
+	// RefundReasonOrderCancelled ones by cancelling a paid order.
+	RefundReasonDispute        RefundReason = "dispute"
+	RefundReasonOrderCancelled RefundReason = "order_cancelled"
+)
+
+type RefundStatus string
+
+const (
+	// RefundStatusPending refunds are recorded but not yet confirmed by the payment provider.
+	RefundStatusPending   RefundStatus = "pending"
+	RefundStatusCompleted RefundStatus = "completed"
+)
+
+// Refund returns part or all of an order's total to the buyer. The platform gives back its
+// fee in proportion and the rest is taken from the seller's payout. The accounting is
+// recorded first and the refund stays pending until the payment provider returns the money.
+type Refund struct {
+	ID               string       `json:"id"`
+	OrderID          string       `json:"order_id"`
+	Amount           int          `json:"amount"`             // JPY, PlatformFeeDelta + NetPayoutDelta
+	Quantity         int          `json:"quantity,omitempty"` // items refunded, 0 for an amount-only refund
+	PlatformFeeDelta int          `json:"platform_fee_delta"`
+	NetPayoutDelta   int          `json:"net_payout_delta"`
+	Reason           RefundReason `json:"reason"`
+	Note             string       `json:"note,omitempty"`
+	InitiatedBy      string       `json:"initiated_by"`
+	InitiatorRole    string       `json:"initiator_role"` // buyer, seller or admin
+	Status           RefundStatus `json:"status"`
+	PaymentIntentID  string       `json:"-"`
+	CreatedAt        time.Time    `json:"created_at"`
+	CompletedAt      *time.Time   `json:"completed_at,omitempty"`
+}
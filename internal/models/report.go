@@ -0,0 +1,38 @@
+package models
+
+import "time"
+
+type ReportTargetType string
+type ReportReason string
+type ReportStatus string
+
+const (
+	ReportTargetListing ReportTargetType = "listing"
+	ReportTargetUser    ReportTargetType = "user"
+
+	ReportReasonScam           ReportReason = "scam"
+	ReportReasonProhibitedItem ReportReason = "prohibited_item"
+	ReportReasonCounterfeit    ReportReason = "counterfeit"
+	ReportReasonInappropriate  ReportReason = "inappropriate"
+	ReportReasonSpam           ReportReason = "spam"
+	ReportReasonHarassment     ReportReason = "harassment"
+	ReportReasonOther          ReportReason = "other"
+
+	ReportStatusOpen      ReportStatus = "open"
+	ReportStatusInReview  ReportStatus = "in_review"
+	ReportStatusActioned  ReportStatus = "actioned"
+	ReportStatusDismissed ReportStatus = "dismissed"
+)
+
+type Report struct {
+	ID         string           `json:"id"`
+	ReporterID string           `json:"reporter_id"`
+	TargetType ReportTargetType `json:"target_type"`
+	TargetID   string           `json:"target_id"`
+	Reason     ReportReason     `json:"reason"`
+	Comment    string           `json:"comment"`
+	Status     ReportStatus     `json:"status"`
+	HandledBy  string           `json:"handled_by,omitempty"`
+	CreatedAt  time.Time        `json:"created_at"`
+	UpdatedAt  time.Time        `json:"updated_at"`
+}
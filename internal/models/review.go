@@ -0,0 +1,33 @@
+package models
+
+import "time"
+
+type ReviewRating string
+
+const (
+	ReviewRatingGood   ReviewRating = "good"
+	ReviewRatingNormal ReviewRating = "normal"
+	ReviewRatingBad    ReviewRating = "bad"
+)
+
+// Review is one side's rating of the other after a completed order.
+type Review struct {
+	ID                string       `json:"id"`
+	OrderID           string       `json:"order_id"`
+	ReviewerID        string       `json:"reviewer_id"`
+	ReviewerName      string       `json:"reviewer_name,omitempty"`       // only in review lists
+	ReviewerAvatarURL string       `json:"reviewer_avatar_url,omitempty"` // only in review lists
+	RevieweeID        string       `json:"reviewee_id"`
+	ReviewerRole      OrderRole    `json:"reviewer_role"`
+	Rating            ReviewRating `json:"rating"`
+	Comment           string       `json:"comment"`
+	VisibleAt         time.Time    `json:"visible_at"` // hidden from others until then
+	CreatedAt         time.Time    `json:"created_at"`
+}
+
+// RatingSummary counts the visible ratings a user received.
+type RatingSummary struct {
+	Good   int `json:"good"`
+	Normal int `json:"normal"`
+	Bad    int `json:"bad"`
+}
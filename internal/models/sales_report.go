@@ -0,0 +1,44 @@
+package models
+
+// SalesGranularity is the length of the periods a sales report is bucketed into.
+type SalesGranularity string
+
+const (
+	SalesGranularityDay   SalesGranularity = "day"
+	SalesGranularityWeek  SalesGranularity = "week" // weeks start on Monday
+	SalesGranularityMonth SalesGranularity = "month"
+)
+
+// SalesTotals sums a seller's paid orders that were not cancelled, in yen. Fees and payouts
+// are after refunds, so GrossSales - Refunds = PlatformFees + NetPayouts.
+type SalesTotals struct {
+	Orders       int `json:"orders"`
+	Quantity     int `json:"quantity"`
+	GrossSales   int `json:"gross_sales"` // order totals paid by buyers, including shipping and tax
+	Refunds      int `json:"refunds"`
+	PlatformFees int `json:"platform_fees"`
+	NetPayouts   int `json:"net_payouts"`
+}
+
+// SalesBucket is the sales of one day, week or month.
+type SalesBucket struct {
+	Start string `json:"start"` // first day of the period, YYYY-MM-DD in JST
+	SalesTotals
+}
+
+// ListingSales is the sales of one listing.
+type ListingSales struct {
+	ListingID        string `json:"listing_id"`
+	ListingTitle     string `json:"listing_title"` // snapshot from its orders
+	ListingMainImage string `json:"listing_main_image"`
+	SalesTotals
+}
+
+type SalesReport struct {
+	Granularity SalesGranularity `json:"granularity"`
+	From        string           `json:"from"` // YYYY-MM-DD in JST, inclusive
+	To          string           `json:"to"`   // YYYY-MM-DD in JST, inclusive
+	Totals      SalesTotals      `json:"totals"`
+	Buckets     []SalesBucket    `json:"buckets"`      // oldest first, including periods without sales
+	TopListings []ListingSales   `json:"top_listings"` // by gross sales less refunds
+}
@@ -0,0 +1,28 @@
+package models
+
+import "time"
+
+// SavedSearch is a query a buyer wants to be notified about. Zero prices and an empty
+// condition mean the filter is not set.
+type SavedSearch struct {
+	ID        string        `json:"id"`
+	UserID    string        `json:"user_id"`
+	Query     string        `json:"query"`
+	Terms     []string      `json:"-"`
+	MinPrice  int           `json:"min_price,omitempty"`
+	MaxPrice  int           `json:"max_price,omitempty"`
+	Condition ItemCondition `json:"condition,omitempty"`
+	CreatedAt time.Time     `json:"created_at"`
+}
+
+type SavedSearchMatch struct {
+	SavedSearchID string `json:"saved_search_id"`
+	ListingID     string `json:"listing_id"`
+	UserID        string `json:"-"`
+}
+
+// SavedSearchDigest is the payload of a saved_search_digest notification.
+type SavedSearchDigest struct {
+	Count   int                `json:"count"`
+	Matches []SavedSearchMatch `json:"matches"`
+}
@@ -0,0 +1,28 @@
+package models
+
+import "time"
+
+type ScreeningSource string
+
+const (
+	ScreeningSourceKeyword ScreeningSource = "keyword"
+	ScreeningSourceAI      ScreeningSource = "ai"
+	// ScreeningSourceUnavailable means the AI check failed and the listing was held to be safe.
+	ScreeningSourceUnavailable ScreeningSource = "unavailable"
+
+	ProhibitedCategoryWeapons      = "weapons"
+	ProhibitedCategoryCounterfeit  = "counterfeit"
+	ProhibitedCategoryTicketResale = "ticket_resale"
+	ProhibitedCategoryPersonalData = "personal_data"
+)
+
+// ListingScreening is the result of screening a listing for prohibited content.
+type ListingScreening struct {
+	ID         string          `json:"id"`
+	ListingID  string          `json:"listing_id"`
+	Flagged    bool            `json:"flagged"`
+	Source     ScreeningSource `json:"source"`
+	Categories []string        `json:"categories"`
+	Reason     string          `json:"reason"`
+	CreatedAt  time.Time       `json:"created_at"`
+}
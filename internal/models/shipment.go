@@ -0,0 +1,33 @@
+package models
+
+import "time"
+
+type Carrier string
+type TrackingStatus string
+
+const (
+	CarrierYamato    Carrier = "yamato"     // ヤマト運輸: nekopos, takkyubin
+	CarrierJapanPost Carrier = "japan_post" // 日本郵便: yu_packet, yu_pack
+	CarrierSagawa    Carrier = "sagawa"     // 佐川急便
+
+	TrackingStatusInTransit TrackingStatus = "in_transit"
+	TrackingStatusDelivered TrackingStatus = "delivered"
+	TrackingStatusReturned  TrackingStatus = "returned" // on its way back to the seller
+)
+
+// Shipment is the package sent for an order.
+type Shipment struct {
+	OrderID        string         `json:"order_id"`
+	Carrier        Carrier        `json:"carrier"`
+	TrackingNumber string         `json:"tracking_number"`
+	TrackingStatus TrackingStatus `json:"tracking_status"`
+	ShippedAt      time.Time      `json:"shipped_at"`
+	DeliveredAt    *time.Time     `json:"delivered_at,omitempty"`
+	LastCheckedAt  *time.Time     `json:"last_checked_at,omitempty"`
+}
+
+// TrackingUpdate is a carrier's report on a package.
+type TrackingUpdate struct {
+	Status      TrackingStatus
+	DeliveredAt *time.Time // when the carrier delivered it, if it has
+}
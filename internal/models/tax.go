@@ -0,0 +1,42 @@
+package models
+
+import "time"
+
+type TaxRate string
+
+const (
+	TaxRateStandard TaxRate = "standard" // 10%
+	TaxRateReduced  TaxRate = "reduced"  // 8%, food, beverages and newspapers
+)
+
+// TaxBreakdown splits a tax-inclusive total by consumption tax rate. Each tax is the amount
+// included in its total, rounded down once per rate.
+type TaxBreakdown struct {
+	StandardRateTotal int `json:"standard_rate_total"`
+	StandardRateTax   int `json:"standard_rate_tax"`
+	ReducedRateTotal  int `json:"reduced_rate_total"`
+	ReducedRateTax    int `json:"reduced_rate_tax"`
+}
+
+type InvoiceLine struct {
+	Description string  `json:"description"`
+	TaxRate     TaxRate `json:"tax_rate"`
+	UnitPrice   int     `json:"unit_price"` // tax-inclusive
+	Quantity    int     `json:"quantity"`
+	Amount      int     `json:"amount"`
+}
+
+// Invoice is the receipt for an order. It is a qualified invoice (適格請求書) when the seller
+// was registered at the time of purchase.
+type Invoice struct {
+	OrderID                  string        `json:"order_id"`
+	Qualified                bool          `json:"qualified"`
+	SellerName               string        `json:"seller_name"`
+	SellerRegistrationNumber string        `json:"seller_registration_number,omitempty"`
+	BuyerName                string        `json:"buyer_name"`
+	TransactionDate          time.Time     `json:"transaction_date"`
+	IssuedAt                 time.Time     `json:"issued_at"`
+	Lines                    []InvoiceLine `json:"lines"`
+	Tax                      TaxBreakdown  `json:"tax"`
+	Total                    int           `json:"total"`
+}
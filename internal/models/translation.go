@@ -0,0 +1,28 @@
+package models
+
+import "time"
+
+// ListingTranslation is a cached translation of a listing's title and description.
+type ListingTranslation struct {
+	ListingID      string    `json:"listing_id"`
+	Language       string    `json:"language"`
+	Title          string    `json:"title"`
+	Description    string    `json:"description"`
+	SourceLanguage string    `json:"source_language"`
+	SourceHash     string    `json:"-"`
+	UpdatedAt      time.Time `json:"updated_at"`
+}
+
+type ListingText struct {
+	Title       string `json:"title"`
+	Description string `json:"description"`
+}
+
+// LocalizedListing is a listing whose title and description may be translated.
+// Original holds the seller's text and TranslatedAt the translation's updated_at when a translation was applied.
+type LocalizedListing struct {
+	*Listing
+	Language     string       `json:"language,omitempty"`
+	Original     *ListingText `json:"original,omitempty"`
+	TranslatedAt time.Time    `json:"-"`
+}
@@ -1,14 +1,29 @@
 package models
 
+type UserRole string
+type UserStatus string
+
+const (
+	UserRoleUser  UserRole = "user"
+	UserRoleAdmin UserRole = "admin"
+
+	UserStatusActive    UserStatus = "active"
+	UserStatusSuspended UserStatus = "suspended"
+)
+
 type User struct {
-	ID        string `json:"id"`
-	Name      string `json:"name"`
-	Email     string `json:"email"`
-	AvatarURL string `json:"avatar_url"`
+	ID                        string     `json:"id"`
+	Name                      string     `json:"name"`
+	Email                     string     `json:"email"`
+	AvatarURL                 string     `json:"avatar_url"`
+	Role                      UserRole   `json:"role"`
+	Status                    UserStatus `json:"status"`
+	InvoiceRegistrationNumber string     `json:"invoice_registration_number,omitempty"` // qualified invoice issuers only
 }
 
 type UserProfile struct {
-	ID        string `json:"id"`
-	Name      string `json:"name"`
-	AvatarURL string `json:"avatar_url"`
+	ID        string         `json:"id"`
+	Name      string         `json:"name"`
+	AvatarURL string         `json:"avatar_url"`
+	Ratings   *RatingSummary `json:"ratings,omitempty"` // only on GET /users/{userId}/profile
 }
@@ -0,0 +1,144 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"uttc-hackathon-backend/internal/models"
+)
+
+// DiscoveryRepo reads the public catalog for sitemaps and syndication feeds.
+type DiscoveryRepo struct {
+	db *sql.DB
+}
+
+func NewDiscoveryRepo(db *sql.DB) *DiscoveryRepo {
+	return &DiscoveryRepo{db: db}
+}
+
+// GetListingSitemapPages splits active listings (ordered by id) into pages of pageSize and
+// returns each page with the newest updated_at on it.
+func (r *DiscoveryRepo) GetListingSitemapPages(ctx context.Context, pageSize int) ([]models.SitemapPage, error) {
+	query := `
+		SELECT page, MAX(updated_at)
+		FROM (
+			SELECT FLOOR((ROW_NUMBER() OVER (ORDER BY l.id) - 1) / ?) + 1 AS page, l.updated_at
+			FROM listings l
+			JOIN users u ON u.id = l.seller_id
+			WHERE l.status = 'active' AND u.status = 'active'
+		) p
+		GROUP BY page
+		ORDER BY page
+	`
+	rows, err := r.db.QueryContext(ctx, query, pageSize)
+	if err != nil {
+		return nil, fmt.Errorf("query listing sitemap pages: %w", err)
+	}
+	defer rows.Close()
+
+	var pages []models.SitemapPage
+	for rows.Next() {
+		p := models.SitemapPage{Kind: models.SitemapKindListings}
+		if err := rows.Scan(&p.Page, &p.LastModified); err != nil {
+			return nil, fmt.Errorf("scan listing sitemap page: %w", err)
+		}
+		pages = append(pages, p)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("iterate listing sitemap pages: %w", err)
+	}
+	return pages, nil
+}
+
+func (r *DiscoveryRepo) CountActiveUsers(ctx context.Context) (int, error) {
+	var n int
+	if err := r.db.QueryRowContext(ctx, `SELECT COUNT(*) FROM users WHERE status = 'active'`).Scan(&n); err != nil {
+		return 0, fmt.Errorf("count active users: %w", err)
+	}
+	return n, nil
+}
+
+// StreamListingSitemapEntries calls fn for each active listing in id order without
+// loading the page into memory. An error from fn stops the iteration and is returned.
+func (r *DiscoveryRepo) StreamListingSitemapEntries(ctx context.Context, limit, offset int, fn func(models.SitemapEntry) error) error {
+	query := `
+		SELECT l.id, l.updated_at
+		FROM listings l
+		JOIN users u ON u.id = l.seller_id
+		WHERE l.status = 'active' AND u.status = 'active'
+		ORDER BY l.id
+		LIMIT ? OFFSET ?
+	`
+	return r.streamEntries(ctx, fn, query, limit, offset)
+}
+
+// StreamUserSitemapEntries calls fn for each active user in id order. Users have no
+// update time, so LastModified is left zero.
+func (r *DiscoveryRepo) StreamUserSitemapEntries(ctx context.Context, limit, offset int, fn func(models.SitemapEntry) error) error {
+	query := `
+		SELECT id, NULL
+		FROM users
+		WHERE status = 'active'
+		ORDER BY id
+		LIMIT ? OFFSET ?
+	`
+	return r.streamEntries(ctx, fn, query, limit, offset)
+}
+
+func (r *DiscoveryRepo) streamEntries(ctx context.Context, fn func(models.SitemapEntry) error, query string, args ...any) error {
+	rows, err := r.db.QueryContext(ctx, query, args...)
+	if err != nil {
+		return fmt.Errorf("query sitemap entries: %w", err)
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var e models.SitemapEntry
+		var lastModified sql.NullTime
+		if err := rows.Scan(&e.ID, &lastModified); err != nil {
+			return fmt.Errorf("scan sitemap entry: %w", err)
+		}
+		e.LastModified = lastModified.Time
+		if err := fn(e); err != nil {
+			return err
+		}
+	}
+	if err := rows.Err(); err != nil {
+		return fmt.Errorf("iterate sitemap entries: %w", err)
+	}
+	return nil
+}
+
+// GetFeedListings returns the newest active listings matching the filter.
+func (r *DiscoveryRepo) GetFeedListings(ctx context.Context, f models.ListingFeedFilter) ([]*models.Listing, error) {
+	query := `
+		SELECT ` + listingColumns + `
+		FROM listings l
+		JOIN users u ON u.id = l.seller_id
+		WHERE l.status = 'active' AND u.status = 'active'
+		  AND (? = '' OR l.seller_id = ?)
+		  AND (? = '' OR EXISTS (
+		      SELECT 1 FROM listing_categories lc WHERE lc.listing_id = l.id AND lc.category_id = ?
+		  ))
+		ORDER BY l.created_at DESC
+		LIMIT ?
+	`
+	rows, err := r.db.QueryContext(ctx, query, f.SellerID, f.SellerID, f.CategoryID, f.CategoryID, f.Limit)
+	if err != nil {
+		return nil, fmt.Errorf("query feed listings: %w", err)
+	}
+	defer rows.Close()
+
+	var listings []*models.Listing
+	for rows.Next() {
+		l, err := scanListing(rows)
+		if err != nil {
+			return nil, fmt.Errorf("scan feed listing: %w", err)
+		}
+		listings = append(listings, l)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("iterate feed listings: %w", err)
+	}
+	return listings, nil
+}
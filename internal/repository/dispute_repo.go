@@ -0,0 +1,319 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"time"
+	"uttc-hackathon-backend/internal/models"
+)
+
+type DisputeRepo struct {
+	db *sql.DB
+}
+
+func NewDisputeRepo(db *sql.DB) *DisputeRepo {
+	return &DisputeRepo{db: db}
+}
+
+var (
+	ErrDisputeNotFound = errors.New("dispute not found")
+	ErrDisputeExists   = errors.New("a dispute has already been opened for this order")
+)
+
+const disputeColumns = `
+	id, order_id, buyer_id, seller_id, reason, status, escalated, respond_by,
+	outcome, refund_amount, resolution_note, resolved_by, resolved_at, created_at, updated_at`
+
+func scanDispute(row rowScanner) (*models.Dispute, error) {
+	var d models.Dispute
+	var respondBy, resolvedAt sql.NullTime
+	var outcome, note, resolvedBy sql.NullString
+	var refund sql.NullInt64
+	if err := row.Scan(
+		&d.ID, &d.OrderID, &d.BuyerID, &d.SellerID, &d.Reason, &d.Status, &d.Escalated, &respondBy,
+		&outcome, &refund, &note, &resolvedBy, &resolvedAt, &d.CreatedAt, &d.UpdatedAt,
+	); err != nil {
+		return nil, err
+	}
+	if respondBy.Valid {
+		d.RespondBy = &respondBy.Time
+	}
+	d.Outcome = models.DisputeOutcome(outcome.String)
+	d.RefundAmount = int(refund.Int64)
+	d.ResolutionNote = note.String
+	d.ResolvedBy = resolvedBy.String
+	if resolvedAt.Valid {
+		d.ResolvedAt = &resolvedAt.Time
+	}
+	return &d, nil
+}
+
+// OpenDispute locks the order, lets fn validate it and build the dispute (with its opening
+// event), and stores the dispute together with the order's new status.
+func (r *DisputeRepo) OpenDispute(ctx context.Context, orderID string, fn func(*models.Order) (*models.Dispute, error)) error {
+	tx, err := r.db.BeginTx(ctx, &sql.TxOptions{Isolation: sql.LevelReadCommitted})
+	if err != nil {
+		return fmt.Errorf("begin tx: %w", err)
+	}
+	defer tx.Rollback()
+
+	o, err := lockOrder(ctx, tx, orderID)
+	if err != nil {
+		return err
+	}
+	statusBefore := o.Status
+
+	d, err := fn(o)
+	if err != nil {
+		return err
+	}
+
+	query := `
+		INSERT INTO disputes (id, order_id, buyer_id, seller_id, reason, status, respond_by)
+		VALUES (?, ?, ?, ?, ?, ?, ?)
+	`
+	_, err = tx.ExecContext(ctx, query, d.ID, d.OrderID, d.BuyerID, d.SellerID, d.Reason, d.Status, d.RespondBy)
+	if err != nil {
+		if isDuplicateEntry(err) {
+			return ErrDisputeExists
+		}
+		return fmt.Errorf("insert dispute: %w", err)
+	}
+
+	for i := range d.Events {
+		if err := insertDisputeEvent(ctx, tx, &d.Events[i]); err != nil {
+			return err
+		}
+	}
+
+	ev := &models.OrderEvent{
+		FromStatus: statusBefore,
+		ActorID:    d.BuyerID,
+		Metadata:   map[string]any{"dispute_id": d.ID, "reason": d.Reason},
+	}
+	if err := saveOrderStatus(ctx, tx, o, ev); err != nil {
+		return err
+	}
+
+	if err := tx.Commit(); err != nil {
+		return fmt.Errorf("commit tx: %w", err)
+	}
+	return nil
+}
+
+// UpdateDispute locks the dispute and its order, lets fn change them and return the event
+// that records the change, and saves all three atomically together with the refund and
+// ledger transactions fn returns, if any. When fn returns a nil event nothing is written.
+func (r *DisputeRepo) UpdateDispute(ctx context.Context, disputeID string, fn func(*models.Dispute, *models.Order) (*models.DisputeEvent, *models.Refund, []*models.LedgerTransaction, error)) error {
+	tx, err := r.db.BeginTx(ctx, &sql.TxOptions{Isolation: sql.LevelReadCommitted})
+	if err != nil {
+		return fmt.Errorf("begin tx: %w", err)
+	}
+	defer tx.Rollback()
+
+	queryDispute := `SELECT ` + disputeColumns + ` FROM disputes WHERE id = ? FOR UPDATE`
+	d, err := scanDispute(tx.QueryRowContext(ctx, queryDispute, disputeID))
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return ErrDisputeNotFound
+		}
+		return fmt.Errorf("get dispute for update: %w", err)
+	}
+
+	o, err := lockOrder(ctx, tx, d.OrderID)
+	if err != nil {
+		return err
+	}
+	statusBefore := o.Status
+
+	ev, rf, lts, err := fn(d, o)
+	if err != nil {
+		return err
+	}
+	if ev == nil {
+		return nil
+	}
+
+	queryUpdate := `
+		UPDATE disputes
+		SET status = ?, escalated = ?, respond_by = ?, outcome = ?, refund_amount = ?,
+		    resolution_note = ?, resolved_by = ?, resolved_at = ?
+		WHERE id = ?
+	`
+	_, err = tx.ExecContext(ctx, queryUpdate,
+		d.Status, d.Escalated, d.RespondBy, nullIfEmpty(d.Outcome), nullIfZero(d.RefundAmount),
+		nullIfEmpty(d.ResolutionNote), nullIfEmpty(d.ResolvedBy), d.ResolvedAt, d.ID,
+	)
+	if err != nil {
+		return fmt.Errorf("update dispute: %w", err)
+	}
+
+	if err := insertDisputeEvent(ctx, tx, ev); err != nil {
+		return err
+	}
+
+	if rf != nil {
+		if err := insertRefund(ctx, tx, rf); err != nil {
+			return err
+		}
+	}
+
+	if o.Status != statusBefore {
+		orderEv := &models.OrderEvent{
+			FromStatus: statusBefore,
+			ActorID:    ev.ActorID,
+			Metadata:   map[string]any{"dispute_id": d.ID, "outcome": d.Outcome},
+		}
+		if rf != nil {
+			orderEv.Metadata["refund_id"] = rf.ID
+			orderEv.Metadata["refund_amount"] = rf.Amount
+		}
+		if err := saveOrderStatus(ctx, tx, o, orderEv); err != nil {
+			return err
+		}
+	}
+
+	for _, lt := range lts {
+		if err := postLedgerTransaction(ctx, tx, lt); err != nil {
+			return err
+		}
+	}
+
+	if err := tx.Commit(); err != nil {
+		return fmt.Errorf("commit tx: %w", err)
+	}
+	return nil
+}
+
+func insertDisputeEvent(ctx context.Context, tx *sql.Tx, ev *models.DisputeEvent) error {
+	images := ev.Images
+	if images == nil {
+		images = []string{}
+	}
+	imagesJSON, err := json.Marshal(images)
+	if err != nil {
+		return fmt.Errorf("marshal dispute evidence: %w", err)
+	}
+
+	query := `
+		INSERT INTO dispute_events (id, dispute_id, actor_id, type, message, images, created_at)
+		VALUES (?, ?, ?, ?, ?, ?, ?)
+	`
+	_, err = tx.ExecContext(ctx, query, ev.ID, ev.DisputeID, nullIfEmpty(ev.ActorID), ev.Type, ev.Message, imagesJSON, ev.CreatedAt)
+	if err != nil {
+		return fmt.Errorf("insert dispute event: %w", err)
+	}
+	return nil
+}
+
+// GetDispute returns the dispute with its timeline.
+func (r *DisputeRepo) GetDispute(ctx context.Context, id string) (*models.Dispute, error) {
+	return r.getDisputeWithEvents(ctx, `SELECT `+disputeColumns+` FROM disputes WHERE id = ?`, id)
+}
+
+// GetDisputeByOrderID returns the order's dispute with its timeline.
+func (r *DisputeRepo) GetDisputeByOrderID(ctx context.Context, orderID string) (*models.Dispute, error) {
+	return r.getDisputeWithEvents(ctx, `SELECT `+disputeColumns+` FROM disputes WHERE order_id = ?`, orderID)
+}
+
+func (r *DisputeRepo) getDisputeWithEvents(ctx context.Context, query string, arg string) (*models.Dispute, error) {
+	d, err := scanDispute(r.db.QueryRowContext(ctx, query, arg))
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, ErrDisputeNotFound
+		}
+		return nil, fmt.Errorf("get dispute: %w", err)
+	}
+
+	queryEvents := `
+		SELECT id, dispute_id, actor_id, type, message, images, created_at
+		FROM dispute_events
+		WHERE dispute_id = ?
+		ORDER BY created_at, id
+	`
+	rows, err := r.db.QueryContext(ctx, queryEvents, d.ID)
+	if err != nil {
+		return nil, fmt.Errorf("query dispute events: %w", err)
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var ev models.DisputeEvent
+		var actorID sql.NullString
+		var images []byte
+		if err := rows.Scan(&ev.ID, &ev.DisputeID, &actorID, &ev.Type, &ev.Message, &images, &ev.CreatedAt); err != nil {
+			return nil, fmt.Errorf("scan dispute event: %w", err)
+		}
+		ev.ActorID = actorID.String
+		if err := json.Unmarshal(images, &ev.Images); err != nil {
+			return nil, fmt.Errorf("unmarshal dispute evidence: %w", err)
+		}
+		d.Events = append(d.Events, ev)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("iterate dispute events: %w", err)
+	}
+	return d, nil
+}
+
+// GetDisputes returns the admin queue: escalated disputes first, then oldest first.
+// An empty status returns all disputes.
+func (r *DisputeRepo) GetDisputes(ctx context.Context, status models.DisputeStatus, limit, offset int) ([]*models.Dispute, error) {
+	query := `
+		SELECT ` + disputeColumns + `
+		FROM disputes
+		WHERE ? = '' OR status = ?
+		ORDER BY escalated DESC, created_at, id
+		LIMIT ? OFFSET ?
+	`
+	rows, err := r.db.QueryContext(ctx, query, status, status, limit, offset)
+	if err != nil {
+		return nil, fmt.Errorf("query disputes: %w", err)
+	}
+	defer rows.Close()
+
+	var disputes []*models.Dispute
+	for rows.Next() {
+		d, err := scanDispute(rows)
+		if err != nil {
+			return nil, fmt.Errorf("scan dispute: %w", err)
+		}
+		disputes = append(disputes, d)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("iterate disputes: %w", err)
+	}
+	return disputes, nil
+}
+
+// GetOverdueDisputeIDs returns disputes still waiting for the seller after their deadline.
+func (r *DisputeRepo) GetOverdueDisputeIDs(ctx context.Context, now time.Time, limit int) ([]string, error) {
+	query := `
+		SELECT id
+		FROM disputes
+		WHERE status = ? AND respond_by <= ?
+		ORDER BY respond_by
+		LIMIT ?
+	`
+	rows, err := r.db.QueryContext(ctx, query, models.DisputeStatusAwaitingSeller, now, limit)
+	if err != nil {
+		return nil, fmt.Errorf("query overdue disputes: %w", err)
+	}
+	defer rows.Close()
+
+	var ids []string
+	for rows.Next() {
+		var id string
+		if err := rows.Scan(&id); err != nil {
+			return nil, fmt.Errorf("scan overdue dispute: %w", err)
+		}
+		ids = append(ids, id)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("iterate overdue disputes: %w", err)
+	}
+	return ids, nil
+}
@@ -0,0 +1,43 @@
+package repository
+
+import (
+	"context"
+	"sync"
+	"time"
+	"uttc-hackathon-backend/internal/models"
+)
+
+// FakeCarrier is an in-memory carrier for local development and tests. Every package is in
+// transit until Deliver or Return is called for its tracking number.
+type FakeCarrier struct {
+	mu       sync.Mutex
+	packages map[string]models.TrackingUpdate
+}
+
+func NewFakeCarrier() *FakeCarrier {
+	return &FakeCarrier{packages: make(map[string]models.TrackingUpdate)}
+}
+
+func (c *FakeCarrier) Track(ctx context.Context, trackingNumber string) (*models.TrackingUpdate, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if u, ok := c.packages[trackingNumber]; ok {
+		return &u, nil
+	}
+	return &models.TrackingUpdate{Status: models.TrackingStatusInTransit}, nil
+}
+
+// Deliver makes the package report delivery at the given time.
+func (c *FakeCarrier) Deliver(trackingNumber string, at time.Time) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.packages[trackingNumber] = models.TrackingUpdate{Status: models.TrackingStatusDelivered, DeliveredAt: &at}
+}
+
+// Return makes the package report that it is going back to the sender.
+func (c *FakeCarrier) Return(trackingNumber string) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.packages[trackingNumber] = models.TrackingUpdate{Status: models.TrackingStatusReturned}
+}
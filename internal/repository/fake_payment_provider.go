@@ -0,0 +1,118 @@
+package repository
+
+import (
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"sync"
+	"uttc-hackathon-backend/internal/models"
+
+	"github.com/oklog/ulid/v2"
+)
+
+var (
+	ErrPaymentIntentNotFound   = errors.New("payment intent not found")
+	ErrInvalidWebhookSignature = errors.New("invalid webhook signature")
+)
+
+type fakeIntent struct {
+	amount   int
+	captured int
+	refunded int
+	refunds  map[string]bool // idempotency keys of the refunds made
+}
+
+// FakePaymentProvider is an in-memory payment provider for local development and tests.
+// Nothing is charged. Webhooks are signed with HMAC-SHA256 of the raw body using the shared
+// secret, hex encoded; use Sign to simulate a callback.
+type FakePaymentProvider struct {
+	secret []byte
+
+	mu      sync.Mutex
+	intents map[string]*fakeIntent
+}
+
+func NewFakePaymentProvider(webhookSecret string) *FakePaymentProvider {
+	return &FakePaymentProvider{secret: []byte(webhookSecret), intents: make(map[string]*fakeIntent)}
+}
+
+func (p *FakePaymentProvider) CreateIntent(ctx context.Context, orderID string, amount int) (*models.PaymentIntent, error) {
+	if amount <= 0 {
+		return nil, fmt.Errorf("create intent for %s: amount must be positive", orderID)
+	}
+	id := "pi_fake_" + ulid.Make().String()
+
+	p.mu.Lock()
+	p.intents[id] = &fakeIntent{amount: amount}
+	p.mu.Unlock()
+
+	return &models.PaymentIntent{ID: id, Amount: amount, ClientSecret: id + "_secret"}, nil
+}
+
+func (p *FakePaymentProvider) Capture(ctx context.Context, intentID string, amount int) error {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	in, ok := p.intents[intentID]
+	if !ok {
+		return ErrPaymentIntentNotFound
+	}
+	if in.captured > 0 {
+		return nil
+	}
+	if amount > in.amount {
+		return fmt.Errorf("capture %s: %d exceeds the authorized %d", intentID, amount, in.amount)
+	}
+	in.captured = amount
+	return nil
+}
+
+func (p *FakePaymentProvider) Refund(ctx context.Context, intentID string, amount int, idempotencyKey string) error {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	in, ok := p.intents[intentID]
+	if !ok {
+		return ErrPaymentIntentNotFound
+	}
+	if in.refunds[idempotencyKey] {
+		return nil
+	}
+	if in.refunded+amount > in.captured {
+		return fmt.Errorf("refund %s: %d exceeds the refundable %d", intentID, amount, in.captured-in.refunded)
+	}
+	if in.refunds == nil {
+		in.refunds = make(map[string]bool)
+	}
+	in.refunds[idempotencyKey] = true
+	in.refunded += amount
+	return nil
+}
+
+func (p *FakePaymentProvider) VerifyWebhook(payload []byte, signature string) (*models.PaymentEvent, error) {
+	got, err := hex.DecodeString(signature)
+	if err != nil || !hmac.Equal(got, p.mac(payload)) {
+		return nil, ErrInvalidWebhookSignature
+	}
+
+	var ev models.PaymentEvent
+	if err := json.Unmarshal(payload, &ev); err != nil {
+		return nil, fmt.Errorf("decode payment event: %w", err)
+	}
+	return &ev, nil
+}
+
+// Sign returns the signature VerifyWebhook expects for payload.
+func (p *FakePaymentProvider) Sign(payload []byte) string {
+	return hex.EncodeToString(p.mac(payload))
+}
+
+func (p *FakePaymentProvider) mac(payload []byte) []byte {
+	m := hmac.New(sha256.New, p.secret)
+	m.Write(payload)
+	return m.Sum(nil)
+}
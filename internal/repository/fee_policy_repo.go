@@ -0,0 +1,102 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"uttc-hackathon-backend/internal/models"
+)
+
+type FeePolicyRepo struct {
+	db *sql.DB
+}
+
+func NewFeePolicyRepo(db *sql.DB) *FeePolicyRepo {
+	return &FeePolicyRepo{db: db}
+}
+
+// GetActiveFeePolicies returns every active policy, including promotions that have not
+// started or have already ended; callers match them against the time of sale.
+func (r *FeePolicyRepo) GetActiveFeePolicies(ctx context.Context) ([]*models.FeePolicy, error) {
+	query := `
+		SELECT id, name, category_id, seller_tier, rate_bps, min_fee, max_fee, rounding, priority, starts_at, ends_at
+		FROM fee_policies
+		WHERE active = TRUE
+	`
+	rows, err := r.db.QueryContext(ctx, query)
+	if err != nil {
+		return nil, fmt.Errorf("query fee policies: %w", err)
+	}
+	defer rows.Close()
+
+	var policies []*models.FeePolicy
+	for rows.Next() {
+		var p models.FeePolicy
+		var categoryID, sellerTier sql.NullString
+		var maxFee sql.NullInt64
+		var startsAt, endsAt sql.NullTime
+		if err := rows.Scan(
+			&p.ID, &p.Name, &categoryID, &sellerTier, &p.RateBps, &p.MinFee, &maxFee, &p.Rounding, &p.Priority, &startsAt, &endsAt,
+		); err != nil {
+			return nil, fmt.Errorf("scan fee policy: %w", err)
+		}
+		p.CategoryID = categoryID.String
+		p.SellerTier = models.SellerTier(sellerTier.String)
+		p.MaxFee = int(maxFee.Int64)
+		if startsAt.Valid {
+			p.StartsAt = &startsAt.Time
+		}
+		if endsAt.Valid {
+			p.EndsAt = &endsAt.Time
+		}
+		policies = append(policies, &p)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("iterate fee policies: %w", err)
+	}
+	return policies, nil
+}
+
+// GetFeeSubject returns the listing seller's tier and the listing's categories together with
+// their ancestors, so a policy on a parent category covers its subcategories.
+func (r *FeePolicyRepo) GetFeeSubject(ctx context.Context, listingID string) (*models.FeeSubject, error) {
+	var subject models.FeeSubject
+	queryTier := `
+		SELECT u.seller_tier
+		FROM listings l
+		JOIN users u ON u.id = l.seller_id
+		WHERE l.id = ?
+	`
+	if err := r.db.QueryRowContext(ctx, queryTier, listingID).Scan(&subject.SellerTier); err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, ErrListingNotFound
+		}
+		return nil, fmt.Errorf("get seller tier: %w", err)
+	}
+
+	queryCategories := `
+		SELECT DISTINCT anc.id
+		FROM listing_categories lc
+		JOIN categories c ON c.id = lc.category_id
+		JOIN categories anc ON c.path = anc.path OR c.path LIKE CONCAT(anc.path, '/%')
+		WHERE lc.listing_id = ?
+	`
+	rows, err := r.db.QueryContext(ctx, queryCategories, listingID)
+	if err != nil {
+		return nil, fmt.Errorf("query listing categories: %w", err)
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var id string
+		if err := rows.Scan(&id); err != nil {
+			return nil, fmt.Errorf("scan listing category: %w", err)
+		}
+		subject.CategoryIDs = append(subject.CategoryIDs, id)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("iterate listing categories: %w", err)
+	}
+	return &subject, nil
+}
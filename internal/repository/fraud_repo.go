@@ -0,0 +1,232 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"time"
+	"uttc-hackathon-backend/internal/models"
+)
+
+var ErrFraudReviewNotFound = errors.New("fraud review not found")
+
+type FraudRepo struct {
+	db *sql.DB
+}
+
+func NewFraudRepo(db *sql.DB) *FraudRepo {
+	return &FraudRepo{db: db}
+}
+
+// GetActiveFraudRules returns the rules that are switched on, in the order they are declared.
+func (r *FraudRepo) GetActiveFraudRules(ctx context.Context) ([]*models.FraudRule, error) {
+	query := `
+		SELECT rule, limit_value, window_minutes, max_account_age_hours
+		FROM fraud_rules
+		WHERE active = TRUE
+		ORDER BY rule
+	`
+	rows, err := r.db.QueryContext(ctx, query)
+	if err != nil {
+		return nil, fmt.Errorf("query fraud rules: %w", err)
+	}
+	defer rows.Close()
+
+	var rules []*models.FraudRule
+	for rows.Next() {
+		var rule models.FraudRule
+		var windowMinutes int
+		var maxAgeHours sql.NullInt64
+		if err := rows.Scan(&rule.Rule, &rule.Limit, &windowMinutes, &maxAgeHours); err != nil {
+			return nil, fmt.Errorf("scan fraud rule: %w", err)
+		}
+		rule.Window = time.Duration(windowMinutes) * time.Minute
+		rule.MaxAccountAge = time.Duration(maxAgeHours.Int64) * time.Hour
+		rules = append(rules, &rule)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("iterate fraud rules: %w", err)
+	}
+	return rules, nil
+}
+
+// GetOrderSubject returns the seller of the listing and the unit price of the variant, or of
+// the listing when the variant is not one of its own.
+func (r *FraudRepo) GetOrderSubject(ctx context.Context, listingID, variantID string) (string, int, error) {
+	query := `
+		SELECT l.seller_id, COALESCE(v.price, l.price)
+		FROM listings l
+		LEFT JOIN listing_variants v ON v.id = ? AND v.listing_id = l.id
+		WHERE l.id = ?
+	`
+	var sellerID string
+	var price int
+	if err := r.db.QueryRowContext(ctx, query, variantID, listingID).Scan(&sellerID, &price); err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return "", 0, ErrListingNotFound
+		}
+		return "", 0, fmt.Errorf("get order subject: %w", err)
+	}
+	return sellerID, price, nil
+}
+
+// GetFraudActivity measures the buyer's orders placed since the given time.
+func (r *FraudRepo) GetFraudActivity(ctx context.Context, buyerID, sellerID string, since time.Time) (*models.FraudActivity, error) {
+	query := `
+		SELECT
+			(SELECT created_at FROM users WHERE id = ?),
+			COUNT(*),
+			COALESCE(SUM(CASE
+				WHEN cancel_reason IS NULL OR cancel_reason NOT IN ('payment_failed', 'payment_expired') THEN total_price
+			END), 0),
+			COALESCE(SUM(seller_id = ?), 0)
+		FROM orders
+		WHERE buyer_id = ? AND created_at >= ?
+	`
+	var a models.FraudActivity
+	var createdAt sql.NullTime
+	err := r.db.QueryRowContext(ctx, query, buyerID, sellerID, buyerID, since).Scan(&createdAt, &a.Orders, &a.Spend, &a.SameSellerOrders)
+	if err != nil {
+		return nil, fmt.Errorf("get fraud activity: %w", err)
+	}
+	a.AccountCreatedAt = createdAt.Time
+	return &a, nil
+}
+
+const fraudReviewColumns = `id, buyer_id, seller_id, listing_id, rule, limit_value, observed_value, attempts, status,
+	reviewed_by, note, created_at, last_attempt_at, reviewed_at`
+
+func scanFraudReview(row rowScanner) (*models.FraudReview, error) {
+	var rv models.FraudReview
+	var reviewedBy sql.NullString
+	var reviewedAt sql.NullTime
+	if err := row.Scan(
+		&rv.ID, &rv.BuyerID, &rv.SellerID, &rv.ListingID, &rv.Rule, &rv.Limit, &rv.Observed, &rv.Attempts, &rv.Status,
+		&reviewedBy, &rv.Note, &rv.CreatedAt, &rv.LastAttemptAt, &reviewedAt,
+	); err != nil {
+		return nil, err
+	}
+	rv.ReviewedBy = reviewedBy.String
+	if reviewedAt.Valid {
+		rv.ReviewedAt = &reviewedAt.Time
+	}
+	return &rv, nil
+}
+
+// RecordFraudBlock adds the blocked order to the buyer's open review for the rule, or opens a
+// review if there is none. It returns the ID of the review.
+func (r *FraudRepo) RecordFraudBlock(ctx context.Context, rv *models.FraudReview) (string, error) {
+	tx, err := r.db.BeginTx(ctx, nil)
+	if err != nil {
+		return "", fmt.Errorf("begin tx: %w", err)
+	}
+	defer tx.Rollback()
+
+	queryOpen := `
+		SELECT id FROM fraud_reviews
+		WHERE buyer_id = ? AND rule = ? AND status = ?
+		LIMIT 1
+		FOR UPDATE
+	`
+	var id string
+	err = tx.QueryRowContext(ctx, queryOpen, rv.BuyerID, rv.Rule, models.FraudReviewStatusOpen).Scan(&id)
+	switch {
+	case errors.Is(err, sql.ErrNoRows):
+		queryInsert := `
+			INSERT INTO fraud_reviews (id, buyer_id, seller_id, listing_id, rule, limit_value, observed_value, created_at, last_attempt_at)
+			VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?)
+		`
+		_, err = tx.ExecContext(ctx, queryInsert,
+			rv.ID, rv.BuyerID, rv.SellerID, rv.ListingID, rv.Rule, rv.Limit, rv.Observed, rv.CreatedAt, rv.LastAttemptAt,
+		)
+		if err != nil {
+			return "", fmt.Errorf("insert fraud review: %w", err)
+		}
+		id = rv.ID
+	case err != nil:
+		return "", fmt.Errorf("get open fraud review: %w", err)
+	default:
+		queryUpdate := `
+			UPDATE fraud_reviews
+			SET seller_id = ?, listing_id = ?, limit_value = ?, observed_value = ?, attempts = attempts + 1, last_attempt_at = ?
+			WHERE id = ?
+		`
+		_, err = tx.ExecContext(ctx, queryUpdate, rv.SellerID, rv.ListingID, rv.Limit, rv.Observed, rv.LastAttemptAt, id)
+		if err != nil {
+			return "", fmt.Errorf("update fraud review: %w", err)
+		}
+	}
+
+	if err := tx.Commit(); err != nil {
+		return "", fmt.Errorf("commit tx: %w", err)
+	}
+	return id, nil
+}
+
+// GetFraudReviews returns reviews with the status, or all reviews when status is empty, oldest first.
+func (r *FraudRepo) GetFraudReviews(ctx context.Context, status models.FraudReviewStatus, limit, offset int) ([]*models.FraudReview, error) {
+	query := `
+		SELECT ` + fraudReviewColumns + `
+		FROM fraud_reviews
+		WHERE ? = '' OR status = ?
+		ORDER BY created_at, id
+		LIMIT ? OFFSET ?
+	`
+	rows, err := r.db.QueryContext(ctx, query, status, status, limit, offset)
+	if err != nil {
+		return nil, fmt.Errorf("query fraud reviews: %w", err)
+	}
+	defer rows.Close()
+
+	var reviews []*models.FraudReview
+	for rows.Next() {
+		rv, err := scanFraudReview(rows)
+		if err != nil {
+			return nil, fmt.Errorf("scan fraud review: %w", err)
+		}
+		reviews = append(reviews, rv)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("iterate fraud reviews: %w", err)
+	}
+	return reviews, nil
+}
+
+// UpdateFraudReview locks the review, lets fn change it and saves the decision. Nothing is
+// written if fn fails.
+func (r *FraudRepo) UpdateFraudReview(ctx context.Context, reviewID string, fn func(*models.FraudReview) error) (*models.FraudReview, error) {
+	tx, err := r.db.BeginTx(ctx, nil)
+	if err != nil {
+		return nil, fmt.Errorf("begin tx: %w", err)
+	}
+	defer tx.Rollback()
+
+	query := `SELECT ` + fraudReviewColumns + ` FROM fraud_reviews WHERE id = ? FOR UPDATE`
+	rv, err := scanFraudReview(tx.QueryRowContext(ctx, query, reviewID))
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, ErrFraudReviewNotFound
+		}
+		return nil, fmt.Errorf("get fraud review for update: %w", err)
+	}
+
+	if err := fn(rv); err != nil {
+		return nil, err
+	}
+
+	queryUpdate := `
+		UPDATE fraud_reviews
+		SET status = ?, reviewed_by = ?, note = ?, reviewed_at = ?
+		WHERE id = ?
+	`
+	_, err = tx.ExecContext(ctx, queryUpdate, rv.Status, nullIfEmpty(rv.ReviewedBy), rv.Note, rv.ReviewedAt, rv.ID)
+	if err != nil {
+		return nil, fmt.Errorf("update fraud review: %w", err)
+	}
+
+	if err := tx.Commit(); err != nil {
+		return nil, fmt.Errorf("commit tx: %w", err)
+	}
+	return rv, nil
+}
@@ -0,0 +1,109 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"time"
+	"uttc-hackathon-backend/internal/models"
+)
+
+type IdempotencyRepo struct {
+	db *sql.DB
+}
+
+func NewIdempotencyRepo(db *sql.DB) *IdempotencyRepo {
+	return &IdempotencyRepo{db: db}
+}
+
+const idempotencyColumns = `
+	user_id, idem_key, fingerprint, status, response_status, response_content_type, response_body, created_at`
+
+func scanIdempotencyRecord(row rowScanner) (*models.IdempotencyRecord, error) {
+	var rec models.IdempotencyRecord
+	var status sql.NullInt64
+	var contentType sql.NullString
+	if err := row.Scan(
+		&rec.UserID, &rec.Key, &rec.Fingerprint, &rec.Status, &status, &contentType, &rec.ResponseBody, &rec.CreatedAt,
+	); err != nil {
+		return nil, err
+	}
+	rec.ResponseStatus = int(status.Int64)
+	rec.ResponseContentType = contentType.String
+	return &rec, nil
+}
+
+// ReserveIdempotencyKey claims the key for a new request. It returns nil when the key was
+// free (or only used before expiredBefore), and otherwise the record of the earlier request.
+func (r *IdempotencyRepo) ReserveIdempotencyKey(ctx context.Context, userID, key, fingerprint string, expiredBefore time.Time) (*models.IdempotencyRecord, error) {
+	tx, err := r.db.BeginTx(ctx, &sql.TxOptions{Isolation: sql.LevelReadCommitted})
+	if err != nil {
+		return nil, fmt.Errorf("begin tx: %w", err)
+	}
+	defer tx.Rollback()
+
+	query := `SELECT ` + idempotencyColumns + ` FROM idempotency_keys WHERE user_id = ? AND idem_key = ? FOR UPDATE`
+	rec, err := scanIdempotencyRecord(tx.QueryRowContext(ctx, query, userID, key))
+	switch {
+	case err == nil && rec.CreatedAt.After(expiredBefore):
+		return rec, nil
+	case err == nil:
+		if _, err := tx.ExecContext(ctx, `DELETE FROM idempotency_keys WHERE user_id = ? AND idem_key = ?`, userID, key); err != nil {
+			return nil, fmt.Errorf("delete expired idempotency key: %w", err)
+		}
+	case !errors.Is(err, sql.ErrNoRows):
+		return nil, fmt.Errorf("get idempotency key: %w", err)
+	}
+
+	queryInsert := `INSERT INTO idempotency_keys (user_id, idem_key, fingerprint, status) VALUES (?, ?, ?, ?)`
+	_, err = tx.ExecContext(ctx, queryInsert, userID, key, fingerprint, models.IdempotencyStatusInFlight)
+	if err != nil {
+		if isDuplicateEntry(err) {
+			// A concurrent request claimed the key first
+			tx.Rollback()
+			rec, err := scanIdempotencyRecord(r.db.QueryRowContext(ctx, `SELECT `+idempotencyColumns+` FROM idempotency_keys WHERE user_id = ? AND idem_key = ?`, userID, key))
+			if err != nil {
+				return nil, fmt.Errorf("get idempotency key: %w", err)
+			}
+			return rec, nil
+		}
+		return nil, fmt.Errorf("insert idempotency key: %w", err)
+	}
+
+	if err := tx.Commit(); err != nil {
+		return nil, fmt.Errorf("commit tx: %w", err)
+	}
+	return nil, nil
+}
+
+// CompleteIdempotencyKey stores the response of the request holding the key.
+func (r *IdempotencyRepo) CompleteIdempotencyKey(ctx context.Context, userID, key string, status int, contentType string, body []byte) error {
+	query := `
+		UPDATE idempotency_keys
+		SET status = ?, response_status = ?, response_content_type = ?, response_body = ?
+		WHERE user_id = ? AND idem_key = ?
+	`
+	_, err := r.db.ExecContext(ctx, query, models.IdempotencyStatusCompleted, status, nullIfEmpty(contentType), body, userID, key)
+	if err != nil {
+		return fmt.Errorf("complete idempotency key: %w", err)
+	}
+	return nil
+}
+
+// ReleaseIdempotencyKey frees a key whose request failed so it can be retried.
+func (r *IdempotencyRepo) ReleaseIdempotencyKey(ctx context.Context, userID, key string) error {
+	query := `DELETE FROM idempotency_keys WHERE user_id = ? AND idem_key = ? AND status = ?`
+	if _, err := r.db.ExecContext(ctx, query, userID, key, models.IdempotencyStatusInFlight); err != nil {
+		return fmt.Errorf("release idempotency key: %w", err)
+	}
+	return nil
+}
+
+// PurgeIdempotencyKeys deletes keys created before the given time.
+func (r *IdempotencyRepo) PurgeIdempotencyKeys(ctx context.Context, before time.Time) error {
+	if _, err := r.db.ExecContext(ctx, `DELETE FROM idempotency_keys WHERE created_at < ?`, before); err != nil {
+		return fmt.Errorf("purge idempotency keys: %w", err)
+	}
+	return nil
+}
@@ -0,0 +1,49 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"time"
+)
+
+// LeaseRepo hands out named leases so only one instance runs a background job at a time.
+// Expiry is judged by the database clock, so instances need not agree on the time.
+type LeaseRepo struct {
+	db *sql.DB
+}
+
+func NewLeaseRepo(db *sql.DB) *LeaseRepo {
+	return &LeaseRepo{db: db}
+}
+
+// AcquireLease takes or renews the lease for holder for ttl. It reports false when another
+// holder's lease has not expired yet.
+func (r *LeaseRepo) AcquireLease(ctx context.Context, name, holder string, ttl time.Duration) (bool, error) {
+	// MySQL applies the assignments in order, so expires_at sees the new holder
+	query := `
+		INSERT INTO job_leases (name, holder, expires_at)
+		VALUES (?, ?, NOW(3) + INTERVAL ? MICROSECOND)
+		ON DUPLICATE KEY UPDATE
+			holder = IF(expires_at <= NOW(3) OR holder = VALUES(holder), VALUES(holder), holder),
+			expires_at = IF(holder = VALUES(holder), VALUES(expires_at), expires_at)
+	`
+	if _, err := r.db.ExecContext(ctx, query, name, holder, ttl.Microseconds()); err != nil {
+		return false, fmt.Errorf("acquire lease %s: %w", name, err)
+	}
+
+	var current string
+	if err := r.db.QueryRowContext(ctx, `SELECT holder FROM job_leases WHERE name = ?`, name).Scan(&current); err != nil {
+		return false, fmt.Errorf("get lease %s: %w", name, err)
+	}
+	return current == holder, nil
+}
+
+// ReleaseLease gives up holder's lease so another instance can take it right away.
+func (r *LeaseRepo) ReleaseLease(ctx context.Context, name, holder string) error {
+	query := `DELETE FROM job_leases WHERE name = ? AND holder = ?`
+	if _, err := r.db.ExecContext(ctx, query, name, holder); err != nil {
+		return fmt.Errorf("release lease %s: %w", name, err)
+	}
+	return nil
+}
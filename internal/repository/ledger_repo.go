@@ -0,0 +1,322 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"uttc-hackathon-backend/internal/models"
+)
+
+type LedgerRepo struct {
+	db *sql.DB
+}
+
+func NewLedgerRepo(db *sql.DB) *LedgerRepo {
+	return &LedgerRepo{db: db}
+}
+
+var (
+	ErrBankAccountNotFound = errors.New("no bank account registered")
+	ErrPayoutNotFound      = errors.New("payout not found")
+	// ErrLedgerUnbalanced means a transaction's entries do not sum to zero.
+	ErrLedgerUnbalanced = errors.New("ledger transaction does not balance")
+)
+
+// queryRower is satisfied by both *sql.DB and *sql.Tx.
+type queryRower interface {
+	QueryContext(ctx context.Context, query string, args ...any) (*sql.Rows, error)
+}
+
+// postLedgerTransaction writes the transaction and its entries within tx. The entries must
+// sum to zero.
+func postLedgerTransaction(ctx context.Context, tx *sql.Tx, lt *models.LedgerTransaction) error {
+	sum := 0
+	for _, e := range lt.Entries {
+		sum += e.Amount
+	}
+	if sum != 0 || len(lt.Entries) == 0 {
+		return fmt.Errorf("%w: %s", ErrLedgerUnbalanced, lt.Type)
+	}
+
+	query := `INSERT INTO ledger_transactions (type, order_id, payout_id, refund_id, created_at) VALUES (?, ?, ?, ?, ?)`
+	res, err := tx.ExecContext(ctx, query, lt.Type, nullIfEmpty(lt.OrderID), nullIfEmpty(lt.PayoutID), nullIfEmpty(lt.RefundID), lt.CreatedAt)
+	if err != nil {
+		return fmt.Errorf("insert ledger transaction: %w", err)
+	}
+	id, err := res.LastInsertId()
+	if err != nil {
+		return fmt.Errorf("insert ledger transaction: %w", err)
+	}
+	lt.ID = id
+
+	queryEntry := `
+		INSERT INTO ledger_entries (transaction_id, account, user_id, amount, created_at)
+		VALUES (?, ?, ?, ?, ?)
+	`
+	for i := range lt.Entries {
+		e := &lt.Entries[i]
+		if _, err := tx.ExecContext(ctx, queryEntry, id, e.Account, nullIfEmpty(e.UserID), e.Amount, lt.CreatedAt); err != nil {
+			return fmt.Errorf("insert ledger entry: %w", err)
+		}
+		e.TransactionID = id
+	}
+	return nil
+}
+
+func sellerBalance(ctx context.Context, q queryRower, userID string) (*models.SellerBalance, error) {
+	query := `
+		SELECT account, COALESCE(SUM(amount), 0)
+		FROM ledger_entries
+		WHERE user_id = ?
+		GROUP BY account
+	`
+	rows, err := q.QueryContext(ctx, query, userID)
+	if err != nil {
+		return nil, fmt.Errorf("query balance: %w", err)
+	}
+	defer rows.Close()
+
+	var b models.SellerBalance
+	for rows.Next() {
+		var account models.LedgerAccount
+		var amount int
+		if err := rows.Scan(&account, &amount); err != nil {
+			return nil, fmt.Errorf("scan balance: %w", err)
+		}
+		switch account {
+		case models.LedgerAccountSellerPending:
+			b.Pending = amount
+		case models.LedgerAccountSellerAvailable:
+			b.Available = amount
+		case models.LedgerAccountSellerPayout:
+			b.InPayout = amount
+		case models.LedgerAccountSellerPaidOut:
+			b.PaidOut = amount
+		}
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("iterate balance: %w", err)
+	}
+	return &b, nil
+}
+
+// GetSellerBalance sums the seller's ledger entries per account.
+func (r *LedgerRepo) GetSellerBalance(ctx context.Context, userID string) (*models.SellerBalance, error) {
+	return sellerBalance(ctx, r.db, userID)
+}
+
+// GetLedgerEntries returns the seller's entries, newest first, with the transaction each
+// belongs to. Refund entries carry the refunded order too.
+func (r *LedgerRepo) GetLedgerEntries(ctx context.Context, userID string, limit, offset int) ([]*models.LedgerEntry, error) {
+	query := `
+		SELECT e.id, e.transaction_id, e.account, e.user_id, e.amount, t.type,
+		       COALESCE(t.order_id, rf.order_id), t.payout_id, t.refund_id, e.created_at
+		FROM ledger_entries e
+		JOIN ledger_transactions t ON t.id = e.transaction_id
+		LEFT JOIN refunds rf ON rf.id = t.refund_id
+		WHERE e.user_id = ?
+		ORDER BY e.created_at DESC, e.id DESC
+		LIMIT ? OFFSET ?
+	`
+	rows, err := r.db.QueryContext(ctx, query, userID, limit, offset)
+	if err != nil {
+		return nil, fmt.Errorf("query ledger entries: %w", err)
+	}
+	defer rows.Close()
+
+	var entries []*models.LedgerEntry
+	for rows.Next() {
+		var e models.LedgerEntry
+		var entryUserID, orderID, payoutID, refundID sql.NullString
+		if err := rows.Scan(&e.ID, &e.TransactionID, &e.Account, &entryUserID, &e.Amount, &e.Type, &orderID, &payoutID, &refundID, &e.CreatedAt); err != nil {
+			return nil, fmt.Errorf("scan ledger entry: %w", err)
+		}
+		e.UserID = entryUserID.String
+		e.OrderID = orderID.String
+		e.PayoutID = payoutID.String
+		e.RefundID = refundID.String
+		entries = append(entries, &e)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("iterate ledger entries: %w", err)
+	}
+	return entries, nil
+}
+
+const bankAccountColumns = `user_id, bank_code, branch_code, account_type, account_number, account_holder, updated_at`
+
+func scanBankAccount(row rowScanner) (*models.BankAccount, error) {
+	var a models.BankAccount
+	if err := row.Scan(&a.UserID, &a.BankCode, &a.BranchCode, &a.AccountType, &a.AccountNumber, &a.AccountHolder, &a.UpdatedAt); err != nil {
+		return nil, err
+	}
+	return &a, nil
+}
+
+func (r *LedgerRepo) GetBankAccount(ctx context.Context, userID string) (*models.BankAccount, error) {
+	query := `SELECT ` + bankAccountColumns + ` FROM bank_accounts WHERE user_id = ?`
+	a, err := scanBankAccount(r.db.QueryRowContext(ctx, query, userID))
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, ErrBankAccountNotFound
+		}
+		return nil, fmt.Errorf("get bank account: %w", err)
+	}
+	return a, nil
+}
+
+// SaveBankAccount registers the user's bank account, replacing any previous one.
+func (r *LedgerRepo) SaveBankAccount(ctx context.Context, a *models.BankAccount) error {
+	query := `
+		INSERT INTO bank_accounts (user_id, bank_code, branch_code, account_type, account_number, account_holder, updated_at)
+		VALUES (?, ?, ?, ?, ?, ?, ?)
+		ON DUPLICATE KEY UPDATE
+			bank_code = VALUES(bank_code), branch_code = VALUES(branch_code), account_type = VALUES(account_type),
+			account_number = VALUES(account_number), account_holder = VALUES(account_holder), updated_at = VALUES(updated_at)
+	`
+	_, err := r.db.ExecContext(ctx, query, a.UserID, a.BankCode, a.BranchCode, a.AccountType, a.AccountNumber, a.AccountHolder, a.UpdatedAt)
+	if err != nil {
+		return fmt.Errorf("save bank account: %w", err)
+	}
+	return nil
+}
+
+const payoutColumns = `
+	id, user_id, amount, status, bank_code, branch_code, account_type, account_number, account_holder,
+	processed_by, processed_at, created_at`
+
+func scanPayout(row rowScanner) (*models.Payout, error) {
+	var p models.Payout
+	var processedBy sql.NullString
+	var processedAt sql.NullTime
+	if err := row.Scan(
+		&p.ID, &p.UserID, &p.Amount, &p.Status, &p.BankCode, &p.BranchCode, &p.AccountType, &p.AccountNumber, &p.AccountHolder,
+		&processedBy, &processedAt, &p.CreatedAt,
+	); err != nil {
+		return nil, err
+	}
+	p.ProcessedBy = processedBy.String
+	if processedAt.Valid {
+		p.ProcessedAt = &processedAt.Time
+	}
+	return &p, nil
+}
+
+// CreatePayout locks the seller's bank account, which serialises payout requests per seller,
+// and lets fn check the balance and build the payout and its ledger transaction. Both are
+// stored atomically.
+func (r *LedgerRepo) CreatePayout(ctx context.Context, userID string, fn func(*models.SellerBalance, *models.BankAccount) (*models.Payout, *models.LedgerTransaction, error)) error {
+	tx, err := r.db.BeginTx(ctx, &sql.TxOptions{Isolation: sql.LevelReadCommitted})
+	if err != nil {
+		return fmt.Errorf("begin tx: %w", err)
+	}
+	defer tx.Rollback()
+
+	queryBank := `SELECT ` + bankAccountColumns + ` FROM bank_accounts WHERE user_id = ? FOR UPDATE`
+	bank, err := scanBankAccount(tx.QueryRowContext(ctx, queryBank, userID))
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return ErrBankAccountNotFound
+		}
+		return fmt.Errorf("get bank account for update: %w", err)
+	}
+
+	balance, err := sellerBalance(ctx, tx, userID)
+	if err != nil {
+		return err
+	}
+
+	p, lt, err := fn(balance, bank)
+	if err != nil {
+		return err
+	}
+
+	query := `
+		INSERT INTO payouts (id, user_id, amount, status, bank_code, branch_code, account_type, account_number, account_holder, created_at)
+		VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?)
+	`
+	_, err = tx.ExecContext(ctx, query,
+		p.ID, p.UserID, p.Amount, p.Status, p.BankCode, p.BranchCode, p.AccountType, p.AccountNumber, p.AccountHolder, p.CreatedAt,
+	)
+	if err != nil {
+		return fmt.Errorf("insert payout: %w", err)
+	}
+
+	if err := postLedgerTransaction(ctx, tx, lt); err != nil {
+		return err
+	}
+
+	if err := tx.Commit(); err != nil {
+		return fmt.Errorf("commit tx: %w", err)
+	}
+	return nil
+}
+
+// UpdatePayout locks the payout, lets fn change its status and build the ledger transaction
+// that records the change, and saves both atomically.
+func (r *LedgerRepo) UpdatePayout(ctx context.Context, payoutID string, fn func(*models.Payout) (*models.LedgerTransaction, error)) error {
+	tx, err := r.db.BeginTx(ctx, &sql.TxOptions{Isolation: sql.LevelReadCommitted})
+	if err != nil {
+		return fmt.Errorf("begin tx: %w", err)
+	}
+	defer tx.Rollback()
+
+	queryPayout := `SELECT ` + payoutColumns + ` FROM payouts WHERE id = ? FOR UPDATE`
+	p, err := scanPayout(tx.QueryRowContext(ctx, queryPayout, payoutID))
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return ErrPayoutNotFound
+		}
+		return fmt.Errorf("get payout for update: %w", err)
+	}
+
+	lt, err := fn(p)
+	if err != nil {
+		return err
+	}
+
+	query := `UPDATE payouts SET status = ?, processed_by = ?, processed_at = ? WHERE id = ?`
+	if _, err := tx.ExecContext(ctx, query, p.Status, nullIfEmpty(p.ProcessedBy), p.ProcessedAt, p.ID); err != nil {
+		return fmt.Errorf("update payout: %w", err)
+	}
+
+	if err := postLedgerTransaction(ctx, tx, lt); err != nil {
+		return err
+	}
+
+	if err := tx.Commit(); err != nil {
+		return fmt.Errorf("commit tx: %w", err)
+	}
+	return nil
+}
+
+// GetPayouts returns payouts, newest first, optionally filtered by seller and status.
+// Empty filters match everything.
+func (r *LedgerRepo) GetPayouts(ctx context.Context, userID string, status models.PayoutStatus, limit, offset int) ([]*models.Payout, error) {
+	query := `
+		SELECT ` + payoutColumns + `
+		FROM payouts
+		WHERE (? = '' OR user_id = ?) AND (? = '' OR status = ?)
+		ORDER BY created_at DESC, id DESC
+		LIMIT ? OFFSET ?
+	`
+	rows, err := r.db.QueryContext(ctx, query, userID, userID, status, status, limit, offset)
+	if err != nil {
+		return nil, fmt.Errorf("query payouts: %w", err)
+	}
+	defer rows.Close()
+
+	var payouts []*models.Payout
+	for rows.Next() {
+		p, err := scanPayout(rows)
+		if err != nil {
+			return nil, fmt.Errorf("scan payout: %w", err)
+		}
+		payouts = append(payouts, p)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("iterate payouts: %w", err)
+	}
+	return payouts, nil
+}
@@ -6,6 +6,7 @@ import (
 	"encoding/json"
 	"errors"
 	"fmt"
+	"strings"
 	"uttc-hackathon-backend/internal/models"
 )
 
@@ -17,109 +18,226 @@ func NewListingRepo(db *sql.DB) *ListingRepo {
 	return &ListingRepo{db: db}
 }
 
-func (r *ListingRepo) GetListingsFeed(ctx context.Context, limit, offset int) ([]*models.Listing, error) {
-	query := `
-		SELECT id, seller_id, title, description, images, price, quantity, status, item_condition, created_at, updated_at
-		FROM listings
-		WHERE status = 'active'
-		ORDER BY created_at DESC
-		LIMIT ? OFFSET ?
-	`
+// rowScanner is implemented by both *sql.Row and *sql.Rows.
+type rowScanner interface {
+	Scan(dest ...any) error
+}
+
+// querier is implemented by both *sql.DB and *sql.Tx.
+type querier interface {
+	QueryContext(ctx context.Context, query string, args ...any) (*sql.Rows, error)
+}
+
+// listingColumns is the column list read by scanListing. Queries must alias listings as l.
+const listingColumns = `
+	l.id, l.seller_id, l.title, l.description, l.images, l.price, l.tax_rate, l.quantity, l.status, l.item_condition,
+	l.shipping_payer, l.shipping_method, l.shipping_size, l.ship_from_prefecture, l.days_to_ship,
+	(SELECT u.invoice_registration_number FROM users u WHERE u.id = l.seller_id),
+	l.created_at, l.updated_at`
+
+func scanListing(row rowScanner) (*models.Listing, error) {
+	var l models.Listing
+	var imagesJSON []byte
+	var shippingMethod, shippingSize, daysToShip, sellerInvoiceNumber sql.NullString
+	var prefecture sql.NullInt64
+
+	err := row.Scan(
+		&l.ID,
+		&l.SellerID,
+		&l.Title,
+		&l.Description,
+		&imagesJSON,
+		&l.Price,
+		&l.TaxRate,
+		&l.Quantity,
+		&l.Status,
+		&l.ItemCondition,
+		&l.ShippingPayer,
+		&shippingMethod,
+		&shippingSize,
+		&prefecture,
+		&daysToShip,
+		&sellerInvoiceNumber,
+		&l.CreatedAt,
+		&l.UpdatedAt,
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := json.Unmarshal(imagesJSON, &l.Images); err != nil {
+		return nil, fmt.Errorf("unmarshal listing images: %w", err)
+	}
+	l.ShippingMethod = models.ShippingMethod(shippingMethod.String)
+	l.ShippingSize = models.ShippingSize(shippingSize.String)
+	l.ShipFromPrefecture = int(prefecture.Int64)
+	l.DaysToShip = models.DaysToShip(daysToShip.String)
+	l.SellerInvoiceNumber = sellerInvoiceNumber.String
 
-	rows, err := r.db.QueryContext(ctx, query, limit, offset)
+	return &l, nil
+}
+
+func (r *ListingRepo) queryListings(ctx context.Context, query string, args ...any) ([]*models.Listing, error) {
+	rows, err := r.db.QueryContext(ctx, query, args...)
 	if err != nil {
-		return nil, fmt.Errorf("query listings feed: %w", err)
+		return nil, fmt.Errorf("query listings: %w", err)
 	}
 	defer rows.Close()
 
 	var listings []*models.Listing
 	for rows.Next() {
-		var l models.Listing
-		var imagesJSON []byte
-
-		err := rows.Scan(
-			&l.ID,
-			&l.SellerID,
-			&l.Title,
-			&l.Description,
-			&imagesJSON,
-			&l.Price,
-			&l.Quantity,
-			&l.Status,
-			&l.ItemCondition,
-			&l.CreatedAt,
-			&l.UpdatedAt,
-		)
+		l, err := scanListing(rows)
 		if err != nil {
 			return nil, fmt.Errorf("scan listing: %w", err)
 		}
-
-		if err := json.Unmarshal(imagesJSON, &l.Images); err != nil {
-			return nil, fmt.Errorf("unmarshal listing images: %w", err)
-		}
-
-		listings = append(listings, &l)
+		listings = append(listings, l)
 	}
 
 	if err := rows.Err(); err != nil {
 		return nil, fmt.Errorf("iterate listings rows: %w", err)
 	}
 
+	if err := loadVariants(ctx, r.db, listings, false); err != nil {
+		return nil, err
+	}
 	return listings, nil
 }
 
+// loadVariants fills in the variants of the given listings with one query. With forUpdate the
+// variant rows are locked, which requires q to be a transaction.
+func loadVariants(ctx context.Context, q querier, listings []*models.Listing, forUpdate bool) error {
+	if len(listings) == 0 {
+		return nil
+	}
+
+	byID := make(map[string]*models.Listing, len(listings))
+	args := make([]any, 0, len(listings))
+	for _, l := range listings {
+		byID[l.ID] = l
+		args = append(args, l.ID)
+	}
+
+	query := `
+		SELECT id, listing_id, label, price, quantity
+		FROM listing_variants
+		WHERE listing_id IN (` + strings.TrimSuffix(strings.Repeat("?,", len(listings)), ",") + `)
+		ORDER BY listing_id, position
+	`
+	if forUpdate {
+		query += " FOR UPDATE"
+	}
+
+	rows, err := q.QueryContext(ctx, query, args...)
+	if err != nil {
+		return fmt.Errorf("query listing variants: %w", err)
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var v models.ListingVariant
+		var listingID string
+		var price sql.NullInt64
+		if err := rows.Scan(&v.ID, &listingID, &v.Label, &price, &v.Quantity); err != nil {
+			return fmt.Errorf("scan listing variant: %w", err)
+		}
+		v.Price = int(price.Int64)
+		if l := byID[listingID]; l != nil {
+			l.Variants = append(l.Variants, v)
+		}
+	}
+	if err := rows.Err(); err != nil {
+		return fmt.Errorf("iterate listing variants: %w", err)
+	}
+	return nil
+}
+
+// nullIfEmpty stores optional enum values as NULL.
+func nullIfEmpty[T ~string](v T) sql.NullString {
+	return sql.NullString{String: string(v), Valid: v != ""}
+}
+
+func nullIfZero(v int) sql.NullInt64 {
+	return sql.NullInt64{Int64: int64(v), Valid: v != 0}
+}
+
+func (r *ListingRepo) GetListingsFeed(ctx context.Context, limit, offset int) ([]*models.Listing, error) {
+	query := `
+		SELECT ` + listingColumns + `
+		FROM listings l
+		JOIN users u ON u.id = l.seller_id
+		WHERE l.status = 'active' AND u.status = 'active'
+		ORDER BY l.created_at DESC
+		LIMIT ? OFFSET ?
+	`
+	return r.queryListings(ctx, query, limit, offset)
+}
+
 func (r *ListingRepo) CreateListing(ctx context.Context, l *models.Listing) error {
 	imagesJSON, err := json.Marshal(l.Images)
 	if err != nil {
 		return fmt.Errorf("marshal listing images: %w", err)
 	}
 
+	tx, err := r.db.BeginTx(ctx, nil)
+	if err != nil {
+		return fmt.Errorf("begin tx: %w", err)
+	}
+	defer tx.Rollback()
+
 	query := `
-		INSERT INTO listings (id, seller_id, title, description, images, price, quantity, status, item_condition)
-		VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?)
+		INSERT INTO listings (
+			id, seller_id, title, description, images, price, tax_rate, quantity, status, item_condition,
+			shipping_payer, shipping_method, shipping_size, ship_from_prefecture, days_to_ship
+		) VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?)
 	`
 
-	_, err = r.db.ExecContext(ctx, query,
+	_, err = tx.ExecContext(ctx, query,
 		l.ID,
 		l.SellerID,
 		l.Title,
 		l.Description,
 		imagesJSON,
 		l.Price,
+		l.TaxRate,
 		l.Quantity,
 		l.Status,
 		l.ItemCondition,
+		l.ShippingPayer,
+		nullIfEmpty(l.ShippingMethod),
+		nullIfEmpty(l.ShippingSize),
+		nullIfZero(l.ShipFromPrefecture),
+		nullIfEmpty(l.DaysToShip),
 	)
 	if err != nil {
 		return fmt.Errorf("insert listing: %w", err)
 	}
 
+	if len(l.Variants) > 0 {
+		placeholders := strings.TrimSuffix(strings.Repeat("(?, ?, ?, ?, ?, ?),", len(l.Variants)), ",")
+		args := make([]any, 0, 6*len(l.Variants))
+		for i, v := range l.Variants {
+			args = append(args, v.ID, l.ID, v.Label, nullIfZero(v.Price), v.Quantity, i)
+		}
+		queryVariants := `INSERT INTO listing_variants (id, listing_id, label, price, quantity, position) VALUES ` + placeholders
+		if _, err := tx.ExecContext(ctx, queryVariants, args...); err != nil {
+			return fmt.Errorf("insert listing variants: %w", err)
+		}
+	}
+
+	if err := tx.Commit(); err != nil {
+		return fmt.Errorf("commit tx: %w", err)
+	}
 	return nil
 }
 
 func (r *ListingRepo) GetListing(ctx context.Context, id string) (*models.Listing, error) {
 	query := `
-		SELECT id, seller_id, title, description, images, price, quantity, status, item_condition, created_at, updated_at
-		FROM listings
-		WHERE id = ?
+		SELECT ` + listingColumns + `
+		FROM listings l
+		WHERE l.id = ?
 	`
 
-	var l models.Listing
-	var imagesJSON []byte
-
-	err := r.db.QueryRowContext(ctx, query, id).Scan(
-		&l.ID,
-		&l.SellerID,
-		&l.Title,
-		&l.Description,
-		&imagesJSON,
-		&l.Price,
-		&l.Quantity,
-		&l.Status,
-		&l.ItemCondition,
-		&l.CreatedAt,
-		&l.UpdatedAt,
-	)
+	l, err := scanListing(r.db.QueryRowContext(ctx, query, id))
 	if err != nil {
 		if errors.Is(err, sql.ErrNoRows) {
 			return nil, nil // Or nil, sql.ErrNoRows - service will handle
@@ -127,9 +245,45 @@ func (r *ListingRepo) GetListing(ctx context.Context, id string) (*models.Listin
 		return nil, fmt.Errorf("get listing: %w", err)
 	}
 
-	if err := json.Unmarshal(imagesJSON, &l.Images); err != nil {
-		return nil, fmt.Errorf("unmarshal listing images: %w", err)
+	if err := loadVariants(ctx, r.db, []*models.Listing{l}, false); err != nil {
+		return nil, err
 	}
+	return l, nil
+}
 
-	return &l, nil
+func (r *ListingRepo) UpdateListingStatus(ctx context.Context, id string, status models.ListingStatus) error {
+	query := `UPDATE listings SET status = ? WHERE id = ?`
+	if _, err := r.db.ExecContext(ctx, query, status, id); err != nil {
+		return fmt.Errorf("update listing status: %w", err)
+	}
+	return nil
+}
+
+func (r *ListingRepo) CreateScreening(ctx context.Context, sc *models.ListingScreening) error {
+	categoriesJSON, err := json.Marshal(sc.Categories)
+	if err != nil {
+		return fmt.Errorf("marshal screening categories: %w", err)
+	}
+
+	query := `
+		INSERT INTO listing_screenings (id, listing_id, flagged, source, categories, reason)
+		VALUES (?, ?, ?, ?, ?, ?)
+	`
+	_, err = r.db.ExecContext(ctx, query, sc.ID, sc.ListingID, sc.Flagged, sc.Source, categoriesJSON, sc.Reason)
+	if err != nil {
+		return fmt.Errorf("insert listing screening: %w", err)
+	}
+	return nil
+}
+
+// GetListingsByStatus returns listings in the given status, oldest first.
+func (r *ListingRepo) GetListingsByStatus(ctx context.Context, status models.ListingStatus, limit, offset int) ([]*models.Listing, error) {
+	query := `
+		SELECT ` + listingColumns + `
+		FROM listings l
+		WHERE l.status = ?
+		ORDER BY l.created_at ASC
+		LIMIT ? OFFSET ?
+	`
+	return r.queryListings(ctx, query, status, limit, offset)
 }
@@ -3,8 +3,12 @@ package repository
 import (
 	"context"
 	"database/sql"
+	"errors"
 	"fmt"
+	"strings"
 	"uttc-hackathon-backend/internal/models"
+
+	"github.com/go-sql-driver/mysql"
 )
 
 type MessageRepository struct {
@@ -15,32 +19,61 @@ func NewMessageRepository(db *sql.DB) *MessageRepository {
 	return &MessageRepository{db: db}
 }
 
+// mysqlErrNoReferencedRow is the MySQL error number for a foreign key pointing at a missing row.
+const mysqlErrNoReferencedRow = 1452
+
+const messageColumns = `id, sender_id, receiver_id, order_id, listing_id, content, created_at`
+
+func scanMessage(rows *sql.Rows) (*models.Message, error) {
+	var m models.Message
+	var orderID, listingID sql.NullString
+	if err := rows.Scan(&m.ID, &m.SenderID, &m.ReceiverID, &orderID, &listingID, &m.Content, &m.CreatedAt); err != nil {
+		return nil, err
+	}
+	m.OrderID = orderID.String
+	m.ListingID = listingID.String
+	return &m, nil
+}
+
+// CreateMessage saves the message. It fails with ErrListingNotFound if the message is about
+// a listing that does not exist.
 func (r *MessageRepository) CreateMessage(ctx context.Context, m *models.Message) error {
 	query := `
-		INSERT INTO messages (id, sender_id, receiver_id, content)
-		VALUES (?, ?, ?, ?)
+		INSERT INTO messages (id, sender_id, receiver_id, order_id, listing_id, content)
+		VALUES (?, ?, ?, ?, ?, ?)
 	`
 	_, err := r.db.ExecContext(ctx, query,
 		m.ID,
 		m.SenderID,
 		m.ReceiverID,
+		nullIfEmpty(m.OrderID),
+		nullIfEmpty(m.ListingID),
 		m.Content,
 	)
 	if err != nil {
+		var mysqlErr *mysql.MySQLError
+		if errors.As(err, &mysqlErr) && mysqlErr.Number == mysqlErrNoReferencedRow &&
+			strings.Contains(mysqlErr.Message, "fk_messages_listing") {
+			return ErrListingNotFound
+		}
 		return fmt.Errorf("insert message: %w", err)
 	}
 	return nil
 }
 
-func (r *MessageRepository) GetMessages(ctx context.Context, userID, otherUserID string) ([]*models.Message, error) {
+// GetMessages returns the direct messages between the two users, or their messages about the
+// listing when listingID is set, oldest first. Messages about orders are left out.
+func (r *MessageRepository) GetMessages(ctx context.Context, userID, otherUserID, listingID string) ([]*models.Message, error) {
 	query := `
-		SELECT id, sender_id, receiver_id, content, created_at
+		SELECT ` + messageColumns + `
 		FROM messages
-		WHERE (sender_id = ? AND receiver_id = ?)
-		   OR (sender_id = ? AND receiver_id = ?)
-		ORDER BY created_at ASC
+		WHERE ((sender_id = ? AND receiver_id = ?)
+		   OR (sender_id = ? AND receiver_id = ?))
+		  AND order_id IS NULL
+		  AND listing_id <=> ?
+		ORDER BY created_at ASC, id ASC
 	`
-	rows, err := r.db.QueryContext(ctx, query, userID, otherUserID, otherUserID, userID)
+	rows, err := r.db.QueryContext(ctx, query, userID, otherUserID, otherUserID, userID, nullIfEmpty(listingID))
 	if err != nil {
 		return nil, fmt.Errorf("query messages: %w", err)
 	}
@@ -48,11 +81,11 @@ func (r *MessageRepository) GetMessages(ctx context.Context, userID, otherUserID
 
 	var messages []*models.Message
 	for rows.Next() {
-		var m models.Message
-		if err := rows.Scan(&m.ID, &m.SenderID, &m.ReceiverID, &m.Content, &m.CreatedAt); err != nil {
+		m, err := scanMessage(rows)
+		if err != nil {
 			return nil, fmt.Errorf("scan message: %w", err)
 		}
-		messages = append(messages, &m)
+		messages = append(messages, m)
 	}
 	if err := rows.Err(); err != nil {
 		return nil, fmt.Errorf("rows error: %w", err)
@@ -60,16 +93,45 @@ func (r *MessageRepository) GetMessages(ctx context.Context, userID, otherUserID
 	return messages, nil
 }
 
-// GetLatestIncomingMessages returns the latest message from each person who sent something to the user
+// GetOrderMessages returns the messages about the order, oldest first.
+func (r *MessageRepository) GetOrderMessages(ctx context.Context, orderID string) ([]*models.Message, error) {
+	query := `
+		SELECT ` + messageColumns + `
+		FROM messages
+		WHERE order_id = ?
+		ORDER BY created_at ASC, id ASC
+	`
+	rows, err := r.db.QueryContext(ctx, query, orderID)
+	if err != nil {
+		return nil, fmt.Errorf("query order messages: %w", err)
+	}
+	defer rows.Close()
+
+	var messages []*models.Message
+	for rows.Next() {
+		m, err := scanMessage(rows)
+		if err != nil {
+			return nil, fmt.Errorf("scan order message: %w", err)
+		}
+		messages = append(messages, m)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("rows order messages error: %w", err)
+	}
+	return messages, nil
+}
+
+// GetLatestIncomingMessages returns the latest message from each person who sent something to the user,
+// per order or listing they were about
 func (r *MessageRepository) GetLatestIncomingMessages(ctx context.Context, userID string) ([]*models.Message, error) {
 	qIncoming := `
-		SELECT id, sender_id, receiver_id, content, created_at
+		SELECT ` + messageColumns + `
 		FROM messages
 		WHERE id IN (
 			SELECT MAX(id)
 			FROM messages
 			WHERE receiver_id = ?
-			GROUP BY sender_id
+			GROUP BY sender_id, order_id, listing_id
 		)
 	`
 	rows, err := r.db.QueryContext(ctx, qIncoming, userID)
@@ -80,11 +142,11 @@ func (r *MessageRepository) GetLatestIncomingMessages(ctx context.Context, userI
 
 	var messages []*models.Message
 	for rows.Next() {
-		var m models.Message
-		if err := rows.Scan(&m.ID, &m.SenderID, &m.ReceiverID, &m.Content, &m.CreatedAt); err != nil {
+		m, err := scanMessage(rows)
+		if err != nil {
 			return nil, fmt.Errorf("scan incoming: %w", err)
 		}
-		messages = append(messages, &m)
+		messages = append(messages, m)
 	}
 	if err := rows.Err(); err != nil {
 		return nil, fmt.Errorf("rows incoming error: %w", err)
@@ -92,16 +154,17 @@ func (r *MessageRepository) GetLatestIncomingMessages(ctx context.Context, userI
 	return messages, nil
 }
 
-// GetLatestOutgoingMessages returns the latest message sending to each person
+// GetLatestOutgoingMessages returns the latest message sending to each person, per order or listing
+// they were about
 func (r *MessageRepository) GetLatestOutgoingMessages(ctx context.Context, userID string) ([]*models.Message, error) {
 	qOutgoing := `
-		SELECT id, sender_id, receiver_id, content, created_at
+		SELECT ` + messageColumns + `
 		FROM messages
 		WHERE id IN (
 			SELECT MAX(id)
 			FROM messages
 			WHERE sender_id = ?
-			GROUP BY receiver_id
+			GROUP BY receiver_id, order_id, listing_id
 		)
 	`
 	rows, err := r.db.QueryContext(ctx, qOutgoing, userID)
@@ -112,11 +175,11 @@ func (r *MessageRepository) GetLatestOutgoingMessages(ctx context.Context, userI
 
 	var messages []*models.Message
 	for rows.Next() {
-		var m models.Message
-		if err := rows.Scan(&m.ID, &m.SenderID, &m.ReceiverID, &m.Content, &m.CreatedAt); err != nil {
+		m, err := scanMessage(rows)
+		if err != nil {
 			return nil, fmt.Errorf("scan outgoing: %w", err)
 		}
-		messages = append(messages, &m)
+		messages = append(messages, m)
 	}
 	if err := rows.Err(); err != nil {
 		return nil, fmt.Errorf("rows outgoing error: %w", err)
@@ -0,0 +1,81 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"uttc-hackathon-backend/internal/models"
+)
+
+type NotificationRepo struct {
+	db *sql.DB
+}
+
+func NewNotificationRepo(db *sql.DB) *NotificationRepo {
+	return &NotificationRepo{db: db}
+}
+
+var ErrNotificationNotFound = errors.New("notification not found")
+
+// GetNotifications returns the user's notifications, newest first.
+func (r *NotificationRepo) GetNotifications(ctx context.Context, userID string, limit, offset int) ([]*models.Notification, error) {
+	query := `
+		SELECT id, user_id, type, payload, read_at, created_at
+		FROM notifications
+		WHERE user_id = ?
+		ORDER BY created_at DESC
+		LIMIT ? OFFSET ?
+	`
+	rows, err := r.db.QueryContext(ctx, query, userID, limit, offset)
+	if err != nil {
+		return nil, fmt.Errorf("query notifications: %w", err)
+	}
+	defer rows.Close()
+
+	var notifications []*models.Notification
+	for rows.Next() {
+		var n models.Notification
+		var payload []byte
+		var readAt sql.NullTime
+		if err := rows.Scan(&n.ID, &n.UserID, &n.Type, &payload, &readAt, &n.CreatedAt); err != nil {
+			return nil, fmt.Errorf("scan notification: %w", err)
+		}
+		n.Payload = payload
+		if readAt.Valid {
+			n.ReadAt = &readAt.Time
+		}
+		notifications = append(notifications, &n)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("iterate notifications: %w", err)
+	}
+	return notifications, nil
+}
+
+// MarkNotificationRead sets read_at on the user's notification. Notifications of other users are reported as not found.
+func (r *NotificationRepo) MarkNotificationRead(ctx context.Context, id, userID string) error {
+	query := `UPDATE notifications SET read_at = CURRENT_TIMESTAMP WHERE id = ? AND user_id = ? AND read_at IS NULL`
+	res, err := r.db.ExecContext(ctx, query, id, userID)
+	if err != nil {
+		return fmt.Errorf("mark notification read: %w", err)
+	}
+	n, err := res.RowsAffected()
+	if err != nil {
+		return fmt.Errorf("mark notification read: %w", err)
+	}
+	if n > 0 {
+		return nil
+	}
+
+	// Nothing changed: either it was already read or it is not the user's notification
+	var exists bool
+	queryExists := `SELECT EXISTS (SELECT 1 FROM notifications WHERE id = ? AND user_id = ?)`
+	if err := r.db.QueryRowContext(ctx, queryExists, id, userID).Scan(&exists); err != nil {
+		return fmt.Errorf("check notification: %w", err)
+	}
+	if !exists {
+		return ErrNotificationNotFound
+	}
+	return nil
+}
@@ -0,0 +1,77 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"fmt"
+	"uttc-hackathon-backend/internal/models"
+)
+
+// insertOrderEvent appends ev to the order's timeline. It must run in the transaction that
+// made the change, so the timeline never disagrees with the order. An empty type is taken
+// from the new status, or is "created" when there was no previous status.
+func insertOrderEvent(ctx context.Context, tx *sql.Tx, ev *models.OrderEvent) error {
+	if ev.Type == "" {
+		ev.Type = models.OrderEventType(ev.ToStatus)
+		if ev.FromStatus == "" {
+			ev.Type = models.OrderEventCreated
+		}
+	}
+	var metadata []byte
+	if len(ev.Metadata) > 0 {
+		var err error
+		if metadata, err = json.Marshal(ev.Metadata); err != nil {
+			return fmt.Errorf("marshal order event metadata: %w", err)
+		}
+	}
+
+	query := `
+		INSERT INTO order_events (order_id, type, actor_id, from_status, to_status, metadata)
+		VALUES (?, ?, ?, ?, ?, ?)
+	`
+	_, err := tx.ExecContext(ctx, query,
+		ev.OrderID, ev.Type, nullIfEmpty(ev.ActorID), nullIfEmpty(ev.FromStatus), ev.ToStatus, metadata,
+	)
+	if err != nil {
+		return fmt.Errorf("insert order event: %w", err)
+	}
+	return nil
+}
+
+// GetOrderEvents returns the order's timeline, oldest first.
+func (r *OrderRepo) GetOrderEvents(ctx context.Context, orderID string) ([]*models.OrderEvent, error) {
+	query := `
+		SELECT id, order_id, type, actor_id, from_status, to_status, metadata, created_at
+		FROM order_events
+		WHERE order_id = ?
+		ORDER BY id
+	`
+	rows, err := r.db.QueryContext(ctx, query, orderID)
+	if err != nil {
+		return nil, fmt.Errorf("query order events: %w", err)
+	}
+	defer rows.Close()
+
+	events := []*models.OrderEvent{}
+	for rows.Next() {
+		var ev models.OrderEvent
+		var actorID, fromStatus sql.NullString
+		var metadata []byte
+		if err := rows.Scan(&ev.ID, &ev.OrderID, &ev.Type, &actorID, &fromStatus, &ev.ToStatus, &metadata, &ev.CreatedAt); err != nil {
+			return nil, fmt.Errorf("scan order event: %w", err)
+		}
+		ev.ActorID = actorID.String
+		ev.FromStatus = models.OrderStatus(fromStatus.String)
+		if len(metadata) > 0 {
+			if err := json.Unmarshal(metadata, &ev.Metadata); err != nil {
+				return nil, fmt.Errorf("unmarshal order event metadata: %w", err)
+			}
+		}
+		events = append(events, &ev)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("iterate order events: %w", err)
+	}
+	return events, nil
+}
@@ -3,9 +3,10 @@ package repository
 import (
 	"context"
 	"database/sql"
-	"encoding/json"
 	"errors"
 	"fmt"
+	"strings"
+	"time"
 	"uttc-hackathon-backend/internal/models"
 )
 
@@ -20,8 +21,61 @@ func NewOrderRepo(db *sql.DB) *OrderRepo {
 var (
 	ErrListingNotFound = errors.New("listing not found or not active")
 	ErrOrderNotFound   = errors.New("order not found")
+	// ErrOrderVersionConflict means the order changed since it was read.
+	ErrOrderVersionConflict = errors.New("order was modified concurrently, please retry")
 )
 
+// orderColumns is the column list read by scanOrder.
+const orderColumns = `
+	id, buyer_id, seller_id, seller_invoice_number, listing_id, variant_id, variant_label, listing_title, listing_main_image,
+	listing_price, item_tax_rate, quantity, shipping_payer, shipping_method, shipping_size, shipping_fee,
+	total_price, standard_rate_total, standard_rate_tax, reduced_rate_total, reduced_rate_tax, platform_fee, net_payout, refunded_amount, refunded_quantity, fee_policy_id, status, version, payment_intent_id, payment_expires_at,
+	delivered_at, completed_at, cancelled_by, cancel_reason, cancel_note, cancelled_at, created_at, updated_at`
+
+// statusTimestampsUpdate stamps delivered_at and completed_at when an order status update
+// moves it to delivered or completed. It must follow the status assignment, which MySQL
+// applies first.
+const statusTimestampsUpdate = `delivered_at = IF(status = 'delivered' AND delivered_at IS NULL, CURRENT_TIMESTAMP, delivered_at),
+	completed_at = IF(status = 'completed' AND completed_at IS NULL, CURRENT_TIMESTAMP, completed_at)`
+
+func scanOrder(row rowScanner) (*models.Order, error) {
+	var o models.Order
+	var sellerInvoiceNumber, variantID, variantLabel, shippingMethod, shippingSize sql.NullString
+	var feePolicyID, paymentIntentID, cancelledBy, cancelReason, cancelNote sql.NullString
+	var paymentExpiresAt, deliveredAt, completedAt, cancelledAt sql.NullTime
+	if err := row.Scan(
+		&o.ID, &o.BuyerID, &o.SellerID, &sellerInvoiceNumber, &o.ListingID, &variantID, &variantLabel, &o.ListingTitle, &o.ListingMainImage,
+		&o.ListingPrice, &o.ItemTaxRate, &o.Quantity, &o.ShippingPayer, &shippingMethod, &shippingSize, &o.ShippingFee,
+		&o.TotalPrice, &o.Tax.StandardRateTotal, &o.Tax.StandardRateTax, &o.Tax.ReducedRateTotal, &o.Tax.ReducedRateTax, &o.PlatformFee, &o.NetPayout, &o.RefundedAmount, &o.RefundedQuantity, &feePolicyID, &o.Status, &o.Version, &paymentIntentID, &paymentExpiresAt,
+		&deliveredAt, &completedAt, &cancelledBy, &cancelReason, &cancelNote, &cancelledAt, &o.CreatedAt, &o.UpdatedAt,
+	); err != nil {
+		return nil, err
+	}
+	o.SellerInvoiceNumber = sellerInvoiceNumber.String
+	o.VariantID = variantID.String
+	o.VariantLabel = variantLabel.String
+	o.ShippingMethod = models.ShippingMethod(shippingMethod.String)
+	o.ShippingSize = models.ShippingSize(shippingSize.String)
+	o.FeePolicyID = feePolicyID.String
+	o.PaymentIntentID = paymentIntentID.String
+	if paymentExpiresAt.Valid {
+		o.PaymentExpiresAt = &paymentExpiresAt.Time
+	}
+	if deliveredAt.Valid {
+		o.DeliveredAt = &deliveredAt.Time
+	}
+	if completedAt.Valid {
+		o.CompletedAt = &completedAt.Time
+	}
+	o.CancelledBy = cancelledBy.String
+	o.CancelReason = models.CancelReason(cancelReason.String)
+	o.CancelNote = cancelNote.String
+	if cancelledAt.Valid {
+		o.CancelledAt = &cancelledAt.Time
+	}
+	return &o, nil
+}
+
 // CreateOrder updates listing and creates order atomically preventing race conditions
 func (r *OrderRepo) CreateOrder(ctx context.Context, listingID string, fn func(*models.Listing) (*models.Order, error)) error {
 	tx, err := r.db.BeginTx(ctx, &sql.TxOptions{Isolation: sql.LevelReadCommitted})
@@ -30,126 +84,576 @@ func (r *OrderRepo) CreateOrder(ctx context.Context, listingID string, fn func(*
 	}
 	defer tx.Rollback()
 
-	// LOCK Listing
-	queryGet := `
-		SELECT id, seller_id, title, description, images, price, quantity, status, item_condition, created_at, updated_at
-		FROM listings
-		WHERE id = ?
-		FOR UPDATE
-	`
-	var l models.Listing
-	var imagesJSON []byte
+	l, err := lockListing(ctx, tx, listingID)
+	if err != nil {
+		return err
+	}
 
-	err = tx.QueryRowContext(ctx, queryGet, listingID).Scan(
-		&l.ID, &l.SellerID, &l.Title, &l.Description, &imagesJSON, &l.Price, &l.Quantity, &l.Status, &l.ItemCondition, &l.CreatedAt, &l.UpdatedAt,
-	)
+	o, err := fn(l)
 	if err != nil {
-		if errors.Is(err, sql.ErrNoRows) {
-			return ErrListingNotFound
-		}
-		return fmt.Errorf("get listing for update: %w", err)
+		return err
 	}
 
-	// Parse images
-	if err := json.Unmarshal(imagesJSON, &l.Images); err != nil {
-		return fmt.Errorf("unmarshal images: %w", err)
+	if err := saveListingStock(ctx, tx, l); err != nil {
+		return err
 	}
 
-	o, err := fn(&l)
+	queryInsert := `
+		INSERT INTO orders (
+			id, buyer_id, seller_id, seller_invoice_number, listing_id, variant_id, variant_label, listing_title, listing_main_image,
+			listing_price, item_tax_rate, quantity, shipping_payer, shipping_method, shipping_size, shipping_fee,
+			total_price, standard_rate_total, standard_rate_tax, reduced_rate_total, reduced_rate_tax,
+			platform_fee, net_payout, fee_policy_id, status, payment_expires_at
+		) VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?)
+	`
+	_, err = tx.ExecContext(ctx, queryInsert,
+		o.ID, o.BuyerID, o.SellerID, nullIfEmpty(o.SellerInvoiceNumber), o.ListingID, nullIfEmpty(o.VariantID), nullIfEmpty(o.VariantLabel), o.ListingTitle, o.ListingMainImage,
+		o.ListingPrice, o.ItemTaxRate, o.Quantity, o.ShippingPayer, nullIfEmpty(o.ShippingMethod), nullIfEmpty(o.ShippingSize), o.ShippingFee,
+		o.TotalPrice, o.Tax.StandardRateTotal, o.Tax.StandardRateTax, o.Tax.ReducedRateTotal, o.Tax.ReducedRateTax,
+		o.PlatformFee, o.NetPayout, nullIfEmpty(o.FeePolicyID), o.Status, o.PaymentExpiresAt,
+	)
 	if err != nil {
+		return fmt.Errorf("insert order: %w", err)
+	}
+
+	ev := &models.OrderEvent{
+		OrderID:  o.ID,
+		ActorID:  o.BuyerID,
+		ToStatus: o.Status,
+		Metadata: map[string]any{"quantity": o.Quantity, "total_price": o.TotalPrice},
+	}
+	if err := insertOrderEvent(ctx, tx, ev); err != nil {
 		return err
 	}
 
-	newImagesJSON, err := json.Marshal(l.Images)
+	if err := tx.Commit(); err != nil {
+		return fmt.Errorf("commit tx: %w", err)
+	}
+
+	return nil
+}
+
+// lockListing reads the listing and its variants with row locks held until tx ends.
+func lockListing(ctx context.Context, tx *sql.Tx, listingID string) (*models.Listing, error) {
+	query := `
+		SELECT ` + listingColumns + `
+		FROM listings l
+		WHERE l.id = ?
+		FOR UPDATE
+	`
+	l, err := scanListing(tx.QueryRowContext(ctx, query, listingID))
 	if err != nil {
-		return fmt.Errorf("marshal images: %w", err)
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, ErrListingNotFound
+		}
+		return nil, fmt.Errorf("get listing for update: %w", err)
 	}
+	if err := loadVariants(ctx, tx, []*models.Listing{l}, true); err != nil {
+		return nil, err
+	}
+	return l, nil
+}
 
-	queryUpdate := `
-		UPDATE listings
-		SET title = ?, description = ?, images = ?, price = ?, quantity = ?, status = ?, item_condition = ?
+// lockOrder reads the order with a row lock held until tx ends.
+func lockOrder(ctx context.Context, tx *sql.Tx, orderID string) (*models.Order, error) {
+	query := `
+		SELECT ` + orderColumns + `
+		FROM orders
 		WHERE id = ?
+		FOR UPDATE
 	`
-	_, err = tx.ExecContext(ctx, queryUpdate,
-		l.Title, l.Description, newImagesJSON, l.Price,
-		l.Quantity, l.Status, l.ItemCondition,
-		listingID,
-	)
+	o, err := scanOrder(tx.QueryRowContext(ctx, query, orderID))
 	if err != nil {
-		return fmt.Errorf("update listing: %w", err)
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, ErrOrderNotFound
+		}
+		return nil, fmt.Errorf("get order for update: %w", err)
 	}
+	return o, nil
+}
 
-	queryInsert := `
-		INSERT INTO orders (
-			id, buyer_id, seller_id, listing_id, listing_title, listing_main_image,
-			listing_price, quantity, total_price, platform_fee, net_payout, status
-		) VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?)
+// saveOrderStatus writes the status, cancellation fields and refund totals (effective fee,
+// payout and refunded amount and quantity) of an order locked by lockOrder, bumps its
+// version and appends ev to its timeline. ev carries the status before the change, the
+// actor and any metadata; the order and new status are filled in here.
+func saveOrderStatus(ctx context.Context, tx *sql.Tx, o *models.Order, ev *models.OrderEvent) error {
+	query := `
+		UPDATE orders
+		SET status = ?, ` + statusTimestampsUpdate + `, version = version + 1,
+		    cancelled_by = ?, cancel_reason = ?, cancel_note = ?, cancelled_at = ?,
+		    platform_fee = ?, net_payout = ?, refunded_amount = ?, refunded_quantity = ?
+		WHERE id = ?
 	`
-	_, err = tx.ExecContext(ctx, queryInsert,
-		o.ID, o.BuyerID, o.SellerID, o.ListingID, o.ListingTitle, o.ListingMainImage,
-		o.ListingPrice, o.Quantity, o.TotalPrice, o.PlatformFee, o.NetPayout, o.Status,
+	_, err := tx.ExecContext(ctx, query,
+		o.Status, nullIfEmpty(o.CancelledBy), nullIfEmpty(o.CancelReason), nullIfEmpty(o.CancelNote), o.CancelledAt,
+		o.PlatformFee, o.NetPayout, o.RefundedAmount, o.RefundedQuantity, o.ID,
 	)
 	if err != nil {
-		return fmt.Errorf("insert order: %w", err)
+		return fmt.Errorf("update order status: %w", err)
+	}
+
+	ev.OrderID = o.ID
+	ev.ToStatus = o.Status
+	return insertOrderEvent(ctx, tx, ev)
+}
+
+// saveListingStock writes back the quantity and status of a listing locked by lockListing,
+// and the quantity of each of its variants.
+func saveListingStock(ctx context.Context, tx *sql.Tx, l *models.Listing) error {
+	query := `UPDATE listings SET quantity = ?, status = ? WHERE id = ?`
+	if _, err := tx.ExecContext(ctx, query, l.Quantity, l.Status, l.ID); err != nil {
+		return fmt.Errorf("update listing: %w", err)
+	}
+
+	for _, v := range l.Variants {
+		queryVariant := `UPDATE listing_variants SET quantity = ? WHERE id = ? AND listing_id = ?`
+		if _, err := tx.ExecContext(ctx, queryVariant, v.Quantity, v.ID, l.ID); err != nil {
+			return fmt.Errorf("update listing variant: %w", err)
+		}
+	}
+	return nil
+}
+
+// CancelOrder locks the order and its listing, lets fn apply the cancellation to both, and
+// saves the result atomically. fn may restore stock on the listing; it must set the order's
+// status and cancellation fields. The refund and ledger transaction fn returns, if any, are
+// saved in the same transaction.
+func (r *OrderRepo) CancelOrder(ctx context.Context, orderID string, fn func(*models.Order, *models.Listing) (*models.Refund, *models.LedgerTransaction, error)) error {
+	tx, err := r.db.BeginTx(ctx, &sql.TxOptions{Isolation: sql.LevelReadCommitted})
+	if err != nil {
+		return fmt.Errorf("begin tx: %w", err)
+	}
+	defer tx.Rollback()
+
+	o, err := lockOrder(ctx, tx, orderID)
+	if err != nil {
+		return err
+	}
+	statusBefore := o.Status
+
+	l, err := lockListing(ctx, tx, o.ListingID)
+	if err != nil {
+		return err
+	}
+
+	rf, lt, err := fn(o, l)
+	if err != nil {
+		return err
+	}
+
+	if err := saveListingStock(ctx, tx, l); err != nil {
+		return err
+	}
+
+	if rf != nil {
+		if err := insertRefund(ctx, tx, rf); err != nil {
+			return err
+		}
+	}
+
+	ev := &models.OrderEvent{FromStatus: statusBefore, Metadata: map[string]any{"reason": o.CancelReason}}
+	if o.CancelledBy != models.CancelledBySystem {
+		ev.ActorID = o.CancelledBy
+	}
+	if o.CancelNote != "" {
+		ev.Metadata["note"] = o.CancelNote
+	}
+	if rf != nil {
+		ev.Metadata["refund_id"] = rf.ID
+		ev.Metadata["refund_amount"] = rf.Amount
+	}
+	if err := saveOrderStatus(ctx, tx, o, ev); err != nil {
+		return err
+	}
+
+	if lt != nil {
+		if err := postLedgerTransaction(ctx, tx, lt); err != nil {
+			return err
+		}
 	}
 
 	if err := tx.Commit(); err != nil {
 		return fmt.Errorf("commit tx: %w", err)
 	}
-
 	return nil
 }
 
 func (r *OrderRepo) GetOrder(ctx context.Context, orderID string) (*models.Order, error) {
 	query := `
-		SELECT id, buyer_id, seller_id, listing_id, listing_title, listing_main_image,
-			listing_price, quantity, total_price, platform_fee, net_payout, status, created_at, updated_at
+		SELECT ` + orderColumns + `
 		FROM orders
 		WHERE id = ?
 	`
-	var o models.Order
-	err := r.db.QueryRowContext(ctx, query, orderID).Scan(
-		&o.ID, &o.BuyerID, &o.SellerID, &o.ListingID, &o.ListingTitle, &o.ListingMainImage,
-		&o.ListingPrice, &o.Quantity, &o.TotalPrice, &o.PlatformFee, &o.NetPayout, &o.Status,
-		&o.CreatedAt, &o.UpdatedAt,
-	)
+	o, err := scanOrder(r.db.QueryRowContext(ctx, query, orderID))
 	if err != nil {
 		if errors.Is(err, sql.ErrNoRows) {
 			return nil, ErrOrderNotFound
 		}
 		return nil, fmt.Errorf("get order: %w", err)
 	}
-	return &o, nil
+	return o, nil
 }
 
-func (r *OrderRepo) GetOrdersByUserID(ctx context.Context, userID string) ([]*models.Order, error) {
-	query := `
-		SELECT id, buyer_id, seller_id, listing_id, listing_title, listing_main_image,
-			listing_price, quantity, total_price, platform_fee, net_payout, status, created_at, updated_at
-		FROM orders
-		WHERE buyer_id = ? OR seller_id = ?
-		ORDER BY created_at DESC
-	`
-	rows, err := r.db.QueryContext(ctx, query, userID, userID)
+// GetOrdersByUserID returns one page of the user's orders matching the filter, newest first.
+// Without a role the buyer and seller sides are read separately and merged, so each side
+// can use its own index instead of an OR across two columns.
+func (r *OrderRepo) GetOrdersByUserID(ctx context.Context, userID string, f models.OrderFilter) ([]*models.Order, error) {
+	var query string
+	var args []any
+	switch f.Role {
+	case models.OrderRoleBuyer, models.OrderRoleSeller:
+		where, whereArgs := orderFilterWhere(f.Role, userID, f)
+		query = `
+			SELECT ` + orderColumns + `
+			FROM orders
+			WHERE ` + where + `
+			ORDER BY created_at DESC, id DESC
+			LIMIT ? OFFSET ?
+		`
+		args = append(whereArgs, f.Limit, f.Offset)
+	default:
+		// Each side needs at most offset+limit rows for the merged page
+		buyerWhere, buyerArgs := orderFilterWhere(models.OrderRoleBuyer, userID, f)
+		sellerWhere, sellerArgs := orderFilterWhere(models.OrderRoleSeller, userID, f)
+		query = `
+			SELECT * FROM (
+				(SELECT ` + orderColumns + ` FROM orders WHERE ` + buyerWhere + `
+				 ORDER BY created_at DESC, id DESC LIMIT ?)
+				UNION ALL
+				(SELECT ` + orderColumns + ` FROM orders WHERE ` + sellerWhere + `
+				 ORDER BY created_at DESC, id DESC LIMIT ?)
+			) o
+			ORDER BY created_at DESC, id DESC
+			LIMIT ? OFFSET ?
+		`
+		args = append(buyerArgs, f.Offset+f.Limit)
+		args = append(args, sellerArgs...)
+		args = append(args, f.Offset+f.Limit, f.Limit, f.Offset)
+	}
+
+	rows, err := r.db.QueryContext(ctx, query, args...)
 	if err != nil {
 		return nil, fmt.Errorf("query orders: %w", err)
 	}
 	defer rows.Close()
 
-	var orders []*models.Order
+	orders := []*models.Order{}
 	for rows.Next() {
-		var o models.Order
-		if err := rows.Scan(
-			&o.ID, &o.BuyerID, &o.SellerID, &o.ListingID, &o.ListingTitle, &o.ListingMainImage,
-			&o.ListingPrice, &o.Quantity, &o.TotalPrice, &o.PlatformFee, &o.NetPayout, &o.Status,
-			&o.CreatedAt, &o.UpdatedAt,
-		); err != nil {
+		o, err := scanOrder(rows)
+		if err != nil {
 			return nil, fmt.Errorf("scan order: %w", err)
 		}
-		orders = append(orders, &o)
+		orders = append(orders, o)
 	}
 	if err := rows.Err(); err != nil {
 		return nil, fmt.Errorf("iterate orders: %w", err)
 	}
 	return orders, nil
 }
+
+// orderFilterWhere builds the conditions for the user's orders on one side of the sale.
+// A user is never both buyer and seller of an order, so the two sides do not overlap.
+func orderFilterWhere(role models.OrderRole, userID string, f models.OrderFilter) (string, []any) {
+	conds := []string{"seller_id = ?"}
+	if role == models.OrderRoleBuyer {
+		conds[0] = "buyer_id = ?"
+	}
+	args := []any{userID}
+	if len(f.Statuses) > 0 {
+		conds = append(conds, "status IN (?"+strings.Repeat(", ?", len(f.Statuses)-1)+")")
+		for _, st := range f.Statuses {
+			args = append(args, st)
+		}
+	}
+	if !f.CreatedFrom.IsZero() {
+		conds = append(conds, "created_at >= ?")
+		args = append(args, f.CreatedFrom)
+	}
+	if !f.CreatedTo.IsZero() {
+		conds = append(conds, "created_at < ?")
+		args = append(args, f.CreatedTo)
+	}
+	return strings.Join(conds, " AND "), args
+}
+
+// CountOrdersByStatus counts the user's orders per status on the given side, or on both
+// sides when role is empty. Statuses without orders are absent from the map.
+func (r *OrderRepo) CountOrdersByStatus(ctx context.Context, userID string, role models.OrderRole) (map[models.OrderStatus]int, error) {
+	var query string
+	var args []any
+	switch role {
+	case models.OrderRoleBuyer:
+		query = `SELECT status, COUNT(*) FROM orders WHERE buyer_id = ? GROUP BY status`
+		args = []any{userID}
+	case models.OrderRoleSeller:
+		query = `SELECT status, COUNT(*) FROM orders WHERE seller_id = ? GROUP BY status`
+		args = []any{userID}
+	default:
+		query = `
+			SELECT status, COUNT(*) FROM (
+				SELECT status FROM orders WHERE buyer_id = ?
+				UNION ALL
+				SELECT status FROM orders WHERE seller_id = ?
+			) o
+			GROUP BY status
+		`
+		args = []any{userID, userID}
+	}
+
+	rows, err := r.db.QueryContext(ctx, query, args...)
+	if err != nil {
+		return nil, fmt.Errorf("count orders: %w", err)
+	}
+	defer rows.Close()
+
+	counts := make(map[models.OrderStatus]int)
+	for rows.Next() {
+		var status models.OrderStatus
+		var n int
+		if err := rows.Scan(&status, &n); err != nil {
+			return nil, fmt.Errorf("scan order count: %w", err)
+		}
+		counts[status] = n
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("iterate order counts: %w", err)
+	}
+	return counts, nil
+}
+
+// UpdateOrderStatus changes the status only if the order is still at the given version,
+// bumps the version and records the change by actorID, or by the platform when actorID is
+// empty. ErrOrderVersionConflict is returned when another change won. A non-nil ledger
+// transaction is posted atomically with the change.
+func (r *OrderRepo) UpdateOrderStatus(ctx context.Context, orderID string, version int, status models.OrderStatus, actorID string, lt *models.LedgerTransaction) error {
+	tx, err := r.db.BeginTx(ctx, &sql.TxOptions{Isolation: sql.LevelReadCommitted})
+	if err != nil {
+		return fmt.Errorf("begin tx: %w", err)
+	}
+	defer tx.Rollback()
+
+	o, err := lockOrder(ctx, tx, orderID)
+	if err != nil {
+		return err
+	}
+	if o.Version != version {
+		return ErrOrderVersionConflict
+	}
+
+	ev := &models.OrderEvent{FromStatus: o.Status, ActorID: actorID}
+	o.Status = status
+	if err := saveOrderStatus(ctx, tx, o, ev); err != nil {
+		return err
+	}
+
+	if lt != nil {
+		if err := postLedgerTransaction(ctx, tx, lt); err != nil {
+			return err
+		}
+	}
+
+	if err := tx.Commit(); err != nil {
+		return fmt.Errorf("commit tx: %w", err)
+	}
+	return nil
+}
+
+// updateOrderWithLedger runs a compare-and-set update on one order and, when it wins, records
+// ev and posts lt in the same transaction.
+func (r *OrderRepo) updateOrderWithLedger(ctx context.Context, query string, args []any, ev *models.OrderEvent, lt *models.LedgerTransaction, op string) error {
+	tx, err := r.db.BeginTx(ctx, &sql.TxOptions{Isolation: sql.LevelReadCommitted})
+	if err != nil {
+		return fmt.Errorf("begin tx: %w", err)
+	}
+	defer tx.Rollback()
+
+	res, err := tx.ExecContext(ctx, query, args...)
+	if err != nil {
+		return fmt.Errorf("%s: %w", op, err)
+	}
+	n, err := res.RowsAffected()
+	if err != nil {
+		return fmt.Errorf("%s: %w", op, err)
+	}
+	if n == 0 {
+		return ErrOrderVersionConflict
+	}
+
+	if err := insertOrderEvent(ctx, tx, ev); err != nil {
+		return err
+	}
+
+	if lt != nil {
+		if err := postLedgerTransaction(ctx, tx, lt); err != nil {
+			return err
+		}
+	}
+
+	if err := tx.Commit(); err != nil {
+		return fmt.Errorf("commit tx: %w", err)
+	}
+	return nil
+}
+
+// SetPaymentIntent links the order to the payment started with the provider.
+func (r *OrderRepo) SetPaymentIntent(ctx context.Context, orderID, intentID string) error {
+	query := `UPDATE orders SET payment_intent_id = ? WHERE id = ?`
+	res, err := r.db.ExecContext(ctx, query, intentID, orderID)
+	if err != nil {
+		return fmt.Errorf("set payment intent: %w", err)
+	}
+	n, err := res.RowsAffected()
+	if err != nil {
+		return fmt.Errorf("set payment intent: %w", err)
+	}
+	if n == 0 {
+		return ErrOrderNotFound
+	}
+	return nil
+}
+
+func (r *OrderRepo) GetOrderByPaymentIntent(ctx context.Context, intentID string) (*models.Order, error) {
+	query := `
+		SELECT ` + orderColumns + `
+		FROM orders
+		WHERE payment_intent_id = ?
+	`
+	o, err := scanOrder(r.db.QueryRowContext(ctx, query, intentID))
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, ErrOrderNotFound
+		}
+		return nil, fmt.Errorf("get order by payment intent: %w", err)
+	}
+	return o, nil
+}
+
+// GetExpiredPendingOrderIDs returns orders still waiting for payment after their payment window.
+func (r *OrderRepo) GetExpiredPendingOrderIDs(ctx context.Context, now time.Time, limit int) ([]string, error) {
+	query := `
+		SELECT id
+		FROM orders
+		WHERE status = ? AND payment_expires_at <= ?
+		ORDER BY payment_expires_at
+		LIMIT ?
+	`
+	rows, err := r.db.QueryContext(ctx, query, models.OrderStatusPendingPayment, now, limit)
+	if err != nil {
+		return nil, fmt.Errorf("query expired pending orders: %w", err)
+	}
+	defer rows.Close()
+
+	var ids []string
+	for rows.Next() {
+		var id string
+		if err := rows.Scan(&id); err != nil {
+			return nil, fmt.Errorf("scan expired pending order: %w", err)
+		}
+		ids = append(ids, id)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("iterate expired pending orders: %w", err)
+	}
+	return ids, nil
+}
+
+// MarkOrderPaid moves a pending order to paid if it is still at the given version, clears
+// its payment deadline and posts the seller's credit.
+func (r *OrderRepo) MarkOrderPaid(ctx context.Context, orderID string, version int, lt *models.LedgerTransaction) error {
+	query := `
+		UPDATE orders
+		SET status = ?, payment_expires_at = NULL, version = version + 1
+		WHERE id = ? AND version = ? AND status = ?
+	`
+	args := []any{models.OrderStatusPaid, orderID, version, models.OrderStatusPendingPayment}
+	ev := &models.OrderEvent{OrderID: orderID, FromStatus: models.OrderStatusPendingPayment, ToStatus: models.OrderStatusPaid}
+	return r.updateOrderWithLedger(ctx, query, args, ev, lt, "mark order paid")
+}
+
+// GetOrdersToAutoComplete returns delivered orders whose buyer has not confirmed receipt
+// since deliveredBefore, oldest first.
+func (r *OrderRepo) GetOrdersToAutoComplete(ctx context.Context, deliveredBefore time.Time, limit int) ([]string, error) {
+	query := `
+		SELECT id
+		FROM orders
+		WHERE status = ? AND delivered_at <= ?
+		ORDER BY delivered_at
+		LIMIT ?
+	`
+	return r.queryOrderIDs(ctx, "orders to auto-complete", query, models.OrderStatusDelivered, deliveredBefore, limit)
+}
+
+// GetOrdersToRemind returns delivered orders waiting for the buyer since deliveredBefore
+// whose buyer has not been reminded yet, oldest first.
+func (r *OrderRepo) GetOrdersToRemind(ctx context.Context, deliveredBefore time.Time, limit int) ([]string, error) {
+	query := `
+		SELECT id
+		FROM orders
+		WHERE status = ? AND delivered_at <= ? AND completion_reminded_at IS NULL
+		ORDER BY delivered_at
+		LIMIT ?
+	`
+	return r.queryOrderIDs(ctx, "orders to remind", query, models.OrderStatusDelivered, deliveredBefore, limit)
+}
+
+func (r *OrderRepo) queryOrderIDs(ctx context.Context, what, query string, args ...any) ([]string, error) {
+	rows, err := r.db.QueryContext(ctx, query, args...)
+	if err != nil {
+		return nil, fmt.Errorf("query %s: %w", what, err)
+	}
+	defer rows.Close()
+
+	var ids []string
+	for rows.Next() {
+		var id string
+		if err := rows.Scan(&id); err != nil {
+			return nil, fmt.Errorf("scan %s: %w", what, err)
+		}
+		ids = append(ids, id)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("iterate %s: %w", what, err)
+	}
+	return ids, nil
+}
+
+// CreateCompletionReminder locks the order and, unless its buyer was already reminded, lets
+// fn build the reminder notification and stores it. A nil notification from fn skips the order.
+func (r *OrderRepo) CreateCompletionReminder(ctx context.Context, orderID string, fn func(*models.Order) (*models.Notification, error)) error {
+	tx, err := r.db.BeginTx(ctx, &sql.TxOptions{Isolation: sql.LevelReadCommitted})
+	if err != nil {
+		return fmt.Errorf("begin tx: %w", err)
+	}
+	defer tx.Rollback()
+
+	o, err := lockOrder(ctx, tx, orderID)
+	if err != nil {
+		return err
+	}
+	var remindedAt sql.NullTime
+	if err := tx.QueryRowContext(ctx, `SELECT completion_reminded_at FROM orders WHERE id = ?`, orderID).Scan(&remindedAt); err != nil {
+		return fmt.Errorf("get completion reminder: %w", err)
+	}
+	if remindedAt.Valid {
+		return nil
+	}
+
+	n, err := fn(o)
+	if err != nil || n == nil {
+		return err
+	}
+
+	queryInsert := `INSERT INTO notifications (id, user_id, type, payload) VALUES (?, ?, ?, ?)`
+	if _, err := tx.ExecContext(ctx, queryInsert, n.ID, n.UserID, n.Type, []byte(n.Payload)); err != nil {
+		return fmt.Errorf("insert notification: %w", err)
+	}
+	queryReminded := `UPDATE orders SET completion_reminded_at = CURRENT_TIMESTAMP WHERE id = ?`
+	if _, err := tx.ExecContext(ctx, queryReminded, orderID); err != nil {
+		return fmt.Errorf("mark completion reminded: %w", err)
+	}
+
+	if err := tx.Commit(); err != nil {
+		return fmt.Errorf("commit tx: %w", err)
+	}
+	return nil
+}
@@ -0,0 +1,177 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"time"
+	"uttc-hackathon-backend/internal/models"
+)
+
+type RefundRepo struct {
+	db *sql.DB
+}
+
+func NewRefundRepo(db *sql.DB) *RefundRepo {
+	return &RefundRepo{db: db}
+}
+
+const refundColumns = `id, order_id, amount, quantity, platform_fee_delta, net_payout_delta,
+	reason, note, initiated_by, initiator_role, status, created_at, completed_at`
+
+// scanRefund scans refundColumns followed by any extra columns into extra.
+func scanRefund(row rowScanner, extra ...any) (*models.Refund, error) {
+	var rf models.Refund
+	var completedAt sql.NullTime
+	dest := []any{&rf.ID, &rf.OrderID, &rf.Amount, &rf.Quantity, &rf.PlatformFeeDelta, &rf.NetPayoutDelta,
+		&rf.Reason, &rf.Note, &rf.InitiatedBy, &rf.InitiatorRole, &rf.Status, &rf.CreatedAt, &completedAt}
+	if err := row.Scan(append(dest, extra...)...); err != nil {
+		return nil, err
+	}
+	if completedAt.Valid {
+		rf.CompletedAt = &completedAt.Time
+	}
+	return &rf, nil
+}
+
+// insertRefund records a refund, normally still pending, in tx.
+func insertRefund(ctx context.Context, tx *sql.Tx, rf *models.Refund) error {
+	query := `
+		INSERT INTO refunds (id, order_id, amount, quantity, platform_fee_delta, net_payout_delta,
+		                     reason, note, initiated_by, initiator_role, status, created_at)
+		VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?)
+	`
+	_, err := tx.ExecContext(ctx, query,
+		rf.ID, rf.OrderID, rf.Amount, rf.Quantity, rf.PlatformFeeDelta, rf.NetPayoutDelta,
+		rf.Reason, rf.Note, rf.InitiatedBy, rf.InitiatorRole, rf.Status, rf.CreatedAt,
+	)
+	if err != nil {
+		return fmt.Errorf("insert refund: %w", err)
+	}
+	return nil
+}
+
+// RefundOrder locks the order and lets fn validate the refund, apply it to the order's
+// effective fee, payout and refunded totals, and build the refund with its ledger
+// transaction. The refund, the order, the ledger transaction and the timeline event are
+// saved atomically. The payment provider is called only after this commits, so the refund
+// is normally saved as pending.
+func (r *RefundRepo) RefundOrder(ctx context.Context, orderID string, fn func(*models.Order) (*models.Refund, *models.LedgerTransaction, error)) error {
+	tx, err := r.db.BeginTx(ctx, &sql.TxOptions{Isolation: sql.LevelReadCommitted})
+	if err != nil {
+		return fmt.Errorf("begin tx: %w", err)
+	}
+	defer tx.Rollback()
+
+	o, err := lockOrder(ctx, tx, orderID)
+	if err != nil {
+		return err
+	}
+
+	rf, lt, err := fn(o)
+	if err != nil {
+		return err
+	}
+
+	if err := insertRefund(ctx, tx, rf); err != nil {
+		return err
+	}
+
+	queryOrder := `
+		UPDATE orders
+		SET platform_fee = ?, net_payout = ?, refunded_amount = ?, refunded_quantity = ?
+		WHERE id = ?
+	`
+	if _, err := tx.ExecContext(ctx, queryOrder, o.PlatformFee, o.NetPayout, o.RefundedAmount, o.RefundedQuantity, o.ID); err != nil {
+		return fmt.Errorf("update order refunds: %w", err)
+	}
+
+	if lt != nil {
+		if err := postLedgerTransaction(ctx, tx, lt); err != nil {
+			return err
+		}
+	}
+
+	ev := &models.OrderEvent{
+		OrderID:    o.ID,
+		Type:       models.OrderEventRefunded,
+		ActorID:    rf.InitiatedBy,
+		FromStatus: o.Status,
+		ToStatus:   o.Status,
+		Metadata:   map[string]any{"refund_id": rf.ID, "amount": rf.Amount, "reason": rf.Reason},
+	}
+	if err := insertOrderEvent(ctx, tx, ev); err != nil {
+		return err
+	}
+
+	if err := tx.Commit(); err != nil {
+		return fmt.Errorf("commit tx: %w", err)
+	}
+	return nil
+}
+
+// GetRefunds returns the order's refunds, oldest first.
+func (r *RefundRepo) GetRefunds(ctx context.Context, orderID string) ([]*models.Refund, error) {
+	query := `SELECT ` + refundColumns + ` FROM refunds WHERE order_id = ? ORDER BY created_at, id`
+	rows, err := r.db.QueryContext(ctx, query, orderID)
+	if err != nil {
+		return nil, fmt.Errorf("query refunds: %w", err)
+	}
+	defer rows.Close()
+
+	refunds := []*models.Refund{}
+	for rows.Next() {
+		rf, err := scanRefund(rows)
+		if err != nil {
+			return nil, fmt.Errorf("scan refund: %w", err)
+		}
+		refunds = append(refunds, rf)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("iterate refunds: %w", err)
+	}
+	return refunds, nil
+}
+
+// GetPendingRefunds returns up to limit refunds created before createdBefore that the
+// payment provider has not confirmed yet, oldest first, with their order's payment intent.
+func (r *RefundRepo) GetPendingRefunds(ctx context.Context, createdBefore time.Time, limit int) ([]*models.Refund, error) {
+	query := `
+		SELECT ` + refundColumns + `,
+		       (SELECT payment_intent_id FROM orders WHERE orders.id = refunds.order_id)
+		FROM refunds
+		WHERE status = 'pending' AND created_at < ?
+		ORDER BY created_at, id
+		LIMIT ?
+	`
+	rows, err := r.db.QueryContext(ctx, query, createdBefore.UTC(), limit)
+	if err != nil {
+		return nil, fmt.Errorf("query pending refunds: %w", err)
+	}
+	defer rows.Close()
+
+	refunds := []*models.Refund{}
+	for rows.Next() {
+		var intentID sql.NullString
+		rf, err := scanRefund(rows, &intentID)
+		if err != nil {
+			return nil, fmt.Errorf("scan pending refund: %w", err)
+		}
+		rf.PaymentIntentID = intentID.String
+		refunds = append(refunds, rf)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("iterate pending refunds: %w", err)
+	}
+	return refunds, nil
+}
+
+// CompleteRefund marks a pending refund as returned to the buyer. Completing a refund
+// that is already completed is a no-op.
+func (r *RefundRepo) CompleteRefund(ctx context.Context, refundID string, at time.Time) error {
+	query := `UPDATE refunds SET status = 'completed', completed_at = ? WHERE id = ? AND status = 'pending'`
+	if _, err := r.db.ExecContext(ctx, query, at.UTC(), refundID); err != nil {
+		return fmt.Errorf("complete refund: %w", err)
+	}
+	return nil
+}
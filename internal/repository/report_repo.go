@@ -0,0 +1,147 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"time"
+	"uttc-hackathon-backend/internal/models"
+
+	"github.com/go-sql-driver/mysql"
+)
+
+type ReportRepo struct {
+	db *sql.DB
+}
+
+func NewReportRepo(db *sql.DB) *ReportRepo {
+	return &ReportRepo{db: db}
+}
+
+var (
+	ErrReportNotFound  = errors.New("report not found")
+	ErrDuplicateReport = errors.New("you have already reported this")
+)
+
+// mysqlErrDuplicateEntry is the MySQL error number for a unique key violation.
+const mysqlErrDuplicateEntry = 1062
+
+func isDuplicateEntry(err error) bool {
+	var mysqlErr *mysql.MySQLError
+	return errors.As(err, &mysqlErr) && mysqlErr.Number == mysqlErrDuplicateEntry
+}
+
+func (r *ReportRepo) CreateReport(ctx context.Context, rp *models.Report) error {
+	query := `
+		INSERT INTO reports (id, reporter_id, target_type, target_id, reason, comment, status)
+		VALUES (?, ?, ?, ?, ?, ?, ?)
+	`
+	_, err := r.db.ExecContext(ctx, query,
+		rp.ID, rp.ReporterID, rp.TargetType, rp.TargetID, rp.Reason, rp.Comment, rp.Status,
+	)
+	if err != nil {
+		if isDuplicateEntry(err) {
+			return ErrDuplicateReport
+		}
+		return fmt.Errorf("insert report: %w", err)
+	}
+	return nil
+}
+
+// CountReportsByReporterSince returns how many reports the user has filed since the given time.
+func (r *ReportRepo) CountReportsByReporterSince(ctx context.Context, reporterID string, since time.Time) (int, error) {
+	query := `SELECT COUNT(*) FROM reports WHERE reporter_id = ? AND created_at >= ?`
+	var n int
+	if err := r.db.QueryRowContext(ctx, query, reporterID, since).Scan(&n); err != nil {
+		return 0, fmt.Errorf("count reports: %w", err)
+	}
+	return n, nil
+}
+
+func (r *ReportRepo) GetReport(ctx context.Context, id string) (*models.Report, error) {
+	query := `
+		SELECT id, reporter_id, target_type, target_id, reason, comment, status, handled_by, created_at, updated_at
+		FROM reports
+		WHERE id = ?
+	`
+	rp, err := scanReport(r.db.QueryRowContext(ctx, query, id))
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, ErrReportNotFound
+		}
+		return nil, fmt.Errorf("get report: %w", err)
+	}
+	return rp, nil
+}
+
+// GetReports returns the moderation queue, oldest first. An empty status returns every report.
+func (r *ReportRepo) GetReports(ctx context.Context, status models.ReportStatus, limit, offset int) ([]*models.Report, error) {
+	query := `
+		SELECT id, reporter_id, target_type, target_id, reason, comment, status, handled_by, created_at, updated_at
+		FROM reports
+		WHERE (? = '' OR status = ?)
+		ORDER BY created_at ASC
+		LIMIT ? OFFSET ?
+	`
+	rows, err := r.db.QueryContext(ctx, query, status, status, limit, offset)
+	if err != nil {
+		return nil, fmt.Errorf("query reports: %w", err)
+	}
+	defer rows.Close()
+
+	var reports []*models.Report
+	for rows.Next() {
+		rp, err := scanReport(rows)
+		if err != nil {
+			return nil, fmt.Errorf("scan report: %w", err)
+		}
+		reports = append(reports, rp)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("iterate reports: %w", err)
+	}
+	return reports, nil
+}
+
+func (r *ReportRepo) UpdateReportStatus(ctx context.Context, id string, status models.ReportStatus, handledBy string) error {
+	query := `UPDATE reports SET status = ?, handled_by = ? WHERE id = ?`
+	res, err := r.db.ExecContext(ctx, query, status, handledBy, id)
+	if err != nil {
+		return fmt.Errorf("update report status: %w", err)
+	}
+	n, err := res.RowsAffected()
+	if err != nil {
+		return fmt.Errorf("update report status: %w", err)
+	}
+	if n == 0 {
+		return ErrReportNotFound
+	}
+	return nil
+}
+
+// ActionReportsForTarget marks every open or in-review report against the target as actioned.
+func (r *ReportRepo) ActionReportsForTarget(ctx context.Context, targetType models.ReportTargetType, targetID, handledBy string) error {
+	query := `
+		UPDATE reports
+		SET status = 'actioned', handled_by = ?
+		WHERE target_type = ? AND target_id = ? AND status IN ('open', 'in_review')
+	`
+	if _, err := r.db.ExecContext(ctx, query, handledBy, targetType, targetID); err != nil {
+		return fmt.Errorf("action reports: %w", err)
+	}
+	return nil
+}
+
+func scanReport(row rowScanner) (*models.Report, error) {
+	var rp models.Report
+	var handledBy sql.NullString
+	if err := row.Scan(
+		&rp.ID, &rp.ReporterID, &rp.TargetType, &rp.TargetID, &rp.Reason, &rp.Comment,
+		&rp.Status, &handledBy, &rp.CreatedAt, &rp.UpdatedAt,
+	); err != nil {
+		return nil, err
+	}
+	rp.HandledBy = handledBy.String
+	return &rp, nil
+}
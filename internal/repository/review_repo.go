@@ -0,0 +1,162 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"time"
+	"uttc-hackathon-backend/internal/models"
+)
+
+type ReviewRepo struct {
+	db *sql.DB
+}
+
+func NewReviewRepo(db *sql.DB) *ReviewRepo {
+	return &ReviewRepo{db: db}
+}
+
+var ErrAlreadyReviewed = errors.New("you have already reviewed this order")
+
+// CreateReview locks the order and the review already left on it, if any, and lets fn
+// validate them and build the new review. The review is stored with its visible_at; when the
+// other side had already reviewed, that review is revealed at the same time.
+func (r *ReviewRepo) CreateReview(ctx context.Context, orderID string, fn func(o *models.Order, existing *models.Review) (*models.Review, error)) error {
+	tx, err := r.db.BeginTx(ctx, &sql.TxOptions{Isolation: sql.LevelReadCommitted})
+	if err != nil {
+		return fmt.Errorf("begin tx: %w", err)
+	}
+	defer tx.Rollback()
+
+	o, err := lockOrder(ctx, tx, orderID)
+	if err != nil {
+		return err
+	}
+
+	// The order lock serializes both sides, so at most one review can exist here
+	var existing *models.Review
+	queryExisting := `
+		SELECT id, order_id, reviewer_id, reviewee_id, reviewer_role, rating, comment, visible_at, created_at
+		FROM reviews
+		WHERE order_id = ?
+		FOR UPDATE
+	`
+	rows, err := tx.QueryContext(ctx, queryExisting, orderID)
+	if err != nil {
+		return fmt.Errorf("query order reviews: %w", err)
+	}
+	for rows.Next() {
+		var rv models.Review
+		if err := rows.Scan(&rv.ID, &rv.OrderID, &rv.ReviewerID, &rv.RevieweeID, &rv.ReviewerRole,
+			&rv.Rating, &rv.Comment, &rv.VisibleAt, &rv.CreatedAt); err != nil {
+			rows.Close()
+			return fmt.Errorf("scan order review: %w", err)
+		}
+		existing = &rv
+	}
+	rows.Close()
+	if err := rows.Err(); err != nil {
+		return fmt.Errorf("iterate order reviews: %w", err)
+	}
+
+	rv, err := fn(o, existing)
+	if err != nil {
+		return err
+	}
+
+	query := `
+		INSERT INTO reviews (id, order_id, reviewer_id, reviewee_id, reviewer_role, rating, comment, visible_at)
+		VALUES (?, ?, ?, ?, ?, ?, ?, ?)
+	`
+	_, err = tx.ExecContext(ctx, query, rv.ID, rv.OrderID, rv.ReviewerID, rv.RevieweeID, rv.ReviewerRole, rv.Rating, rv.Comment, rv.VisibleAt)
+	if err != nil {
+		if isDuplicateEntry(err) {
+			return ErrAlreadyReviewed
+		}
+		return fmt.Errorf("insert review: %w", err)
+	}
+
+	if existing != nil {
+		queryReveal := `UPDATE reviews SET visible_at = ? WHERE id = ? AND visible_at > ?`
+		if _, err := tx.ExecContext(ctx, queryReveal, rv.VisibleAt, existing.ID, rv.VisibleAt); err != nil {
+			return fmt.Errorf("reveal review: %w", err)
+		}
+	}
+
+	if err := tx.Commit(); err != nil {
+		return fmt.Errorf("commit tx: %w", err)
+	}
+	return nil
+}
+
+// GetReviews returns the reviews the user received that are visible at now, newest first,
+// with the reviewer's name and avatar.
+func (r *ReviewRepo) GetReviews(ctx context.Context, revieweeID string, now time.Time, limit, offset int) ([]*models.Review, error) {
+	query := `
+		SELECT rv.id, rv.order_id, rv.reviewer_id, u.username, u.avatarUrl, rv.reviewee_id, rv.reviewer_role,
+		       rv.rating, rv.comment, rv.visible_at, rv.created_at
+		FROM reviews rv
+		JOIN users u ON u.id = rv.reviewer_id
+		WHERE rv.reviewee_id = ? AND rv.visible_at <= ?
+		ORDER BY rv.created_at DESC, rv.id DESC
+		LIMIT ? OFFSET ?
+	`
+	rows, err := r.db.QueryContext(ctx, query, revieweeID, now, limit, offset)
+	if err != nil {
+		return nil, fmt.Errorf("query reviews: %w", err)
+	}
+	defer rows.Close()
+
+	reviews := []*models.Review{}
+	for rows.Next() {
+		var rv models.Review
+		var avatarURL sql.NullString
+		if err := rows.Scan(&rv.ID, &rv.OrderID, &rv.ReviewerID, &rv.ReviewerName, &avatarURL, &rv.RevieweeID,
+			&rv.ReviewerRole, &rv.Rating, &rv.Comment, &rv.VisibleAt, &rv.CreatedAt); err != nil {
+			return nil, fmt.Errorf("scan review: %w", err)
+		}
+		rv.ReviewerAvatarURL = avatarURL.String
+		reviews = append(reviews, &rv)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("iterate reviews: %w", err)
+	}
+	return reviews, nil
+}
+
+// GetRatingSummary counts the ratings the user received that are visible at now.
+func (r *ReviewRepo) GetRatingSummary(ctx context.Context, revieweeID string, now time.Time) (*models.RatingSummary, error) {
+	query := `
+		SELECT rating, COUNT(*)
+		FROM reviews
+		WHERE reviewee_id = ? AND visible_at <= ?
+		GROUP BY rating
+	`
+	rows, err := r.db.QueryContext(ctx, query, revieweeID, now)
+	if err != nil {
+		return nil, fmt.Errorf("count ratings: %w", err)
+	}
+	defer rows.Close()
+
+	var summary models.RatingSummary
+	for rows.Next() {
+		var rating models.ReviewRating
+		var n int
+		if err := rows.Scan(&rating, &n); err != nil {
+			return nil, fmt.Errorf("scan rating count: %w", err)
+		}
+		switch rating {
+		case models.ReviewRatingGood:
+			summary.Good = n
+		case models.ReviewRatingNormal:
+			summary.Normal = n
+		case models.ReviewRatingBad:
+			summary.Bad = n
+		}
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("iterate rating counts: %w", err)
+	}
+	return &summary, nil
+}
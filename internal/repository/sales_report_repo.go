@@ -0,0 +1,109 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"time"
+	"uttc-hackathon-backend/internal/models"
+)
+
+type SalesReportRepo struct {
+	db *sql.DB
+}
+
+func NewSalesReportRepo(db *sql.DB) *SalesReportRepo {
+	return &SalesReportRepo{db: db}
+}
+
+// Orders count as sales once paid, unless cancelled. Timestamps are read in the session
+// time zone, which client.InitDB pins to UTC, and bucketed by the day in Japan, which has
+// no daylight saving time, so a fixed offset is used instead of depending on the server's
+// time zone tables.
+const (
+	salesOrdersWhere = `seller_id = ? AND created_at >= ? AND created_at < ? AND status NOT IN ('pending_payment', 'cancelled')`
+	salesTotalsCols  = `COUNT(*), COALESCE(SUM(quantity), 0), COALESCE(SUM(total_price), 0), COALESCE(SUM(refunded_amount), 0),
+		COALESCE(SUM(platform_fee), 0), COALESCE(SUM(net_payout), 0)`
+	jstDay = `DATE(CONVERT_TZ(created_at, '+00:00', '+09:00'))`
+)
+
+// salesBucketExpr returns the SQL for the first JST day of the order's period.
+func salesBucketExpr(g models.SalesGranularity) (string, error) {
+	switch g {
+	case models.SalesGranularityDay:
+		return jstDay, nil
+	case models.SalesGranularityWeek:
+		return fmt.Sprintf("DATE_SUB(%[1]s, INTERVAL WEEKDAY(%[1]s) DAY)", jstDay), nil
+	case models.SalesGranularityMonth:
+		return fmt.Sprintf("DATE_SUB(%[1]s, INTERVAL DAYOFMONTH(%[1]s) - 1 DAY)", jstDay), nil
+	default:
+		return "", fmt.Errorf("unknown sales granularity %q", g)
+	}
+}
+
+// GetSalesBuckets returns the seller's sales per period for orders created in [from, to),
+// oldest first. Periods without sales are left out.
+func (r *SalesReportRepo) GetSalesBuckets(ctx context.Context, sellerID string, g models.SalesGranularity, from, to time.Time) ([]models.SalesBucket, error) {
+	bucket, err := salesBucketExpr(g)
+	if err != nil {
+		return nil, err
+	}
+	query := `SELECT ` + bucket + ` AS bucket, ` + salesTotalsCols + `
+		FROM orders
+		WHERE ` + salesOrdersWhere + `
+		GROUP BY bucket
+		ORDER BY bucket`
+
+	rows, err := r.db.QueryContext(ctx, query, sellerID, from.UTC(), to.UTC())
+	if err != nil {
+		return nil, fmt.Errorf("query sales buckets: %w", err)
+	}
+	defer rows.Close()
+
+	var buckets []models.SalesBucket
+	for rows.Next() {
+		var b models.SalesBucket
+		var start time.Time
+		t := &b.SalesTotals
+		if err := rows.Scan(&start, &t.Orders, &t.Quantity, &t.GrossSales, &t.Refunds, &t.PlatformFees, &t.NetPayouts); err != nil {
+			return nil, fmt.Errorf("scan sales bucket: %w", err)
+		}
+		b.Start = start.Format(time.DateOnly)
+		buckets = append(buckets, b)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("iterate sales buckets: %w", err)
+	}
+	return buckets, nil
+}
+
+// GetTopListings returns the seller's best selling listings for orders created in [from, to),
+// by gross sales less refunds.
+func (r *SalesReportRepo) GetTopListings(ctx context.Context, sellerID string, from, to time.Time, limit int) ([]models.ListingSales, error) {
+	query := `SELECT listing_id, MAX(listing_title), MAX(listing_main_image), ` + salesTotalsCols + `
+		FROM orders
+		WHERE ` + salesOrdersWhere + `
+		GROUP BY listing_id
+		ORDER BY SUM(total_price) - SUM(refunded_amount) DESC, listing_id
+		LIMIT ?`
+
+	rows, err := r.db.QueryContext(ctx, query, sellerID, from.UTC(), to.UTC(), limit)
+	if err != nil {
+		return nil, fmt.Errorf("query top listings: %w", err)
+	}
+	defer rows.Close()
+
+	var listings []models.ListingSales
+	for rows.Next() {
+		var l models.ListingSales
+		t := &l.SalesTotals
+		if err := rows.Scan(&l.ListingID, &l.ListingTitle, &l.ListingMainImage, &t.Orders, &t.Quantity, &t.GrossSales, &t.Refunds, &t.PlatformFees, &t.NetPayouts); err != nil {
+			return nil, fmt.Errorf("scan top listing: %w", err)
+		}
+		listings = append(listings, l)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("iterate top listings: %w", err)
+	}
+	return listings, nil
+}
@@ -0,0 +1,279 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+	"uttc-hackathon-backend/internal/models"
+)
+
+type SavedSearchRepo struct {
+	db *sql.DB
+}
+
+func NewSavedSearchRepo(db *sql.DB) *SavedSearchRepo {
+	return &SavedSearchRepo{db: db}
+}
+
+var ErrSavedSearchNotFound = errors.New("saved search not found")
+
+const savedSearchColumns = `ss.id, ss.user_id, ss.query, ss.min_price, ss.max_price, ss.item_condition, ss.created_at`
+
+func scanSavedSearch(row rowScanner) (*models.SavedSearch, error) {
+	var ss models.SavedSearch
+	var minPrice, maxPrice sql.NullInt64
+	var condition sql.NullString
+	if err := row.Scan(&ss.ID, &ss.UserID, &ss.Query, &minPrice, &maxPrice, &condition, &ss.CreatedAt); err != nil {
+		return nil, err
+	}
+	ss.MinPrice = int(minPrice.Int64)
+	ss.MaxPrice = int(maxPrice.Int64)
+	ss.Condition = models.ItemCondition(condition.String)
+	return &ss, nil
+}
+
+func (r *SavedSearchRepo) querySavedSearches(ctx context.Context, query string, args ...any) ([]*models.SavedSearch, error) {
+	rows, err := r.db.QueryContext(ctx, query, args...)
+	if err != nil {
+		return nil, fmt.Errorf("query saved searches: %w", err)
+	}
+	defer rows.Close()
+
+	var searches []*models.SavedSearch
+	for rows.Next() {
+		ss, err := scanSavedSearch(rows)
+		if err != nil {
+			return nil, fmt.Errorf("scan saved search: %w", err)
+		}
+		searches = append(searches, ss)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("iterate saved searches: %w", err)
+	}
+	return searches, nil
+}
+
+// CreateSavedSearch stores the search and its terms in the inverted index.
+func (r *SavedSearchRepo) CreateSavedSearch(ctx context.Context, ss *models.SavedSearch) error {
+	tx, err := r.db.BeginTx(ctx, nil)
+	if err != nil {
+		return fmt.Errorf("begin tx: %w", err)
+	}
+	defer tx.Rollback()
+
+	query := `
+		INSERT INTO saved_searches (id, user_id, query, term_count, min_price, max_price, item_condition)
+		VALUES (?, ?, ?, ?, ?, ?, ?)
+	`
+	_, err = tx.ExecContext(ctx, query,
+		ss.ID, ss.UserID, ss.Query, len(ss.Terms),
+		nullIfZero(ss.MinPrice), nullIfZero(ss.MaxPrice), nullIfEmpty(ss.Condition),
+	)
+	if err != nil {
+		return fmt.Errorf("insert saved search: %w", err)
+	}
+
+	placeholders := strings.TrimSuffix(strings.Repeat("(?, ?),", len(ss.Terms)), ",")
+	args := make([]any, 0, 2*len(ss.Terms))
+	for _, t := range ss.Terms {
+		args = append(args, t, ss.ID)
+	}
+	if _, err := tx.ExecContext(ctx, `INSERT INTO saved_search_terms (term, saved_search_id) VALUES `+placeholders, args...); err != nil {
+		return fmt.Errorf("insert saved search terms: %w", err)
+	}
+
+	if err := tx.Commit(); err != nil {
+		return fmt.Errorf("commit tx: %w", err)
+	}
+	return nil
+}
+
+func (r *SavedSearchRepo) CountSavedSearches(ctx context.Context, userID string) (int, error) {
+	var n int
+	if err := r.db.QueryRowContext(ctx, `SELECT COUNT(*) FROM saved_searches WHERE user_id = ?`, userID).Scan(&n); err != nil {
+		return 0, fmt.Errorf("count saved searches: %w", err)
+	}
+	return n, nil
+}
+
+func (r *SavedSearchRepo) GetSavedSearches(ctx context.Context, userID string) ([]*models.SavedSearch, error) {
+	query := `
+		SELECT ` + savedSearchColumns + `
+		FROM saved_searches ss
+		WHERE ss.user_id = ?
+		ORDER BY ss.created_at DESC
+	`
+	return r.querySavedSearches(ctx, query, userID)
+}
+
+// DeleteSavedSearch deletes the user's saved search. Searches owned by others are reported as not found.
+func (r *SavedSearchRepo) DeleteSavedSearch(ctx context.Context, id, userID string) error {
+	res, err := r.db.ExecContext(ctx, `DELETE FROM saved_searches WHERE id = ? AND user_id = ?`, id, userID)
+	if err != nil {
+		return fmt.Errorf("delete saved search: %w", err)
+	}
+	n, err := res.RowsAffected()
+	if err != nil {
+		return fmt.Errorf("delete saved search: %w", err)
+	}
+	if n == 0 {
+		return ErrSavedSearchNotFound
+	}
+	return nil
+}
+
+// GetSavedSearchCandidates returns the saved searches whose every term is among the given
+// listing terms, using the term index. Price and condition filters are left to the caller.
+func (r *SavedSearchRepo) GetSavedSearchCandidates(ctx context.Context, terms []string) ([]*models.SavedSearch, error) {
+	if len(terms) == 0 {
+		return nil, nil
+	}
+
+	placeholders := strings.TrimSuffix(strings.Repeat("?,", len(terms)), ",")
+	query := `
+		SELECT ` + savedSearchColumns + `
+		FROM saved_searches ss
+		JOIN (
+			SELECT saved_search_id, COUNT(*) AS hits
+			FROM saved_search_terms
+			WHERE term IN (` + placeholders + `)
+			GROUP BY saved_search_id
+		) h ON h.saved_search_id = ss.id
+		WHERE h.hits = ss.term_count
+	`
+	args := make([]any, len(terms))
+	for i, t := range terms {
+		args[i] = t
+	}
+	return r.querySavedSearches(ctx, query, args...)
+}
+
+// RecordMatches stores new matches as pending. Matches already recorded are ignored.
+func (r *SavedSearchRepo) RecordMatches(ctx context.Context, matches []models.SavedSearchMatch) error {
+	if len(matches) == 0 {
+		return nil
+	}
+	placeholders := strings.TrimSuffix(strings.Repeat("(?, ?, ?),", len(matches)), ",")
+	args := make([]any, 0, 3*len(matches))
+	for _, m := range matches {
+		args = append(args, m.SavedSearchID, m.ListingID, m.UserID)
+	}
+	query := `INSERT IGNORE INTO saved_search_matches (saved_search_id, listing_id, user_id) VALUES ` + placeholders
+	if _, err := r.db.ExecContext(ctx, query, args...); err != nil {
+		return fmt.Errorf("insert saved search matches: %w", err)
+	}
+	return nil
+}
+
+// GetUsersWithPendingMatches returns users that have matches not yet sent in a digest.
+func (r *SavedSearchRepo) GetUsersWithPendingMatches(ctx context.Context, limit int) ([]string, error) {
+	query := `
+		SELECT DISTINCT user_id
+		FROM saved_search_matches
+		WHERE notification_id IS NULL
+		LIMIT ?
+	`
+	rows, err := r.db.QueryContext(ctx, query, limit)
+	if err != nil {
+		return nil, fmt.Errorf("query pending match users: %w", err)
+	}
+	defer rows.Close()
+
+	var users []string
+	for rows.Next() {
+		var id string
+		if err := rows.Scan(&id); err != nil {
+			return nil, fmt.Errorf("scan pending match user: %w", err)
+		}
+		users = append(users, id)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("iterate pending match users: %w", err)
+	}
+	return users, nil
+}
+
+// CreateDigest bundles the user's pending matches into one notification built by fn.
+// Nothing is created, and nil is returned, when the user already got a digest after
+// notBefore or has no pending matches. The user row is locked so concurrent callers
+// cannot both send a digest.
+func (r *SavedSearchRepo) CreateDigest(ctx context.Context, userID string, notBefore time.Time, fn func([]models.SavedSearchMatch) (*models.Notification, error)) (*models.Notification, error) {
+	tx, err := r.db.BeginTx(ctx, nil)
+	if err != nil {
+		return nil, fmt.Errorf("begin tx: %w", err)
+	}
+	defer tx.Rollback()
+
+	var locked string
+	if err := tx.QueryRowContext(ctx, `SELECT id FROM users WHERE id = ? FOR UPDATE`, userID).Scan(&locked); err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("lock user: %w", err)
+	}
+
+	var recent bool
+	queryRecent := `
+		SELECT EXISTS (
+			SELECT 1 FROM notifications
+			WHERE user_id = ? AND type = ? AND created_at > ?
+		)
+	`
+	if err := tx.QueryRowContext(ctx, queryRecent, userID, models.NotificationTypeSavedSearchDigest, notBefore).Scan(&recent); err != nil {
+		return nil, fmt.Errorf("check recent digest: %w", err)
+	}
+	if recent {
+		return nil, nil
+	}
+
+	queryPending := `
+		SELECT saved_search_id, listing_id, user_id
+		FROM saved_search_matches
+		WHERE user_id = ? AND notification_id IS NULL
+		ORDER BY created_at
+		FOR UPDATE
+	`
+	rows, err := tx.QueryContext(ctx, queryPending, userID)
+	if err != nil {
+		return nil, fmt.Errorf("query pending matches: %w", err)
+	}
+	var matches []models.SavedSearchMatch
+	for rows.Next() {
+		var m models.SavedSearchMatch
+		if err := rows.Scan(&m.SavedSearchID, &m.ListingID, &m.UserID); err != nil {
+			rows.Close()
+			return nil, fmt.Errorf("scan pending match: %w", err)
+		}
+		matches = append(matches, m)
+	}
+	rows.Close()
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("iterate pending matches: %w", err)
+	}
+	if len(matches) == 0 {
+		return nil, nil
+	}
+
+	n, err := fn(matches)
+	if err != nil {
+		return nil, err
+	}
+
+	queryInsert := `INSERT INTO notifications (id, user_id, type, payload) VALUES (?, ?, ?, ?)`
+	if _, err := tx.ExecContext(ctx, queryInsert, n.ID, n.UserID, n.Type, []byte(n.Payload)); err != nil {
+		return nil, fmt.Errorf("insert notification: %w", err)
+	}
+
+	queryClaim := `UPDATE saved_search_matches SET notification_id = ? WHERE user_id = ? AND notification_id IS NULL`
+	if _, err := tx.ExecContext(ctx, queryClaim, n.ID, userID); err != nil {
+		return nil, fmt.Errorf("claim pending matches: %w", err)
+	}
+
+	if err := tx.Commit(); err != nil {
+		return nil, fmt.Errorf("commit tx: %w", err)
+	}
+	return n, nil
+}
@@ -0,0 +1,186 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"time"
+	"uttc-hackathon-backend/internal/models"
+)
+
+type ShipmentRepo struct {
+	db *sql.DB
+}
+
+func NewShipmentRepo(db *sql.DB) *ShipmentRepo {
+	return &ShipmentRepo{db: db}
+}
+
+var (
+	ErrShipmentNotFound   = errors.New("shipment not found")
+	ErrTrackingNumberUsed = errors.New("the tracking number is already used by another order")
+)
+
+const shipmentColumns = `order_id, carrier, tracking_number, tracking_status, shipped_at, delivered_at, last_checked_at`
+
+func scanShipment(row rowScanner) (*models.Shipment, error) {
+	var sh models.Shipment
+	var deliveredAt, lastCheckedAt sql.NullTime
+	if err := row.Scan(
+		&sh.OrderID, &sh.Carrier, &sh.TrackingNumber, &sh.TrackingStatus, &sh.ShippedAt, &deliveredAt, &lastCheckedAt,
+	); err != nil {
+		return nil, err
+	}
+	if deliveredAt.Valid {
+		sh.DeliveredAt = &deliveredAt.Time
+	}
+	if lastCheckedAt.Valid {
+		sh.LastCheckedAt = &lastCheckedAt.Time
+	}
+	return &sh, nil
+}
+
+// ShipOrder locks the order, lets fn mark it shipped and build its shipment, and saves the
+// order's new status together with the shipment. fn returns a nil shipment when the seller
+// gave no tracking number.
+func (r *ShipmentRepo) ShipOrder(ctx context.Context, orderID string, fn func(*models.Order) (*models.Shipment, error)) error {
+	tx, err := r.db.BeginTx(ctx, &sql.TxOptions{Isolation: sql.LevelReadCommitted})
+	if err != nil {
+		return fmt.Errorf("begin tx: %w", err)
+	}
+	defer tx.Rollback()
+
+	o, err := lockOrder(ctx, tx, orderID)
+	if err != nil {
+		return err
+	}
+	statusBefore := o.Status
+
+	sh, err := fn(o)
+	if err != nil {
+		return err
+	}
+
+	if sh != nil {
+		query := `
+			INSERT INTO shipments (order_id, carrier, tracking_number, tracking_status, shipped_at)
+			VALUES (?, ?, ?, ?, ?)
+		`
+		_, err = tx.ExecContext(ctx, query, sh.OrderID, sh.Carrier, sh.TrackingNumber, sh.TrackingStatus, sh.ShippedAt)
+		if err != nil {
+			if isDuplicateEntry(err) {
+				return ErrTrackingNumberUsed
+			}
+			return fmt.Errorf("insert shipment: %w", err)
+		}
+	}
+
+	ev := &models.OrderEvent{FromStatus: statusBefore, ActorID: o.SellerID}
+	if sh != nil {
+		ev.Metadata = map[string]any{"carrier": sh.Carrier, "tracking_number": sh.TrackingNumber}
+	}
+	if err := saveOrderStatus(ctx, tx, o, ev); err != nil {
+		return err
+	}
+
+	if err := tx.Commit(); err != nil {
+		return fmt.Errorf("commit tx: %w", err)
+	}
+	return nil
+}
+
+func (r *ShipmentRepo) GetShipment(ctx context.Context, orderID string) (*models.Shipment, error) {
+	query := `SELECT ` + shipmentColumns + ` FROM shipments WHERE order_id = ?`
+	sh, err := scanShipment(r.db.QueryRowContext(ctx, query, orderID))
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, ErrShipmentNotFound
+		}
+		return nil, fmt.Errorf("get shipment: %w", err)
+	}
+	return sh, nil
+}
+
+// GetShipmentsToTrack returns packages still in transit for orders awaiting delivery that
+// were not checked since checkedBefore, least recently checked first.
+func (r *ShipmentRepo) GetShipmentsToTrack(ctx context.Context, checkedBefore time.Time, limit int) ([]*models.Shipment, error) {
+	query := `
+		SELECT s.order_id, s.carrier, s.tracking_number, s.tracking_status, s.shipped_at, s.delivered_at, s.last_checked_at
+		FROM shipments s
+		JOIN orders o ON o.id = s.order_id
+		WHERE s.tracking_status = 'in_transit'
+		  AND o.status = 'shipped'
+		  AND (s.last_checked_at IS NULL OR s.last_checked_at < ?)
+		ORDER BY s.last_checked_at
+		LIMIT ?
+	`
+	rows, err := r.db.QueryContext(ctx, query, checkedBefore, limit)
+	if err != nil {
+		return nil, fmt.Errorf("query shipments to track: %w", err)
+	}
+	defer rows.Close()
+
+	var shipments []*models.Shipment
+	for rows.Next() {
+		sh, err := scanShipment(rows)
+		if err != nil {
+			return nil, fmt.Errorf("scan shipment: %w", err)
+		}
+		shipments = append(shipments, sh)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("iterate shipments: %w", err)
+	}
+	return shipments, nil
+}
+
+// UpdateShipment locks the shipment and its order, lets fn change them, and saves the
+// shipment's tracking state and, if fn changed it, the order's status.
+func (r *ShipmentRepo) UpdateShipment(ctx context.Context, orderID string, fn func(*models.Shipment, *models.Order) error) error {
+	tx, err := r.db.BeginTx(ctx, &sql.TxOptions{Isolation: sql.LevelReadCommitted})
+	if err != nil {
+		return fmt.Errorf("begin tx: %w", err)
+	}
+	defer tx.Rollback()
+
+	query := `SELECT ` + shipmentColumns + ` FROM shipments WHERE order_id = ? FOR UPDATE`
+	sh, err := scanShipment(tx.QueryRowContext(ctx, query, orderID))
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return ErrShipmentNotFound
+		}
+		return fmt.Errorf("get shipment for update: %w", err)
+	}
+
+	o, err := lockOrder(ctx, tx, orderID)
+	if err != nil {
+		return err
+	}
+	statusBefore := o.Status
+
+	if err := fn(sh, o); err != nil {
+		return err
+	}
+
+	queryUpdate := `UPDATE shipments SET tracking_status = ?, delivered_at = ?, last_checked_at = ? WHERE order_id = ?`
+	if _, err := tx.ExecContext(ctx, queryUpdate, sh.TrackingStatus, sh.DeliveredAt, sh.LastCheckedAt, orderID); err != nil {
+		return fmt.Errorf("update shipment: %w", err)
+	}
+
+	// Tracking changes are the carrier's doing, not a user's
+	if o.Status != statusBefore {
+		ev := &models.OrderEvent{
+			FromStatus: statusBefore,
+			Metadata:   map[string]any{"carrier": sh.Carrier, "tracking_status": sh.TrackingStatus},
+		}
+		if err := saveOrderStatus(ctx, tx, o, ev); err != nil {
+			return err
+		}
+	}
+
+	if err := tx.Commit(); err != nil {
+		return fmt.Errorf("commit tx: %w", err)
+	}
+	return nil
+}
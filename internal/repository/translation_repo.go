@@ -0,0 +1,98 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"strings"
+	"uttc-hackathon-backend/internal/models"
+)
+
+type TranslationRepo struct {
+	db *sql.DB
+}
+
+func NewTranslationRepo(db *sql.DB) *TranslationRepo {
+	return &TranslationRepo{db: db}
+}
+
+// GetListingTranslation returns the cached translation, or nil if there is none.
+func (r *TranslationRepo) GetListingTranslation(ctx context.Context, listingID, language string) (*models.ListingTranslation, error) {
+	query := `
+		SELECT listing_id, language, title, description, source_language, source_hash, updated_at
+		FROM listing_translations
+		WHERE listing_id = ? AND language = ?
+	`
+	var t models.ListingTranslation
+	err := r.db.QueryRowContext(ctx, query, listingID, language).Scan(
+		&t.ListingID, &t.Language, &t.Title, &t.Description, &t.SourceLanguage, &t.SourceHash, &t.UpdatedAt,
+	)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("get listing translation: %w", err)
+	}
+	return &t, nil
+}
+
+// GetListingTranslations returns cached translations for several listings, keyed by listing ID.
+func (r *TranslationRepo) GetListingTranslations(ctx context.Context, listingIDs []string, language string) (map[string]*models.ListingTranslation, error) {
+	result := make(map[string]*models.ListingTranslation)
+	if len(listingIDs) == 0 {
+		return result, nil
+	}
+
+	placeholders := strings.TrimSuffix(strings.Repeat("?,", len(listingIDs)), ",")
+	query := `
+		SELECT listing_id, language, title, description, source_language, source_hash, updated_at
+		FROM listing_translations
+		WHERE language = ? AND listing_id IN (` + placeholders + `)
+	`
+	args := make([]any, 0, len(listingIDs)+1)
+	args = append(args, language)
+	for _, id := range listingIDs {
+		args = append(args, id)
+	}
+
+	rows, err := r.db.QueryContext(ctx, query, args...)
+	if err != nil {
+		return nil, fmt.Errorf("query listing translations: %w", err)
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var t models.ListingTranslation
+		if err := rows.Scan(
+			&t.ListingID, &t.Language, &t.Title, &t.Description, &t.SourceLanguage, &t.SourceHash, &t.UpdatedAt,
+		); err != nil {
+			return nil, fmt.Errorf("scan listing translation: %w", err)
+		}
+		result[t.ListingID] = &t
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("iterate listing translations: %w", err)
+	}
+	return result, nil
+}
+
+// UpsertListingTranslation stores a translation, replacing any stale one for the same language.
+func (r *TranslationRepo) UpsertListingTranslation(ctx context.Context, t *models.ListingTranslation) error {
+	query := `
+		INSERT INTO listing_translations (listing_id, language, title, description, source_language, source_hash)
+		VALUES (?, ?, ?, ?, ?, ?)
+		ON DUPLICATE KEY UPDATE
+			title = VALUES(title),
+			description = VALUES(description),
+			source_language = VALUES(source_language),
+			source_hash = VALUES(source_hash)
+	`
+	_, err := r.db.ExecContext(ctx, query,
+		t.ListingID, t.Language, t.Title, t.Description, t.SourceLanguage, t.SourceHash,
+	)
+	if err != nil {
+		return fmt.Errorf("upsert listing translation: %w", err)
+	}
+	return nil
+}
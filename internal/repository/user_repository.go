@@ -4,9 +4,12 @@ import (
 	"context"
 	"database/sql"
 	"errors"
+	"fmt"
 	"uttc-hackathon-backend/internal/models"
 )
 
+var ErrUserNotFound = errors.New("user not found")
+
 type UserRepo struct {
 	db *sql.DB
 }
@@ -16,17 +19,18 @@ func NewUserRepo(db *sql.DB) *UserRepo {
 }
 
 func (r *UserRepo) GetUser(ctx context.Context, id string) (*models.User, error) {
-	const q = "SELECT id, username, email, avatarUrl FROM users WHERE id = ?"
+	const q = "SELECT id, username, email, avatarUrl, role, status, invoice_registration_number FROM users WHERE id = ?"
 	row := r.db.QueryRowContext(ctx, q, id)
 	var u models.User
-	var avatarURL sql.NullString
-	if err := row.Scan(&u.ID, &u.Name, &u.Email, &avatarURL); err != nil {
+	var avatarURL, invoiceNumber sql.NullString
+	if err := row.Scan(&u.ID, &u.Name, &u.Email, &avatarURL, &u.Role, &u.Status, &invoiceNumber); err != nil {
 		if errors.Is(err, sql.ErrNoRows) {
 			return nil, errors.New("user not found")
 		}
 		return nil, err
 	}
 	u.AvatarURL = avatarURL.String
+	u.InvoiceRegistrationNumber = invoiceNumber.String
 	return &u, nil
 }
 
@@ -50,3 +54,51 @@ func (r *UserRepo) GetUserProfile(ctx context.Context, id string) (*models.UserP
 	u.AvatarURL = avatarURL.String
 	return &u, nil
 }
+
+// GetUserStatus returns the account status, or an empty status if the user does not exist.
+func (r *UserRepo) GetUserStatus(ctx context.Context, id string) (models.UserStatus, error) {
+	const q = "SELECT status FROM users WHERE id = ?"
+	var status models.UserStatus
+	if err := r.db.QueryRowContext(ctx, q, id).Scan(&status); err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return "", nil
+		}
+		return "", err
+	}
+	return status, nil
+}
+
+// UpdateUserStatus sets the account status, failing with ErrUserNotFound if there is no such user.
+func (r *UserRepo) UpdateUserStatus(ctx context.Context, id string, status models.UserStatus) error {
+	const q = "UPDATE users SET status = ? WHERE id = ?"
+	res, err := r.db.ExecContext(ctx, q, status, id)
+	if err != nil {
+		return fmt.Errorf("update user status: %w", err)
+	}
+	n, err := res.RowsAffected()
+	if err != nil {
+		return fmt.Errorf("update user status: %w", err)
+	}
+	if n > 0 {
+		return nil
+	}
+
+	// Nothing changed: either the status was already set or the user does not exist
+	var exists bool
+	const qExists = "SELECT EXISTS (SELECT 1 FROM users WHERE id = ?)"
+	if err := r.db.QueryRowContext(ctx, qExists, id).Scan(&exists); err != nil {
+		return fmt.Errorf("check user: %w", err)
+	}
+	if !exists {
+		return ErrUserNotFound
+	}
+	return nil
+}
+
+// UpdateInvoiceRegistrationNumber sets the user's qualified invoice registration number; an
+// empty number clears it.
+func (r *UserRepo) UpdateInvoiceRegistrationNumber(ctx context.Context, id, number string) error {
+	const q = "UPDATE users SET invoice_registration_number = ? WHERE id = ?"
+	_, err := r.db.ExecContext(ctx, q, nullIfEmpty(number), id)
+	return err
+}
@@ -0,0 +1,178 @@
+package service
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"log"
+	"time"
+	"uttc-hackathon-backend/internal/models"
+	"uttc-hackathon-backend/internal/repository"
+
+	"github.com/oklog/ulid/v2"
+)
+
+const (
+	// DefaultAutoCompleteAfter is how long a delivered order waits for the buyer to confirm
+	// receipt before it is completed for them.
+	DefaultAutoCompleteAfter = 7 * 24 * time.Hour
+	// CompletionReminderLead is how long before the automatic completion the buyer is reminded.
+	CompletionReminderLead = 24 * time.Hour
+
+	autoCompleteLease     = "order_auto_complete"
+	autoCompleteLeaseTTL  = 5 * time.Minute
+	autoCompleteBatchSize = 100
+)
+
+// AutoCompleteRepository is the part of the order repository the scheduler needs.
+type AutoCompleteRepository interface {
+	GetOrder(ctx context.Context, orderID string) (*models.Order, error)
+	UpdateOrderStatus(ctx context.Context, orderID string, version int, status models.OrderStatus, actorID string, lt *models.LedgerTransaction) error
+	GetOrdersToAutoComplete(ctx context.Context, deliveredBefore time.Time, limit int) ([]string, error)
+	GetOrdersToRemind(ctx context.Context, deliveredBefore time.Time, limit int) ([]string, error)
+	CreateCompletionReminder(ctx context.Context, orderID string, fn func(*models.Order) (*models.Notification, error)) error
+}
+
+// LeaseRepository hands out named, expiring leases shared by every instance.
+type LeaseRepository interface {
+	AcquireLease(ctx context.Context, name, holder string, ttl time.Duration) (bool, error)
+	ReleaseLease(ctx context.Context, name, holder string) error
+}
+
+// AutoCompleteService completes delivered orders the buyer did not confirm within the grace
+// period, so the seller's balance is released, and reminds buyers the day before. Disputed
+// orders are left alone: a dispute moves the order out of delivered.
+type AutoCompleteService struct {
+	repo   AutoCompleteRepository
+	leases LeaseRepository
+	after  time.Duration
+	holder string // identifies this instance to the lease
+	now    func() time.Time
+}
+
+func NewAutoCompleteService(repo AutoCompleteRepository, leases LeaseRepository, after time.Duration) *AutoCompleteService {
+	return &AutoCompleteService{repo: repo, leases: leases, after: after, holder: ulid.Make().String(), now: time.Now}
+}
+
+// RunOnce completes overdue orders and then reminds buyers whose orders are due soon, so no
+// one is reminded of an order that is completed in the same pass. Only the instance holding
+// the lease does the work; the others return zeros. It stops early when ctx is cancelled.
+func (s *AutoCompleteService) RunOnce(ctx context.Context) (completed, reminded int, err error) {
+	acquired, err := s.leases.AcquireLease(ctx, autoCompleteLease, s.holder, autoCompleteLeaseTTL)
+	if err != nil || !acquired {
+		return 0, 0, err
+	}
+	defer func() {
+		if err := s.leases.ReleaseLease(context.WithoutCancel(ctx), autoCompleteLease, s.holder); err != nil {
+			log.Printf("release auto-complete lease: %v", err)
+		}
+	}()
+
+	now := s.now()
+	ids, err := s.repo.GetOrdersToAutoComplete(ctx, now.Add(-s.after), autoCompleteBatchSize)
+	if err != nil {
+		return 0, 0, err
+	}
+	for _, id := range ids {
+		if ctx.Err() != nil {
+			return completed, reminded, nil
+		}
+		done, err := s.completeOrder(ctx, id)
+		if err != nil {
+			log.Printf("auto-complete order %s: %v", id, err)
+			continue
+		}
+		if done {
+			completed++
+		}
+	}
+
+	ids, err = s.repo.GetOrdersToRemind(ctx, now.Add(-max(s.after-CompletionReminderLead, 0)), autoCompleteBatchSize)
+	if err != nil {
+		return completed, 0, err
+	}
+	for _, id := range ids {
+		if ctx.Err() != nil {
+			return completed, reminded, nil
+		}
+		sent, err := s.remind(ctx, id)
+		if err != nil {
+			log.Printf("send completion reminder for order %s: %v", id, err)
+			continue
+		}
+		if sent {
+			reminded++
+		}
+	}
+	return completed, reminded, nil
+}
+
+// completeOrder completes the order if it is still delivered and overdue. It reports false,
+// without error, when the buyer acted first.
+func (s *AutoCompleteService) completeOrder(ctx context.Context, orderID string) (bool, error) {
+	o, err := s.repo.GetOrder(ctx, orderID)
+	if err != nil {
+		return false, err
+	}
+	now := s.now()
+	if o.Status != models.OrderStatusDelivered || o.DeliveredAt == nil || o.DeliveredAt.Add(s.after).After(now) {
+		return false, nil
+	}
+
+	// No actor: the platform completes the order
+	err = s.repo.UpdateOrderStatus(ctx, o.ID, o.Version, models.OrderStatusCompleted, "", orderCompletedLedger(o, now))
+	if errors.Is(err, repository.ErrOrderVersionConflict) {
+		return false, nil
+	}
+	if err != nil {
+		return false, err
+	}
+	return true, nil
+}
+
+// remind notifies the buyer that the order will be completed for them. It reports whether
+// a reminder was sent.
+func (s *AutoCompleteService) remind(ctx context.Context, orderID string) (bool, error) {
+	sent := false
+	err := s.repo.CreateCompletionReminder(ctx, orderID, func(o *models.Order) (*models.Notification, error) {
+		if o.Status != models.OrderStatusDelivered || o.DeliveredAt == nil {
+			return nil, nil
+		}
+		payload, err := json.Marshal(models.OrderCompletionReminder{
+			OrderID:        o.ID,
+			ListingTitle:   o.ListingTitle,
+			AutoCompleteAt: o.DeliveredAt.Add(s.after),
+		})
+		if err != nil {
+			return nil, err
+		}
+		sent = true
+		return &models.Notification{
+			ID:        "ntf_" + ulid.Make().String(),
+			UserID:    o.BuyerID,
+			Type:      models.NotificationTypeOrderCompletionReminder,
+			Payload:   payload,
+			CreatedAt: s.now(),
+		}, nil
+	})
+	if err != nil {
+		return false, err
+	}
+	return sent, nil
+}
+
+// RunAutoCompleteLoop calls RunOnce every interval until ctx is cancelled.
+func (s *AutoCompleteService) RunAutoCompleteLoop(ctx context.Context, interval time.Duration) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			if _, _, err := s.RunOnce(ctx); err != nil {
+				log.Printf("auto-complete delivered orders: %v", err)
+			}
+		}
+	}
+}
@@ -0,0 +1,228 @@
+package service
+
+import (
+	"context"
+	"encoding/json"
+	"testing"
+	"time"
+
+	"uttc-hackathon-backend/internal/models"
+	"uttc-hackathon-backend/internal/repository"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+type MockAutoCompleteRepository struct {
+	mock.Mock
+}
+
+func (m *MockAutoCompleteRepository) GetOrder(ctx context.Context, orderID string) (*models.Order, error) {
+	args := m.Called(ctx, orderID)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*models.Order), args.Error(1)
+}
+
+func (m *MockAutoCompleteRepository) UpdateOrderStatus(ctx context.Context, orderID string, version int, status models.OrderStatus, actorID string, lt *models.LedgerTransaction) error {
+	args := m.Called(ctx, orderID, version, status, actorID, lt)
+	return args.Error(0)
+}
+
+func (m *MockAutoCompleteRepository) GetOrdersToAutoComplete(ctx context.Context, deliveredBefore time.Time, limit int) ([]string, error) {
+	args := m.Called(ctx, deliveredBefore, limit)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]string), args.Error(1)
+}
+
+func (m *MockAutoCompleteRepository) GetOrdersToRemind(ctx context.Context, deliveredBefore time.Time, limit int) ([]string, error) {
+	args := m.Called(ctx, deliveredBefore, limit)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]string), args.Error(1)
+}
+
+func (m *MockAutoCompleteRepository) CreateCompletionReminder(ctx context.Context, orderID string, fn func(*models.Order) (*models.Notification, error)) error {
+	args := m.Called(ctx, orderID, fn)
+	return args.Error(0)
+}
+
+type MockLeaseRepository struct {
+	mock.Mock
+}
+
+func (m *MockLeaseRepository) AcquireLease(ctx context.Context, name, holder string, ttl time.Duration) (bool, error) {
+	args := m.Called(ctx, name, holder, ttl)
+	return args.Bool(0), args.Error(1)
+}
+
+func (m *MockLeaseRepository) ReleaseLease(ctx context.Context, name, holder string) error {
+	args := m.Called(ctx, name, holder)
+	return args.Error(0)
+}
+
+var autoCompleteTestNow = time.Date(2024, 9, 10, 12, 0, 0, 0, time.UTC)
+
+func newTestAutoCompleteService(repo *MockAutoCompleteRepository, leases *MockLeaseRepository) *AutoCompleteService {
+	s := NewAutoCompleteService(repo, leases, DefaultAutoCompleteAfter)
+	s.now = func() time.Time { return autoCompleteTestNow }
+	return s
+}
+
+func deliveredOrder(id string, deliveredAt time.Time) *models.Order {
+	return &models.Order{
+		ID:           id,
+		BuyerID:      "buyer1",
+		SellerID:     "seller1",
+		ListingTitle: "Camera",
+		TotalPrice:   1000,
+		Status:       models.OrderStatusDelivered,
+		Version:      3,
+		DeliveredAt:  &deliveredAt,
+	}
+}
+
+func TestAutoCompleteService_RunOnce_LeaseHeldElsewhere(t *testing.T) {
+	repo := new(MockAutoCompleteRepository)
+	leases := new(MockLeaseRepository)
+	leases.On("AcquireLease", mock.Anything, autoCompleteLease, mock.Anything, autoCompleteLeaseTTL).Return(false, nil)
+	s := newTestAutoCompleteService(repo, leases)
+
+	completed, reminded, err := s.RunOnce(context.Background())
+
+	assert.NoError(t, err)
+	assert.Equal(t, 0, completed)
+	assert.Equal(t, 0, reminded)
+	repo.AssertNotCalled(t, "GetOrdersToAutoComplete", mock.Anything, mock.Anything, mock.Anything)
+	leases.AssertNotCalled(t, "ReleaseLease", mock.Anything, mock.Anything, mock.Anything)
+}
+
+func TestAutoCompleteService_RunOnce_Complete(t *testing.T) {
+	tests := []struct {
+		name          string
+		order         *models.Order
+		updateErr     error
+		wantUpdate    bool
+		wantCompleted int
+	}{
+		{
+			name:          "Overdue",
+			order:         deliveredOrder("ord_1", autoCompleteTestNow.Add(-8*24*time.Hour)),
+			wantUpdate:    true,
+			wantCompleted: 1,
+		},
+		{
+			name:  "Not Yet Due",
+			order: deliveredOrder("ord_1", autoCompleteTestNow.Add(-6*24*time.Hour)),
+		},
+		{
+			name: "Disputed Meanwhile",
+			order: func() *models.Order {
+				o := deliveredOrder("ord_1", autoCompleteTestNow.Add(-8*24*time.Hour))
+				o.Status = models.OrderStatusDisputed
+				return o
+			}(),
+		},
+		{
+			name:       "Buyer Completed Concurrently",
+			order:      deliveredOrder("ord_1", autoCompleteTestNow.Add(-8*24*time.Hour)),
+			updateErr:  repository.ErrOrderVersionConflict,
+			wantUpdate: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			repo := new(MockAutoCompleteRepository)
+			leases := new(MockLeaseRepository)
+			leases.On("AcquireLease", mock.Anything, autoCompleteLease, mock.Anything, autoCompleteLeaseTTL).Return(true, nil)
+			leases.On("ReleaseLease", mock.Anything, autoCompleteLease, mock.Anything).Return(nil)
+			repo.On("GetOrdersToAutoComplete", mock.Anything, autoCompleteTestNow.Add(-DefaultAutoCompleteAfter), autoCompleteBatchSize).
+				Return([]string{"ord_1"}, nil)
+			repo.On("GetOrdersToRemind", mock.Anything, mock.Anything, autoCompleteBatchSize).Return([]string{}, nil)
+			repo.On("GetOrder", mock.Anything, "ord_1").Return(tt.order, nil)
+			if tt.wantUpdate {
+				repo.On("UpdateOrderStatus", mock.Anything, "ord_1", 3, models.OrderStatusCompleted, "",
+					mock.MatchedBy(func(lt *models.LedgerTransaction) bool { return lt != nil && lt.OrderID == "ord_1" })).
+					Return(tt.updateErr)
+			}
+			s := newTestAutoCompleteService(repo, leases)
+
+			completed, reminded, err := s.RunOnce(context.Background())
+
+			assert.NoError(t, err)
+			assert.Equal(t, tt.wantCompleted, completed)
+			assert.Equal(t, 0, reminded)
+			if !tt.wantUpdate {
+				repo.AssertNotCalled(t, "UpdateOrderStatus", mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything)
+			}
+			repo.AssertExpectations(t)
+			leases.AssertExpectations(t)
+		})
+	}
+}
+
+func TestAutoCompleteService_RunOnce_Remind(t *testing.T) {
+	deliveredAt := autoCompleteTestNow.Add(-6*24*time.Hour - time.Hour)
+	tests := []struct {
+		name         string
+		order        *models.Order
+		wantReminded int
+	}{
+		{name: "Due Tomorrow", order: deliveredOrder("ord_2", deliveredAt), wantReminded: 1},
+		{
+			name: "Already Completed",
+			order: func() *models.Order {
+				o := deliveredOrder("ord_2", deliveredAt)
+				o.Status = models.OrderStatusCompleted
+				return o
+			}(),
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			repo := new(MockAutoCompleteRepository)
+			leases := new(MockLeaseRepository)
+			leases.On("AcquireLease", mock.Anything, autoCompleteLease, mock.Anything, autoCompleteLeaseTTL).Return(true, nil)
+			leases.On("ReleaseLease", mock.Anything, autoCompleteLease, mock.Anything).Return(nil)
+			repo.On("GetOrdersToAutoComplete", mock.Anything, mock.Anything, autoCompleteBatchSize).Return([]string{}, nil)
+			repo.On("GetOrdersToRemind", mock.Anything, autoCompleteTestNow.Add(-(DefaultAutoCompleteAfter-CompletionReminderLead)), autoCompleteBatchSize).
+				Return([]string{"ord_2"}, nil)
+
+			var notification *models.Notification
+			repo.On("CreateCompletionReminder", mock.Anything, "ord_2", mock.Anything).
+				Run(func(args mock.Arguments) {
+					fn := args.Get(2).(func(*models.Order) (*models.Notification, error))
+					n, err := fn(tt.order)
+					assert.NoError(t, err)
+					notification = n
+				}).
+				Return(nil)
+			s := newTestAutoCompleteService(repo, leases)
+
+			completed, reminded, err := s.RunOnce(context.Background())
+
+			assert.NoError(t, err)
+			assert.Equal(t, 0, completed)
+			assert.Equal(t, tt.wantReminded, reminded)
+			if tt.wantReminded == 0 {
+				assert.Nil(t, notification)
+				return
+			}
+			if assert.NotNil(t, notification) {
+				assert.Equal(t, "buyer1", notification.UserID)
+				assert.Equal(t, models.NotificationTypeOrderCompletionReminder, notification.Type)
+				var payload models.OrderCompletionReminder
+				assert.NoError(t, json.Unmarshal(notification.Payload, &payload))
+				assert.Equal(t, "ord_2", payload.OrderID)
+				assert.Equal(t, "Camera", payload.ListingTitle)
+				assert.True(t, deliveredAt.Add(DefaultAutoCompleteAfter).Equal(payload.AutoCompleteAt))
+			}
+		})
+	}
+}
@@ -0,0 +1,79 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"uttc-hackathon-backend/internal/models"
+)
+
+const (
+	// SitemapPageSize is the number of URLs per child sitemap (the protocol allows up to 50,000).
+	SitemapPageSize = 10000
+
+	DefaultFeedLimit = 50
+	MaxFeedLimit     = 100
+)
+
+var ErrSitemapNotFound = errors.New("sitemap not found")
+
+// DiscoveryService serves the public catalog to search engines and feed readers.
+type DiscoveryService struct {
+	repo DiscoveryRepository
+}
+
+type DiscoveryRepository interface {
+	GetListingSitemapPages(ctx context.Context, pageSize int) ([]models.SitemapPage, error)
+	CountActiveUsers(ctx context.Context) (int, error)
+	StreamListingSitemapEntries(ctx context.Context, limit, offset int, fn func(models.SitemapEntry) error) error
+	StreamUserSitemapEntries(ctx context.Context, limit, offset int, fn func(models.SitemapEntry) error) error
+	GetFeedListings(ctx context.Context, f models.ListingFeedFilter) ([]*models.Listing, error)
+}
+
+func NewDiscoveryService(repo DiscoveryRepository) *DiscoveryService {
+	return &DiscoveryService{repo: repo}
+}
+
+// GetSitemapIndex returns every child sitemap: listing pages first, then profile pages.
+func (s *DiscoveryService) GetSitemapIndex(ctx context.Context) ([]models.SitemapPage, error) {
+	pages, err := s.repo.GetListingSitemapPages(ctx, SitemapPageSize)
+	if err != nil {
+		return nil, err
+	}
+
+	users, err := s.repo.CountActiveUsers(ctx)
+	if err != nil {
+		return nil, err
+	}
+	for p := 1; (p-1)*SitemapPageSize < users; p++ {
+		pages = append(pages, models.SitemapPage{Kind: models.SitemapKindUsers, Page: p})
+	}
+	return pages, nil
+}
+
+// StreamSitemap calls fn for each entry on the given child sitemap page (1-based).
+func (s *DiscoveryService) StreamSitemap(ctx context.Context, kind models.SitemapKind, page int, fn func(models.SitemapEntry) error) error {
+	if page < 1 {
+		return ErrSitemapNotFound
+	}
+	offset := (page - 1) * SitemapPageSize
+
+	switch kind {
+	case models.SitemapKindListings:
+		return s.repo.StreamListingSitemapEntries(ctx, SitemapPageSize, offset, fn)
+	case models.SitemapKindUsers:
+		return s.repo.StreamUserSitemapEntries(ctx, SitemapPageSize, offset, fn)
+	default:
+		return ErrSitemapNotFound
+	}
+}
+
+// GetFeedListings returns the newest active listings for the syndication feed.
+func (s *DiscoveryService) GetFeedListings(ctx context.Context, f models.ListingFeedFilter) ([]*models.Listing, error) {
+	if f.Limit <= 0 {
+		f.Limit = DefaultFeedLimit
+	}
+	if f.Limit > MaxFeedLimit {
+		f.Limit = MaxFeedLimit
+	}
+	return s.repo.GetFeedListings(ctx, f)
+}
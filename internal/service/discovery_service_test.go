@@ -0,0 +1,163 @@
+package service
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"uttc-hackathon-backend/internal/models"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+type MockDiscoveryRepository struct {
+	mock.Mock
+}
+
+func (m *MockDiscoveryRepository) GetListingSitemapPages(ctx context.Context, pageSize int) ([]models.SitemapPage, error) {
+	args := m.Called(ctx, pageSize)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]models.SitemapPage), args.Error(1)
+}
+
+func (m *MockDiscoveryRepository) CountActiveUsers(ctx context.Context) (int, error) {
+	args := m.Called(ctx)
+	return args.Int(0), args.Error(1)
+}
+
+func (m *MockDiscoveryRepository) StreamListingSitemapEntries(ctx context.Context, limit, offset int, fn func(models.SitemapEntry) error) error {
+	args := m.Called(ctx, limit, offset, fn)
+	return args.Error(0)
+}
+
+func (m *MockDiscoveryRepository) StreamUserSitemapEntries(ctx context.Context, limit, offset int, fn func(models.SitemapEntry) error) error {
+	args := m.Called(ctx, limit, offset, fn)
+	return args.Error(0)
+}
+
+func (m *MockDiscoveryRepository) GetFeedListings(ctx context.Context, f models.ListingFeedFilter) ([]*models.Listing, error) {
+	args := m.Called(ctx, f)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]*models.Listing), args.Error(1)
+}
+
+func TestDiscoveryService_GetSitemapIndex(t *testing.T) {
+	lastMod := time.Date(2025, 1, 2, 3, 4, 5, 0, time.UTC)
+	tests := []struct {
+		name      string
+		users     int
+		wantUsers int
+	}{
+		{name: "No Users", users: 0, wantUsers: 0},
+		{name: "One Page", users: 1, wantUsers: 1},
+		{name: "Exactly Full Page", users: SitemapPageSize, wantUsers: 1},
+		{name: "Spills To Second Page", users: SitemapPageSize + 1, wantUsers: 2},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			repo := new(MockDiscoveryRepository)
+			repo.On("GetListingSitemapPages", mock.Anything, SitemapPageSize).Return([]models.SitemapPage{
+				{Kind: models.SitemapKindListings, Page: 1, LastModified: lastMod},
+			}, nil)
+			repo.On("CountActiveUsers", mock.Anything).Return(tt.users, nil)
+
+			s := NewDiscoveryService(repo)
+			pages, err := s.GetSitemapIndex(context.Background())
+
+			assert.NoError(t, err)
+			assert.Len(t, pages, 1+tt.wantUsers)
+			assert.Equal(t, models.SitemapKindListings, pages[0].Kind)
+			for i, p := range pages[1:] {
+				assert.Equal(t, models.SitemapKindUsers, p.Kind)
+				assert.Equal(t, i+1, p.Page)
+			}
+			repo.AssertExpectations(t)
+		})
+	}
+}
+
+func TestDiscoveryService_StreamSitemap(t *testing.T) {
+	tests := []struct {
+		name      string
+		kind      models.SitemapKind
+		page      int
+		mockSetup func(*MockDiscoveryRepository)
+		wantErr   error
+	}{
+		{
+			name: "Listings Page 2",
+			kind: models.SitemapKindListings,
+			page: 2,
+			mockSetup: func(m *MockDiscoveryRepository) {
+				m.On("StreamListingSitemapEntries", mock.Anything, SitemapPageSize, SitemapPageSize, mock.Anything).Return(nil)
+			},
+		},
+		{
+			name: "Users Page 1",
+			kind: models.SitemapKindUsers,
+			page: 1,
+			mockSetup: func(m *MockDiscoveryRepository) {
+				m.On("StreamUserSitemapEntries", mock.Anything, SitemapPageSize, 0, mock.Anything).Return(nil)
+			},
+		},
+		{
+			name:      "Unknown Kind",
+			kind:      "orders",
+			page:      1,
+			mockSetup: func(m *MockDiscoveryRepository) {},
+			wantErr:   ErrSitemapNotFound,
+		},
+		{
+			name:      "Page Zero",
+			kind:      models.SitemapKindListings,
+			page:      0,
+			mockSetup: func(m *MockDiscoveryRepository) {},
+			wantErr:   ErrSitemapNotFound,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			repo := new(MockDiscoveryRepository)
+			tt.mockSetup(repo)
+
+			s := NewDiscoveryService(repo)
+			err := s.StreamSitemap(context.Background(), tt.kind, tt.page, func(models.SitemapEntry) error { return nil })
+
+			assert.Equal(t, tt.wantErr, err)
+			repo.AssertExpectations(t)
+		})
+	}
+}
+
+func TestDiscoveryService_GetFeedListings(t *testing.T) {
+	tests := []struct {
+		name      string
+		limit     int
+		wantLimit int
+	}{
+		{name: "Default", limit: 0, wantLimit: DefaultFeedLimit},
+		{name: "Custom", limit: 10, wantLimit: 10},
+		{name: "Capped", limit: 1000, wantLimit: MaxFeedLimit},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			repo := new(MockDiscoveryRepository)
+			want := models.ListingFeedFilter{SellerID: "u1", Limit: tt.wantLimit}
+			repo.On("GetFeedListings", mock.Anything, want).Return([]*models.Listing{}, nil)
+
+			s := NewDiscoveryService(repo)
+			_, err := s.GetFeedListings(context.Background(), models.ListingFeedFilter{SellerID: "u1", Limit: tt.limit})
+
+			assert.NoError(t, err)
+			repo.AssertExpectations(t)
+		})
+	}
+}
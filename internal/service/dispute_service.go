@@ -0,0 +1,348 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"log"
+	"strings"
+	"time"
+	"unicode/utf8"
+	"uttc-hackathon-backend/internal/models"
+
+	"github.com/oklog/ulid/v2"
+)
+
+const (
+	// SellerResponseWindow is how long the seller has to answer a dispute before it is
+	// escalated to the admins.
+	SellerResponseWindow = 72 * time.Hour
+	MaxDisputeMessage    = 2000
+	MaxDisputeImages     = 5
+	MaxResolutionNote    = 1000
+	DefaultDisputesLimit = 50
+	MaxDisputesLimit     = 100
+	escalationBatchSize  = 100
+)
+
+var (
+	ErrInvalidDisputeReason     = errors.New("invalid dispute reason")
+	ErrInvalidDisputeStatus     = errors.New("invalid dispute status")
+	ErrDisputeMessageRequired   = errors.New("please describe the problem")
+	ErrDisputeMessageLong       = errors.New("message must be 2000 characters or fewer")
+	ErrTooManyDisputeImages     = errors.New("at most 5 evidence images are allowed")
+	ErrDisputeNotAwaitingSeller = errors.New("the dispute is no longer waiting for the seller")
+	ErrDisputeResolved          = errors.New("the dispute has already been resolved")
+	ErrInvalidDisputeOutcome    = errors.New("invalid dispute outcome")
+	ErrInvalidRefundAmount      = errors.New("partial refunds must be more than 0 and less than the unrefunded order total")
+	ErrResolutionNoteLong       = errors.New("resolution note must be 1000 characters or fewer")
+)
+
+type DisputeRepository interface {
+	OpenDispute(ctx context.Context, orderID string, fn func(*models.Order) (*models.Dispute, error)) error
+	UpdateDispute(ctx context.Context, disputeID string, fn func(*models.Dispute, *models.Order) (*models.DisputeEvent, *models.Refund, []*models.LedgerTransaction, error)) error
+	GetDispute(ctx context.Context, id string) (*models.Dispute, error)
+	GetDisputeByOrderID(ctx context.Context, orderID string) (*models.Dispute, error)
+	GetDisputes(ctx context.Context, status models.DisputeStatus, limit, offset int) ([]*models.Dispute, error)
+	GetOverdueDisputeIDs(ctx context.Context, now time.Time, limit int) ([]string, error)
+}
+
+type DisputeService struct {
+	repo     DisputeRepository
+	payments PaymentProvider
+	refunds  RefundCompleter
+	now      func() time.Time
+}
+
+func NewDisputeService(repo DisputeRepository, payments PaymentProvider, refunds RefundCompleter) *DisputeService {
+	return &DisputeService{repo: repo, payments: payments, refunds: refunds, now: time.Now}
+}
+
+// validateEvidence checks a party's statement and its evidence image URLs.
+func validateEvidence(message string, images []string) error {
+	if strings.TrimSpace(message) == "" {
+		return ErrDisputeMessageRequired
+	}
+	if utf8.RuneCountInString(message) > MaxDisputeMessage {
+		return ErrDisputeMessageLong
+	}
+	if len(images) > MaxDisputeImages {
+		return ErrTooManyDisputeImages
+	}
+	for _, url := range images {
+		if !strings.HasPrefix(url, FirebaseStoragePrefix) {
+			return ErrInvalidImageURL
+		}
+	}
+	return nil
+}
+
+func (s *DisputeService) newEvent(disputeID, actorID string, typ models.DisputeEventType, message string, images []string, at time.Time) *models.DisputeEvent {
+	return &models.DisputeEvent{
+		ID:        "dev_" + ulid.Make().String(),
+		DisputeID: disputeID,
+		ActorID:   actorID,
+		Type:      typ,
+		Message:   message,
+		Images:    images,
+		CreatedAt: at,
+	}
+}
+
+// OpenDispute lets the buyer dispute a shipped or delivered order. The order moves to
+// "disputed" and the seller has SellerResponseWindow to respond.
+func (s *DisputeService) OpenDispute(ctx context.Context, userID, orderID string, reason models.DisputeReason, message string, images []string) (*models.Dispute, error) {
+	switch reason {
+	case models.DisputeReasonNotReceived, models.DisputeReasonNotAsDescribed, models.DisputeReasonDamaged,
+		models.DisputeReasonCounterfeit, models.DisputeReasonOther:
+	default:
+		return nil, ErrInvalidDisputeReason
+	}
+	if err := validateEvidence(message, images); err != nil {
+		return nil, err
+	}
+
+	var opened *models.Dispute
+	err := s.repo.OpenDispute(ctx, orderID, func(o *models.Order) (*models.Dispute, error) {
+		role := orderRole(o, userID)
+		if role == "" {
+			return nil, ErrUnauthorized
+		}
+		next, err := nextOrderStatus(o, role, OrderActionDispute)
+		if err != nil {
+			return nil, err
+		}
+
+		now := s.now()
+		respondBy := now.Add(SellerResponseWindow)
+		d := &models.Dispute{
+			ID:        "dsp_" + ulid.Make().String(),
+			OrderID:   o.ID,
+			BuyerID:   o.BuyerID,
+			SellerID:  o.SellerID,
+			Reason:    reason,
+			Status:    models.DisputeStatusAwaitingSeller,
+			RespondBy: &respondBy,
+			CreatedAt: now,
+			UpdatedAt: now,
+		}
+		d.Events = []models.DisputeEvent{*s.newEvent(d.ID, userID, models.DisputeEventOpened, message, images, now)}
+
+		o.Status = next
+		o.Version++
+		opened = d
+		return d, nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return opened, nil
+}
+
+// GetOrderDispute returns the order's dispute to its buyer or seller.
+func (s *DisputeService) GetOrderDispute(ctx context.Context, userID, orderID string) (*models.Dispute, error) {
+	d, err := s.repo.GetDisputeByOrderID(ctx, orderID)
+	if err != nil {
+		return nil, err
+	}
+	if userID != d.BuyerID && userID != d.SellerID {
+		return nil, ErrUnauthorized
+	}
+	return d, nil
+}
+
+// RespondToDispute records the seller's side of the story and hands the dispute to the admins.
+// The seller can respond once, before the deadline.
+func (s *DisputeService) RespondToDispute(ctx context.Context, userID, orderID, message string, images []string) (*models.Dispute, error) {
+	if err := validateEvidence(message, images); err != nil {
+		return nil, err
+	}
+
+	d, err := s.repo.GetDisputeByOrderID(ctx, orderID)
+	if err != nil {
+		return nil, err
+	}
+	if userID != d.BuyerID && userID != d.SellerID {
+		return nil, ErrUnauthorized
+	}
+	if userID != d.SellerID {
+		return nil, ErrOrderActionForbidden
+	}
+
+	var responded *models.Dispute
+	err = s.repo.UpdateDispute(ctx, d.ID, func(locked *models.Dispute, o *models.Order) (*models.DisputeEvent, *models.Refund, []*models.LedgerTransaction, error) {
+		if locked.Status != models.DisputeStatusAwaitingSeller {
+			return nil, nil, nil, ErrDisputeNotAwaitingSeller
+		}
+		now := s.now()
+		locked.Status = models.DisputeStatusAwaitingAdmin
+		locked.RespondBy = nil
+		locked.UpdatedAt = now
+		ev := s.newEvent(locked.ID, userID, models.DisputeEventSellerResponded, message, images, now)
+		locked.Events = append(d.Events, *ev)
+		responded = locked
+		return ev, nil, nil, nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return responded, nil
+}
+
+// GetDisputes returns the admin queue, escalated disputes first.
+func (s *DisputeService) GetDisputes(ctx context.Context, status models.DisputeStatus, limit, offset int) ([]*models.Dispute, error) {
+	switch status {
+	case "", models.DisputeStatusAwaitingSeller, models.DisputeStatusAwaitingAdmin, models.DisputeStatusResolved:
+	default:
+		return nil, ErrInvalidDisputeStatus
+	}
+	if limit <= 0 {
+		limit = DefaultDisputesLimit
+	}
+	if limit > MaxDisputesLimit {
+		limit = MaxDisputesLimit
+	}
+	if offset < 0 {
+		offset = 0
+	}
+	return s.repo.GetDisputes(ctx, status, limit, offset)
+}
+
+// GetDispute returns a dispute with its timeline for the admins.
+func (s *DisputeService) GetDispute(ctx context.Context, id string) (*models.Dispute, error) {
+	return s.repo.GetDispute(ctx, id)
+}
+
+// ResolveDispute closes the dispute with the admin's decision. A full refund returns what
+// earlier refunds left of the order and cancels it; a partial refund or a release to the
+// seller completes it. Dispute refunds are recorded like any other refund, reducing the
+// order's fee and payout, and sent to the payment provider once the resolution commits.
+// Admins may decide before the seller has responded.
+func (s *DisputeService) ResolveDispute(ctx context.Context, adminID, disputeID string, outcome models.DisputeOutcome, refundAmount int, note string) (*models.Dispute, error) {
+	switch outcome {
+	case models.DisputeOutcomeFullRefund, models.DisputeOutcomePartialRefund, models.DisputeOutcomeReleaseToSeller:
+	default:
+		return nil, ErrInvalidDisputeOutcome
+	}
+	if utf8.RuneCountInString(note) > MaxResolutionNote {
+		return nil, ErrResolutionNoteLong
+	}
+
+	var resolved *models.Dispute
+	var refund *models.Refund
+	err := s.repo.UpdateDispute(ctx, disputeID, func(d *models.Dispute, o *models.Order) (*models.DisputeEvent, *models.Refund, []*models.LedgerTransaction, error) {
+		if d.Status == models.DisputeStatusResolved {
+			return nil, nil, nil, ErrDisputeResolved
+		}
+
+		now := s.now()
+		d.RefundAmount = 0
+		switch outcome {
+		case models.DisputeOutcomeFullRefund:
+			d.RefundAmount = o.TotalPrice - o.RefundedAmount
+		case models.DisputeOutcomePartialRefund:
+			if refundAmount <= 0 || refundAmount >= o.TotalPrice-o.RefundedAmount {
+				return nil, nil, nil, ErrInvalidRefundAmount
+			}
+			d.RefundAmount = refundAmount
+		}
+
+		// The refund comes out of the seller's pending balance before the order leaves the
+		// disputed status
+		var rf *models.Refund
+		var lts []*models.LedgerTransaction
+		if d.RefundAmount > 0 {
+			rf = applyRefund(o, d.RefundAmount, 0, models.RefundReasonDispute, note, adminID, models.ActorRoleAdmin, now)
+			if lt := orderRefundedLedger(o, rf, now); lt != nil {
+				lts = append(lts, lt)
+			}
+		}
+
+		switch outcome {
+		case models.DisputeOutcomeFullRefund:
+			if lt := orderCancelledLedger(o, o.Status, now); lt != nil {
+				lts = append(lts, lt)
+			}
+			o.Status = models.OrderStatusCancelled
+			o.CancelledBy = adminID
+			o.CancelReason = models.CancelReasonDisputeRefunded
+			o.CancelledAt = &now
+		case models.DisputeOutcomePartialRefund, models.DisputeOutcomeReleaseToSeller:
+			lts = append(lts, orderCompletedLedger(o, now))
+			o.Status = models.OrderStatusCompleted
+			o.CompletedAt = &now
+		}
+		o.Version++
+
+		d.Status = models.DisputeStatusResolved
+		d.RespondBy = nil
+		d.Outcome = outcome
+		d.ResolutionNote = note
+		d.ResolvedBy = adminID
+		d.ResolvedAt = &now
+		d.UpdatedAt = now
+		resolved = d
+		refund = rf
+		return s.newEvent(d.ID, adminID, models.DisputeEventResolved, note, nil, now), rf, lts, nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	if refund != nil {
+		if err := settleRefund(ctx, s.payments, s.refunds, refund, s.now()); err != nil {
+			log.Printf("settle refund %s: %v", refund.ID, err)
+		}
+	}
+	return resolved, nil
+}
+
+// EscalateOverdueDisputes hands every dispute whose seller missed the deadline to the
+// admins, marked as escalated so it sorts first in the queue. It returns how many were escalated.
+func (s *DisputeService) EscalateOverdueDisputes(ctx context.Context) (int, error) {
+	ids, err := s.repo.GetOverdueDisputeIDs(ctx, s.now(), escalationBatchSize)
+	if err != nil {
+		return 0, err
+	}
+
+	escalated := 0
+	for _, id := range ids {
+		var changed bool
+		err := s.repo.UpdateDispute(ctx, id, func(d *models.Dispute, o *models.Order) (*models.DisputeEvent, *models.Refund, []*models.LedgerTransaction, error) {
+			now := s.now()
+			// The seller may have responded since the overdue list was read
+			if d.Status != models.DisputeStatusAwaitingSeller || d.RespondBy == nil || d.RespondBy.After(now) {
+				return nil, nil, nil, nil
+			}
+			d.Status = models.DisputeStatusAwaitingAdmin
+			d.Escalated = true
+			d.RespondBy = nil
+			d.UpdatedAt = now
+			changed = true
+			return s.newEvent(d.ID, "", models.DisputeEventEscalated, "The seller did not respond in time.", nil, now), nil, nil, nil
+		})
+		if err != nil {
+			log.Printf("escalate dispute %s: %v", id, err)
+			continue
+		}
+		if changed {
+			escalated++
+		}
+	}
+	return escalated, nil
+}
+
+// RunEscalationLoop calls EscalateOverdueDisputes every interval until ctx is cancelled.
+func (s *DisputeService) RunEscalationLoop(ctx context.Context, interval time.Duration) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			if _, err := s.EscalateOverdueDisputes(ctx); err != nil {
+				log.Printf("escalate overdue disputes: %v", err)
+			}
+		}
+	}
+}
@@ -0,0 +1,353 @@
+package service
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"uttc-hackathon-backend/internal/models"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+type MockDisputeRepository struct {
+	mock.Mock
+}
+
+func (m *MockDisputeRepository) OpenDispute(ctx context.Context, orderID string, fn func(*models.Order) (*models.Dispute, error)) error {
+	args := m.Called(ctx, orderID, fn)
+	return args.Error(0)
+}
+
+func (m *MockDisputeRepository) UpdateDispute(ctx context.Context, disputeID string, fn func(*models.Dispute, *models.Order) (*models.DisputeEvent, *models.Refund, []*models.LedgerTransaction, error)) error {
+	args := m.Called(ctx, disputeID, fn)
+	return args.Error(0)
+}
+
+func (m *MockDisputeRepository) GetDispute(ctx context.Context, id string) (*models.Dispute, error) {
+	args := m.Called(ctx, id)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*models.Dispute), args.Error(1)
+}
+
+func (m *MockDisputeRepository) GetDisputeByOrderID(ctx context.Context, orderID string) (*models.Dispute, error) {
+	args := m.Called(ctx, orderID)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*models.Dispute), args.Error(1)
+}
+
+func (m *MockDisputeRepository) GetDisputes(ctx context.Context, status models.DisputeStatus, limit, offset int) ([]*models.Dispute, error) {
+	args := m.Called(ctx, status, limit, offset)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]*models.Dispute), args.Error(1)
+}
+
+func (m *MockDisputeRepository) GetOverdueDisputeIDs(ctx context.Context, now time.Time, limit int) ([]string, error) {
+	args := m.Called(ctx, now, limit)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]string), args.Error(1)
+}
+
+var disputeTestNow = time.Date(2024, 5, 1, 12, 0, 0, 0, time.UTC)
+
+func newTestDisputeService(repo *MockDisputeRepository) *DisputeService {
+	svc := NewDisputeService(repo, new(MockPaymentProvider), new(MockRefundRepository))
+	svc.now = func() time.Time { return disputeTestNow }
+	return svc
+}
+
+func TestDisputeService_OpenDispute(t *testing.T) {
+	evidence := []string{FirebaseStoragePrefix + "/v0/b/app/o/dispute.jpg"}
+
+	tests := []struct {
+		name    string
+		userID  string
+		status  models.OrderStatus
+		reason  models.DisputeReason
+		message string
+		images  []string
+		wantErr error
+	}{
+		{name: "Shipped Order", userID: "buyer", status: models.OrderStatusShipped, reason: models.DisputeReasonNotReceived, message: "Never arrived", images: evidence},
+		{name: "Delivered Order", userID: "buyer", status: models.OrderStatusDelivered, reason: models.DisputeReasonDamaged, message: "Broken screen"},
+		{name: "Seller Cannot Open", userID: "seller", status: models.OrderStatusShipped, reason: models.DisputeReasonOther, message: "x", wantErr: ErrOrderActionForbidden},
+		{name: "Stranger", userID: "stranger", status: models.OrderStatusShipped, reason: models.DisputeReasonOther, message: "x", wantErr: ErrUnauthorized},
+		{name: "Not Yet Shipped", userID: "buyer", status: models.OrderStatusPaid, reason: models.DisputeReasonOther, message: "x", wantErr: ErrInvalidOrderTransition},
+		{name: "Already Disputed", userID: "buyer", status: models.OrderStatusDisputed, reason: models.DisputeReasonOther, message: "x", wantErr: ErrInvalidOrderTransition},
+		{name: "Invalid Reason", userID: "buyer", status: models.OrderStatusShipped, reason: "bored", message: "x", wantErr: ErrInvalidDisputeReason},
+		{name: "Blank Message", userID: "buyer", status: models.OrderStatusShipped, reason: models.DisputeReasonOther, message: "  ", wantErr: ErrDisputeMessageRequired},
+		{name: "Foreign Image", userID: "buyer", status: models.OrderStatusShipped, reason: models.DisputeReasonOther, message: "x", images: []string{"https://example.com/a.jpg"}, wantErr: ErrInvalidImageURL},
+		{name: "Too Many Images", userID: "buyer", status: models.OrderStatusShipped, reason: models.DisputeReasonOther, message: "x", images: make([]string, MaxDisputeImages+1), wantErr: ErrTooManyDisputeImages},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			repo := new(MockDisputeRepository)
+			o := &models.Order{ID: "ord1", BuyerID: "buyer", SellerID: "seller", Status: tt.status, Version: 2}
+			repo.On("OpenDispute", mock.Anything, "ord1", mock.Anything).
+				Return(tt.wantErr).
+				Run(func(args mock.Arguments) {
+					fn := args.Get(2).(func(*models.Order) (*models.Dispute, error))
+					_, err := fn(o)
+					assert.Equal(t, tt.wantErr, err)
+				}).
+				Maybe() // not reached when the request itself is invalid
+			svc := newTestDisputeService(repo)
+
+			d, err := svc.OpenDispute(context.Background(), tt.userID, "ord1", tt.reason, tt.message, tt.images)
+
+			if tt.wantErr != nil {
+				assert.ErrorIs(t, err, tt.wantErr)
+				assert.Equal(t, tt.status, o.Status)
+				return
+			}
+			assert.NoError(t, err)
+			assert.Equal(t, models.OrderStatusDisputed, o.Status)
+			assert.Equal(t, 3, o.Version)
+			assert.Equal(t, models.DisputeStatusAwaitingSeller, d.Status)
+			assert.Equal(t, "seller", d.SellerID)
+			assert.Equal(t, disputeTestNow.Add(SellerResponseWindow), *d.RespondBy)
+			assert.Len(t, d.Events, 1)
+			assert.Equal(t, models.DisputeEventOpened, d.Events[0].Type)
+			assert.Equal(t, "buyer", d.Events[0].ActorID)
+			assert.Equal(t, tt.images, d.Events[0].Images)
+		})
+	}
+}
+
+func TestDisputeService_RespondToDispute(t *testing.T) {
+	respondBy := disputeTestNow.Add(time.Hour)
+
+	tests := []struct {
+		name    string
+		userID  string
+		status  models.DisputeStatus
+		wantErr error
+	}{
+		{name: "Seller Responds", userID: "seller", status: models.DisputeStatusAwaitingSeller},
+		{name: "Buyer Cannot Respond", userID: "buyer", status: models.DisputeStatusAwaitingSeller, wantErr: ErrOrderActionForbidden},
+		{name: "Stranger", userID: "stranger", status: models.DisputeStatusAwaitingSeller, wantErr: ErrUnauthorized},
+		{name: "Already Escalated", userID: "seller", status: models.DisputeStatusAwaitingAdmin, wantErr: ErrDisputeNotAwaitingSeller},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			repo := new(MockDisputeRepository)
+			stored := func() *models.Dispute {
+				return &models.Dispute{
+					ID: "dsp1", OrderID: "ord1", BuyerID: "buyer", SellerID: "seller", Status: tt.status, RespondBy: &respondBy,
+					Events: []models.DisputeEvent{{ID: "dev1", Type: models.DisputeEventOpened}},
+				}
+			}
+			repo.On("GetDisputeByOrderID", mock.Anything, "ord1").Return(stored(), nil)
+			if tt.userID == "seller" {
+				repo.On("UpdateDispute", mock.Anything, "dsp1", mock.Anything).
+					Return(tt.wantErr).
+					Run(func(args mock.Arguments) {
+						fn := args.Get(2).(func(*models.Dispute, *models.Order) (*models.DisputeEvent, *models.Refund, []*models.LedgerTransaction, error))
+						ev, _, _, err := fn(stored(), &models.Order{ID: "ord1", Status: models.OrderStatusDisputed})
+						assert.Equal(t, tt.wantErr, err)
+						if err == nil {
+							assert.Equal(t, models.DisputeEventSellerResponded, ev.Type)
+							assert.Equal(t, "seller", ev.ActorID)
+						}
+					})
+			}
+			svc := newTestDisputeService(repo)
+
+			d, err := svc.RespondToDispute(context.Background(), tt.userID, "ord1", "It was fine when shipped", nil)
+
+			if tt.wantErr != nil {
+				assert.ErrorIs(t, err, tt.wantErr)
+			} else {
+				assert.NoError(t, err)
+				assert.Equal(t, models.DisputeStatusAwaitingAdmin, d.Status)
+				assert.Nil(t, d.RespondBy)
+				assert.Len(t, d.Events, 2)
+			}
+			repo.AssertExpectations(t)
+		})
+	}
+}
+
+func TestDisputeService_ResolveDispute(t *testing.T) {
+	// 3000 yen order with a 300 yen fee
+	disputed := func() *models.Order {
+		return &models.Order{ID: "ord1", SellerID: "seller", TotalPrice: 3000, PlatformFee: 300, NetPayout: 2700,
+			Status: models.OrderStatusDisputed, Version: 4, PaymentIntentID: "pi_1"}
+	}
+	refundedBefore := func() *models.Order {
+		o := disputed()
+		o.RefundedAmount, o.PlatformFee, o.NetPayout = 1000, 200, 1800
+		return o
+	}
+
+	tests := []struct {
+		name          string
+		outcome       models.DisputeOutcome
+		refundAmount  int
+		disputeStatus models.DisputeStatus
+		order         func() *models.Order
+		wantErr       error
+		wantRefund    int
+		wantFeeDelta  int
+		wantOrder     models.OrderStatus
+		wantLedgers   []models.LedgerTransactionType
+		wantNetPayout int
+	}{
+		{
+			name: "Full Refund Cancels Order", outcome: models.DisputeOutcomeFullRefund, disputeStatus: models.DisputeStatusAwaitingAdmin, order: disputed,
+			wantRefund: 3000, wantFeeDelta: 300, wantOrder: models.OrderStatusCancelled,
+			wantLedgers: []models.LedgerTransactionType{models.LedgerTxOrderRefunded}, wantNetPayout: 0,
+		},
+		{
+			name: "Full Refund After Earlier Refund", outcome: models.DisputeOutcomeFullRefund, disputeStatus: models.DisputeStatusAwaitingAdmin, order: refundedBefore,
+			wantRefund: 2000, wantFeeDelta: 200, wantOrder: models.OrderStatusCancelled,
+			wantLedgers: []models.LedgerTransactionType{models.LedgerTxOrderRefunded}, wantNetPayout: 0,
+		},
+		{
+			name: "Partial Refund Completes Order", outcome: models.DisputeOutcomePartialRefund, refundAmount: 1000, disputeStatus: models.DisputeStatusAwaitingAdmin, order: disputed,
+			wantRefund: 1000, wantFeeDelta: 100, wantOrder: models.OrderStatusCompleted,
+			wantLedgers: []models.LedgerTransactionType{models.LedgerTxOrderRefunded, models.LedgerTxOrderCompleted}, wantNetPayout: 1800,
+		},
+		{
+			name: "Release Before Seller Responds", outcome: models.DisputeOutcomeReleaseToSeller, refundAmount: 500, disputeStatus: models.DisputeStatusAwaitingSeller, order: disputed,
+			wantRefund: 0, wantOrder: models.OrderStatusCompleted,
+			wantLedgers: []models.LedgerTransactionType{models.LedgerTxOrderCompleted}, wantNetPayout: 2700,
+		},
+		{name: "Partial Refund Of Everything", outcome: models.DisputeOutcomePartialRefund, refundAmount: 3000, disputeStatus: models.DisputeStatusAwaitingAdmin, order: disputed, wantErr: ErrInvalidRefundAmount},
+		{name: "Partial Refund Of The Unrefunded Rest", outcome: models.DisputeOutcomePartialRefund, refundAmount: 2000, disputeStatus: models.DisputeStatusAwaitingAdmin, order: refundedBefore, wantErr: ErrInvalidRefundAmount},
+		{name: "Partial Refund Of Nothing", outcome: models.DisputeOutcomePartialRefund, disputeStatus: models.DisputeStatusAwaitingAdmin, order: disputed, wantErr: ErrInvalidRefundAmount},
+		{name: "Already Resolved", outcome: models.DisputeOutcomeFullRefund, disputeStatus: models.DisputeStatusResolved, order: disputed, wantErr: ErrDisputeResolved},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			repo := new(MockDisputeRepository)
+			respondBy := disputeTestNow.Add(time.Hour)
+			d := &models.Dispute{ID: "dsp1", OrderID: "ord1", Status: tt.disputeStatus, RespondBy: &respondBy}
+			o := tt.order()
+			refundedAmount := o.RefundedAmount
+			var rf *models.Refund
+			var committed bool
+			repo.On("UpdateDispute", mock.Anything, "dsp1", mock.Anything).
+				Return(tt.wantErr).
+				Run(func(args mock.Arguments) {
+					fn := args.Get(2).(func(*models.Dispute, *models.Order) (*models.DisputeEvent, *models.Refund, []*models.LedgerTransaction, error))
+					ev, refund, lts, err := fn(d, o)
+					assert.Equal(t, tt.wantErr, err)
+					if err == nil {
+						rf = refund
+						committed = true
+						assert.Equal(t, models.DisputeEventResolved, ev.Type)
+						assert.Equal(t, "admin", ev.ActorID)
+						var types []models.LedgerTransactionType
+						for _, lt := range lts {
+							types = append(types, lt.Type)
+							assertBalanced(t, lt)
+						}
+						assert.Equal(t, tt.wantLedgers, types)
+					}
+				})
+			payments := new(MockPaymentProvider)
+			payments.On("Refund", mock.Anything, "pi_1", tt.wantRefund, mock.Anything).Return(nil).Run(func(args mock.Arguments) {
+				// No money moves while the dispute and order are locked
+				assert.True(t, committed)
+			}).Maybe()
+			refunds := new(MockRefundRepository)
+			refunds.On("CompleteRefund", mock.Anything, mock.Anything, disputeTestNow).Return(nil).Maybe()
+			svc := NewDisputeService(repo, payments, refunds)
+			svc.now = func() time.Time { return disputeTestNow }
+
+			got, err := svc.ResolveDispute(context.Background(), "admin", "dsp1", tt.outcome, tt.refundAmount, "Checked the photos")
+
+			if tt.wantErr != nil {
+				assert.ErrorIs(t, err, tt.wantErr)
+				assert.Equal(t, models.OrderStatusDisputed, o.Status)
+				payments.AssertNotCalled(t, "Refund", mock.Anything, mock.Anything, mock.Anything, mock.Anything)
+				return
+			}
+			assert.NoError(t, err)
+			assert.Equal(t, models.DisputeStatusResolved, got.Status)
+			assert.Equal(t, tt.outcome, got.Outcome)
+			assert.Equal(t, tt.wantRefund, got.RefundAmount)
+			assert.Equal(t, "admin", got.ResolvedBy)
+			assert.Nil(t, got.RespondBy)
+			assert.Equal(t, tt.wantOrder, o.Status)
+			assert.Equal(t, 5, o.Version)
+			assert.Equal(t, tt.wantNetPayout, o.NetPayout)
+			assert.Equal(t, refundedAmount+tt.wantRefund, o.RefundedAmount)
+			if tt.wantRefund > 0 {
+				assert.Equal(t, tt.wantRefund, rf.Amount)
+				assert.Equal(t, tt.wantFeeDelta, rf.PlatformFeeDelta)
+				assert.Equal(t, models.RefundReasonDispute, rf.Reason)
+				assert.Equal(t, models.ActorRoleAdmin, rf.InitiatorRole)
+				assert.Equal(t, models.RefundStatusCompleted, rf.Status)
+				payments.AssertCalled(t, "Refund", mock.Anything, "pi_1", tt.wantRefund, rf.ID)
+				refunds.AssertCalled(t, "CompleteRefund", mock.Anything, rf.ID, disputeTestNow)
+			} else {
+				assert.Nil(t, rf)
+				payments.AssertNotCalled(t, "Refund", mock.Anything, mock.Anything, mock.Anything, mock.Anything)
+			}
+			if tt.wantOrder == models.OrderStatusCancelled {
+				assert.Equal(t, models.CancelReasonDisputeRefunded, o.CancelReason)
+				assert.Equal(t, "admin", o.CancelledBy)
+			}
+		})
+	}
+}
+
+func TestDisputeService_ResolveDispute_InvalidOutcome(t *testing.T) {
+	repo := new(MockDisputeRepository)
+	svc := newTestDisputeService(repo)
+
+	_, err := svc.ResolveDispute(context.Background(), "admin", "dsp1", "split", 0, "")
+
+	assert.ErrorIs(t, err, ErrInvalidDisputeOutcome)
+	repo.AssertExpectations(t)
+}
+
+func TestDisputeService_EscalateOverdueDisputes(t *testing.T) {
+	repo := new(MockDisputeRepository)
+	overdue := disputeTestNow.Add(-time.Minute)
+
+	repo.On("GetOverdueDisputeIDs", mock.Anything, disputeTestNow, escalationBatchSize).Return([]string{"dsp1", "dsp2"}, nil)
+
+	// dsp1 is still waiting for the seller
+	dsp1 := &models.Dispute{ID: "dsp1", Status: models.DisputeStatusAwaitingSeller, RespondBy: &overdue}
+	repo.On("UpdateDispute", mock.Anything, "dsp1", mock.Anything).Return(nil).Run(func(args mock.Arguments) {
+		fn := args.Get(2).(func(*models.Dispute, *models.Order) (*models.DisputeEvent, *models.Refund, []*models.LedgerTransaction, error))
+		ev, _, _, err := fn(dsp1, &models.Order{Status: models.OrderStatusDisputed})
+		assert.NoError(t, err)
+		assert.Equal(t, models.DisputeEventEscalated, ev.Type)
+		assert.Empty(t, ev.ActorID)
+	})
+	// The seller of dsp2 responded after the overdue list was read
+	dsp2 := &models.Dispute{ID: "dsp2", Status: models.DisputeStatusAwaitingAdmin}
+	repo.On("UpdateDispute", mock.Anything, "dsp2", mock.Anything).Return(nil).Run(func(args mock.Arguments) {
+		fn := args.Get(2).(func(*models.Dispute, *models.Order) (*models.DisputeEvent, *models.Refund, []*models.LedgerTransaction, error))
+		ev, _, _, err := fn(dsp2, &models.Order{Status: models.OrderStatusDisputed})
+		assert.NoError(t, err)
+		assert.Nil(t, ev)
+	})
+
+	svc := newTestDisputeService(repo)
+	n, err := svc.EscalateOverdueDisputes(context.Background())
+
+	assert.NoError(t, err)
+	assert.Equal(t, 1, n)
+	assert.Equal(t, models.DisputeStatusAwaitingAdmin, dsp1.Status)
+	assert.True(t, dsp1.Escalated)
+	assert.Nil(t, dsp1.RespondBy)
+	assert.False(t, dsp2.Escalated)
+	repo.AssertExpectations(t)
+}
@@ -0,0 +1,144 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"log"
+	"slices"
+	"sync"
+	"time"
+	"uttc-hackathon-backend/internal/models"
+)
+
+// FeePolicyCacheTTL is how long the fee schedule is served from memory before it is
+// reloaded, so schedule changes take effect within this time.
+const FeePolicyCacheTTL = 5 * time.Minute
+
+// ErrNoFeePolicy means the fee schedule has no policy for the sale, which is a configuration
+// error: the schedule should always contain a catch-all policy.
+var ErrNoFeePolicy = errors.New("no fee policy applies to this listing")
+
+type FeePolicyRepository interface {
+	GetActiveFeePolicies(ctx context.Context) ([]*models.FeePolicy, error)
+	GetFeeSubject(ctx context.Context, listingID string) (*models.FeeSubject, error)
+}
+
+// FeeService picks the platform fee policy for a sale from the schedule stored in the
+// database, which it caches in process.
+type FeeService struct {
+	repo FeePolicyRepository
+	now  func() time.Time
+
+	mu       sync.Mutex
+	policies []*models.FeePolicy
+	loadedAt time.Time
+}
+
+func NewFeeService(repo FeePolicyRepository) *FeeService {
+	return &FeeService{repo: repo, now: time.Now}
+}
+
+// activePolicies returns the cached schedule, reloading it when it is older than
+// FeePolicyCacheTTL. A failed reload keeps serving the previous schedule.
+func (s *FeeService) activePolicies(ctx context.Context) ([]*models.FeePolicy, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if !s.loadedAt.IsZero() && s.now().Sub(s.loadedAt) < FeePolicyCacheTTL {
+		return s.policies, nil
+	}
+	policies, err := s.repo.GetActiveFeePolicies(ctx)
+	if err != nil {
+		if !s.loadedAt.IsZero() {
+			log.Printf("reload fee policies, serving cached schedule: %v", err)
+			return s.policies, nil
+		}
+		return nil, err
+	}
+	s.policies = policies
+	s.loadedAt = s.now()
+	return policies, nil
+}
+
+// PolicyFor returns the fee policy that applies to a sale of the listing now.
+func (s *FeeService) PolicyFor(ctx context.Context, listingID string) (*models.FeePolicy, error) {
+	subject, err := s.repo.GetFeeSubject(ctx, listingID)
+	if err != nil {
+		return nil, err
+	}
+	policies, err := s.activePolicies(ctx)
+	if err != nil {
+		return nil, err
+	}
+	p := selectFeePolicy(policies, subject, s.now())
+	if p == nil {
+		return nil, ErrNoFeePolicy
+	}
+	return p, nil
+}
+
+// feePolicySpecificity counts the conditions a policy sets; among policies of the same
+// priority the most specific wins.
+func feePolicySpecificity(p *models.FeePolicy) int {
+	n := 0
+	if p.CategoryID != "" {
+		n++
+	}
+	if p.SellerTier != "" {
+		n++
+	}
+	return n
+}
+
+// selectFeePolicy returns the matching policy with the highest priority, then the most
+// specific one, then the lowest ID so the choice is stable. It returns nil when none matches.
+func selectFeePolicy(policies []*models.FeePolicy, subject *models.FeeSubject, at time.Time) *models.FeePolicy {
+	var best *models.FeePolicy
+	for _, p := range policies {
+		if p.CategoryID != "" && !slices.Contains(subject.CategoryIDs, p.CategoryID) {
+			continue
+		}
+		if p.SellerTier != "" && p.SellerTier != subject.SellerTier {
+			continue
+		}
+		if p.StartsAt != nil && at.Before(*p.StartsAt) {
+			continue
+		}
+		if p.EndsAt != nil && !at.Before(*p.EndsAt) {
+			continue
+		}
+
+		if best == nil || p.Priority > best.Priority {
+			best = p
+			continue
+		}
+		if p.Priority < best.Priority {
+			continue
+		}
+		if ps, bs := feePolicySpecificity(p), feePolicySpecificity(best); ps > bs || (ps == bs && p.ID < best.ID) {
+			best = p
+		}
+	}
+	return best
+}
+
+// PlatformFee applies the policy to an item subtotal: the rate, rounded as configured, then
+// the minimum and maximum caps. The fee never exceeds the subtotal.
+func PlatformFee(p *models.FeePolicy, subtotal int) int {
+	scaled := subtotal * p.RateBps
+	var fee int
+	switch p.Rounding {
+	case models.FeeRoundingDown:
+		fee = scaled / 10000
+	case models.FeeRoundingHalfUp:
+		fee = (scaled + 5000) / 10000
+	default:
+		fee = (scaled + 9999) / 10000
+	}
+
+	fee = max(fee, p.MinFee)
+	if p.MaxFee > 0 {
+		fee = min(fee, p.MaxFee)
+	}
+	return min(fee, subtotal)
+}
@@ -0,0 +1,156 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"uttc-hackathon-backend/internal/models"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+type MockFeePolicyRepository struct {
+	mock.Mock
+}
+
+func (m *MockFeePolicyRepository) GetActiveFeePolicies(ctx context.Context) ([]*models.FeePolicy, error) {
+	args := m.Called(ctx)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]*models.FeePolicy), args.Error(1)
+}
+
+func (m *MockFeePolicyRepository) GetFeeSubject(ctx context.Context, listingID string) (*models.FeeSubject, error) {
+	args := m.Called(ctx, listingID)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*models.FeeSubject), args.Error(1)
+}
+
+type MockFeeSchedule struct {
+	mock.Mock
+}
+
+func (m *MockFeeSchedule) PolicyFor(ctx context.Context, listingID string) (*models.FeePolicy, error) {
+	args := m.Called(ctx, listingID)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*models.FeePolicy), args.Error(1)
+}
+
+var defaultFeePolicy = &models.FeePolicy{ID: "fee_default", RateBps: 1000, Rounding: models.FeeRoundingUp}
+
+// newTestFeeSchedule returns a fee schedule that charges the standard 10% on every listing.
+func newTestFeeSchedule() *MockFeeSchedule {
+	fees := new(MockFeeSchedule)
+	fees.On("PolicyFor", mock.Anything, mock.Anything).Return(defaultFeePolicy, nil).Maybe()
+	return fees
+}
+
+func TestPlatformFee(t *testing.T) {
+	tests := []struct {
+		name     string
+		policy   models.FeePolicy
+		subtotal int
+		want     int
+	}{
+		{name: "Round Up", policy: models.FeePolicy{RateBps: 1000, Rounding: models.FeeRoundingUp}, subtotal: 999, want: 100},
+		{name: "Round Down", policy: models.FeePolicy{RateBps: 1000, Rounding: models.FeeRoundingDown}, subtotal: 999, want: 99},
+		{name: "Half Up Below Half", policy: models.FeePolicy{RateBps: 850, Rounding: models.FeeRoundingHalfUp}, subtotal: 1005, want: 85},
+		{name: "Half Up At Half", policy: models.FeePolicy{RateBps: 1000, Rounding: models.FeeRoundingHalfUp}, subtotal: 1005, want: 101},
+		{name: "Exact", policy: models.FeePolicy{RateBps: 1000, Rounding: models.FeeRoundingUp}, subtotal: 1000, want: 100},
+		{name: "Minimum Fee", policy: models.FeePolicy{RateBps: 1000, MinFee: 50, Rounding: models.FeeRoundingUp}, subtotal: 300, want: 50},
+		{name: "Maximum Fee", policy: models.FeePolicy{RateBps: 1000, MaxFee: 5000, Rounding: models.FeeRoundingUp}, subtotal: 100000, want: 5000},
+		{name: "Minimum Above Subtotal", policy: models.FeePolicy{RateBps: 1000, MinFee: 500, Rounding: models.FeeRoundingUp}, subtotal: 300, want: 300},
+		{name: "Free Promotion", policy: models.FeePolicy{RateBps: 0, Rounding: models.FeeRoundingUp}, subtotal: 3000, want: 0},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.want, PlatformFee(&tt.policy, tt.subtotal))
+		})
+	}
+}
+
+func TestSelectFeePolicy(t *testing.T) {
+	now := time.Date(2024, 11, 15, 12, 0, 0, 0, time.UTC)
+	saleStart := time.Date(2024, 11, 11, 0, 0, 0, 0, time.UTC)
+	saleEnd := time.Date(2024, 11, 18, 0, 0, 0, 0, time.UTC)
+	past := time.Date(2024, 11, 1, 0, 0, 0, 0, time.UTC)
+
+	base := &models.FeePolicy{ID: "fee_default", RateBps: 1000}
+	fashion := &models.FeePolicy{ID: "fee_fashion", CategoryID: "cat_fashion", RateBps: 800}
+	gold := &models.FeePolicy{ID: "fee_gold", SellerTier: models.SellerTierGold, RateBps: 700}
+	goldFashion := &models.FeePolicy{ID: "fee_gold_fashion", CategoryID: "cat_fashion", SellerTier: models.SellerTierGold, RateBps: 600}
+	sale := &models.FeePolicy{ID: "fee_sale", RateBps: 500, Priority: 10, StartsAt: &saleStart, EndsAt: &saleEnd}
+	ended := &models.FeePolicy{ID: "fee_ended", RateBps: 100, Priority: 20, EndsAt: &past}
+
+	tests := []struct {
+		name     string
+		policies []*models.FeePolicy
+		subject  models.FeeSubject
+		want     *models.FeePolicy
+	}{
+		{name: "Catch-All", policies: []*models.FeePolicy{base, fashion}, subject: models.FeeSubject{SellerTier: models.SellerTierStandard}, want: base},
+		{name: "Category Over Catch-All", policies: []*models.FeePolicy{base, fashion}, subject: models.FeeSubject{CategoryIDs: []string{"cat_root", "cat_fashion"}}, want: fashion},
+		{name: "Tier", policies: []*models.FeePolicy{base, gold}, subject: models.FeeSubject{SellerTier: models.SellerTierGold}, want: gold},
+		{name: "Category And Tier Most Specific", policies: []*models.FeePolicy{base, fashion, gold, goldFashion}, subject: models.FeeSubject{SellerTier: models.SellerTierGold, CategoryIDs: []string{"cat_fashion"}}, want: goldFashion},
+		{name: "Promotion Priority Wins", policies: []*models.FeePolicy{base, goldFashion, sale}, subject: models.FeeSubject{SellerTier: models.SellerTierGold, CategoryIDs: []string{"cat_fashion"}}, want: sale},
+		{name: "Ended Promotion Ignored", policies: []*models.FeePolicy{base, ended}, subject: models.FeeSubject{}, want: base},
+		{name: "Same Specificity Lowest ID", policies: []*models.FeePolicy{gold, fashion}, subject: models.FeeSubject{SellerTier: models.SellerTierGold, CategoryIDs: []string{"cat_fashion"}}, want: fashion},
+		{name: "Nothing Matches", policies: []*models.FeePolicy{fashion, gold}, subject: models.FeeSubject{SellerTier: models.SellerTierStandard}, want: nil},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.want, selectFeePolicy(tt.policies, &tt.subject, now))
+		})
+	}
+
+	t.Run("Promotion Window Is Half Open", func(t *testing.T) {
+		policies := []*models.FeePolicy{base, sale}
+		assert.Equal(t, sale, selectFeePolicy(policies, &models.FeeSubject{}, saleStart))
+		assert.Equal(t, base, selectFeePolicy(policies, &models.FeeSubject{}, saleEnd))
+	})
+}
+
+func TestFeeService_PolicyFor_Cache(t *testing.T) {
+	now := time.Date(2024, 7, 1, 0, 0, 0, 0, time.UTC)
+	repo := new(MockFeePolicyRepository)
+	repo.On("GetFeeSubject", mock.Anything, "lst1").Return(&models.FeeSubject{SellerTier: models.SellerTierStandard}, nil)
+	repo.On("GetActiveFeePolicies", mock.Anything).Return([]*models.FeePolicy{defaultFeePolicy}, nil).Once()
+
+	s := NewFeeService(repo)
+	s.now = func() time.Time { return now }
+
+	for range 3 {
+		p, err := s.PolicyFor(context.Background(), "lst1")
+		assert.NoError(t, err)
+		assert.Equal(t, defaultFeePolicy, p)
+	}
+	repo.AssertNumberOfCalls(t, "GetActiveFeePolicies", 1)
+
+	// After the TTL the schedule is reloaded; a failed reload keeps the cached one
+	now = now.Add(FeePolicyCacheTTL)
+	repo.On("GetActiveFeePolicies", mock.Anything).Return(nil, errors.New("db down")).Once()
+	p, err := s.PolicyFor(context.Background(), "lst1")
+	assert.NoError(t, err)
+	assert.Equal(t, defaultFeePolicy, p)
+	repo.AssertNumberOfCalls(t, "GetActiveFeePolicies", 2)
+}
+
+func TestFeeService_PolicyFor_NoPolicy(t *testing.T) {
+	repo := new(MockFeePolicyRepository)
+	repo.On("GetFeeSubject", mock.Anything, "lst1").Return(&models.FeeSubject{SellerTier: models.SellerTierStandard}, nil)
+	repo.On("GetActiveFeePolicies", mock.Anything).Return([]*models.FeePolicy{}, nil)
+
+	_, err := NewFeeService(repo).PolicyFor(context.Background(), "lst1")
+
+	assert.Equal(t, ErrNoFeePolicy, err)
+}
@@ -0,0 +1,213 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log"
+	"strings"
+	"sync"
+	"time"
+	"unicode/utf8"
+	"uttc-hackathon-backend/internal/models"
+	"uttc-hackathon-backend/internal/repository"
+
+	"github.com/oklog/ulid/v2"
+)
+
+const (
+	// FraudRulesCacheTTL is how long the fraud rules are served from memory before they are
+	// reloaded, so limit changes take effect within this time.
+	FraudRulesCacheTTL       = 5 * time.Minute
+	MaxFraudReviewNote       = 1000
+	DefaultFraudReviewsLimit = 50
+	MaxFraudReviewsLimit     = 100
+)
+
+var (
+	// ErrOrderBlocked matches every FraudViolationError.
+	ErrOrderBlocked             = errors.New("this order cannot be placed right now")
+	ErrInvalidFraudReviewStatus = errors.New("status must be cleared or confirmed")
+	ErrFraudReviewResolved      = errors.New("the fraud review has already been resolved")
+	ErrFraudReviewNoteLong      = errors.New("note must be 1000 characters or fewer")
+)
+
+// FraudViolationError is returned for an order that breaks a fraud rule. Rule is the error
+// code shown to the buyer; ReviewID is the review opened for admins.
+type FraudViolationError struct {
+	Rule     models.FraudRuleKind
+	ReviewID string
+}
+
+func (e *FraudViolationError) Error() string {
+	return fmt.Sprintf("%s: %s", ErrOrderBlocked, e.Rule)
+}
+
+func (e *FraudViolationError) Is(target error) bool {
+	return target == ErrOrderBlocked
+}
+
+type FraudRepository interface {
+	GetActiveFraudRules(ctx context.Context) ([]*models.FraudRule, error)
+	GetOrderSubject(ctx context.Context, listingID, variantID string) (string, int, error)
+	GetFraudActivity(ctx context.Context, buyerID, sellerID string, since time.Time) (*models.FraudActivity, error)
+	RecordFraudBlock(ctx context.Context, rv *models.FraudReview) (string, error)
+	GetFraudReviews(ctx context.Context, status models.FraudReviewStatus, limit, offset int) ([]*models.FraudReview, error)
+	UpdateFraudReview(ctx context.Context, reviewID string, fn func(*models.FraudReview) error) (*models.FraudReview, error)
+}
+
+// FraudService runs velocity checks on new orders against the rules stored in the database,
+// which it caches in process, and keeps the queue of blocked orders for admins.
+type FraudService struct {
+	repo FraudRepository
+	now  func() time.Time
+
+	mu       sync.Mutex
+	rules    []*models.FraudRule
+	loadedAt time.Time
+}
+
+func NewFraudService(repo FraudRepository) *FraudService {
+	return &FraudService{repo: repo, now: time.Now}
+}
+
+// activeRules returns the cached rules, reloading them when they are older than
+// FraudRulesCacheTTL. A failed reload keeps serving the previous rules.
+func (s *FraudService) activeRules(ctx context.Context) ([]*models.FraudRule, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if !s.loadedAt.IsZero() && s.now().Sub(s.loadedAt) < FraudRulesCacheTTL {
+		return s.rules, nil
+	}
+	rules, err := s.repo.GetActiveFraudRules(ctx)
+	if err != nil {
+		if !s.loadedAt.IsZero() {
+			log.Printf("reload fraud rules, serving cached rules: %v", err)
+			return s.rules, nil
+		}
+		return nil, err
+	}
+	s.rules = rules
+	s.loadedAt = s.now()
+	return rules, nil
+}
+
+// CheckOrder measures the order the buyer is about to place against each rule and returns a
+// FraudViolationError for the first it breaks, recording the attempt for review. The order
+// counts at its item subtotal; shipping is left out. Orders for missing listings pass, so
+// that creating them reports the real error.
+func (s *FraudService) CheckOrder(ctx context.Context, buyerID string, req *models.Order) error {
+	rules, err := s.activeRules(ctx)
+	if err != nil || len(rules) == 0 {
+		return err
+	}
+
+	sellerID, price, err := s.repo.GetOrderSubject(ctx, req.ListingID, req.VariantID)
+	if err != nil {
+		if errors.Is(err, repository.ErrListingNotFound) {
+			return nil
+		}
+		return err
+	}
+	amount := price * req.Quantity
+
+	now := s.now()
+	// Rules sharing a window share the measurement
+	activity := make(map[time.Duration]*models.FraudActivity)
+	for _, rule := range rules {
+		a, ok := activity[rule.Window]
+		if !ok {
+			a, err = s.repo.GetFraudActivity(ctx, buyerID, sellerID, now.Add(-rule.Window))
+			if err != nil {
+				return err
+			}
+			activity[rule.Window] = a
+		}
+
+		var observed int
+		switch rule.Rule {
+		case models.FraudRuleOrderVelocity:
+			observed = a.Orders + 1
+		case models.FraudRuleSpendLimit:
+			observed = a.Spend + amount
+		case models.FraudRuleNewAccountLimit:
+			if a.AccountCreatedAt.IsZero() || now.Sub(a.AccountCreatedAt) >= rule.MaxAccountAge {
+				continue
+			}
+			observed = a.Spend + amount
+		case models.FraudRuleSameSellerVelocity:
+			observed = a.SameSellerOrders + 1
+		default:
+			continue
+		}
+		if observed <= rule.Limit {
+			continue
+		}
+
+		rv := &models.FraudReview{
+			ID:            "frv_" + ulid.Make().String(),
+			BuyerID:       buyerID,
+			SellerID:      sellerID,
+			ListingID:     req.ListingID,
+			Rule:          rule.Rule,
+			Limit:         rule.Limit,
+			Observed:      observed,
+			Attempts:      1,
+			Status:        models.FraudReviewStatusOpen,
+			CreatedAt:     now,
+			LastAttemptAt: now,
+		}
+		// The order is blocked even if the review cannot be written
+		reviewID, err := s.repo.RecordFraudBlock(ctx, rv)
+		if err != nil {
+			log.Printf("record fraud block for buyer %s: %v", buyerID, err)
+		}
+		return &FraudViolationError{Rule: rule.Rule, ReviewID: reviewID}
+	}
+	return nil
+}
+
+// GetReviews returns fraud reviews with the status, or all when status is empty, oldest first.
+func (s *FraudService) GetReviews(ctx context.Context, status models.FraudReviewStatus, limit, offset int) ([]*models.FraudReview, error) {
+	switch status {
+	case "", models.FraudReviewStatusOpen, models.FraudReviewStatusCleared, models.FraudReviewStatusConfirmed:
+	default:
+		return nil, ErrInvalidFraudReviewStatus
+	}
+	if limit <= 0 {
+		limit = DefaultFraudReviewsLimit
+	}
+	if limit > MaxFraudReviewsLimit {
+		limit = MaxFraudReviewsLimit
+	}
+	if offset < 0 {
+		offset = 0
+	}
+	return s.repo.GetFraudReviews(ctx, status, limit, offset)
+}
+
+// ResolveReview records an admin's decision on an open review. Clearing does not lift the
+// limit: the buyer can order again once the window has passed or the rule is relaxed.
+// Confirmed fraud is acted on separately, e.g. by suspending the account.
+func (s *FraudService) ResolveReview(ctx context.Context, adminID, reviewID string, status models.FraudReviewStatus, note string) (*models.FraudReview, error) {
+	if status != models.FraudReviewStatusCleared && status != models.FraudReviewStatusConfirmed {
+		return nil, ErrInvalidFraudReviewStatus
+	}
+	note = strings.TrimSpace(note)
+	if utf8.RuneCountInString(note) > MaxFraudReviewNote {
+		return nil, ErrFraudReviewNoteLong
+	}
+
+	return s.repo.UpdateFraudReview(ctx, reviewID, func(rv *models.FraudReview) error {
+		if rv.Status != models.FraudReviewStatusOpen {
+			return ErrFraudReviewResolved
+		}
+		now := s.now()
+		rv.Status = status
+		rv.ReviewedBy = adminID
+		rv.Note = note
+		rv.ReviewedAt = &now
+		return nil
+	})
+}
@@ -0,0 +1,269 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"uttc-hackathon-backend/internal/models"
+	"uttc-hackathon-backend/internal/repository"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+type MockFraudRepository struct {
+	mock.Mock
+}
+
+func (m *MockFraudRepository) GetActiveFraudRules(ctx context.Context) ([]*models.FraudRule, error) {
+	args := m.Called(ctx)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]*models.FraudRule), args.Error(1)
+}
+
+func (m *MockFraudRepository) GetOrderSubject(ctx context.Context, listingID, variantID string) (string, int, error) {
+	args := m.Called(ctx, listingID, variantID)
+	return args.String(0), args.Int(1), args.Error(2)
+}
+
+func (m *MockFraudRepository) GetFraudActivity(ctx context.Context, buyerID, sellerID string, since time.Time) (*models.FraudActivity, error) {
+	args := m.Called(ctx, buyerID, sellerID, since)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*models.FraudActivity), args.Error(1)
+}
+
+func (m *MockFraudRepository) RecordFraudBlock(ctx context.Context, rv *models.FraudReview) (string, error) {
+	args := m.Called(ctx, rv)
+	return args.String(0), args.Error(1)
+}
+
+func (m *MockFraudRepository) GetFraudReviews(ctx context.Context, status models.FraudReviewStatus, limit, offset int) ([]*models.FraudReview, error) {
+	args := m.Called(ctx, status, limit, offset)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]*models.FraudReview), args.Error(1)
+}
+
+func (m *MockFraudRepository) UpdateFraudReview(ctx context.Context, reviewID string, fn func(*models.FraudReview) error) (*models.FraudReview, error) {
+	args := m.Called(ctx, reviewID, fn)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*models.FraudReview), args.Error(1)
+}
+
+type MockFraudScreen struct {
+	mock.Mock
+}
+
+func (m *MockFraudScreen) CheckOrder(ctx context.Context, buyerID string, req *models.Order) error {
+	args := m.Called(ctx, buyerID, req)
+	return args.Error(0)
+}
+
+// newTestFraudScreen returns a fraud screen that lets every order through.
+func newTestFraudScreen() *MockFraudScreen {
+	fraud := new(MockFraudScreen)
+	fraud.On("CheckOrder", mock.Anything, mock.Anything, mock.Anything).Return(nil).Maybe()
+	return fraud
+}
+
+var fraudTestNow = time.Date(2024, 8, 1, 12, 0, 0, 0, time.UTC)
+
+var testFraudRules = []*models.FraudRule{
+	{Rule: models.FraudRuleOrderVelocity, Limit: 10, Window: time.Hour},
+	{Rule: models.FraudRuleSpendLimit, Limit: 100000, Window: 24 * time.Hour},
+	{Rule: models.FraudRuleNewAccountLimit, Limit: 30000, Window: 24 * time.Hour, MaxAccountAge: 72 * time.Hour},
+	{Rule: models.FraudRuleSameSellerVelocity, Limit: 3, Window: 7 * 24 * time.Hour},
+}
+
+func TestFraudService_CheckOrder(t *testing.T) {
+	oldAccount := fraudTestNow.Add(-365 * 24 * time.Hour)
+
+	tests := []struct {
+		name      string
+		quantity  int
+		hour      *models.FraudActivity
+		day       *models.FraudActivity
+		week      *models.FraudActivity
+		wantRule  models.FraudRuleKind
+		wantLimit int
+		wantSeen  int
+	}{
+		{
+			name:     "Within Limits",
+			quantity: 1,
+			hour:     &models.FraudActivity{AccountCreatedAt: oldAccount, Orders: 9},
+			day:      &models.FraudActivity{AccountCreatedAt: oldAccount, Spend: 90000},
+			week:     &models.FraudActivity{AccountCreatedAt: oldAccount, SameSellerOrders: 2},
+		},
+		{
+			name:      "Too Many Orders In An Hour",
+			quantity:  1,
+			hour:      &models.FraudActivity{AccountCreatedAt: oldAccount, Orders: 10},
+			wantRule:  models.FraudRuleOrderVelocity,
+			wantLimit: 10,
+			wantSeen:  11,
+		},
+		{
+			name:      "Daily Spend Counts The New Order",
+			quantity:  2,
+			hour:      &models.FraudActivity{AccountCreatedAt: oldAccount, Orders: 1},
+			day:       &models.FraudActivity{AccountCreatedAt: oldAccount, Spend: 95000},
+			wantRule:  models.FraudRuleSpendLimit,
+			wantLimit: 100000,
+			wantSeen:  105000,
+		},
+		{
+			name:      "New Account Limit",
+			quantity:  1,
+			hour:      &models.FraudActivity{AccountCreatedAt: fraudTestNow.Add(-time.Hour)},
+			day:       &models.FraudActivity{AccountCreatedAt: fraudTestNow.Add(-time.Hour), Spend: 27000},
+			wantRule:  models.FraudRuleNewAccountLimit,
+			wantLimit: 30000,
+			wantSeen:  32000,
+		},
+		{
+			name:     "Older Accounts Skip The New Account Limit",
+			quantity: 1,
+			hour:     &models.FraudActivity{AccountCreatedAt: fraudTestNow.Add(-72 * time.Hour)},
+			day:      &models.FraudActivity{AccountCreatedAt: fraudTestNow.Add(-72 * time.Hour), Spend: 27000},
+			week:     &models.FraudActivity{AccountCreatedAt: fraudTestNow.Add(-72 * time.Hour)},
+		},
+		{
+			name:      "Repeated Orders From One Seller",
+			quantity:  1,
+			hour:      &models.FraudActivity{AccountCreatedAt: oldAccount},
+			day:       &models.FraudActivity{AccountCreatedAt: oldAccount},
+			week:      &models.FraudActivity{AccountCreatedAt: oldAccount, SameSellerOrders: 3},
+			wantRule:  models.FraudRuleSameSellerVelocity,
+			wantLimit: 3,
+			wantSeen:  4,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			repo := new(MockFraudRepository)
+			repo.On("GetActiveFraudRules", mock.Anything).Return(testFraudRules, nil)
+			repo.On("GetOrderSubject", mock.Anything, "lst1", "").Return("seller1", 5000, nil)
+			repo.On("GetFraudActivity", mock.Anything, "buyer1", "seller1", fraudTestNow.Add(-time.Hour)).Return(tt.hour, nil).Maybe()
+			repo.On("GetFraudActivity", mock.Anything, "buyer1", "seller1", fraudTestNow.Add(-24*time.Hour)).Return(tt.day, nil).Maybe()
+			repo.On("GetFraudActivity", mock.Anything, "buyer1", "seller1", fraudTestNow.Add(-7*24*time.Hour)).Return(tt.week, nil).Maybe()
+			repo.On("RecordFraudBlock", mock.Anything, mock.Anything).Return("frv_1", nil).Maybe()
+
+			s := NewFraudService(repo)
+			s.now = func() time.Time { return fraudTestNow }
+
+			err := s.CheckOrder(context.Background(), "buyer1", &models.Order{ListingID: "lst1", Quantity: tt.quantity})
+
+			if tt.wantRule == "" {
+				assert.NoError(t, err)
+				repo.AssertNotCalled(t, "RecordFraudBlock", mock.Anything, mock.Anything)
+				return
+			}
+			var violation *FraudViolationError
+			assert.ErrorAs(t, err, &violation)
+			assert.ErrorIs(t, err, ErrOrderBlocked)
+			assert.Equal(t, tt.wantRule, violation.Rule)
+			assert.Equal(t, "frv_1", violation.ReviewID)
+			repo.AssertCalled(t, "RecordFraudBlock", mock.Anything, mock.MatchedBy(func(rv *models.FraudReview) bool {
+				return rv.BuyerID == "buyer1" && rv.SellerID == "seller1" && rv.ListingID == "lst1" && rv.Rule == tt.wantRule &&
+					rv.Limit == tt.wantLimit && rv.Observed == tt.wantSeen && rv.Status == models.FraudReviewStatusOpen
+			}))
+		})
+	}
+}
+
+func TestFraudService_CheckOrder_BlocksWhenReviewFails(t *testing.T) {
+	repo := new(MockFraudRepository)
+	repo.On("GetActiveFraudRules", mock.Anything).Return(testFraudRules[:1], nil)
+	repo.On("GetOrderSubject", mock.Anything, "lst1", "").Return("seller1", 5000, nil)
+	repo.On("GetFraudActivity", mock.Anything, "buyer1", "seller1", mock.Anything).Return(&models.FraudActivity{Orders: 10}, nil)
+	repo.On("RecordFraudBlock", mock.Anything, mock.Anything).Return("", errors.New("db down"))
+
+	s := NewFraudService(repo)
+	err := s.CheckOrder(context.Background(), "buyer1", &models.Order{ListingID: "lst1", Quantity: 1})
+
+	assert.ErrorIs(t, err, ErrOrderBlocked)
+}
+
+func TestFraudService_CheckOrder_MissingListingPasses(t *testing.T) {
+	repo := new(MockFraudRepository)
+	repo.On("GetActiveFraudRules", mock.Anything).Return(testFraudRules, nil)
+	repo.On("GetOrderSubject", mock.Anything, "lst1", "").Return("", 0, repository.ErrListingNotFound)
+
+	s := NewFraudService(repo)
+	err := s.CheckOrder(context.Background(), "buyer1", &models.Order{ListingID: "lst1", Quantity: 1})
+
+	assert.NoError(t, err)
+	repo.AssertNotCalled(t, "GetFraudActivity", mock.Anything, mock.Anything, mock.Anything, mock.Anything)
+}
+
+func TestFraudService_CheckOrder_Cache(t *testing.T) {
+	now := fraudTestNow
+	repo := new(MockFraudRepository)
+	repo.On("GetActiveFraudRules", mock.Anything).Return(testFraudRules[:1], nil).Once()
+	repo.On("GetOrderSubject", mock.Anything, "lst1", "").Return("seller1", 5000, nil)
+	repo.On("GetFraudActivity", mock.Anything, "buyer1", "seller1", mock.Anything).Return(&models.FraudActivity{}, nil)
+
+	s := NewFraudService(repo)
+	s.now = func() time.Time { return now }
+
+	for range 3 {
+		assert.NoError(t, s.CheckOrder(context.Background(), "buyer1", &models.Order{ListingID: "lst1", Quantity: 1}))
+	}
+	repo.AssertNumberOfCalls(t, "GetActiveFraudRules", 1)
+
+	// After the TTL the rules are reloaded; a failed reload keeps the cached ones
+	now = now.Add(FraudRulesCacheTTL)
+	repo.On("GetActiveFraudRules", mock.Anything).Return(nil, errors.New("db down")).Once()
+	assert.NoError(t, s.CheckOrder(context.Background(), "buyer1", &models.Order{ListingID: "lst1", Quantity: 1}))
+	repo.AssertNumberOfCalls(t, "GetActiveFraudRules", 2)
+}
+
+func TestFraudService_ResolveReview(t *testing.T) {
+	tests := []struct {
+		name    string
+		status  models.FraudReviewStatus
+		current models.FraudReviewStatus
+		wantErr error
+	}{
+		{name: "Clear", status: models.FraudReviewStatusCleared, current: models.FraudReviewStatusOpen},
+		{name: "Confirm", status: models.FraudReviewStatusConfirmed, current: models.FraudReviewStatusOpen},
+		{name: "Already Resolved", status: models.FraudReviewStatusCleared, current: models.FraudReviewStatusConfirmed, wantErr: ErrFraudReviewResolved},
+		{name: "Cannot Reopen", status: models.FraudReviewStatusOpen, wantErr: ErrInvalidFraudReviewStatus},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			repo := new(MockFraudRepository)
+			rv := &models.FraudReview{ID: "frv_1", Status: tt.current}
+			repo.On("UpdateFraudReview", mock.Anything, "frv_1", mock.Anything).Return(rv, tt.wantErr).Run(func(args mock.Arguments) {
+				fn := args.Get(2).(func(*models.FraudReview) error)
+				assert.Equal(t, tt.wantErr, fn(rv))
+			}).Maybe()
+
+			s := NewFraudService(repo)
+			s.now = func() time.Time { return fraudTestNow }
+
+			got, err := s.ResolveReview(context.Background(), "admin1", "frv_1", tt.status, " legitimate bulk buyer ")
+			if tt.wantErr != nil {
+				assert.ErrorIs(t, err, tt.wantErr)
+				return
+			}
+			assert.NoError(t, err)
+			assert.Equal(t, tt.status, got.Status)
+			assert.Equal(t, "admin1", got.ReviewedBy)
+			assert.Equal(t, "legitimate bulk buyer", got.Note)
+			assert.Equal(t, fraudTestNow, *got.ReviewedAt)
+		})
+	}
+}
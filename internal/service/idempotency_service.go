@@ -0,0 +1,100 @@
+package service
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"log"
+	"net/http"
+	"time"
+	"uttc-hackathon-backend/internal/models"
+)
+
+const (
+	// IdempotencyKeyTTL is how long a stored response is replayed for its key.
+	IdempotencyKeyTTL    = 24 * time.Hour
+	MaxIdempotencyKeyLen = 255
+)
+
+var (
+	ErrIdempotencyKeyInvalid  = errors.New("Idempotency-Key must be 1 to 255 characters")
+	ErrIdempotencyKeyInFlight = errors.New("a request with this Idempotency-Key is still in progress")
+	ErrIdempotencyKeyReused   = errors.New("Idempotency-Key was already used for a different request")
+)
+
+type IdempotencyRepository interface {
+	ReserveIdempotencyKey(ctx context.Context, userID, key, fingerprint string, expiredBefore time.Time) (*models.IdempotencyRecord, error)
+	CompleteIdempotencyKey(ctx context.Context, userID, key string, status int, contentType string, body []byte) error
+	ReleaseIdempotencyKey(ctx context.Context, userID, key string) error
+	PurgeIdempotencyKeys(ctx context.Context, before time.Time) error
+}
+
+type IdempotencyService struct {
+	repo IdempotencyRepository
+	now  func() time.Time
+}
+
+func NewIdempotencyService(repo IdempotencyRepository) *IdempotencyService {
+	return &IdempotencyService{repo: repo, now: time.Now}
+}
+
+// RequestFingerprint identifies a request by its method, path and body.
+func RequestFingerprint(method, path string, body []byte) string {
+	h := sha256.New()
+	h.Write([]byte(method + " " + path + "\n"))
+	h.Write(body)
+	return hex.EncodeToString(h.Sum(nil))
+}
+
+// Begin claims the key for the user's request. It returns nil when the request should run,
+// or the completed earlier request whose response must be replayed. A request still running
+// with the key gives ErrIdempotencyKeyInFlight, and a different request that used the key
+// gives ErrIdempotencyKeyReused.
+func (s *IdempotencyService) Begin(ctx context.Context, userID, key, fingerprint string) (*models.IdempotencyRecord, error) {
+	if key == "" || len(key) > MaxIdempotencyKeyLen {
+		return nil, ErrIdempotencyKeyInvalid
+	}
+
+	rec, err := s.repo.ReserveIdempotencyKey(ctx, userID, key, fingerprint, s.now().Add(-IdempotencyKeyTTL))
+	if err != nil || rec == nil {
+		return nil, err
+	}
+	if rec.Fingerprint != fingerprint {
+		return nil, ErrIdempotencyKeyReused
+	}
+	if rec.Status != models.IdempotencyStatusCompleted {
+		return nil, ErrIdempotencyKeyInFlight
+	}
+	return rec, nil
+}
+
+// Finish stores the response of a request started with Begin. Server errors are not stored;
+// the key is released so the client can retry.
+func (s *IdempotencyService) Finish(ctx context.Context, userID, key string, status int, contentType string, body []byte) error {
+	if status >= http.StatusInternalServerError {
+		return s.repo.ReleaseIdempotencyKey(ctx, userID, key)
+	}
+	return s.repo.CompleteIdempotencyKey(ctx, userID, key, status, contentType, body)
+}
+
+// Abandon releases the key of a request that ended without a response.
+func (s *IdempotencyService) Abandon(ctx context.Context, userID, key string) error {
+	return s.repo.ReleaseIdempotencyKey(ctx, userID, key)
+}
+
+// RunPurgeLoop deletes expired keys every interval until ctx is cancelled.
+func (s *IdempotencyService) RunPurgeLoop(ctx context.Context, interval time.Duration) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			if err := s.repo.PurgeIdempotencyKeys(ctx, s.now().Add(-IdempotencyKeyTTL)); err != nil {
+				log.Printf("purge idempotency keys: %v", err)
+			}
+		}
+	}
+}
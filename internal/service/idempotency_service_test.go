@@ -0,0 +1,119 @@
+package service
+
+import (
+	"context"
+	"strings"
+	"testing"
+	"time"
+
+	"uttc-hackathon-backend/internal/models"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+type MockIdempotencyRepository struct {
+	mock.Mock
+}
+
+func (m *MockIdempotencyRepository) ReserveIdempotencyKey(ctx context.Context, userID, key, fingerprint string, expiredBefore time.Time) (*models.IdempotencyRecord, error) {
+	args := m.Called(ctx, userID, key, fingerprint, expiredBefore)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*models.IdempotencyRecord), args.Error(1)
+}
+
+func (m *MockIdempotencyRepository) CompleteIdempotencyKey(ctx context.Context, userID, key string, status int, contentType string, body []byte) error {
+	args := m.Called(ctx, userID, key, status, contentType, body)
+	return args.Error(0)
+}
+
+func (m *MockIdempotencyRepository) ReleaseIdempotencyKey(ctx context.Context, userID, key string) error {
+	args := m.Called(ctx, userID, key)
+	return args.Error(0)
+}
+
+func (m *MockIdempotencyRepository) PurgeIdempotencyKeys(ctx context.Context, before time.Time) error {
+	args := m.Called(ctx, before)
+	return args.Error(0)
+}
+
+func TestRequestFingerprint(t *testing.T) {
+	base := RequestFingerprint("POST", "/orders", []byte(`{"listing_id":"lst1"}`))
+
+	assert.Len(t, base, 64)
+	assert.Equal(t, base, RequestFingerprint("POST", "/orders", []byte(`{"listing_id":"lst1"}`)))
+	assert.NotEqual(t, base, RequestFingerprint("POST", "/orders", []byte(`{"listing_id":"lst2"}`)))
+	assert.NotEqual(t, base, RequestFingerprint("POST", "/listings", []byte(`{"listing_id":"lst1"}`)))
+}
+
+func TestIdempotencyService_Begin(t *testing.T) {
+	now := time.Date(2024, 7, 1, 0, 0, 0, 0, time.UTC)
+	completed := &models.IdempotencyRecord{
+		Fingerprint: "fp1", Status: models.IdempotencyStatusCompleted,
+		ResponseStatus: 201, ResponseContentType: "application/json", ResponseBody: []byte(`{"id":"ord1"}`),
+	}
+
+	tests := []struct {
+		name       string
+		key        string
+		stored     *models.IdempotencyRecord
+		wantReplay bool
+		wantErr    error
+	}{
+		{name: "New Key Runs", key: "k1"},
+		{name: "Completed Retry Replays", key: "k1", stored: completed, wantReplay: true},
+		{name: "In Flight Duplicate", key: "k1", stored: &models.IdempotencyRecord{Fingerprint: "fp1", Status: models.IdempotencyStatusInFlight}, wantErr: ErrIdempotencyKeyInFlight},
+		{name: "Different Body", key: "k1", stored: &models.IdempotencyRecord{Fingerprint: "fp2", Status: models.IdempotencyStatusCompleted}, wantErr: ErrIdempotencyKeyReused},
+		{name: "Different Body While In Flight", key: "k1", stored: &models.IdempotencyRecord{Fingerprint: "fp2", Status: models.IdempotencyStatusInFlight}, wantErr: ErrIdempotencyKeyReused},
+		{name: "Key Too Long", key: strings.Repeat("k", MaxIdempotencyKeyLen+1), wantErr: ErrIdempotencyKeyInvalid},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			repo := new(MockIdempotencyRepository)
+			if tt.wantErr != ErrIdempotencyKeyInvalid {
+				var stored any
+				if tt.stored != nil {
+					stored = tt.stored
+				}
+				repo.On("ReserveIdempotencyKey", mock.Anything, "user1", tt.key, "fp1", now.Add(-IdempotencyKeyTTL)).Return(stored, nil)
+			}
+			s := NewIdempotencyService(repo)
+			s.now = func() time.Time { return now }
+
+			rec, err := s.Begin(context.Background(), "user1", tt.key, "fp1")
+
+			assert.Equal(t, tt.wantErr, err)
+			if tt.wantReplay {
+				assert.Equal(t, tt.stored, rec)
+			} else {
+				assert.Nil(t, rec)
+			}
+			repo.AssertExpectations(t)
+		})
+	}
+}
+
+func TestIdempotencyService_Finish(t *testing.T) {
+	t.Run("Stores Client Errors", func(t *testing.T) {
+		repo := new(MockIdempotencyRepository)
+		repo.On("CompleteIdempotencyKey", mock.Anything, "user1", "k1", 409, "text/plain", []byte("insufficient stock")).Return(nil)
+
+		err := NewIdempotencyService(repo).Finish(context.Background(), "user1", "k1", 409, "text/plain", []byte("insufficient stock"))
+
+		assert.NoError(t, err)
+		repo.AssertExpectations(t)
+	})
+
+	t.Run("Releases On Server Error", func(t *testing.T) {
+		repo := new(MockIdempotencyRepository)
+		repo.On("ReleaseIdempotencyKey", mock.Anything, "user1", "k1").Return(nil)
+
+		err := NewIdempotencyService(repo).Finish(context.Background(), "user1", "k1", 503, "text/plain", []byte("unavailable"))
+
+		assert.NoError(t, err)
+		repo.AssertExpectations(t)
+	})
+}
@@ -0,0 +1,93 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"time"
+	"uttc-hackathon-backend/internal/models"
+)
+
+var ErrInvoiceNotAvailable = errors.New("invoice is only available for paid orders")
+
+// OrderReader reads an order on behalf of one of its parties, failing with ErrUnauthorized
+// for anyone else. OrderService implements it.
+type OrderReader interface {
+	GetOrder(ctx context.Context, userID, orderID string) (*models.Order, error)
+}
+
+// InvoiceUserReader looks up the names printed on an invoice.
+type InvoiceUserReader interface {
+	GetUserProfile(ctx context.Context, id string) (*models.UserProfile, error)
+}
+
+// InvoiceService issues invoices for orders from their stored tax breakdown.
+type InvoiceService struct {
+	orders OrderReader
+	users  InvoiceUserReader
+	now    func() time.Time
+}
+
+func NewInvoiceService(orders OrderReader, users InvoiceUserReader) *InvoiceService {
+	return &InvoiceService{orders: orders, users: users, now: time.Now}
+}
+
+// GetInvoice returns the invoice for an order to its buyer or seller. Orders that were never
+// paid or were cancelled have no sale to invoice.
+func (s *InvoiceService) GetInvoice(ctx context.Context, userID, orderID string) (*models.Invoice, error) {
+	o, err := s.orders.GetOrder(ctx, userID, orderID)
+	if err != nil {
+		return nil, err
+	}
+	if o.Status == models.OrderStatusPendingPayment || o.Status == models.OrderStatusCancelled {
+		return nil, ErrInvoiceNotAvailable
+	}
+
+	seller, err := s.users.GetUserProfile(ctx, o.SellerID)
+	if err != nil {
+		return nil, err
+	}
+	buyer, err := s.users.GetUserProfile(ctx, o.BuyerID)
+	if err != nil {
+		return nil, err
+	}
+	return buildInvoice(o, seller.Name, buyer.Name, s.now()), nil
+}
+
+// buildInvoice lists the item and, when the buyer paid it, the shipping fee. The registration
+// number is the one snapshotted on the order, so later changes to the seller's registration
+// do not alter past invoices.
+func buildInvoice(o *models.Order, sellerName, buyerName string, issuedAt time.Time) *models.Invoice {
+	description := o.ListingTitle
+	if o.VariantLabel != "" {
+		description += " (" + o.VariantLabel + ")"
+	}
+	lines := []models.InvoiceLine{{
+		Description: description,
+		TaxRate:     o.ItemTaxRate,
+		UnitPrice:   o.ListingPrice,
+		Quantity:    o.Quantity,
+		Amount:      o.ListingPrice * o.Quantity,
+	}}
+	if o.ShippingFee > 0 {
+		lines = append(lines, models.InvoiceLine{
+			Description: "送料",
+			TaxRate:     models.TaxRateStandard,
+			UnitPrice:   o.ShippingFee,
+			Quantity:    1,
+			Amount:      o.ShippingFee,
+		})
+	}
+
+	return &models.Invoice{
+		OrderID:                  o.ID,
+		Qualified:                o.SellerInvoiceNumber != "",
+		SellerName:               sellerName,
+		SellerRegistrationNumber: o.SellerInvoiceNumber,
+		BuyerName:                buyerName,
+		TransactionDate:          o.CreatedAt,
+		IssuedAt:                 issuedAt,
+		Lines:                    lines,
+		Tax:                      o.Tax,
+		Total:                    o.TotalPrice,
+	}
+}
@@ -0,0 +1,119 @@
+package service
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"uttc-hackathon-backend/internal/models"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+func TestInvoiceService_GetInvoice(t *testing.T) {
+	createdAt := time.Date(2024, 10, 1, 3, 0, 0, 0, time.UTC)
+	issuedAt := time.Date(2024, 10, 5, 0, 0, 0, 0, time.UTC)
+	paid := func() *models.Order {
+		return &models.Order{
+			ID: "ord1", BuyerID: "buyer1", SellerID: "seller1", SellerInvoiceNumber: "T7000012050002",
+			ListingTitle: "Rice 5kg", VariantLabel: "Koshihikari", ListingPrice: 1000, ItemTaxRate: models.TaxRateReduced, Quantity: 2,
+			ShippingPayer: models.ShippingPayerBuyer, ShippingFee: 210, TotalPrice: 2210,
+			Tax:    models.TaxBreakdown{StandardRateTotal: 210, StandardRateTax: 19, ReducedRateTotal: 2000, ReducedRateTax: 148},
+			Status: models.OrderStatusShipped, CreatedAt: createdAt,
+		}
+	}
+
+	tests := []struct {
+		name    string
+		userID  string
+		order   func() *models.Order
+		want    *models.Invoice
+		wantErr error
+	}{
+		{
+			name:   "Qualified Invoice For Buyer",
+			userID: "buyer1",
+			order:  paid,
+			want: &models.Invoice{
+				OrderID: "ord1", Qualified: true, SellerName: "Seller", SellerRegistrationNumber: "T7000012050002", BuyerName: "Buyer",
+				TransactionDate: createdAt, IssuedAt: issuedAt,
+				Lines: []models.InvoiceLine{
+					{Description: "Rice 5kg (Koshihikari)", TaxRate: models.TaxRateReduced, UnitPrice: 1000, Quantity: 2, Amount: 2000},
+					{Description: "送料", TaxRate: models.TaxRateStandard, UnitPrice: 210, Quantity: 1, Amount: 210},
+				},
+				Tax:   models.TaxBreakdown{StandardRateTotal: 210, StandardRateTax: 19, ReducedRateTotal: 2000, ReducedRateTax: 148},
+				Total: 2210,
+			},
+		},
+		{
+			name:   "Unregistered Seller",
+			userID: "seller1",
+			order: func() *models.Order {
+				o := paid()
+				o.SellerInvoiceNumber = ""
+				o.VariantLabel = ""
+				o.ShippingFee = 0
+				o.TotalPrice = 2000
+				o.Tax = models.TaxBreakdown{ReducedRateTotal: 2000, ReducedRateTax: 148}
+				return o
+			},
+			want: &models.Invoice{
+				OrderID: "ord1", SellerName: "Seller", BuyerName: "Buyer",
+				TransactionDate: createdAt, IssuedAt: issuedAt,
+				Lines: []models.InvoiceLine{
+					{Description: "Rice 5kg", TaxRate: models.TaxRateReduced, UnitPrice: 1000, Quantity: 2, Amount: 2000},
+				},
+				Tax:   models.TaxBreakdown{ReducedRateTotal: 2000, ReducedRateTax: 148},
+				Total: 2000,
+			},
+		},
+		{
+			name:    "Not A Party",
+			userID:  "other",
+			order:   paid,
+			wantErr: ErrUnauthorized,
+		},
+		{
+			name:   "Pending Payment",
+			userID: "buyer1",
+			order: func() *models.Order {
+				o := paid()
+				o.Status = models.OrderStatusPendingPayment
+				return o
+			},
+			wantErr: ErrInvoiceNotAvailable,
+		},
+		{
+			name:   "Cancelled",
+			userID: "buyer1",
+			order: func() *models.Order {
+				o := paid()
+				o.Status = models.OrderStatusCancelled
+				return o
+			},
+			wantErr: ErrInvoiceNotAvailable,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			orderRepo := new(MockOrderRepository)
+			orderRepo.On("GetOrder", mock.Anything, "ord1").Return(tt.order(), nil)
+			userRepo := new(MockUserRepository)
+			userRepo.On("GetUserProfile", mock.Anything, "seller1").Return(&models.UserProfile{ID: "seller1", Name: "Seller"}, nil).Maybe()
+			userRepo.On("GetUserProfile", mock.Anything, "buyer1").Return(&models.UserProfile{ID: "buyer1", Name: "Buyer"}, nil).Maybe()
+
+			s := NewInvoiceService(newTestOrderService(orderRepo), NewUserService(userRepo, new(MockFirebaseRepository)))
+			s.now = func() time.Time { return issuedAt }
+			got, err := s.GetInvoice(context.Background(), tt.userID, "ord1")
+
+			if tt.wantErr != nil {
+				assert.Equal(t, tt.wantErr, err)
+				return
+			}
+			assert.NoError(t, err)
+			assert.Equal(t, tt.want, got)
+		})
+	}
+}
@@ -0,0 +1,279 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"time"
+	"unicode"
+	"unicode/utf8"
+	"uttc-hackathon-backend/internal/models"
+
+	"github.com/oklog/ulid/v2"
+)
+
+const (
+	// MinPayoutAmount is the smallest payout a seller can request, in yen.
+	MinPayoutAmount     = 1000
+	MaxAccountHolderLen = 100
+	DefaultLedgerLimit  = 50
+	MaxLedgerLimit      = 100
+)
+
+var (
+	ErrInvalidBankCode      = errors.New("bank code must be 4 digits")
+	ErrInvalidBranchCode    = errors.New("branch code must be 3 digits")
+	ErrInvalidAccountType   = errors.New("account type must be ordinary or current")
+	ErrInvalidAccountNumber = errors.New("account number must be 7 digits")
+	ErrInvalidAccountHolder = errors.New("account holder must be full-width katakana, 100 characters or fewer")
+	ErrPayoutTooSmall       = errors.New("payouts must be at least 1000 yen")
+	ErrInsufficientBalance  = errors.New("amount exceeds the available balance")
+	ErrPayoutProcessed      = errors.New("the payout has already been processed")
+	ErrInvalidPayoutStatus  = errors.New("invalid payout status")
+)
+
+type LedgerRepository interface {
+	GetSellerBalance(ctx context.Context, userID string) (*models.SellerBalance, error)
+	GetLedgerEntries(ctx context.Context, userID string, limit, offset int) ([]*models.LedgerEntry, error)
+	GetBankAccount(ctx context.Context, userID string) (*models.BankAccount, error)
+	SaveBankAccount(ctx context.Context, a *models.BankAccount) error
+	CreatePayout(ctx context.Context, userID string, fn func(*models.SellerBalance, *models.BankAccount) (*models.Payout, *models.LedgerTransaction, error)) error
+	UpdatePayout(ctx context.Context, payoutID string, fn func(*models.Payout) (*models.LedgerTransaction, error)) error
+	GetPayouts(ctx context.Context, userID string, status models.PayoutStatus, limit, offset int) ([]*models.Payout, error)
+}
+
+// LedgerService exposes sellers' balances, which are always derived from ledger entries,
+// and moves available money out to their bank accounts.
+type LedgerService struct {
+	repo LedgerRepository
+	now  func() time.Time
+}
+
+func NewLedgerService(repo LedgerRepository) *LedgerService {
+	return &LedgerService{repo: repo, now: time.Now}
+}
+
+func ledgerPage(limit, offset int) (int, int) {
+	if limit <= 0 {
+		limit = DefaultLedgerLimit
+	}
+	if limit > MaxLedgerLimit {
+		limit = MaxLedgerLimit
+	}
+	if offset < 0 {
+		offset = 0
+	}
+	return limit, offset
+}
+
+func (s *LedgerService) GetBalance(ctx context.Context, userID string) (*models.SellerBalance, error) {
+	return s.repo.GetSellerBalance(ctx, userID)
+}
+
+// GetLedger returns the seller's ledger entries, newest first.
+func (s *LedgerService) GetLedger(ctx context.Context, userID string, limit, offset int) ([]*models.LedgerEntry, error) {
+	limit, offset = ledgerPage(limit, offset)
+	return s.repo.GetLedgerEntries(ctx, userID, limit, offset)
+}
+
+func isDigits(v string, n int) bool {
+	if len(v) != n {
+		return false
+	}
+	for _, r := range v {
+		if r < '0' || r > '9' {
+			return false
+		}
+	}
+	return true
+}
+
+// isAccountHolderName reports whether name uses only the characters banks accept for
+// account holders: full-width katakana, the long vowel mark and spaces.
+func isAccountHolderName(name string) bool {
+	if strings.TrimSpace(name) == "" || utf8.RuneCountInString(name) > MaxAccountHolderLen {
+		return false
+	}
+	for _, r := range name {
+		if r >= 0xFF00 { // half-width katakana
+			return false
+		}
+		if !unicode.Is(unicode.Katakana, r) && r != 'ー' && r != '　' && r != ' ' {
+			return false
+		}
+	}
+	return true
+}
+
+// maskAccountNumber hides all but the last three digits.
+func maskAccountNumber(n string) string {
+	if len(n) <= 3 {
+		return n
+	}
+	return strings.Repeat("*", len(n)-3) + n[len(n)-3:]
+}
+
+// GetBankAccount returns the seller's registered account with its number masked.
+func (s *LedgerService) GetBankAccount(ctx context.Context, userID string) (*models.BankAccount, error) {
+	a, err := s.repo.GetBankAccount(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+	a.AccountNumber = maskAccountNumber(a.AccountNumber)
+	return a, nil
+}
+
+// SaveBankAccount validates and registers the seller's payout account, replacing the old one.
+// Payouts already requested keep the account they were requested with.
+func (s *LedgerService) SaveBankAccount(ctx context.Context, userID string, req *models.BankAccount) (*models.BankAccount, error) {
+	if !isDigits(req.BankCode, 4) {
+		return nil, ErrInvalidBankCode
+	}
+	if !isDigits(req.BranchCode, 3) {
+		return nil, ErrInvalidBranchCode
+	}
+	if req.AccountType != models.BankAccountTypeOrdinary && req.AccountType != models.BankAccountTypeCurrent {
+		return nil, ErrInvalidAccountType
+	}
+	if !isDigits(req.AccountNumber, 7) {
+		return nil, ErrInvalidAccountNumber
+	}
+	holder := strings.TrimSpace(req.AccountHolder)
+	if !isAccountHolderName(holder) {
+		return nil, ErrInvalidAccountHolder
+	}
+
+	a := &models.BankAccount{
+		UserID:        userID,
+		BankCode:      req.BankCode,
+		BranchCode:    req.BranchCode,
+		AccountType:   req.AccountType,
+		AccountNumber: req.AccountNumber,
+		AccountHolder: holder,
+		UpdatedAt:     s.now(),
+	}
+	if err := s.repo.SaveBankAccount(ctx, a); err != nil {
+		return nil, err
+	}
+	masked := *a
+	masked.AccountNumber = maskAccountNumber(a.AccountNumber)
+	return &masked, nil
+}
+
+// RequestPayout moves amount from the seller's available balance into a payout to their
+// registered bank account. The balance is checked with the seller's payout requests
+// serialised, so concurrent requests cannot overdraw it.
+func (s *LedgerService) RequestPayout(ctx context.Context, userID string, amount int) (*models.Payout, error) {
+	if amount < MinPayoutAmount {
+		return nil, ErrPayoutTooSmall
+	}
+
+	var created *models.Payout
+	err := s.repo.CreatePayout(ctx, userID, func(b *models.SellerBalance, bank *models.BankAccount) (*models.Payout, *models.LedgerTransaction, error) {
+		if amount > b.Available {
+			return nil, nil, ErrInsufficientBalance
+		}
+
+		now := s.now()
+		p := &models.Payout{
+			ID:            "pyo_" + ulid.Make().String(),
+			UserID:        userID,
+			Amount:        amount,
+			Status:        models.PayoutStatusRequested,
+			BankCode:      bank.BankCode,
+			BranchCode:    bank.BranchCode,
+			AccountType:   bank.AccountType,
+			AccountNumber: bank.AccountNumber,
+			AccountHolder: bank.AccountHolder,
+			CreatedAt:     now,
+		}
+		lt := &models.LedgerTransaction{
+			Type:     models.LedgerTxPayoutRequested,
+			PayoutID: p.ID,
+			Entries: []models.LedgerEntry{
+				ledgerEntry(models.LedgerAccountSellerAvailable, userID, -amount),
+				ledgerEntry(models.LedgerAccountSellerPayout, userID, amount),
+			},
+			CreatedAt: now,
+		}
+		created = p
+		return p, lt, nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	masked := *created
+	masked.AccountNumber = maskAccountNumber(created.AccountNumber)
+	return &masked, nil
+}
+
+// GetMyPayouts returns the seller's payouts, newest first, with account numbers masked.
+func (s *LedgerService) GetMyPayouts(ctx context.Context, userID string, limit, offset int) ([]*models.Payout, error) {
+	limit, offset = ledgerPage(limit, offset)
+	payouts, err := s.repo.GetPayouts(ctx, userID, "", limit, offset)
+	if err != nil {
+		return nil, err
+	}
+	for _, p := range payouts {
+		p.AccountNumber = maskAccountNumber(p.AccountNumber)
+	}
+	return payouts, nil
+}
+
+// GetPayouts returns payouts of every seller for the admins, who need the full account
+// numbers to make the transfers.
+func (s *LedgerService) GetPayouts(ctx context.Context, status models.PayoutStatus, limit, offset int) ([]*models.Payout, error) {
+	switch status {
+	case "", models.PayoutStatusRequested, models.PayoutStatusPaid, models.PayoutStatusFailed:
+	default:
+		return nil, ErrInvalidPayoutStatus
+	}
+	limit, offset = ledgerPage(limit, offset)
+	return s.repo.GetPayouts(ctx, "", status, limit, offset)
+}
+
+// MarkPayoutPaid records that the admin transferred the payout.
+func (s *LedgerService) MarkPayoutPaid(ctx context.Context, adminID, payoutID string) (*models.Payout, error) {
+	return s.processPayout(ctx, adminID, payoutID, models.PayoutStatusPaid)
+}
+
+// MarkPayoutFailed records that the transfer was rejected (a closed account, say) and
+// returns the amount to the seller's available balance.
+func (s *LedgerService) MarkPayoutFailed(ctx context.Context, adminID, payoutID string) (*models.Payout, error) {
+	return s.processPayout(ctx, adminID, payoutID, models.PayoutStatusFailed)
+}
+
+func (s *LedgerService) processPayout(ctx context.Context, adminID, payoutID string, status models.PayoutStatus) (*models.Payout, error) {
+	var processed *models.Payout
+	err := s.repo.UpdatePayout(ctx, payoutID, func(p *models.Payout) (*models.LedgerTransaction, error) {
+		if p.Status != models.PayoutStatusRequested {
+			return nil, ErrPayoutProcessed
+		}
+
+		now := s.now()
+		p.Status = status
+		p.ProcessedBy = adminID
+		p.ProcessedAt = &now
+
+		lt := &models.LedgerTransaction{PayoutID: p.ID, CreatedAt: now}
+		if status == models.PayoutStatusPaid {
+			lt.Type = models.LedgerTxPayoutPaid
+			lt.Entries = []models.LedgerEntry{
+				ledgerEntry(models.LedgerAccountSellerPayout, p.UserID, -p.Amount),
+				ledgerEntry(models.LedgerAccountSellerPaidOut, p.UserID, p.Amount),
+			}
+		} else {
+			lt.Type = models.LedgerTxPayoutFailed
+			lt.Entries = []models.LedgerEntry{
+				ledgerEntry(models.LedgerAccountSellerPayout, p.UserID, -p.Amount),
+				ledgerEntry(models.LedgerAccountSellerAvailable, p.UserID, p.Amount),
+			}
+		}
+		processed = p
+		return lt, nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return processed, nil
+}
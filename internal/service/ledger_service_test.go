@@ -0,0 +1,280 @@
+package service
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"uttc-hackathon-backend/internal/models"
+	"uttc-hackathon-backend/internal/repository"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+type MockLedgerRepository struct {
+	mock.Mock
+}
+
+func (m *MockLedgerRepository) GetSellerBalance(ctx context.Context, userID string) (*models.SellerBalance, error) {
+	args := m.Called(ctx, userID)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*models.SellerBalance), args.Error(1)
+}
+
+func (m *MockLedgerRepository) GetLedgerEntries(ctx context.Context, userID string, limit, offset int) ([]*models.LedgerEntry, error) {
+	args := m.Called(ctx, userID, limit, offset)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]*models.LedgerEntry), args.Error(1)
+}
+
+func (m *MockLedgerRepository) GetBankAccount(ctx context.Context, userID string) (*models.BankAccount, error) {
+	args := m.Called(ctx, userID)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*models.BankAccount), args.Error(1)
+}
+
+func (m *MockLedgerRepository) SaveBankAccount(ctx context.Context, a *models.BankAccount) error {
+	args := m.Called(ctx, a)
+	return args.Error(0)
+}
+
+func (m *MockLedgerRepository) CreatePayout(ctx context.Context, userID string, fn func(*models.SellerBalance, *models.BankAccount) (*models.Payout, *models.LedgerTransaction, error)) error {
+	args := m.Called(ctx, userID, fn)
+	return args.Error(0)
+}
+
+func (m *MockLedgerRepository) UpdatePayout(ctx context.Context, payoutID string, fn func(*models.Payout) (*models.LedgerTransaction, error)) error {
+	args := m.Called(ctx, payoutID, fn)
+	return args.Error(0)
+}
+
+func (m *MockLedgerRepository) GetPayouts(ctx context.Context, userID string, status models.PayoutStatus, limit, offset int) ([]*models.Payout, error) {
+	args := m.Called(ctx, userID, status, limit, offset)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]*models.Payout), args.Error(1)
+}
+
+var ledgerTestNow = time.Date(2024, 8, 1, 12, 0, 0, 0, time.UTC)
+
+func newTestLedgerService(repo *MockLedgerRepository) *LedgerService {
+	s := NewLedgerService(repo)
+	s.now = func() time.Time { return ledgerTestNow }
+	return s
+}
+
+// assertBalanced checks that the transaction's entries sum to zero.
+func assertBalanced(t *testing.T, lt *models.LedgerTransaction) {
+	t.Helper()
+	sum := 0
+	for _, e := range lt.Entries {
+		sum += e.Amount
+	}
+	assert.Zero(t, sum, "entries of %s must sum to zero", lt.Type)
+}
+
+// sellerAmounts sums the transaction's entries per seller account.
+func sellerAmounts(lt *models.LedgerTransaction) map[models.LedgerAccount]int {
+	amounts := map[models.LedgerAccount]int{}
+	for _, e := range lt.Entries {
+		if e.UserID != "" {
+			amounts[e.Account] += e.Amount
+		}
+	}
+	return amounts
+}
+
+func TestOrderLedger(t *testing.T) {
+	o := &models.Order{ID: "ord1", SellerID: "seller", TotalPrice: 3000, NetPayout: 2700}
+
+	paid := orderPaidLedger(o, ledgerTestNow)
+	assertBalanced(t, paid)
+	assert.Equal(t, map[models.LedgerAccount]int{models.LedgerAccountSellerPending: 2700}, sellerAmounts(paid))
+
+	completed := orderCompletedLedger(o, ledgerTestNow)
+	assertBalanced(t, completed)
+	assert.Equal(t, map[models.LedgerAccount]int{
+		models.LedgerAccountSellerPending: -2700, models.LedgerAccountSellerAvailable: 2700,
+	}, sellerAmounts(completed))
+
+	cancelled := orderCancelledLedger(o, models.OrderStatusShipped, ledgerTestNow)
+	assertBalanced(t, cancelled)
+	assert.Equal(t, map[models.LedgerAccount]int{models.LedgerAccountSellerPending: -2700}, sellerAmounts(cancelled))
+
+	assert.Nil(t, orderCancelledLedger(o, models.OrderStatusPendingPayment, ledgerTestNow))
+	// Refunds already took back the payout of a fully refunded order
+	assert.Nil(t, orderCancelledLedger(&models.Order{ID: "ord2", SellerID: "seller", TotalPrice: 3000, RefundedAmount: 3000}, models.OrderStatusDisputed, ledgerTestNow))
+}
+
+func TestLedgerService_SaveBankAccount(t *testing.T) {
+	valid := func() *models.BankAccount {
+		return &models.BankAccount{
+			BankCode: "0001", BranchCode: "123", AccountType: models.BankAccountTypeOrdinary,
+			AccountNumber: "1234567", AccountHolder: "ヤマダ　タロウ",
+		}
+	}
+
+	tests := []struct {
+		name    string
+		modify  func(*models.BankAccount)
+		wantErr error
+	}{
+		{name: "Valid", modify: func(a *models.BankAccount) {}},
+		{name: "Long Vowel And Half-Width Space", modify: func(a *models.BankAccount) { a.AccountHolder = "サトー ハナコ" }},
+		{name: "Short Bank Code", modify: func(a *models.BankAccount) { a.BankCode = "001" }, wantErr: ErrInvalidBankCode},
+		{name: "Letters In Branch Code", modify: func(a *models.BankAccount) { a.BranchCode = "12a" }, wantErr: ErrInvalidBranchCode},
+		{name: "Savings Account Type", modify: func(a *models.BankAccount) { a.AccountType = "savings" }, wantErr: ErrInvalidAccountType},
+		{name: "Long Account Number", modify: func(a *models.BankAccount) { a.AccountNumber = "12345678" }, wantErr: ErrInvalidAccountNumber},
+		{name: "Kanji Holder", modify: func(a *models.BankAccount) { a.AccountHolder = "山田太郎" }, wantErr: ErrInvalidAccountHolder},
+		{name: "Half-Width Katakana Holder", modify: func(a *models.BankAccount) { a.AccountHolder = "ﾔﾏﾀﾞ ﾀﾛｳ" }, wantErr: ErrInvalidAccountHolder},
+		{name: "Blank Holder", modify: func(a *models.BankAccount) { a.AccountHolder = "　" }, wantErr: ErrInvalidAccountHolder},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			repo := new(MockLedgerRepository)
+			req := valid()
+			tt.modify(req)
+			if tt.wantErr == nil {
+				repo.On("SaveBankAccount", mock.Anything, mock.MatchedBy(func(a *models.BankAccount) bool {
+					return a.UserID == "seller" && a.AccountNumber == req.AccountNumber
+				})).Return(nil)
+			}
+
+			got, err := newTestLedgerService(repo).SaveBankAccount(context.Background(), "seller", req)
+
+			assert.Equal(t, tt.wantErr, err)
+			if tt.wantErr == nil {
+				assert.Equal(t, "****567", got.AccountNumber)
+			}
+			repo.AssertExpectations(t)
+		})
+	}
+}
+
+func TestLedgerService_RequestPayout(t *testing.T) {
+	bank := &models.BankAccount{
+		UserID: "seller", BankCode: "0001", BranchCode: "123", AccountType: models.BankAccountTypeOrdinary,
+		AccountNumber: "1234567", AccountHolder: "ヤマダ　タロウ",
+	}
+
+	tests := []struct {
+		name      string
+		amount    int
+		available int
+		repoErr   error
+		wantErr   error
+	}{
+		{name: "Whole Balance", amount: 5000, available: 5000},
+		{name: "Part Of Balance", amount: 1000, available: 5000},
+		{name: "More Than Available", amount: 5001, available: 5000, wantErr: ErrInsufficientBalance},
+		{name: "Below Minimum", amount: MinPayoutAmount - 1, available: 5000, wantErr: ErrPayoutTooSmall},
+		{name: "No Bank Account", amount: 1000, repoErr: repository.ErrBankAccountNotFound, wantErr: repository.ErrBankAccountNotFound},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			repo := new(MockLedgerRepository)
+			if tt.amount >= MinPayoutAmount {
+				call := repo.On("CreatePayout", mock.Anything, "seller", mock.Anything)
+				if tt.repoErr != nil {
+					call.Return(tt.repoErr)
+				} else {
+					call.Return(tt.wantErr).Run(func(args mock.Arguments) {
+						fn := args.Get(2).(func(*models.SellerBalance, *models.BankAccount) (*models.Payout, *models.LedgerTransaction, error))
+						p, lt, err := fn(&models.SellerBalance{Available: tt.available}, bank)
+						assert.Equal(t, tt.wantErr, err)
+						if err == nil {
+							assert.Equal(t, bank.AccountNumber, p.AccountNumber)
+							assert.Equal(t, p.ID, lt.PayoutID)
+							assertBalanced(t, lt)
+							assert.Equal(t, map[models.LedgerAccount]int{
+								models.LedgerAccountSellerAvailable: -tt.amount, models.LedgerAccountSellerPayout: tt.amount,
+							}, sellerAmounts(lt))
+						}
+					})
+				}
+			}
+
+			got, err := newTestLedgerService(repo).RequestPayout(context.Background(), "seller", tt.amount)
+
+			assert.Equal(t, tt.wantErr, err)
+			if tt.wantErr == nil {
+				assert.Equal(t, models.PayoutStatusRequested, got.Status)
+				assert.Equal(t, tt.amount, got.Amount)
+				assert.Equal(t, "****567", got.AccountNumber)
+			}
+			repo.AssertExpectations(t)
+		})
+	}
+}
+
+func TestLedgerService_ProcessPayout(t *testing.T) {
+	tests := []struct {
+		name        string
+		fail        bool
+		status      models.PayoutStatus
+		wantErr     error
+		wantLedger  models.LedgerTransactionType
+		wantAccount models.LedgerAccount
+	}{
+		{name: "Paid", status: models.PayoutStatusRequested, wantLedger: models.LedgerTxPayoutPaid, wantAccount: models.LedgerAccountSellerPaidOut},
+		{name: "Failed Returns To Available", fail: true, status: models.PayoutStatusRequested, wantLedger: models.LedgerTxPayoutFailed, wantAccount: models.LedgerAccountSellerAvailable},
+		{name: "Already Paid", status: models.PayoutStatusPaid, wantErr: ErrPayoutProcessed},
+		{name: "Fail After Failure", fail: true, status: models.PayoutStatusFailed, wantErr: ErrPayoutProcessed},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			repo := new(MockLedgerRepository)
+			p := &models.Payout{ID: "pyo_1", UserID: "seller", Amount: 3000, Status: tt.status}
+			repo.On("UpdatePayout", mock.Anything, "pyo_1", mock.Anything).Return(tt.wantErr).Run(func(args mock.Arguments) {
+				fn := args.Get(2).(func(*models.Payout) (*models.LedgerTransaction, error))
+				lt, err := fn(p)
+				assert.Equal(t, tt.wantErr, err)
+				if err == nil {
+					assert.Equal(t, tt.wantLedger, lt.Type)
+					assertBalanced(t, lt)
+					assert.Equal(t, map[models.LedgerAccount]int{
+						models.LedgerAccountSellerPayout: -3000, tt.wantAccount: 3000,
+					}, sellerAmounts(lt))
+				}
+			})
+			s := newTestLedgerService(repo)
+
+			var got *models.Payout
+			var err error
+			if tt.fail {
+				got, err = s.MarkPayoutFailed(context.Background(), "admin", "pyo_1")
+			} else {
+				got, err = s.MarkPayoutPaid(context.Background(), "admin", "pyo_1")
+			}
+
+			assert.Equal(t, tt.wantErr, err)
+			if tt.wantErr == nil {
+				assert.Equal(t, "admin", got.ProcessedBy)
+				assert.Equal(t, ledgerTestNow, *got.ProcessedAt)
+			} else {
+				assert.Equal(t, tt.status, p.Status)
+			}
+			repo.AssertExpectations(t)
+		})
+	}
+}
+
+func TestLedgerService_GetPayouts_InvalidStatus(t *testing.T) {
+	repo := new(MockLedgerRepository)
+
+	_, err := newTestLedgerService(repo).GetPayouts(context.Background(), "cancelled", 0, 0)
+
+	assert.Equal(t, ErrInvalidPayoutStatus, err)
+	repo.AssertExpectations(t)
+}
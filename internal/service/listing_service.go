@@ -3,7 +3,9 @@ package service
 import (
 	"context"
 	"errors"
+	"log"
 	"strings"
+	"time"
 	"uttc-hackathon-backend/internal/models"
 
 	"github.com/oklog/ulid/v2"
@@ -15,17 +17,24 @@ const (
 )
 
 type ListingService struct {
-	repo ListingRepository
+	repo     ListingRepository
+	screener ListingScreener
 }
 
 type ListingRepository interface {
 	GetListingsFeed(ctx context.Context, limit, offset int) ([]*models.Listing, error)
 	CreateListing(ctx context.Context, l *models.Listing) error
 	GetListing(ctx context.Context, id string) (*models.Listing, error)
+	CreateScreening(ctx context.Context, sc *models.ListingScreening) error
 }
 
-func NewListingService(repo ListingRepository) *ListingService {
-	return &ListingService{repo: repo}
+// ListingScreener checks listings for prohibited content before they are published.
+type ListingScreener interface {
+	Screen(ctx context.Context, l *models.Listing) *models.ListingScreening
+}
+
+func NewListingService(repo ListingRepository, screener ListingScreener) *ListingService {
+	return &ListingService{repo: repo, screener: screener}
 }
 
 func (s *ListingService) GetFeed(ctx context.Context, limit, offset int) ([]*models.Listing, error) {
@@ -55,7 +64,8 @@ func (s *ListingService) GetListing(ctx context.Context, id string) (*models.Lis
 	if err != nil {
 		return nil, err
 	}
-	if l == nil {
+	// Listings taken down or held for review are hidden from the public
+	if l == nil || l.Status == models.ListingStatusRemoved || l.Status == models.ListingStatusPendingReview {
 		return nil, ErrListingNotFound
 	}
 	return l, nil
@@ -76,12 +86,39 @@ func (s *ListingService) CreateListing(ctx context.Context, req *models.Listing)
 			return nil, ErrInvalidImageURL
 		}
 	}
+	if err := validateTaxRate(req); err != nil {
+		return nil, err
+	}
+	if err := validateShipping(req); err != nil {
+		return nil, err
+	}
+	if err := prepareVariants(req); err != nil {
+		return nil, err
+	}
 
 	req.ID = "lst_" + ulid.Make().String()
 
+	// Listings being published are screened first; flagged ones wait for an admin
+	var screening *models.ListingScreening
+	if req.Status == models.ListingStatusActive {
+		screening = s.screener.Screen(ctx, req)
+		if screening.Flagged {
+			req.Status = models.ListingStatusPendingReview
+		}
+	}
+
 	if err := s.repo.CreateListing(ctx, req); err != nil {
 		return nil, err
 	}
 
+	if screening != nil {
+		screening.ID = "scr_" + ulid.Make().String()
+		screening.ListingID = req.ID
+		screening.CreatedAt = time.Now()
+		if err := s.repo.CreateScreening(ctx, screening); err != nil {
+			log.Printf("failed to save screening for listing %s: %v", req.ID, err)
+		}
+	}
+
 	return req, nil
 }
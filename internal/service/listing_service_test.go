@@ -35,6 +35,20 @@ func (m *MockListingRepository) GetListing(ctx context.Context, id string) (*mod
 	return args.Get(0).(*models.Listing), args.Error(1)
 }
 
+func (m *MockListingRepository) CreateScreening(ctx context.Context, sc *models.ListingScreening) error {
+	args := m.Called(ctx, sc)
+	return args.Error(0)
+}
+
+type MockListingScreener struct {
+	mock.Mock
+}
+
+func (m *MockListingScreener) Screen(ctx context.Context, l *models.Listing) *models.ListingScreening {
+	args := m.Called(ctx, l)
+	return args.Get(0).(*models.ListingScreening)
+}
+
 func TestListingService_GetFeed(t *testing.T) {
 	// We want to test the parameter normalization logic in the service
 	tests := []struct {
@@ -85,7 +99,7 @@ func TestListingService_GetFeed(t *testing.T) {
 			// Expect repository to be called with normalized values
 			repo.On("GetListingsFeed", mock.Anything, tt.wantLimit, tt.wantOffset).Return(tt.mockReturn, nil)
 
-			s := NewListingService(repo)
+			s := NewListingService(repo, new(MockListingScreener))
 			got, err := s.GetFeed(context.Background(), tt.limit, tt.offset)
 
 			assert.NoError(t, err)
@@ -154,6 +168,114 @@ func TestListingService_CreateListing(t *testing.T) {
 			wantErr:   true,
 			errType:   ErrInvalidImageURL,
 		},
+		{
+			name: "Buyer Pays Shipping Without Method",
+			req: &models.Listing{
+				Title: "Item", Price: 500, Images: []models.ListingImage{validImage},
+				ShippingPayer: models.ShippingPayerBuyer,
+			},
+			mockSetup: func(m *MockListingRepository) {},
+			wantErr:   true,
+			errType:   ErrShippingMethodRequired,
+		},
+		{
+			name: "Unsupported Shipping Size",
+			req: &models.Listing{
+				Title: "Item", Price: 500, Images: []models.ListingImage{validImage},
+				ShippingMethod: models.ShippingMethodYuPacket, ShippingSize: models.ShippingSize100,
+			},
+			mockSetup: func(m *MockListingRepository) {},
+			wantErr:   true,
+			errType:   ErrInvalidShippingMethod,
+		},
+		{
+			name: "Invalid Shipping Payer",
+			req: &models.Listing{
+				Title: "Item", Price: 500, Images: []models.ListingImage{validImage},
+				ShippingPayer: "platform",
+			},
+			mockSetup: func(m *MockListingRepository) {},
+			wantErr:   true,
+			errType:   ErrInvalidShippingPayer,
+		},
+		{
+			name: "Invalid Prefecture",
+			req: &models.Listing{
+				Title: "Item", Price: 500, Images: []models.ListingImage{validImage},
+				ShipFromPrefecture: 48,
+			},
+			mockSetup: func(m *MockListingRepository) {},
+			wantErr:   true,
+			errType:   ErrInvalidPrefecture,
+		},
+		{
+			name: "Duplicate Variant Label",
+			req: &models.Listing{
+				Title: "Tee", Price: 500, Images: []models.ListingImage{validImage},
+				Variants: []models.ListingVariant{{Label: "M", Quantity: 1}, {Label: " M ", Quantity: 2}},
+			},
+			mockSetup: func(m *MockListingRepository) {},
+			wantErr:   true,
+			errType:   ErrDuplicateVariantLabel,
+		},
+		{
+			name: "Variant Price Too Low",
+			req: &models.Listing{
+				Title: "Tee", Price: 500, Images: []models.ListingImage{validImage},
+				Variants: []models.ListingVariant{{Label: "S", Price: 50, Quantity: 1}},
+			},
+			mockSetup: func(m *MockListingRepository) {},
+			wantErr:   true,
+			errType:   ErrVariantPriceInvalid,
+		},
+		{
+			name: "Variant Without Label",
+			req: &models.Listing{
+				Title: "Tee", Price: 500, Images: []models.ListingImage{validImage},
+				Variants: []models.ListingVariant{{Label: "", Quantity: 1}},
+			},
+			mockSetup: func(m *MockListingRepository) {},
+			wantErr:   true,
+			errType:   ErrVariantLabelRequired,
+		},
+		{
+			name: "Variants Set Total Quantity",
+			req: &models.Listing{
+				Title: "Tee", Price: 500, Quantity: 99, Images: []models.ListingImage{validImage},
+				Variants: []models.ListingVariant{{Label: "S", Quantity: 2}, {Label: "M", Price: 600, Quantity: 3}},
+			},
+			mockSetup: func(m *MockListingRepository) {
+				m.On("CreateListing", mock.Anything, mock.MatchedBy(func(l *models.Listing) bool {
+					return l.Quantity == 5 && len(l.Variants) == 2 &&
+						len(l.Variants[0].ID) == 30 && l.Variants[0].ID != l.Variants[1].ID
+				})).Return(nil)
+			},
+			wantErr: false,
+		},
+		{
+			name: "Invalid Days To Ship",
+			req: &models.Listing{
+				Title: "Item", Price: 500, Images: []models.ListingImage{validImage},
+				DaysToShip: "tomorrow",
+			},
+			mockSetup: func(m *MockListingRepository) {},
+			wantErr:   true,
+			errType:   ErrInvalidDaysToShip,
+		},
+		{
+			name: "Buyer Pays Shipping",
+			req: &models.Listing{
+				Title: "Item", Price: 500, Images: []models.ListingImage{validImage},
+				ShippingPayer: models.ShippingPayerBuyer, ShippingMethod: models.ShippingMethodYuPack, ShippingSize: models.ShippingSize80,
+				ShipFromPrefecture: 13, DaysToShip: models.DaysToShip2To3,
+			},
+			mockSetup: func(m *MockListingRepository) {
+				m.On("CreateListing", mock.Anything, mock.MatchedBy(func(l *models.Listing) bool {
+					return l.ShippingPayer == models.ShippingPayerBuyer && l.ShipFromPrefecture == 13
+				})).Return(nil)
+			},
+			wantErr: false,
+		},
 		{
 			name: "Repo Error",
 			req: &models.Listing{
@@ -172,7 +294,7 @@ func TestListingService_CreateListing(t *testing.T) {
 			repo := new(MockListingRepository)
 			tt.mockSetup(repo)
 
-			s := NewListingService(repo)
+			s := NewListingService(repo, new(MockListingScreener))
 			got, err := s.CreateListing(context.Background(), tt.req)
 
 			if tt.wantErr {
@@ -220,6 +342,16 @@ func TestListingService_GetListing(t *testing.T) {
 			wantErr: true,
 			errType: ErrListingNotFound,
 		},
+		{
+			name: "Removed",
+			id:   "lst1",
+			mockSetup: func(m *MockListingRepository) {
+				m.On("GetListing", mock.Anything, "lst1").Return(&models.Listing{ID: "lst1", Status: models.ListingStatusRemoved}, nil)
+			},
+			want:    nil,
+			wantErr: true,
+			errType: ErrListingNotFound,
+		},
 		{
 			name: "DB Error",
 			id:   "lst1",
@@ -237,7 +369,7 @@ func TestListingService_GetListing(t *testing.T) {
 			repo := new(MockListingRepository)
 			tt.mockSetup(repo)
 
-			s := NewListingService(repo)
+			s := NewListingService(repo, new(MockListingScreener))
 			got, err := s.GetListing(context.Background(), tt.id)
 
 			if tt.wantErr {
@@ -253,3 +385,42 @@ func TestListingService_GetListing(t *testing.T) {
 		})
 	}
 }
+
+func TestListingService_CreateListing_Screening(t *testing.T) {
+	validImage := models.ListingImage{URL: "https://firebasestorage.googleapis.com/v0/b/bucket/o/image.jpg"}
+
+	tests := []struct {
+		name       string
+		status     models.ListingStatus
+		flagged    bool
+		wantStatus models.ListingStatus
+	}{
+		{name: "Clean Listing Goes Active", status: models.ListingStatusActive, flagged: false, wantStatus: models.ListingStatusActive},
+		{name: "Flagged Listing Held", status: models.ListingStatusActive, flagged: true, wantStatus: models.ListingStatusPendingReview},
+		{name: "Draft Not Screened", status: models.ListingStatusDraft, wantStatus: models.ListingStatusDraft},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			repo := new(MockListingRepository)
+			screener := new(MockListingScreener)
+			repo.On("CreateListing", mock.Anything, mock.Anything).Return(nil)
+			if tt.status == models.ListingStatusActive {
+				screener.On("Screen", mock.Anything, mock.Anything).Return(&models.ListingScreening{Flagged: tt.flagged})
+				repo.On("CreateScreening", mock.Anything, mock.MatchedBy(func(sc *models.ListingScreening) bool {
+					return sc.ListingID != "" && sc.Flagged == tt.flagged
+				})).Return(nil)
+			}
+
+			s := NewListingService(repo, screener)
+			got, err := s.CreateListing(context.Background(), &models.Listing{
+				Title: "Item", Price: 500, Images: []models.ListingImage{validImage}, Status: tt.status,
+			})
+
+			assert.NoError(t, err)
+			assert.Equal(t, tt.wantStatus, got.Status)
+			repo.AssertExpectations(t)
+			screener.AssertExpectations(t)
+		})
+	}
+}
@@ -21,7 +21,8 @@ var (
 
 type MessageRepository interface {
 	CreateMessage(ctx context.Context, m *models.Message) error
-	GetMessages(ctx context.Context, userID, otherUserID string) ([]*models.Message, error)
+	GetMessages(ctx context.Context, userID, otherUserID, listingID string) ([]*models.Message, error)
+	GetOrderMessages(ctx context.Context, orderID string) ([]*models.Message, error)
 	GetLatestIncomingMessages(ctx context.Context, userID string) ([]*models.Message, error)
 	GetLatestOutgoingMessages(ctx context.Context, userID string) ([]*models.Message, error)
 }
@@ -33,16 +34,19 @@ type MessageUserRepo interface {
 type MessageService struct {
 	repo     MessageRepository
 	userRepo MessageUserRepo
+	orders   OrderReader
 }
 
-func NewMessageService(repo MessageRepository, userRepo MessageUserRepo) *MessageService {
+func NewMessageService(repo MessageRepository, userRepo MessageUserRepo, orders OrderReader) *MessageService {
 	return &MessageService{
 		repo:     repo,
 		userRepo: userRepo,
+		orders:   orders,
 	}
 }
 
-func (s *MessageService) CreateMessage(ctx context.Context, senderID, receiverID, content string) (*models.Message, error) {
+// CreateMessage sends a direct message, or a message about the listing when listingID is set.
+func (s *MessageService) CreateMessage(ctx context.Context, senderID, receiverID, listingID, content string) (*models.Message, error) {
 	if content == "" {
 		return nil, ErrContentRequired
 	}
@@ -58,6 +62,7 @@ func (s *MessageService) CreateMessage(ctx context.Context, senderID, receiverID
 		ID:         id,
 		SenderID:   senderID,
 		ReceiverID: receiverID,
+		ListingID:  listingID,
 		Content:    content,
 		CreatedAt:  now,
 	}
@@ -69,8 +74,49 @@ func (s *MessageService) CreateMessage(ctx context.Context, senderID, receiverID
 	return msg, nil
 }
 
-func (s *MessageService) GetMessages(ctx context.Context, userID, otherUserID string) ([]*models.Message, error) {
-	return s.repo.GetMessages(ctx, userID, otherUserID)
+// CreateOrderMessage sends a message about the order from its buyer to its seller or the
+// other way round. Anyone else gets ErrUnauthorized.
+func (s *MessageService) CreateOrderMessage(ctx context.Context, userID, orderID, content string) (*models.Message, error) {
+	if content == "" {
+		return nil, ErrContentRequired
+	}
+
+	o, err := s.orders.GetOrder(ctx, userID, orderID)
+	if err != nil {
+		return nil, err
+	}
+	receiverID := o.SellerID
+	if userID == o.SellerID {
+		receiverID = o.BuyerID
+	}
+
+	msg := &models.Message{
+		ID:         "msg_" + ulid.Make().String(),
+		SenderID:   userID,
+		ReceiverID: receiverID,
+		OrderID:    o.ID,
+		Content:    content,
+		CreatedAt:  time.Now(),
+	}
+	if err := s.repo.CreateMessage(ctx, msg); err != nil {
+		return nil, err
+	}
+	return msg, nil
+}
+
+// GetMessages returns the direct messages with the other user, or the messages about the
+// listing when listingID is set.
+func (s *MessageService) GetMessages(ctx context.Context, userID, otherUserID, listingID string) ([]*models.Message, error) {
+	return s.repo.GetMessages(ctx, userID, otherUserID, listingID)
+}
+
+// GetOrderMessages returns the messages about the order to its buyer or seller, oldest first.
+func (s *MessageService) GetOrderMessages(ctx context.Context, userID, orderID string) ([]*models.Message, error) {
+	o, err := s.orders.GetOrder(ctx, userID, orderID)
+	if err != nil {
+		return nil, err
+	}
+	return s.repo.GetOrderMessages(ctx, o.ID)
 }
 
 func (s *MessageService) GetConversations(ctx context.Context, userID string) ([]models.Conversation, error) {
@@ -83,15 +129,17 @@ func (s *MessageService) GetConversations(ctx context.Context, userID string) ([
 		return nil, fmt.Errorf("get outgoing messages: %w", err)
 	}
 
-	conversationMap := make(map[string]*models.Message)
+	// One thread per partner and order or listing; direct messages have neither
+	type threadKey struct{ partnerID, orderID, listingID string }
+	conversationMap := make(map[threadKey]*models.Message)
 	for _, m := range incoming {
-		conversationMap[m.SenderID] = m
+		conversationMap[threadKey{m.SenderID, m.OrderID, m.ListingID}] = m
 	}
 	for _, m := range outgoing {
-		partnerID := m.ReceiverID
+		key := threadKey{m.ReceiverID, m.OrderID, m.ListingID}
 		// compare timestamp and keep newer message
-		if existing, ok := conversationMap[partnerID]; !ok || m.CreatedAt.After(existing.CreatedAt) {
-			conversationMap[partnerID] = m
+		if existing, ok := conversationMap[key]; !ok || m.CreatedAt.After(existing.CreatedAt) {
+			conversationMap[key] = m
 		}
 	}
 
@@ -123,8 +171,10 @@ func (s *MessageService) GetConversations(ctx context.Context, userID string) ([
 		}
 
 		conversations = append(conversations, models.Conversation{
-			Message: m,
-			User:    userProfile,
+			Message:   m,
+			User:      userProfile,
+			OrderID:   m.OrderID,
+			ListingID: m.ListingID,
 		})
 	}
 
@@ -20,8 +20,16 @@ func (m *MockMessageRepository) CreateMessage(ctx context.Context, msg *models.M
 	return args.Error(0)
 }
 
-func (m *MockMessageRepository) GetMessages(ctx context.Context, userID, otherUserID string) ([]*models.Message, error) {
-	args := m.Called(ctx, userID, otherUserID)
+func (m *MockMessageRepository) GetMessages(ctx context.Context, userID, otherUserID, listingID string) ([]*models.Message, error) {
+	args := m.Called(ctx, userID, otherUserID, listingID)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]*models.Message), args.Error(1)
+}
+
+func (m *MockMessageRepository) GetOrderMessages(ctx context.Context, orderID string) ([]*models.Message, error) {
+	args := m.Called(ctx, orderID)
 	if args.Get(0) == nil {
 		return nil, args.Error(1)
 	}
@@ -115,8 +123,8 @@ func TestMessageService_CreateMessage(t *testing.T) {
 			userRepo := new(MockMessageUserRepo)
 			tt.mockSetup(repo)
 
-			s := NewMessageService(repo, userRepo)
-			got, err := s.CreateMessage(context.Background(), tt.senderID, tt.receiverID, tt.content)
+			s := NewMessageService(repo, userRepo, nil)
+			got, err := s.CreateMessage(context.Background(), tt.senderID, tt.receiverID, "", tt.content)
 
 			if tt.wantErr {
 				assert.Error(t, err)
@@ -186,7 +194,7 @@ func TestMessageService_GetConversations(t *testing.T) {
 			userRepo := new(MockMessageUserRepo)
 			tt.mockSetup(repo, userRepo)
 
-			s := NewMessageService(repo, userRepo)
+			s := NewMessageService(repo, userRepo, nil)
 			got, err := s.GetConversations(context.Background(), tt.userID)
 
 			if tt.wantErr {
@@ -212,3 +220,90 @@ func TestMessageService_GetConversations(t *testing.T) {
 		})
 	}
 }
+
+func TestMessageService_GetConversations_ThreadsByOrder(t *testing.T) {
+	now := time.Now()
+	repo := new(MockMessageRepository)
+	userRepo := new(MockMessageUserRepo)
+
+	// The same partner in a direct thread, an order thread and a listing thread
+	direct := &models.Message{ID: "m1", SenderID: "u2", ReceiverID: "u1", CreatedAt: now.Add(-3 * time.Hour), Content: "Hi"}
+	order := &models.Message{ID: "m2", SenderID: "u2", ReceiverID: "u1", OrderID: "ord_1", CreatedAt: now.Add(-2 * time.Hour), Content: "Shipped"}
+	orderReply := &models.Message{ID: "m3", SenderID: "u1", ReceiverID: "u2", OrderID: "ord_1", CreatedAt: now.Add(-time.Hour), Content: "Thanks"}
+	listing := &models.Message{ID: "m4", SenderID: "u1", ReceiverID: "u2", ListingID: "lst_1", CreatedAt: now, Content: "Still available?"}
+
+	repo.On("GetLatestIncomingMessages", mock.Anything, "u1").Return([]*models.Message{direct, order}, nil)
+	repo.On("GetLatestOutgoingMessages", mock.Anything, "u1").Return([]*models.Message{orderReply, listing}, nil)
+	userRepo.On("GetUserProfile", mock.Anything, "u2").Return(&models.UserProfile{ID: "u2"}, nil)
+
+	s := NewMessageService(repo, userRepo, nil)
+	got, err := s.GetConversations(context.Background(), "u1")
+
+	assert.NoError(t, err)
+	assert.Len(t, got, 3)
+	assert.Equal(t, "lst_1", got[0].ListingID)
+	assert.Equal(t, "ord_1", got[1].OrderID)
+	assert.Equal(t, "Thanks", got[1].Message.Content)
+	assert.Equal(t, "", got[2].OrderID)
+	assert.Equal(t, "", got[2].ListingID)
+	assert.Equal(t, "Hi", got[2].Message.Content)
+}
+
+func TestMessageService_CreateOrderMessage(t *testing.T) {
+	tests := []struct {
+		name         string
+		userID       string
+		content      string
+		wantReceiver string
+		wantErr      error
+	}{
+		{name: "Buyer To Seller", userID: "buyer1", content: "When will it ship?", wantReceiver: "seller1"},
+		{name: "Seller To Buyer", userID: "seller1", content: "Tomorrow", wantReceiver: "buyer1"},
+		{name: "Not Part Of The Order", userID: "other", content: "Hello", wantErr: ErrUnauthorized},
+		{name: "Empty Content", userID: "buyer1", content: "", wantErr: ErrContentRequired},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			repo := new(MockMessageRepository)
+			orderRepo := new(MockOrderRepository)
+			orderRepo.On("GetOrder", mock.Anything, "ord_1").
+				Return(&models.Order{ID: "ord_1", BuyerID: "buyer1", SellerID: "seller1"}, nil).Maybe()
+			repo.On("CreateMessage", mock.Anything, mock.MatchedBy(func(msg *models.Message) bool {
+				return msg.SenderID == tt.userID && msg.ReceiverID == tt.wantReceiver && msg.OrderID == "ord_1"
+			})).Return(nil).Maybe()
+
+			s := NewMessageService(repo, new(MockMessageUserRepo), newTestOrderService(orderRepo))
+			got, err := s.CreateOrderMessage(context.Background(), tt.userID, "ord_1", tt.content)
+
+			if tt.wantErr != nil {
+				assert.ErrorIs(t, err, tt.wantErr)
+				repo.AssertNotCalled(t, "CreateMessage", mock.Anything, mock.Anything)
+				return
+			}
+			assert.NoError(t, err)
+			assert.Equal(t, "ord_1", got.OrderID)
+			assert.Equal(t, tt.wantReceiver, got.ReceiverID)
+			repo.AssertExpectations(t)
+		})
+	}
+}
+
+func TestMessageService_GetOrderMessages(t *testing.T) {
+	repo := new(MockMessageRepository)
+	orderRepo := new(MockOrderRepository)
+	orderRepo.On("GetOrder", mock.Anything, "ord_1").
+		Return(&models.Order{ID: "ord_1", BuyerID: "buyer1", SellerID: "seller1"}, nil)
+	messages := []*models.Message{{ID: "m1", SenderID: "buyer1", ReceiverID: "seller1", OrderID: "ord_1"}}
+	repo.On("GetOrderMessages", mock.Anything, "ord_1").Return(messages, nil)
+
+	s := NewMessageService(repo, new(MockMessageUserRepo), newTestOrderService(orderRepo))
+
+	got, err := s.GetOrderMessages(context.Background(), "seller1", "ord_1")
+	assert.NoError(t, err)
+	assert.Equal(t, messages, got)
+
+	_, err = s.GetOrderMessages(context.Background(), "other", "ord_1")
+	assert.ErrorIs(t, err, ErrUnauthorized)
+	repo.AssertNumberOfCalls(t, "GetOrderMessages", 1)
+}
@@ -0,0 +1,247 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"time"
+	"uttc-hackathon-backend/internal/models"
+
+	"github.com/oklog/ulid/v2"
+)
+
+const (
+	// MaxReportsPerHour limits how many reports a single user can file in an hour.
+	MaxReportsPerHour   = 10
+	MaxReportComment    = 1000
+	DefaultReportsLimit = 50
+)
+
+var (
+	ErrInvalidReportReason = errors.New("invalid report reason")
+	ErrInvalidReportStatus = errors.New("invalid report status")
+	ErrReportCommentLong   = errors.New("comment must be at most 1000 characters")
+	ErrSelfReport          = errors.New("cannot report yourself")
+	ErrReportRateLimited   = errors.New("too many reports, please try again later")
+	ErrReportClosed        = errors.New("report is already closed")
+	ErrSelfSuspend         = errors.New("cannot suspend yourself")
+	ErrListingNotPending   = errors.New("listing is not pending review")
+)
+
+type ReportRepository interface {
+	CreateReport(ctx context.Context, rp *models.Report) error
+	CountReportsByReporterSince(ctx context.Context, reporterID string, since time.Time) (int, error)
+	GetReport(ctx context.Context, id string) (*models.Report, error)
+	GetReports(ctx context.Context, status models.ReportStatus, limit, offset int) ([]*models.Report, error)
+	UpdateReportStatus(ctx context.Context, id string, status models.ReportStatus, handledBy string) error
+	ActionReportsForTarget(ctx context.Context, targetType models.ReportTargetType, targetID, handledBy string) error
+}
+
+type ModerationListingRepo interface {
+	GetListing(ctx context.Context, id string) (*models.Listing, error)
+	GetListingsByStatus(ctx context.Context, status models.ListingStatus, limit, offset int) ([]*models.Listing, error)
+	UpdateListingStatus(ctx context.Context, id string, status models.ListingStatus) error
+}
+
+type ModerationUserRepo interface {
+	GetUserProfile(ctx context.Context, id string) (*models.UserProfile, error)
+	UpdateUserStatus(ctx context.Context, id string, status models.UserStatus) error
+}
+
+type ModerationService struct {
+	repo        ReportRepository
+	listingRepo ModerationListingRepo
+	userRepo    ModerationUserRepo
+}
+
+func NewModerationService(repo ReportRepository, listingRepo ModerationListingRepo, userRepo ModerationUserRepo) *ModerationService {
+	return &ModerationService{
+		repo:        repo,
+		listingRepo: listingRepo,
+		userRepo:    userRepo,
+	}
+}
+
+func isValidReportReason(reason models.ReportReason) bool {
+	switch reason {
+	case models.ReportReasonScam,
+		models.ReportReasonProhibitedItem,
+		models.ReportReasonCounterfeit,
+		models.ReportReasonInappropriate,
+		models.ReportReasonSpam,
+		models.ReportReasonHarassment,
+		models.ReportReasonOther:
+		return true
+	}
+	return false
+}
+
+// ReportListing files a report against a listing.
+func (s *ModerationService) ReportListing(ctx context.Context, reporterID, listingID string, reason models.ReportReason, comment string) (*models.Report, error) {
+	l, err := s.listingRepo.GetListing(ctx, listingID)
+	if err != nil {
+		return nil, err
+	}
+	if l == nil {
+		return nil, ErrListingNotFound
+	}
+	if l.SellerID == reporterID {
+		return nil, ErrSelfReport
+	}
+	return s.createReport(ctx, reporterID, models.ReportTargetListing, listingID, reason, comment)
+}
+
+// ReportUser files a report against another user.
+func (s *ModerationService) ReportUser(ctx context.Context, reporterID, userID string, reason models.ReportReason, comment string) (*models.Report, error) {
+	if reporterID == userID {
+		return nil, ErrSelfReport
+	}
+	p, err := s.userRepo.GetUserProfile(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+	if p == nil {
+		return nil, ErrUserNotFound
+	}
+	return s.createReport(ctx, reporterID, models.ReportTargetUser, userID, reason, comment)
+}
+
+func (s *ModerationService) createReport(ctx context.Context, reporterID string, targetType models.ReportTargetType, targetID string, reason models.ReportReason, comment string) (*models.Report, error) {
+	if !isValidReportReason(reason) {
+		return nil, ErrInvalidReportReason
+	}
+	if len([]rune(comment)) > MaxReportComment {
+		return nil, ErrReportCommentLong
+	}
+
+	now := time.Now()
+	n, err := s.repo.CountReportsByReporterSince(ctx, reporterID, now.Add(-time.Hour))
+	if err != nil {
+		return nil, err
+	}
+	if n >= MaxReportsPerHour {
+		return nil, ErrReportRateLimited
+	}
+
+	rp := &models.Report{
+		ID:         "rpt_" + ulid.Make().String(),
+		ReporterID: reporterID,
+		TargetType: targetType,
+		TargetID:   targetID,
+		Reason:     reason,
+		Comment:    comment,
+		Status:     models.ReportStatusOpen,
+		CreatedAt:  now,
+		UpdatedAt:  now,
+	}
+	// Duplicates are rejected by the unique key on (reporter, target)
+	if err := s.repo.CreateReport(ctx, rp); err != nil {
+		return nil, err
+	}
+	return rp, nil
+}
+
+// GetReports returns the moderation queue filtered by status.
+func (s *ModerationService) GetReports(ctx context.Context, status models.ReportStatus, limit, offset int) ([]*models.Report, error) {
+	switch status {
+	case "", models.ReportStatusOpen, models.ReportStatusInReview, models.ReportStatusActioned, models.ReportStatusDismissed:
+	default:
+		return nil, ErrInvalidReportStatus
+	}
+	if limit <= 0 {
+		limit = DefaultReportsLimit
+	}
+	if limit > 100 {
+		limit = 100
+	}
+	if offset < 0 {
+		offset = 0
+	}
+	return s.repo.GetReports(ctx, status, limit, offset)
+}
+
+// UpdateReportStatus moves a report through the queue. Actioned and dismissed reports are final.
+func (s *ModerationService) UpdateReportStatus(ctx context.Context, adminID, reportID string, status models.ReportStatus) (*models.Report, error) {
+	switch status {
+	case models.ReportStatusInReview, models.ReportStatusActioned, models.ReportStatusDismissed:
+	default:
+		return nil, ErrInvalidReportStatus
+	}
+
+	rp, err := s.repo.GetReport(ctx, reportID)
+	if err != nil {
+		return nil, err
+	}
+	if rp.Status == models.ReportStatusActioned || rp.Status == models.ReportStatusDismissed {
+		return nil, ErrReportClosed
+	}
+
+	if err := s.repo.UpdateReportStatus(ctx, reportID, status, adminID); err != nil {
+		return nil, err
+	}
+	rp.Status = status
+	rp.HandledBy = adminID
+	rp.UpdatedAt = time.Now()
+	return rp, nil
+}
+
+// TakeDownListing hides a listing from the feed and blocks new orders on it.
+func (s *ModerationService) TakeDownListing(ctx context.Context, adminID, listingID string) error {
+	l, err := s.listingRepo.GetListing(ctx, listingID)
+	if err != nil {
+		return err
+	}
+	if l == nil {
+		return ErrListingNotFound
+	}
+	if err := s.listingRepo.UpdateListingStatus(ctx, listingID, models.ListingStatusRemoved); err != nil {
+		return err
+	}
+	return s.repo.ActionReportsForTarget(ctx, models.ReportTargetListing, listingID, adminID)
+}
+
+// GetPendingListings returns listings held by content screening, oldest first.
+func (s *ModerationService) GetPendingListings(ctx context.Context, limit, offset int) ([]*models.Listing, error) {
+	if limit <= 0 {
+		limit = DefaultReportsLimit
+	}
+	if limit > 100 {
+		limit = 100
+	}
+	if offset < 0 {
+		offset = 0
+	}
+	return s.listingRepo.GetListingsByStatus(ctx, models.ListingStatusPendingReview, limit, offset)
+}
+
+// ApproveListing publishes a listing that was held by content screening.
+func (s *ModerationService) ApproveListing(ctx context.Context, adminID, listingID string) error {
+	l, err := s.listingRepo.GetListing(ctx, listingID)
+	if err != nil {
+		return err
+	}
+	if l == nil {
+		return ErrListingNotFound
+	}
+	if l.Status != models.ListingStatusPendingReview {
+		return ErrListingNotPending
+	}
+	return s.listingRepo.UpdateListingStatus(ctx, listingID, models.ListingStatusActive)
+}
+
+// SuspendUser blocks the user from authenticated endpoints and hides their listings.
+func (s *ModerationService) SuspendUser(ctx context.Context, adminID, userID string) error {
+	if adminID == userID {
+		return ErrSelfSuspend
+	}
+	p, err := s.userRepo.GetUserProfile(ctx, userID)
+	if err != nil {
+		return err
+	}
+	if p == nil {
+		return ErrUserNotFound
+	}
+	if err := s.userRepo.UpdateUserStatus(ctx, userID, models.UserStatusSuspended); err != nil {
+		return err
+	}
+	return s.repo.ActionReportsForTarget(ctx, models.ReportTargetUser, userID, adminID)
+}
@@ -0,0 +1,407 @@
+package service
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"uttc-hackathon-backend/internal/models"
+	"uttc-hackathon-backend/internal/repository"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+type MockReportRepository struct {
+	mock.Mock
+}
+
+func (m *MockReportRepository) CreateReport(ctx context.Context, rp *models.Report) error {
+	args := m.Called(ctx, rp)
+	return args.Error(0)
+}
+
+func (m *MockReportRepository) CountReportsByReporterSince(ctx context.Context, reporterID string, since time.Time) (int, error) {
+	args := m.Called(ctx, reporterID, since)
+	return args.Int(0), args.Error(1)
+}
+
+func (m *MockReportRepository) GetReport(ctx context.Context, id string) (*models.Report, error) {
+	args := m.Called(ctx, id)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*models.Report), args.Error(1)
+}
+
+func (m *MockReportRepository) GetReports(ctx context.Context, status models.ReportStatus, limit, offset int) ([]*models.Report, error) {
+	args := m.Called(ctx, status, limit, offset)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]*models.Report), args.Error(1)
+}
+
+func (m *MockReportRepository) UpdateReportStatus(ctx context.Context, id string, status models.ReportStatus, handledBy string) error {
+	args := m.Called(ctx, id, status, handledBy)
+	return args.Error(0)
+}
+
+func (m *MockReportRepository) ActionReportsForTarget(ctx context.Context, targetType models.ReportTargetType, targetID, handledBy string) error {
+	args := m.Called(ctx, targetType, targetID, handledBy)
+	return args.Error(0)
+}
+
+type MockModerationListingRepo struct {
+	mock.Mock
+}
+
+func (m *MockModerationListingRepo) GetListing(ctx context.Context, id string) (*models.Listing, error) {
+	args := m.Called(ctx, id)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*models.Listing), args.Error(1)
+}
+
+func (m *MockModerationListingRepo) GetListingsByStatus(ctx context.Context, status models.ListingStatus, limit, offset int) ([]*models.Listing, error) {
+	args := m.Called(ctx, status, limit, offset)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]*models.Listing), args.Error(1)
+}
+
+func (m *MockModerationListingRepo) UpdateListingStatus(ctx context.Context, id string, status models.ListingStatus) error {
+	args := m.Called(ctx, id, status)
+	return args.Error(0)
+}
+
+type MockModerationUserRepo struct {
+	mock.Mock
+}
+
+func (m *MockModerationUserRepo) GetUserProfile(ctx context.Context, id string) (*models.UserProfile, error) {
+	args := m.Called(ctx, id)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*models.UserProfile), args.Error(1)
+}
+
+func (m *MockModerationUserRepo) UpdateUserStatus(ctx context.Context, id string, status models.UserStatus) error {
+	args := m.Called(ctx, id, status)
+	return args.Error(0)
+}
+
+func TestModerationService_ReportListing(t *testing.T) {
+	listing := &models.Listing{ID: "lst1", SellerID: "seller1"}
+
+	tests := []struct {
+		name      string
+		userID    string
+		reason    models.ReportReason
+		mockSetup func(*MockReportRepository, *MockModerationListingRepo)
+		wantErr   bool
+		errType   error
+	}{
+		{
+			name:   "Success",
+			userID: "u1",
+			reason: models.ReportReasonScam,
+			mockSetup: func(r *MockReportRepository, l *MockModerationListingRepo) {
+				l.On("GetListing", mock.Anything, "lst1").Return(listing, nil)
+				r.On("CountReportsByReporterSince", mock.Anything, "u1", mock.Anything).Return(0, nil)
+				r.On("CreateReport", mock.Anything, mock.MatchedBy(func(rp *models.Report) bool {
+					return rp.ID != "" && rp.TargetType == models.ReportTargetListing && rp.TargetID == "lst1" &&
+						rp.Status == models.ReportStatusOpen
+				})).Return(nil)
+			},
+			wantErr: false,
+		},
+		{
+			name:   "Listing Not Found",
+			userID: "u1",
+			reason: models.ReportReasonScam,
+			mockSetup: func(r *MockReportRepository, l *MockModerationListingRepo) {
+				l.On("GetListing", mock.Anything, "lst1").Return(nil, nil)
+			},
+			wantErr: true,
+			errType: ErrListingNotFound,
+		},
+		{
+			name:   "Own Listing",
+			userID: "seller1",
+			reason: models.ReportReasonScam,
+			mockSetup: func(r *MockReportRepository, l *MockModerationListingRepo) {
+				l.On("GetListing", mock.Anything, "lst1").Return(listing, nil)
+			},
+			wantErr: true,
+			errType: ErrSelfReport,
+		},
+		{
+			name:   "Invalid Reason",
+			userID: "u1",
+			reason: "boring",
+			mockSetup: func(r *MockReportRepository, l *MockModerationListingRepo) {
+				l.On("GetListing", mock.Anything, "lst1").Return(listing, nil)
+			},
+			wantErr: true,
+			errType: ErrInvalidReportReason,
+		},
+		{
+			name:   "Rate Limited",
+			userID: "u1",
+			reason: models.ReportReasonSpam,
+			mockSetup: func(r *MockReportRepository, l *MockModerationListingRepo) {
+				l.On("GetListing", mock.Anything, "lst1").Return(listing, nil)
+				r.On("CountReportsByReporterSince", mock.Anything, "u1", mock.Anything).Return(MaxReportsPerHour, nil)
+			},
+			wantErr: true,
+			errType: ErrReportRateLimited,
+		},
+		{
+			name:   "Duplicate",
+			userID: "u1",
+			reason: models.ReportReasonSpam,
+			mockSetup: func(r *MockReportRepository, l *MockModerationListingRepo) {
+				l.On("GetListing", mock.Anything, "lst1").Return(listing, nil)
+				r.On("CountReportsByReporterSince", mock.Anything, "u1", mock.Anything).Return(1, nil)
+				r.On("CreateReport", mock.Anything, mock.Anything).Return(repository.ErrDuplicateReport)
+			},
+			wantErr: true,
+			errType: repository.ErrDuplicateReport,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			repo := new(MockReportRepository)
+			listingRepo := new(MockModerationListingRepo)
+			userRepo := new(MockModerationUserRepo)
+			tt.mockSetup(repo, listingRepo)
+
+			s := NewModerationService(repo, listingRepo, userRepo)
+			got, err := s.ReportListing(context.Background(), tt.userID, "lst1", tt.reason, "")
+
+			if tt.wantErr {
+				assert.Error(t, err)
+				if tt.errType != nil {
+					assert.Equal(t, tt.errType, err)
+				}
+			} else {
+				assert.NoError(t, err)
+				assert.Contains(t, got.ID, "rpt_")
+			}
+			repo.AssertExpectations(t)
+			listingRepo.AssertExpectations(t)
+		})
+	}
+}
+
+func TestModerationService_ReportUser(t *testing.T) {
+	tests := []struct {
+		name      string
+		targetID  string
+		mockSetup func(*MockReportRepository, *MockModerationUserRepo)
+		wantErr   bool
+		errType   error
+	}{
+		{
+			name:     "Success",
+			targetID: "u2",
+			mockSetup: func(r *MockReportRepository, u *MockModerationUserRepo) {
+				u.On("GetUserProfile", mock.Anything, "u2").Return(&models.UserProfile{ID: "u2"}, nil)
+				r.On("CountReportsByReporterSince", mock.Anything, "u1", mock.Anything).Return(0, nil)
+				r.On("CreateReport", mock.Anything, mock.MatchedBy(func(rp *models.Report) bool {
+					return rp.TargetType == models.ReportTargetUser && rp.TargetID == "u2"
+				})).Return(nil)
+			},
+			wantErr: false,
+		},
+		{
+			name:      "Self",
+			targetID:  "u1",
+			mockSetup: func(r *MockReportRepository, u *MockModerationUserRepo) {},
+			wantErr:   true,
+			errType:   ErrSelfReport,
+		},
+		{
+			name:     "User Not Found",
+			targetID: "u2",
+			mockSetup: func(r *MockReportRepository, u *MockModerationUserRepo) {
+				u.On("GetUserProfile", mock.Anything, "u2").Return(nil, nil)
+			},
+			wantErr: true,
+			errType: ErrUserNotFound,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			repo := new(MockReportRepository)
+			listingRepo := new(MockModerationListingRepo)
+			userRepo := new(MockModerationUserRepo)
+			tt.mockSetup(repo, userRepo)
+
+			s := NewModerationService(repo, listingRepo, userRepo)
+			_, err := s.ReportUser(context.Background(), "u1", tt.targetID, models.ReportReasonHarassment, "rude")
+
+			if tt.wantErr {
+				assert.Error(t, err)
+				if tt.errType != nil {
+					assert.Equal(t, tt.errType, err)
+				}
+			} else {
+				assert.NoError(t, err)
+			}
+			repo.AssertExpectations(t)
+			userRepo.AssertExpectations(t)
+		})
+	}
+}
+
+func TestModerationService_UpdateReportStatus(t *testing.T) {
+	tests := []struct {
+		name      string
+		status    models.ReportStatus
+		current   models.ReportStatus
+		mockSetup func(*MockReportRepository, models.ReportStatus)
+		wantErr   bool
+		errType   error
+	}{
+		{
+			name:    "Open to In Review",
+			status:  models.ReportStatusInReview,
+			current: models.ReportStatusOpen,
+			mockSetup: func(r *MockReportRepository, current models.ReportStatus) {
+				r.On("GetReport", mock.Anything, "rpt1").Return(&models.Report{ID: "rpt1", Status: current}, nil)
+				r.On("UpdateReportStatus", mock.Anything, "rpt1", models.ReportStatusInReview, "admin1").Return(nil)
+			},
+			wantErr: false,
+		},
+		{
+			name:    "Already Dismissed",
+			status:  models.ReportStatusActioned,
+			current: models.ReportStatusDismissed,
+			mockSetup: func(r *MockReportRepository, current models.ReportStatus) {
+				r.On("GetReport", mock.Anything, "rpt1").Return(&models.Report{ID: "rpt1", Status: current}, nil)
+			},
+			wantErr: true,
+			errType: ErrReportClosed,
+		},
+		{
+			name:      "Back to Open",
+			status:    models.ReportStatusOpen,
+			mockSetup: func(r *MockReportRepository, current models.ReportStatus) {},
+			wantErr:   true,
+			errType:   ErrInvalidReportStatus,
+		},
+		{
+			name:   "Not Found",
+			status: models.ReportStatusDismissed,
+			mockSetup: func(r *MockReportRepository, current models.ReportStatus) {
+				r.On("GetReport", mock.Anything, "rpt1").Return(nil, repository.ErrReportNotFound)
+			},
+			wantErr: true,
+			errType: repository.ErrReportNotFound,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			repo := new(MockReportRepository)
+			tt.mockSetup(repo, tt.current)
+
+			s := NewModerationService(repo, new(MockModerationListingRepo), new(MockModerationUserRepo))
+			got, err := s.UpdateReportStatus(context.Background(), "admin1", "rpt1", tt.status)
+
+			if tt.wantErr {
+				assert.Error(t, err)
+				if tt.errType != nil {
+					assert.Equal(t, tt.errType, err)
+				}
+			} else {
+				assert.NoError(t, err)
+				assert.Equal(t, tt.status, got.Status)
+				assert.Equal(t, "admin1", got.HandledBy)
+			}
+			repo.AssertExpectations(t)
+		})
+	}
+}
+
+func TestModerationService_TakeDownListing(t *testing.T) {
+	repo := new(MockReportRepository)
+	listingRepo := new(MockModerationListingRepo)
+	listingRepo.On("GetListing", mock.Anything, "lst1").Return(&models.Listing{ID: "lst1", Status: models.ListingStatusActive}, nil)
+	listingRepo.On("UpdateListingStatus", mock.Anything, "lst1", models.ListingStatusRemoved).Return(nil)
+	repo.On("ActionReportsForTarget", mock.Anything, models.ReportTargetListing, "lst1", "admin1").Return(nil)
+
+	s := NewModerationService(repo, listingRepo, new(MockModerationUserRepo))
+	err := s.TakeDownListing(context.Background(), "admin1", "lst1")
+
+	assert.NoError(t, err)
+	repo.AssertExpectations(t)
+	listingRepo.AssertExpectations(t)
+}
+
+func TestModerationService_ApproveListing(t *testing.T) {
+	tests := []struct {
+		name      string
+		status    models.ListingStatus
+		mockSetup func(*MockModerationListingRepo)
+		wantErr   error
+	}{
+		{
+			name:   "Success",
+			status: models.ListingStatusPendingReview,
+			mockSetup: func(m *MockModerationListingRepo) {
+				m.On("UpdateListingStatus", mock.Anything, "lst1", models.ListingStatusActive).Return(nil)
+			},
+		},
+		{
+			name:      "Not Pending",
+			status:    models.ListingStatusActive,
+			mockSetup: func(m *MockModerationListingRepo) {},
+			wantErr:   ErrListingNotPending,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			listingRepo := new(MockModerationListingRepo)
+			listingRepo.On("GetListing", mock.Anything, "lst1").Return(&models.Listing{ID: "lst1", Status: tt.status}, nil)
+			tt.mockSetup(listingRepo)
+
+			s := NewModerationService(new(MockReportRepository), listingRepo, new(MockModerationUserRepo))
+			err := s.ApproveListing(context.Background(), "admin1", "lst1")
+
+			assert.Equal(t, tt.wantErr, err)
+			listingRepo.AssertExpectations(t)
+		})
+	}
+}
+
+func TestModerationService_SuspendUser(t *testing.T) {
+	t.Run("Success", func(t *testing.T) {
+		repo := new(MockReportRepository)
+		userRepo := new(MockModerationUserRepo)
+		userRepo.On("GetUserProfile", mock.Anything, "u2").Return(&models.UserProfile{ID: "u2"}, nil)
+		userRepo.On("UpdateUserStatus", mock.Anything, "u2", models.UserStatusSuspended).Return(nil)
+		repo.On("ActionReportsForTarget", mock.Anything, models.ReportTargetUser, "u2", "admin1").Return(nil)
+
+		s := NewModerationService(repo, new(MockModerationListingRepo), userRepo)
+		err := s.SuspendUser(context.Background(), "admin1", "u2")
+
+		assert.NoError(t, err)
+		repo.AssertExpectations(t)
+		userRepo.AssertExpectations(t)
+	})
+
+	t.Run("Self", func(t *testing.T) {
+		s := NewModerationService(new(MockReportRepository), new(MockModerationListingRepo), new(MockModerationUserRepo))
+		err := s.SuspendUser(context.Background(), "admin1", "admin1")
+		assert.Equal(t, ErrSelfSuspend, err)
+	})
+}
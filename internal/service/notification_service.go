@@ -0,0 +1,36 @@
+package service
+
+import (
+	"context"
+	"uttc-hackathon-backend/internal/models"
+)
+
+type NotificationService struct {
+	repo NotificationRepository
+}
+
+type NotificationRepository interface {
+	GetNotifications(ctx context.Context, userID string, limit, offset int) ([]*models.Notification, error)
+	MarkNotificationRead(ctx context.Context, id, userID string) error
+}
+
+func NewNotificationService(repo NotificationRepository) *NotificationService {
+	return &NotificationService{repo: repo}
+}
+
+func (s *NotificationService) GetNotifications(ctx context.Context, userID string, limit, offset int) ([]*models.Notification, error) {
+	if limit <= 0 {
+		limit = 20
+	}
+	if limit > 100 {
+		limit = 100
+	}
+	if offset < 0 {
+		offset = 0
+	}
+	return s.repo.GetNotifications(ctx, userID, limit, offset)
+}
+
+func (s *NotificationService) MarkRead(ctx context.Context, userID, id string) error {
+	return s.repo.MarkNotificationRead(ctx, id, userID)
+}
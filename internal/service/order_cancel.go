@@ -0,0 +1,134 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"log"
+	"unicode/utf8"
+	"uttc-hackathon-backend/internal/models"
+)
+
+const MaxCancelNote = 500
+
+var (
+	ErrInvalidCancelReason = errors.New("invalid cancel reason for the order's status")
+	ErrCancelNoteLong      = errors.New("cancel note must be 500 characters or fewer")
+	ErrCancelAfterShipping = errors.New("buyers cannot cancel after the order has shipped; open a dispute instead")
+)
+
+// cancelReasonsBeforeShipping and cancelReasonsAfterShipping list the reasons accepted in each phase.
+var (
+	cancelReasonsBeforeShipping = map[models.CancelReason]bool{
+		models.CancelReasonChangedMind:       true,
+		models.CancelReasonOutOfStock:        true,
+		models.CancelReasonBuyerUnresponsive: true,
+		models.CancelReasonOther:             true,
+	}
+	cancelReasonsAfterShipping = map[models.CancelReason]bool{
+		models.CancelReasonReturnedToSender: true,
+		models.CancelReasonLostInTransit:    true,
+	}
+)
+
+// cancelPolicy checks the phase-specific cancellation rules and reports whether the order's
+// quantity goes back on sale.
+//
+// Before shipping either side may cancel. Stock is restored unless the seller no longer has
+// the item (out_of_stock).
+//
+// After shipping only the seller may cancel, and only when the parcel came back or was lost.
+// Stock is restored only for a returned parcel.
+func cancelPolicy(o *models.Order, role models.OrderRole, reason models.CancelReason) (restoreStock bool, err error) {
+	switch o.Status {
+	case models.OrderStatusPaid:
+		if !cancelReasonsBeforeShipping[reason] {
+			return false, ErrInvalidCancelReason
+		}
+		return reason != models.CancelReasonOutOfStock, nil
+	case models.OrderStatusShipped:
+		if role != models.OrderRoleSeller {
+			return false, ErrCancelAfterShipping
+		}
+		if !cancelReasonsAfterShipping[reason] {
+			return false, ErrInvalidCancelReason
+		}
+		return reason == models.CancelReasonReturnedToSender, nil
+	default:
+		return false, ErrInvalidOrderTransition
+	}
+}
+
+// restoreStock puts the order's quantity back on the listing (and its variant). A sold-out
+// listing goes back on sale; listings the seller unpublished or an admin removed keep their status.
+func restoreStock(o *models.Order, l *models.Listing) {
+	if o.VariantID != "" {
+		if v := findVariant(l, o.VariantID); v != nil {
+			v.Quantity += o.Quantity
+		}
+	}
+	l.Quantity += o.Quantity
+	if l.Status == models.ListingStatusSold && l.Quantity > 0 {
+		l.Status = models.ListingStatusActive
+	}
+}
+
+// CancelOrder cancels the order for the user, recording who cancelled and why, and restores
+// the listing's stock when the cancellation policy allows it. The buyer has already paid, so
+// whatever earlier refunds left of the order is refunded to them. Everything happens in one
+// transaction with the order and listing locked; the refund is sent to the payment provider
+// after it commits.
+func (s *OrderService) CancelOrder(ctx context.Context, userID, orderID string, reason models.CancelReason, note string) (*models.Order, error) {
+	if utf8.RuneCountInString(note) > MaxCancelNote {
+		return nil, ErrCancelNoteLong
+	}
+
+	var cancelled *models.Order
+	var refund *models.Refund
+	err := s.repo.CancelOrder(ctx, orderID, func(o *models.Order, l *models.Listing) (*models.Refund, *models.LedgerTransaction, error) {
+		role := orderRole(o, userID)
+		if role == "" {
+			return nil, nil, ErrUnauthorized
+		}
+		next, err := nextOrderStatus(o, role, OrderActionCancel)
+		if err != nil {
+			return nil, nil, err
+		}
+		restore, err := cancelPolicy(o, role, reason)
+		if err != nil {
+			return nil, nil, err
+		}
+
+		if restore {
+			restoreStock(o, l)
+		}
+
+		now := s.now()
+		// The refund takes back the seller's whole pending payout, so only an order that
+		// earlier refunds already emptied needs the plain reversal
+		var rf *models.Refund
+		lt := orderCancelledLedger(o, o.Status, now)
+		if remaining := o.TotalPrice - o.RefundedAmount; remaining > 0 {
+			rf = applyRefund(o, remaining, o.Quantity-o.RefundedQuantity, models.RefundReasonOrderCancelled, note, userID, string(role), now)
+			lt = orderRefundedLedger(o, rf, now)
+		}
+		o.Status = next
+		o.Version++
+		o.CancelledBy = userID
+		o.CancelReason = reason
+		o.CancelNote = note
+		o.CancelledAt = &now
+		cancelled = o
+		refund = rf
+		return rf, lt, nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	if refund != nil {
+		if err := settleRefund(ctx, s.payments, s.refunds, refund, s.now()); err != nil {
+			log.Printf("settle refund %s: %v", refund.ID, err)
+		}
+	}
+	return cancelled, nil
+}
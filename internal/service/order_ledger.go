@@ -0,0 +1,82 @@
+package service
+
+import (
+	"time"
+	"uttc-hackathon-backend/internal/models"
+)
+
+// The seller's share of a sale (the order's net payout) is credited to seller_pending when
+// the buyer pays, moves to seller_available when the order completes, and is reversed when a
+// paid order is cancelled. The other side of each entry is platform_clearing, which holds the
+// buyer's money until it is owed to someone.
+
+func ledgerEntry(account models.LedgerAccount, userID string, amount int) models.LedgerEntry {
+	return models.LedgerEntry{Account: account, UserID: userID, Amount: amount}
+}
+
+// orderPaidLedger credits the seller's pending balance with the order's net payout.
+func orderPaidLedger(o *models.Order, at time.Time) *models.LedgerTransaction {
+	return &models.LedgerTransaction{
+		Type:    models.LedgerTxOrderPaid,
+		OrderID: o.ID,
+		Entries: []models.LedgerEntry{
+			ledgerEntry(models.LedgerAccountPlatformClearing, "", -o.NetPayout),
+			ledgerEntry(models.LedgerAccountSellerPending, o.SellerID, o.NetPayout),
+		},
+		CreatedAt: at,
+	}
+}
+
+// orderCompletedLedger makes the net payout, already reduced by any refunds, available to
+// the seller.
+func orderCompletedLedger(o *models.Order, at time.Time) *models.LedgerTransaction {
+	return &models.LedgerTransaction{
+		Type:    models.LedgerTxOrderCompleted,
+		OrderID: o.ID,
+		Entries: []models.LedgerEntry{
+			ledgerEntry(models.LedgerAccountSellerPending, o.SellerID, -o.NetPayout),
+			ledgerEntry(models.LedgerAccountSellerAvailable, o.SellerID, o.NetPayout),
+		},
+		CreatedAt: at,
+	}
+}
+
+// orderCancelledLedger reverses the pending credit of a cancelled order. Orders cancelled
+// before payment were never credited, and refunds already took back the whole payout of
+// fully refunded orders, so nil is returned for them.
+func orderCancelledLedger(o *models.Order, from models.OrderStatus, at time.Time) *models.LedgerTransaction {
+	if from == models.OrderStatusPendingPayment || o.NetPayout == 0 {
+		return nil
+	}
+	return &models.LedgerTransaction{
+		Type:    models.LedgerTxOrderCancelled,
+		OrderID: o.ID,
+		Entries: []models.LedgerEntry{
+			ledgerEntry(models.LedgerAccountSellerPending, o.SellerID, -o.NetPayout),
+			ledgerEntry(models.LedgerAccountPlatformClearing, "", o.NetPayout),
+		},
+		CreatedAt: at,
+	}
+}
+
+// orderRefundedLedger takes the seller's part of a refund back from their pending balance,
+// or from their available balance once the order has completed. Refunds the platform fee
+// covers entirely take nothing from the seller, so nil is returned for them.
+func orderRefundedLedger(o *models.Order, rf *models.Refund, at time.Time) *models.LedgerTransaction {
+	if rf.NetPayoutDelta == 0 {
+		return nil
+	}
+	account := models.LedgerAccountSellerPending
+	if o.Status == models.OrderStatusCompleted {
+		account = models.LedgerAccountSellerAvailable
+	}
+	return &models.LedgerTransaction{
+		Type:     models.LedgerTxOrderRefunded,
+		RefundID: rf.ID,
+		Entries: []models.LedgerEntry{
+			ledgerEntry(account, o.SellerID, -rf.NetPayoutDelta),
+			ledgerEntry(models.LedgerAccountPlatformClearing, "", rf.NetPayoutDelta),
+		},
+		CreatedAt: at,
+	}
+}
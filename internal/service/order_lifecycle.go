@@ -0,0 +1,148 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"slices"
+	"uttc-hackathon-backend/internal/models"
+)
+
+// OrderAction is a status change requested by the buyer or the seller.
+type OrderAction string
+
+const (
+	OrderActionShip     OrderAction = "ship"
+	OrderActionDeliver  OrderAction = "deliver"
+	OrderActionComplete OrderAction = "complete"
+	OrderActionCancel   OrderAction = "cancel"
+	OrderActionDispute  OrderAction = "dispute"
+)
+
+var (
+	ErrInvalidOrderAction     = errors.New("unknown order action")
+	ErrOrderActionForbidden   = errors.New("you are not allowed to perform this action on the order")
+	ErrInvalidOrderTransition = errors.New("order cannot change to that status from its current status")
+)
+
+type orderTransition struct {
+	from  []models.OrderStatus
+	to    models.OrderStatus
+	roles []models.OrderRole
+}
+
+// orderTransitions is the order state machine: which role may move an order from which
+// statuses, and to what.
+//
+//	pending_payment ──(payment webhook)──▶ paid; released automatically if unpaid (see order_payment.go)
+//
+//	paid ──ship──▶ shipped ──deliver──▶ delivered ──complete──▶ completed
+//	  │             │   │                  │
+//	  └───cancel────┘   └─────dispute──────┴──▶ disputed
+//	        │
+//	        ▼
+//	    cancelled
+//
+// Shipped orders with a tracking number also move to delivered when the carrier reports
+// delivery; see ShipmentService.SyncTracking.
+// Cancellation has further rules by reason and by whether the order has shipped; see cancelPolicy.
+// A disputed order leaves the machine only through an admin's resolution; see ResolveDispute.
+var orderTransitions = map[OrderAction]orderTransition{
+	OrderActionShip: {
+		from:  []models.OrderStatus{models.OrderStatusPaid},
+		to:    models.OrderStatusShipped,
+		roles: []models.OrderRole{models.OrderRoleSeller},
+	},
+	// Either side may confirm delivery: the seller from tracking, the buyer on receipt
+	OrderActionDeliver: {
+		from:  []models.OrderStatus{models.OrderStatusShipped},
+		to:    models.OrderStatusDelivered,
+		roles: []models.OrderRole{models.OrderRoleBuyer, models.OrderRoleSeller},
+	},
+	OrderActionComplete: {
+		from:  []models.OrderStatus{models.OrderStatusDelivered},
+		to:    models.OrderStatusCompleted,
+		roles: []models.OrderRole{models.OrderRoleBuyer},
+	},
+	OrderActionCancel: {
+		from:  []models.OrderStatus{models.OrderStatusPaid, models.OrderStatusShipped},
+		to:    models.OrderStatusCancelled,
+		roles: []models.OrderRole{models.OrderRoleBuyer, models.OrderRoleSeller},
+	},
+	OrderActionDispute: {
+		from:  []models.OrderStatus{models.OrderStatusShipped, models.OrderStatusDelivered},
+		to:    models.OrderStatusDisputed,
+		roles: []models.OrderRole{models.OrderRoleBuyer},
+	},
+}
+
+// orderRole returns the user's role in the order, or "" if they are not part of it.
+func orderRole(o *models.Order, userID string) models.OrderRole {
+	switch userID {
+	case o.SellerID:
+		return models.OrderRoleSeller
+	case o.BuyerID:
+		return models.OrderRoleBuyer
+	default:
+		return ""
+	}
+}
+
+// nextOrderStatus validates the action against the state machine and returns the new status.
+func nextOrderStatus(o *models.Order, role models.OrderRole, action OrderAction) (models.OrderStatus, error) {
+	t, ok := orderTransitions[action]
+	if !ok {
+		return "", ErrInvalidOrderAction
+	}
+	if !slices.Contains(t.roles, role) {
+		return "", ErrOrderActionForbidden
+	}
+	if !slices.Contains(t.from, o.Status) {
+		return "", ErrInvalidOrderTransition
+	}
+	return t.to, nil
+}
+
+// TransitionOrder applies the action for the user. The status is written only if the order
+// has not changed since it was read (repository.ErrOrderVersionConflict otherwise).
+// Cancellation and disputes carry more data, so they go through CancelOrder and
+// DisputeService.OpenDispute instead; shipping with a tracking number goes through
+// ShipmentService.ShipOrder.
+func (s *OrderService) TransitionOrder(ctx context.Context, userID, orderID string, action OrderAction) (*models.Order, error) {
+	if action == OrderActionCancel || action == OrderActionDispute {
+		return nil, ErrInvalidOrderAction
+	}
+
+	o, err := s.repo.GetOrder(ctx, orderID)
+	if err != nil {
+		return nil, err
+	}
+	role := orderRole(o, userID)
+	if role == "" {
+		return nil, ErrUnauthorized
+	}
+
+	next, err := nextOrderStatus(o, role, action)
+	if err != nil {
+		return nil, err
+	}
+
+	// Completion releases the seller's pending balance
+	now := s.now()
+	var lt *models.LedgerTransaction
+	if next == models.OrderStatusCompleted {
+		lt = orderCompletedLedger(o, now)
+	}
+
+	if err := s.repo.UpdateOrderStatus(ctx, o.ID, o.Version, next, userID, lt); err != nil {
+		return nil, err
+	}
+	o.Status = next
+	o.Version++
+	switch next {
+	case models.OrderStatusDelivered:
+		o.DeliveredAt = &now
+	case models.OrderStatusCompleted:
+		o.CompletedAt = &now
+	}
+	return o, nil
+}
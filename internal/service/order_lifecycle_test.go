@@ -0,0 +1,444 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"testing"
+
+	"uttc-hackathon-backend/internal/models"
+	"uttc-hackathon-backend/internal/repository"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+var allOrderStatuses = []models.OrderStatus{
+	models.OrderStatusPendingPayment,
+	models.OrderStatusPaid,
+	models.OrderStatusShipped,
+	models.OrderStatusDelivered,
+	models.OrderStatusCompleted,
+	models.OrderStatusCancelled,
+	models.OrderStatusDisputed,
+}
+
+func TestNextOrderStatus_StateMachine(t *testing.T) {
+	// The full specification: every (action, role, from) not listed here must be rejected.
+	allowed := []struct {
+		action OrderAction
+		role   models.OrderRole
+		from   models.OrderStatus
+		to     models.OrderStatus
+	}{
+		{OrderActionShip, models.OrderRoleSeller, models.OrderStatusPaid, models.OrderStatusShipped},
+		{OrderActionDeliver, models.OrderRoleSeller, models.OrderStatusShipped, models.OrderStatusDelivered},
+		{OrderActionDeliver, models.OrderRoleBuyer, models.OrderStatusShipped, models.OrderStatusDelivered},
+		{OrderActionComplete, models.OrderRoleBuyer, models.OrderStatusDelivered, models.OrderStatusCompleted},
+		{OrderActionCancel, models.OrderRoleBuyer, models.OrderStatusPaid, models.OrderStatusCancelled},
+		{OrderActionCancel, models.OrderRoleSeller, models.OrderStatusPaid, models.OrderStatusCancelled},
+		{OrderActionCancel, models.OrderRoleBuyer, models.OrderStatusShipped, models.OrderStatusCancelled}, // then refused by cancelPolicy
+		{OrderActionCancel, models.OrderRoleSeller, models.OrderStatusShipped, models.OrderStatusCancelled},
+		{OrderActionDispute, models.OrderRoleBuyer, models.OrderStatusShipped, models.OrderStatusDisputed},
+		{OrderActionDispute, models.OrderRoleBuyer, models.OrderStatusDelivered, models.OrderStatusDisputed},
+	}
+	// Roles that may perform each action at all, so rejected cases know which error to expect
+	roleAllowed := map[OrderAction]map[models.OrderRole]bool{
+		OrderActionShip:     {models.OrderRoleSeller: true},
+		OrderActionDeliver:  {models.OrderRoleSeller: true, models.OrderRoleBuyer: true},
+		OrderActionComplete: {models.OrderRoleBuyer: true},
+		OrderActionCancel:   {models.OrderRoleSeller: true, models.OrderRoleBuyer: true},
+		OrderActionDispute:  {models.OrderRoleBuyer: true},
+	}
+
+	for _, action := range []OrderAction{OrderActionShip, OrderActionDeliver, OrderActionComplete, OrderActionCancel, OrderActionDispute} {
+		for _, role := range []models.OrderRole{models.OrderRoleBuyer, models.OrderRoleSeller} {
+			for _, from := range allOrderStatuses {
+				var want models.OrderStatus
+				for _, a := range allowed {
+					if a.action == action && a.role == role && a.from == from {
+						want = a.to
+					}
+				}
+				var wantErr error
+				if want == "" {
+					if roleAllowed[action][role] {
+						wantErr = ErrInvalidOrderTransition
+					} else {
+						wantErr = ErrOrderActionForbidden
+					}
+				}
+
+				t.Run(fmt.Sprintf("%s by %s from %s", action, role, from), func(t *testing.T) {
+					got, err := nextOrderStatus(&models.Order{Status: from}, role, action)
+					assert.Equal(t, wantErr, err)
+					assert.Equal(t, want, got)
+				})
+			}
+		}
+	}
+}
+
+func TestOrderService_TransitionOrder(t *testing.T) {
+	tests := []struct {
+		name      string
+		userID    string
+		action    OrderAction
+		mockSetup func(*MockOrderRepository)
+		wantErr   error
+	}{
+		{
+			name:   "Ship Writes Next Version",
+			userID: "seller",
+			action: OrderActionShip,
+			mockSetup: func(m *MockOrderRepository) {
+				m.On("GetOrder", mock.Anything, "ord1").Return(&models.Order{
+					ID: "ord1", BuyerID: "buyer", SellerID: "seller", Status: models.OrderStatusPaid, Version: 3,
+				}, nil)
+				m.On("UpdateOrderStatus", mock.Anything, "ord1", 3, models.OrderStatusShipped, "seller", (*models.LedgerTransaction)(nil)).Return(nil)
+			},
+		},
+		{
+			name:   "Complete By Buyer",
+			userID: "buyer",
+			action: OrderActionComplete,
+			mockSetup: func(m *MockOrderRepository) {
+				m.On("GetOrder", mock.Anything, "ord1").Return(&models.Order{
+					ID: "ord1", BuyerID: "buyer", SellerID: "seller", Status: models.OrderStatusDelivered,
+				}, nil)
+				m.On("UpdateOrderStatus", mock.Anything, "ord1", 0, models.OrderStatusCompleted, "buyer", mock.MatchedBy(func(lt *models.LedgerTransaction) bool {
+					return lt.Type == models.LedgerTxOrderCompleted && lt.OrderID == "ord1"
+				})).Return(nil)
+			},
+		},
+		{
+			name:   "Stranger",
+			userID: "stranger",
+			action: OrderActionShip,
+			mockSetup: func(m *MockOrderRepository) {
+				m.On("GetOrder", mock.Anything, "ord1").Return(&models.Order{
+					ID: "ord1", BuyerID: "buyer", SellerID: "seller", Status: models.OrderStatusPaid,
+				}, nil)
+			},
+			wantErr: ErrUnauthorized,
+		},
+		{
+			name:   "Wrong Role",
+			userID: "buyer",
+			action: OrderActionShip,
+			mockSetup: func(m *MockOrderRepository) {
+				m.On("GetOrder", mock.Anything, "ord1").Return(&models.Order{
+					ID: "ord1", BuyerID: "buyer", SellerID: "seller", Status: models.OrderStatusPaid,
+				}, nil)
+			},
+			wantErr: ErrOrderActionForbidden,
+		},
+		{
+			name:      "Cancel Needs CancelOrder",
+			userID:    "buyer",
+			action:    OrderActionCancel,
+			mockSetup: func(m *MockOrderRepository) {},
+			wantErr:   ErrInvalidOrderAction,
+		},
+		{
+			name:      "Dispute Needs OpenDispute",
+			userID:    "buyer",
+			action:    OrderActionDispute,
+			mockSetup: func(m *MockOrderRepository) {},
+			wantErr:   ErrInvalidOrderAction,
+		},
+		{
+			name:   "Version Conflict",
+			userID: "seller",
+			action: OrderActionShip,
+			mockSetup: func(m *MockOrderRepository) {
+				m.On("GetOrder", mock.Anything, "ord1").Return(&models.Order{
+					ID: "ord1", BuyerID: "buyer", SellerID: "seller", Status: models.OrderStatusPaid, Version: 2,
+				}, nil)
+				m.On("UpdateOrderStatus", mock.Anything, "ord1", 2, models.OrderStatusShipped, "seller", (*models.LedgerTransaction)(nil)).Return(repository.ErrOrderVersionConflict)
+			},
+			wantErr: repository.ErrOrderVersionConflict,
+		},
+		{
+			name:   "Order Not Found",
+			userID: "seller",
+			action: OrderActionShip,
+			mockSetup: func(m *MockOrderRepository) {
+				m.On("GetOrder", mock.Anything, "ord1").Return(nil, repository.ErrOrderNotFound)
+			},
+			wantErr: repository.ErrOrderNotFound,
+		},
+		{
+			name:   "Unknown Action",
+			userID: "seller",
+			action: "refund",
+			mockSetup: func(m *MockOrderRepository) {
+				m.On("GetOrder", mock.Anything, "ord1").Return(&models.Order{
+					ID: "ord1", BuyerID: "buyer", SellerID: "seller", Status: models.OrderStatusPaid,
+				}, nil)
+			},
+			wantErr: ErrInvalidOrderAction,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			repo := new(MockOrderRepository)
+			tt.mockSetup(repo)
+
+			s := newTestOrderService(repo)
+			_, err := s.TransitionOrder(context.Background(), tt.userID, "ord1", tt.action)
+
+			assert.Equal(t, tt.wantErr, err)
+			repo.AssertExpectations(t)
+		})
+	}
+}
+
+func TestOrderService_CancelOrder(t *testing.T) {
+	tests := []struct {
+		name         string
+		userID       string
+		orderStatus  models.OrderStatus
+		variantID    string
+		listing      *models.Listing
+		reason       models.CancelReason
+		refunded     int // refunded before the cancellation
+		wantErr      error
+		wantQuantity int
+		wantVariant  int
+		wantListing  models.ListingStatus
+		wantRefund   int
+	}{
+		{
+			name:         "Buyer Before Shipping Restores Stock",
+			userID:       "buyer",
+			orderStatus:  models.OrderStatusPaid,
+			listing:      &models.Listing{ID: "lst1", Quantity: 1, Status: models.ListingStatusActive},
+			reason:       models.CancelReasonChangedMind,
+			wantQuantity: 3,
+			wantListing:  models.ListingStatusActive,
+			wantRefund:   2000,
+		},
+		{
+			name:         "Sold Out Listing Goes Back On Sale",
+			userID:       "seller",
+			orderStatus:  models.OrderStatusPaid,
+			listing:      &models.Listing{ID: "lst1", Quantity: 0, Status: models.ListingStatusSold},
+			reason:       models.CancelReasonBuyerUnresponsive,
+			wantQuantity: 2,
+			wantListing:  models.ListingStatusActive,
+			wantRefund:   2000,
+		},
+		{
+			name:         "Unpublished Listing Stays Unpublished",
+			userID:       "buyer",
+			orderStatus:  models.OrderStatusPaid,
+			listing:      &models.Listing{ID: "lst1", Quantity: 0, Status: models.ListingStatusDraft},
+			reason:       models.CancelReasonChangedMind,
+			wantQuantity: 2,
+			wantListing:  models.ListingStatusDraft,
+			wantRefund:   2000,
+		},
+		{
+			name:         "Removed Listing Stays Removed",
+			userID:       "buyer",
+			orderStatus:  models.OrderStatusPaid,
+			listing:      &models.Listing{ID: "lst1", Quantity: 0, Status: models.ListingStatusRemoved},
+			reason:       models.CancelReasonOther,
+			wantQuantity: 2,
+			wantListing:  models.ListingStatusRemoved,
+			wantRefund:   2000,
+		},
+		{
+			name:         "Out Of Stock Does Not Restore",
+			userID:       "seller",
+			orderStatus:  models.OrderStatusPaid,
+			listing:      &models.Listing{ID: "lst1", Quantity: 0, Status: models.ListingStatusSold},
+			reason:       models.CancelReasonOutOfStock,
+			wantQuantity: 0,
+			wantListing:  models.ListingStatusSold,
+			wantRefund:   2000,
+		},
+		{
+			name:        "Variant Stock Restored",
+			userID:      "buyer",
+			orderStatus: models.OrderStatusPaid,
+			variantID:   "var_m",
+			listing: &models.Listing{ID: "lst1", Quantity: 0, Status: models.ListingStatusSold, Variants: []models.ListingVariant{
+				{ID: "var_s", Quantity: 0}, {ID: "var_m", Quantity: 0},
+			}},
+			reason:       models.CancelReasonChangedMind,
+			wantQuantity: 2,
+			wantVariant:  2,
+			wantListing:  models.ListingStatusActive,
+			wantRefund:   2000,
+		},
+		{
+			name:         "Seller After Shipping Returned",
+			userID:       "seller",
+			orderStatus:  models.OrderStatusShipped,
+			listing:      &models.Listing{ID: "lst1", Quantity: 0, Status: models.ListingStatusSold},
+			reason:       models.CancelReasonReturnedToSender,
+			wantQuantity: 2,
+			wantListing:  models.ListingStatusActive,
+			wantRefund:   2000,
+		},
+		{
+			name:         "Partly Refunded Order Refunds The Rest",
+			userID:       "seller",
+			orderStatus:  models.OrderStatusShipped,
+			listing:      &models.Listing{ID: "lst1", Quantity: 0, Status: models.ListingStatusSold},
+			reason:       models.CancelReasonReturnedToSender,
+			refunded:     500,
+			wantQuantity: 2,
+			wantListing:  models.ListingStatusActive,
+			wantRefund:   1500,
+		},
+		{
+			name:         "Fully Refunded Order Has Nothing Left To Refund",
+			userID:       "buyer",
+			orderStatus:  models.OrderStatusPaid,
+			listing:      &models.Listing{ID: "lst1", Quantity: 0, Status: models.ListingStatusSold},
+			reason:       models.CancelReasonChangedMind,
+			refunded:     2000,
+			wantQuantity: 2,
+			wantListing:  models.ListingStatusActive,
+			wantRefund:   0,
+		},
+		{
+			name:         "Seller After Shipping Lost",
+			userID:       "seller",
+			orderStatus:  models.OrderStatusShipped,
+			listing:      &models.Listing{ID: "lst1", Quantity: 0, Status: models.ListingStatusSold},
+			reason:       models.CancelReasonLostInTransit,
+			wantQuantity: 0,
+			wantListing:  models.ListingStatusSold,
+			wantRefund:   2000,
+		},
+		{
+			name:        "Buyer After Shipping",
+			userID:      "buyer",
+			orderStatus: models.OrderStatusShipped,
+			listing:     &models.Listing{ID: "lst1"},
+			reason:      models.CancelReasonReturnedToSender,
+			wantErr:     ErrCancelAfterShipping,
+		},
+		{
+			name:        "Post-Shipping Reason Before Shipping",
+			userID:      "seller",
+			orderStatus: models.OrderStatusPaid,
+			listing:     &models.Listing{ID: "lst1"},
+			reason:      models.CancelReasonLostInTransit,
+			wantErr:     ErrInvalidCancelReason,
+		},
+		{
+			name:        "Pre-Shipping Reason After Shipping",
+			userID:      "seller",
+			orderStatus: models.OrderStatusShipped,
+			listing:     &models.Listing{ID: "lst1"},
+			reason:      models.CancelReasonChangedMind,
+			wantErr:     ErrInvalidCancelReason,
+		},
+		{
+			name:        "Delivered",
+			userID:      "seller",
+			orderStatus: models.OrderStatusDelivered,
+			listing:     &models.Listing{ID: "lst1"},
+			reason:      models.CancelReasonOther,
+			wantErr:     ErrInvalidOrderTransition,
+		},
+		{
+			name:        "Stranger",
+			userID:      "stranger",
+			orderStatus: models.OrderStatusPaid,
+			listing:     &models.Listing{ID: "lst1"},
+			reason:      models.CancelReasonOther,
+			wantErr:     ErrUnauthorized,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// 2 items for 2000 yen with a 200 yen fee, less what was refunded before
+			order := &models.Order{
+				ID: "ord1", BuyerID: "buyer", SellerID: "seller", ListingID: "lst1", VariantID: tt.variantID, Quantity: 2,
+				TotalPrice: 2000, PlatformFee: 200 - tt.refunded/10, NetPayout: 1800 - tt.refunded*9/10, RefundedAmount: tt.refunded,
+				Status: tt.orderStatus, Version: 4, PaymentIntentID: "pi_1",
+			}
+			repo := new(MockOrderRepository)
+			payments := new(MockPaymentProvider)
+			refunds := new(MockRefundRepository)
+			var committed bool
+			var rf *models.Refund
+			repo.On("CancelOrder", mock.Anything, "ord1", mock.Anything).Return(tt.wantErr).Run(func(args mock.Arguments) {
+				fn := args.Get(2).(func(*models.Order, *models.Listing) (*models.Refund, *models.LedgerTransaction, error))
+				refund, lt, err := fn(order, tt.listing)
+				assert.Equal(t, tt.wantErr, err)
+				if err == nil {
+					rf = refund
+					committed = true
+					// The refund takes back the seller's pending payout
+					if tt.wantRefund > 0 {
+						assert.Equal(t, models.LedgerTxOrderRefunded, lt.Type)
+						assert.Equal(t, refund.ID, lt.RefundID)
+						assertBalanced(t, lt)
+					} else {
+						assert.Nil(t, lt)
+					}
+				}
+			})
+			payments.On("Refund", mock.Anything, "pi_1", tt.wantRefund, mock.Anything).Return(nil).Run(func(args mock.Arguments) {
+				// No money moves while the order is locked
+				assert.True(t, committed)
+			}).Maybe()
+			refunds.On("CompleteRefund", mock.Anything, mock.Anything, mock.Anything).Return(nil).Maybe()
+
+			s := NewOrderService(repo, payments, newTestFeeSchedule(), newTestFraudScreen(), refunds)
+			got, err := s.CancelOrder(context.Background(), tt.userID, "ord1", tt.reason, "note")
+
+			if tt.wantErr != nil {
+				assert.Equal(t, tt.wantErr, err)
+				assert.Nil(t, got)
+				payments.AssertNotCalled(t, "Refund", mock.Anything, mock.Anything, mock.Anything, mock.Anything)
+				return
+			}
+			assert.NoError(t, err)
+			if tt.wantRefund > 0 {
+				assert.Equal(t, tt.wantRefund, rf.Amount)
+				assert.Equal(t, models.RefundReasonOrderCancelled, rf.Reason)
+				assert.Equal(t, tt.userID, rf.InitiatedBy)
+				assert.Equal(t, models.RefundStatusCompleted, rf.Status)
+				payments.AssertCalled(t, "Refund", mock.Anything, "pi_1", tt.wantRefund, rf.ID)
+				refunds.AssertCalled(t, "CompleteRefund", mock.Anything, rf.ID, mock.Anything)
+			} else {
+				assert.Nil(t, rf)
+				payments.AssertNotCalled(t, "Refund", mock.Anything, mock.Anything, mock.Anything, mock.Anything)
+			}
+			assert.Equal(t, 2000, got.RefundedAmount)
+			assert.Equal(t, 0, got.PlatformFee)
+			assert.Equal(t, 0, got.NetPayout)
+			assert.Equal(t, models.OrderStatusCancelled, got.Status)
+			assert.Equal(t, 5, got.Version)
+			assert.Equal(t, tt.userID, got.CancelledBy)
+			assert.Equal(t, tt.reason, got.CancelReason)
+			assert.NotNil(t, got.CancelledAt)
+			assert.Equal(t, tt.wantQuantity, tt.listing.Quantity)
+			assert.Equal(t, tt.wantListing, tt.listing.Status)
+			if tt.variantID != "" {
+				assert.Equal(t, tt.wantVariant, findVariant(tt.listing, tt.variantID).Quantity)
+				assert.Equal(t, 0, tt.listing.Variants[0].Quantity)
+			}
+			repo.AssertExpectations(t)
+		})
+	}
+}
+
+func TestOrderService_CancelOrder_NoteTooLong(t *testing.T) {
+	repo := new(MockOrderRepository)
+	s := newTestOrderService(repo)
+
+	_, err := s.CancelOrder(context.Background(), "buyer", "ord1", models.CancelReasonOther, string(make([]rune, MaxCancelNote+1)))
+
+	assert.Equal(t, ErrCancelNoteLong, err)
+	repo.AssertExpectations(t)
+}
@@ -0,0 +1,83 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"slices"
+	"uttc-hackathon-backend/internal/models"
+)
+
+const (
+	DefaultOrderListLimit = 50
+	MaxOrderListLimit     = 100
+)
+
+var (
+	ErrInvalidOrderRole   = errors.New("role must be buyer or seller")
+	ErrInvalidOrderStatus = errors.New("unknown order status")
+	ErrInvalidDateRange   = errors.New("the start of the date range must be before its end")
+)
+
+// orderStatuses lists every order status in lifecycle order.
+var orderStatuses = []models.OrderStatus{
+	models.OrderStatusPendingPayment,
+	models.OrderStatusPaid,
+	models.OrderStatusShipped,
+	models.OrderStatusDelivered,
+	models.OrderStatusCompleted,
+	models.OrderStatusCancelled,
+	models.OrderStatusDisputed,
+}
+
+func validateOrderRole(role models.OrderRole) error {
+	switch role {
+	case "", models.OrderRoleBuyer, models.OrderRoleSeller:
+		return nil
+	default:
+		return ErrInvalidOrderRole
+	}
+}
+
+// GetOrdersByUser returns one page of the orders the user bought or sold, newest first.
+func (s *OrderService) GetOrdersByUser(ctx context.Context, userID string, f models.OrderFilter) ([]*models.Order, error) {
+	if err := validateOrderRole(f.Role); err != nil {
+		return nil, err
+	}
+	for _, st := range f.Statuses {
+		if !slices.Contains(orderStatuses, st) {
+			return nil, ErrInvalidOrderStatus
+		}
+	}
+	if !f.CreatedFrom.IsZero() && !f.CreatedTo.IsZero() && !f.CreatedFrom.Before(f.CreatedTo) {
+		return nil, ErrInvalidDateRange
+	}
+	if f.Limit <= 0 {
+		f.Limit = DefaultOrderListLimit
+	}
+	if f.Limit > MaxOrderListLimit {
+		f.Limit = MaxOrderListLimit
+	}
+	if f.Offset < 0 {
+		f.Offset = 0
+	}
+	return s.repo.GetOrdersByUserID(ctx, userID, f)
+}
+
+// GetOrderSummary counts the user's orders per status on one side of the sale, or both
+// when role is empty. Every status is present in the counts.
+func (s *OrderService) GetOrderSummary(ctx context.Context, userID string, role models.OrderRole) (*models.OrderSummary, error) {
+	if err := validateOrderRole(role); err != nil {
+		return nil, err
+	}
+	counts, err := s.repo.CountOrdersByStatus(ctx, userID, role)
+	if err != nil {
+		return nil, err
+	}
+
+	summary := &models.OrderSummary{Role: role, Counts: make(map[models.OrderStatus]int, len(orderStatuses))}
+	for _, st := range orderStatuses {
+		summary.Counts[st] = counts[st]
+		summary.Total += counts[st]
+	}
+	return summary, nil
+}
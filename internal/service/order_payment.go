@@ -0,0 +1,176 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log"
+	"time"
+	"uttc-hackathon-backend/internal/models"
+	"uttc-hackathon-backend/internal/repository"
+)
+
+const (
+	// PaymentWindow is how long a new order holds its stock while the buyer pays.
+	PaymentWindow = 30 * time.Minute
+	// paymentExpiryBatchSize bounds how many orders one ExpireUnpaidOrders pass releases.
+	paymentExpiryBatchSize = 100
+)
+
+var (
+	ErrPaymentUnavailable    = errors.New("payment could not be started, please try again")
+	ErrInvalidPaymentWebhook = errors.New("invalid payment webhook")
+)
+
+// PaymentProvider takes payments for orders. Amounts are in JPY.
+type PaymentProvider interface {
+	// CreateIntent starts a payment the buyer completes on the client with the intent's secret.
+	CreateIntent(ctx context.Context, orderID string, amount int) (*models.PaymentIntent, error)
+	// Capture collects an authorized payment. Capturing an already captured intent is a no-op.
+	Capture(ctx context.Context, intentID string, amount int) error
+	// Refund returns amount of a captured payment to the buyer. Refunds with the same
+	// idempotency key are made only once.
+	Refund(ctx context.Context, intentID string, amount int, idempotencyKey string) error
+	// VerifyWebhook checks the callback's signature and decodes it.
+	VerifyWebhook(payload []byte, signature string) (*models.PaymentEvent, error)
+}
+
+// errOrderNotPending tells releasePendingOrder's callback to leave the order alone.
+var errOrderNotPending = errors.New("order is no longer pending payment")
+
+// startPayment creates the payment intent for a new pending order. If the payment cannot be
+// started the order is cancelled right away so its stock is not held for the whole window.
+func (s *OrderService) startPayment(ctx context.Context, o *models.Order) error {
+	intent, err := s.payments.CreateIntent(ctx, o.ID, o.TotalPrice)
+	if err == nil {
+		err = s.repo.SetPaymentIntent(ctx, o.ID, intent.ID)
+	}
+	if err != nil {
+		log.Printf("start payment for order %s: %v", o.ID, err)
+		if _, releaseErr := s.releasePendingOrder(context.WithoutCancel(ctx), o.ID, models.CancelReasonPaymentFailed); releaseErr != nil {
+			log.Printf("release order %s after payment error: %v", o.ID, releaseErr)
+		}
+		return ErrPaymentUnavailable
+	}
+
+	o.PaymentIntentID = intent.ID
+	o.PaymentSecret = intent.ClientSecret
+	return nil
+}
+
+// releasePendingOrder cancels an order that is still waiting for payment and puts its stock
+// back on sale. It reports false, without error, when the order is no longer pending or its
+// payment window has not closed yet for an expiry.
+func (s *OrderService) releasePendingOrder(ctx context.Context, orderID string, reason models.CancelReason) (bool, error) {
+	err := s.repo.CancelOrder(ctx, orderID, func(o *models.Order, l *models.Listing) (*models.Refund, *models.LedgerTransaction, error) {
+		now := s.now()
+		if o.Status != models.OrderStatusPendingPayment {
+			return nil, nil, errOrderNotPending
+		}
+		if reason == models.CancelReasonPaymentExpired && o.PaymentExpiresAt != nil && o.PaymentExpiresAt.After(now) {
+			return nil, nil, errOrderNotPending
+		}
+
+		restoreStock(o, l)
+		o.Status = models.OrderStatusCancelled
+		o.Version++
+		o.CancelledBy = models.CancelledBySystem
+		o.CancelReason = reason
+		o.CancelledAt = &now
+		// Unpaid orders were never charged or credited to the seller
+		return nil, nil, nil
+	})
+	if errors.Is(err, errOrderNotPending) {
+		return false, nil
+	}
+	if err != nil {
+		return false, err
+	}
+	return true, nil
+}
+
+// HandlePaymentWebhook verifies a provider callback and applies it. An authorized payment
+// marks the order paid and is then captured; a failed payment releases the order's stock.
+// Callbacks may be delivered more than once, so every step is safe to repeat, and an error
+// makes the provider retry.
+func (s *OrderService) HandlePaymentWebhook(ctx context.Context, payload []byte, signature string) error {
+	ev, err := s.payments.VerifyWebhook(payload, signature)
+	if err != nil {
+		return fmt.Errorf("%w: %v", ErrInvalidPaymentWebhook, err)
+	}
+
+	o, err := s.repo.GetOrderByPaymentIntent(ctx, ev.IntentID)
+	if err != nil {
+		if errors.Is(err, repository.ErrOrderNotFound) {
+			log.Printf("payment event %s for unknown intent %s ignored", ev.ID, ev.IntentID)
+			return nil
+		}
+		return err
+	}
+
+	switch ev.Type {
+	case models.PaymentEventAuthorized:
+		// Retrying cannot fix a wrong amount; leave it uncaptured for the window to expire
+		if ev.Amount != o.TotalPrice {
+			log.Printf("payment event %s: amount %d does not match order %s total %d", ev.ID, ev.Amount, o.ID, o.TotalPrice)
+			return nil
+		}
+		switch o.Status {
+		case models.OrderStatusPendingPayment:
+			if err := s.repo.MarkOrderPaid(ctx, o.ID, o.Version, orderPaidLedger(o, s.now())); err != nil {
+				return err
+			}
+		case models.OrderStatusPaid:
+			// A retry after a failed capture
+		default:
+			// The payment window closed first; the authorization lapses uncaptured
+			log.Printf("payment event %s for %s order %s not captured", ev.ID, o.Status, o.ID)
+			return nil
+		}
+		return s.payments.Capture(ctx, o.PaymentIntentID, o.TotalPrice)
+	case models.PaymentEventFailed:
+		_, err := s.releasePendingOrder(ctx, o.ID, models.CancelReasonPaymentFailed)
+		return err
+	default:
+		log.Printf("payment event %s of type %q ignored", ev.ID, ev.Type)
+		return nil
+	}
+}
+
+// ExpireUnpaidOrders releases the stock of orders whose payment window closed. It returns
+// how many orders were cancelled.
+func (s *OrderService) ExpireUnpaidOrders(ctx context.Context) (int, error) {
+	ids, err := s.repo.GetExpiredPendingOrderIDs(ctx, s.now(), paymentExpiryBatchSize)
+	if err != nil {
+		return 0, err
+	}
+
+	expired := 0
+	for _, id := range ids {
+		released, err := s.releasePendingOrder(ctx, id, models.CancelReasonPaymentExpired)
+		if err != nil {
+			log.Printf("expire unpaid order %s: %v", id, err)
+			continue
+		}
+		if released {
+			expired++
+		}
+	}
+	return expired, nil
+}
+
+// RunPaymentExpiryLoop calls ExpireUnpaidOrders every interval until ctx is cancelled.
+func (s *OrderService) RunPaymentExpiryLoop(ctx context.Context, interval time.Duration) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			if _, err := s.ExpireUnpaidOrders(ctx); err != nil {
+				log.Printf("expire unpaid orders: %v", err)
+			}
+		}
+	}
+}
@@ -0,0 +1,264 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"uttc-hackathon-backend/internal/models"
+	"uttc-hackathon-backend/internal/repository"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+type MockPaymentProvider struct {
+	mock.Mock
+}
+
+func (m *MockPaymentProvider) CreateIntent(ctx context.Context, orderID string, amount int) (*models.PaymentIntent, error) {
+	args := m.Called(ctx, orderID, amount)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*models.PaymentIntent), args.Error(1)
+}
+
+func (m *MockPaymentProvider) Capture(ctx context.Context, intentID string, amount int) error {
+	args := m.Called(ctx, intentID, amount)
+	return args.Error(0)
+}
+
+func (m *MockPaymentProvider) Refund(ctx context.Context, intentID string, amount int, idempotencyKey string) error {
+	args := m.Called(ctx, intentID, amount, idempotencyKey)
+	return args.Error(0)
+}
+
+func (m *MockPaymentProvider) VerifyWebhook(payload []byte, signature string) (*models.PaymentEvent, error) {
+	args := m.Called(payload, signature)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*models.PaymentEvent), args.Error(1)
+}
+
+var paymentTestNow = time.Date(2024, 6, 1, 9, 0, 0, 0, time.UTC)
+
+func TestOrderService_CreateOrder_StartsPayment(t *testing.T) {
+	repo := new(MockOrderRepository)
+	payments := new(MockPaymentProvider)
+	listing := &models.Listing{ID: "lst1", SellerID: "seller", Status: models.ListingStatusActive, Quantity: 1, Price: 2000}
+
+	repo.On("CreateOrder", mock.Anything, "lst1", mock.Anything).Return(nil).Run(func(args mock.Arguments) {
+		fn := args.Get(2).(func(*models.Listing) (*models.Order, error))
+		_, err := fn(listing)
+		assert.NoError(t, err)
+	})
+	payments.On("CreateIntent", mock.Anything, mock.AnythingOfType("string"), 2000).
+		Return(&models.PaymentIntent{ID: "pi_1", Amount: 2000, ClientSecret: "pi_1_secret"}, nil)
+	repo.On("SetPaymentIntent", mock.Anything, mock.AnythingOfType("string"), "pi_1").Return(nil)
+
+	s := NewOrderService(repo, payments, newTestFeeSchedule(), newTestFraudScreen(), new(MockRefundRepository))
+	s.now = func() time.Time { return paymentTestNow }
+	got, err := s.CreateOrder(context.Background(), "buyer", &models.Order{ListingID: "lst1", Quantity: 1, Status: models.OrderStatusPaid})
+
+	assert.NoError(t, err)
+	assert.Equal(t, models.OrderStatusPendingPayment, got.Status)
+	assert.Equal(t, "pi_1", got.PaymentIntentID)
+	assert.Equal(t, "pi_1_secret", got.PaymentSecret)
+	assert.Equal(t, paymentTestNow.Add(PaymentWindow), *got.PaymentExpiresAt)
+	assert.Equal(t, models.ListingStatusSold, listing.Status) // held while the buyer pays
+	repo.AssertExpectations(t)
+	payments.AssertExpectations(t)
+}
+
+func TestOrderService_CreateOrder_PaymentUnavailableReleasesStock(t *testing.T) {
+	repo := new(MockOrderRepository)
+	payments := new(MockPaymentProvider)
+	listing := &models.Listing{ID: "lst1", SellerID: "seller", Status: models.ListingStatusActive, Quantity: 1, Price: 2000}
+
+	var created *models.Order
+	repo.On("CreateOrder", mock.Anything, "lst1", mock.Anything).Return(nil).Run(func(args mock.Arguments) {
+		fn := args.Get(2).(func(*models.Listing) (*models.Order, error))
+		created, _ = fn(listing)
+	})
+	payments.On("CreateIntent", mock.Anything, mock.Anything, 2000).Return(nil, errors.New("provider down"))
+	repo.On("CancelOrder", mock.Anything, mock.AnythingOfType("string"), mock.Anything).Return(nil).Run(func(args mock.Arguments) {
+		fn := args.Get(2).(func(*models.Order, *models.Listing) (*models.Refund, *models.LedgerTransaction, error))
+		rf, lt, err := fn(created, listing)
+		assert.NoError(t, err)
+		assert.Nil(t, rf)
+		assert.Nil(t, lt)
+	})
+
+	s := NewOrderService(repo, payments, newTestFeeSchedule(), newTestFraudScreen(), new(MockRefundRepository))
+	_, err := s.CreateOrder(context.Background(), "buyer", &models.Order{ListingID: "lst1", Quantity: 1})
+
+	assert.ErrorIs(t, err, ErrPaymentUnavailable)
+	assert.Equal(t, models.OrderStatusCancelled, created.Status)
+	assert.Equal(t, models.CancelReasonPaymentFailed, created.CancelReason)
+	assert.Equal(t, models.CancelledBySystem, created.CancelledBy)
+	assert.Equal(t, models.ListingStatusActive, listing.Status)
+	assert.Equal(t, 1, listing.Quantity)
+	repo.AssertExpectations(t)
+}
+
+func TestOrderService_HandlePaymentWebhook(t *testing.T) {
+	payload := []byte(`{}`)
+
+	tests := []struct {
+		name      string
+		event     *models.PaymentEvent
+		order     *models.Order
+		mockSetup func(*MockOrderRepository, *MockPaymentProvider, *models.Order)
+		wantErr   error
+		wantOrder models.OrderStatus
+	}{
+		{
+			name:  "Authorized Marks Paid And Captures",
+			event: &models.PaymentEvent{ID: "evt1", Type: models.PaymentEventAuthorized, IntentID: "pi_1", Amount: 3000},
+			order: &models.Order{ID: "ord1", Status: models.OrderStatusPendingPayment, TotalPrice: 3000, PaymentIntentID: "pi_1", Version: 1},
+			mockSetup: func(r *MockOrderRepository, p *MockPaymentProvider, o *models.Order) {
+				r.On("MarkOrderPaid", mock.Anything, "ord1", 1, mock.MatchedBy(func(lt *models.LedgerTransaction) bool {
+					return lt.Type == models.LedgerTxOrderPaid && lt.OrderID == "ord1"
+				})).Return(nil)
+				p.On("Capture", mock.Anything, "pi_1", 3000).Return(nil)
+			},
+			wantOrder: models.OrderStatusPendingPayment,
+		},
+		{
+			name:  "Retry After Failed Capture",
+			event: &models.PaymentEvent{ID: "evt1", Type: models.PaymentEventAuthorized, IntentID: "pi_1", Amount: 3000},
+			order: &models.Order{ID: "ord1", Status: models.OrderStatusPaid, TotalPrice: 3000, PaymentIntentID: "pi_1", Version: 2},
+			mockSetup: func(r *MockOrderRepository, p *MockPaymentProvider, o *models.Order) {
+				p.On("Capture", mock.Anything, "pi_1", 3000).Return(nil)
+			},
+			wantOrder: models.OrderStatusPaid,
+		},
+		{
+			name:      "Authorized After Expiry Is Not Captured",
+			event:     &models.PaymentEvent{ID: "evt1", Type: models.PaymentEventAuthorized, IntentID: "pi_1", Amount: 3000},
+			order:     &models.Order{ID: "ord1", Status: models.OrderStatusCancelled, TotalPrice: 3000, PaymentIntentID: "pi_1"},
+			mockSetup: func(r *MockOrderRepository, p *MockPaymentProvider, o *models.Order) {},
+			wantOrder: models.OrderStatusCancelled,
+		},
+		{
+			name:  "Paid Concurrently",
+			event: &models.PaymentEvent{ID: "evt1", Type: models.PaymentEventAuthorized, IntentID: "pi_1", Amount: 3000},
+			order: &models.Order{ID: "ord1", Status: models.OrderStatusPendingPayment, TotalPrice: 3000, PaymentIntentID: "pi_1", Version: 1},
+			mockSetup: func(r *MockOrderRepository, p *MockPaymentProvider, o *models.Order) {
+				r.On("MarkOrderPaid", mock.Anything, "ord1", 1, mock.Anything).Return(repository.ErrOrderVersionConflict)
+			},
+			wantErr:   repository.ErrOrderVersionConflict,
+			wantOrder: models.OrderStatusPendingPayment,
+		},
+		{
+			name:      "Amount Mismatch Is Not Captured",
+			event:     &models.PaymentEvent{ID: "evt1", Type: models.PaymentEventAuthorized, IntentID: "pi_1", Amount: 100},
+			order:     &models.Order{ID: "ord1", Status: models.OrderStatusPendingPayment, TotalPrice: 3000, PaymentIntentID: "pi_1"},
+			mockSetup: func(r *MockOrderRepository, p *MockPaymentProvider, o *models.Order) {},
+			wantOrder: models.OrderStatusPendingPayment,
+		},
+		{
+			name:  "Failed Releases Stock",
+			event: &models.PaymentEvent{ID: "evt1", Type: models.PaymentEventFailed, IntentID: "pi_1"},
+			order: &models.Order{ID: "ord1", Status: models.OrderStatusPendingPayment, TotalPrice: 3000, PaymentIntentID: "pi_1", Quantity: 1},
+			mockSetup: func(r *MockOrderRepository, p *MockPaymentProvider, o *models.Order) {
+				r.On("CancelOrder", mock.Anything, "ord1", mock.Anything).Return(nil).Run(func(args mock.Arguments) {
+					fn := args.Get(2).(func(*models.Order, *models.Listing) (*models.Refund, *models.LedgerTransaction, error))
+					_, _, err := fn(o, &models.Listing{Status: models.ListingStatusSold})
+					assert.NoError(t, err)
+				})
+			},
+			wantOrder: models.OrderStatusCancelled,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			repo := new(MockOrderRepository)
+			payments := new(MockPaymentProvider)
+			payments.On("VerifyWebhook", payload, "sig").Return(tt.event, nil)
+			repo.On("GetOrderByPaymentIntent", mock.Anything, "pi_1").Return(tt.order, nil)
+			tt.mockSetup(repo, payments, tt.order)
+
+			s := NewOrderService(repo, payments, newTestFeeSchedule(), newTestFraudScreen(), new(MockRefundRepository))
+			err := s.HandlePaymentWebhook(context.Background(), payload, "sig")
+
+			if tt.wantErr != nil {
+				assert.ErrorIs(t, err, tt.wantErr)
+			} else {
+				assert.NoError(t, err)
+			}
+			assert.Equal(t, tt.wantOrder, tt.order.Status)
+			repo.AssertExpectations(t)
+			payments.AssertExpectations(t)
+		})
+	}
+}
+
+func TestOrderService_HandlePaymentWebhook_Rejects(t *testing.T) {
+	repo := new(MockOrderRepository)
+	payments := new(MockPaymentProvider)
+	payments.On("VerifyWebhook", []byte("forged"), "bad").Return(nil, repository.ErrInvalidWebhookSignature)
+
+	s := NewOrderService(repo, payments, newTestFeeSchedule(), newTestFraudScreen(), new(MockRefundRepository))
+	err := s.HandlePaymentWebhook(context.Background(), []byte("forged"), "bad")
+
+	assert.ErrorIs(t, err, ErrInvalidPaymentWebhook)
+	repo.AssertExpectations(t)
+}
+
+func TestOrderService_HandlePaymentWebhook_UnknownIntent(t *testing.T) {
+	repo := new(MockOrderRepository)
+	payments := new(MockPaymentProvider)
+	payments.On("VerifyWebhook", mock.Anything, "sig").Return(&models.PaymentEvent{Type: models.PaymentEventAuthorized, IntentID: "pi_x"}, nil)
+	repo.On("GetOrderByPaymentIntent", mock.Anything, "pi_x").Return(nil, repository.ErrOrderNotFound)
+
+	s := NewOrderService(repo, payments, newTestFeeSchedule(), newTestFraudScreen(), new(MockRefundRepository))
+	err := s.HandlePaymentWebhook(context.Background(), []byte(`{}`), "sig")
+
+	assert.NoError(t, err) // acknowledged so the provider stops retrying
+}
+
+func TestOrderService_ExpireUnpaidOrders(t *testing.T) {
+	repo := new(MockOrderRepository)
+	expiredAt := paymentTestNow.Add(-time.Minute)
+
+	expired := &models.Order{ID: "ord1", Status: models.OrderStatusPendingPayment, Quantity: 2, VariantID: "var1", PaymentExpiresAt: &expiredAt}
+	listing := &models.Listing{
+		Status: models.ListingStatusSold, Quantity: 0,
+		Variants: []models.ListingVariant{{ID: "var1", Quantity: 0}},
+	}
+	paid := &models.Order{ID: "ord2", Status: models.OrderStatusPaid}
+
+	repo.On("GetExpiredPendingOrderIDs", mock.Anything, paymentTestNow, paymentExpiryBatchSize).Return([]string{"ord1", "ord2"}, nil)
+	repo.On("CancelOrder", mock.Anything, "ord1", mock.Anything).Return(nil).Run(func(args mock.Arguments) {
+		fn := args.Get(2).(func(*models.Order, *models.Listing) (*models.Refund, *models.LedgerTransaction, error))
+		rf, lt, err := fn(expired, listing)
+		assert.NoError(t, err)
+		assert.Nil(t, rf) // never charged
+		assert.Nil(t, lt) // never credited
+	})
+	// ord2 was paid after the expired list was read
+	repo.On("CancelOrder", mock.Anything, "ord2", mock.Anything).Return(errOrderNotPending).Run(func(args mock.Arguments) {
+		fn := args.Get(2).(func(*models.Order, *models.Listing) (*models.Refund, *models.LedgerTransaction, error))
+		_, _, err := fn(paid, &models.Listing{})
+		assert.Equal(t, errOrderNotPending, err)
+	})
+
+	s := NewOrderService(repo, new(MockPaymentProvider), newTestFeeSchedule(), newTestFraudScreen(), new(MockRefundRepository))
+	s.now = func() time.Time { return paymentTestNow }
+	n, err := s.ExpireUnpaidOrders(context.Background())
+
+	assert.NoError(t, err)
+	assert.Equal(t, 1, n)
+	assert.Equal(t, models.OrderStatusCancelled, expired.Status)
+	assert.Equal(t, models.CancelReasonPaymentExpired, expired.CancelReason)
+	assert.Equal(t, 2, listing.Quantity)
+	assert.Equal(t, 2, listing.Variants[0].Quantity)
+	assert.Equal(t, models.ListingStatusActive, listing.Status)
+	assert.Equal(t, models.OrderStatusPaid, paid.Status)
+	repo.AssertExpectations(t)
+}
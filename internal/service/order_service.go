@@ -18,17 +18,41 @@ var (
 )
 
 type OrderService struct {
-	repo OrderRepository
+	repo     OrderRepository
+	payments PaymentProvider
+	fees     FeeSchedule
+	fraud    FraudScreen
+	refunds  RefundCompleter
+	now      func() time.Time
+}
+
+// FeeSchedule picks the platform fee policy for a sale of the listing.
+type FeeSchedule interface {
+	PolicyFor(ctx context.Context, listingID string) (*models.FeePolicy, error)
+}
+
+// FraudScreen vets an order before it is placed, failing with a FraudViolationError when the
+// buyer breaks a fraud rule.
+type FraudScreen interface {
+	CheckOrder(ctx context.Context, buyerID string, req *models.Order) error
 }
 
 type OrderRepository interface {
 	CreateOrder(ctx context.Context, listingID string, fn func(*models.Listing) (*models.Order, error)) error
 	GetOrder(ctx context.Context, orderID string) (*models.Order, error)
-	GetOrdersByUserID(ctx context.Context, userID string) ([]*models.Order, error)
+	GetOrdersByUserID(ctx context.Context, userID string, f models.OrderFilter) ([]*models.Order, error)
+	CountOrdersByStatus(ctx context.Context, userID string, role models.OrderRole) (map[models.OrderStatus]int, error)
+	UpdateOrderStatus(ctx context.Context, orderID string, version int, status models.OrderStatus, actorID string, lt *models.LedgerTransaction) error
+	CancelOrder(ctx context.Context, orderID string, fn func(*models.Order, *models.Listing) (*models.Refund, *models.LedgerTransaction, error)) error
+	SetPaymentIntent(ctx context.Context, orderID, intentID string) error
+	GetOrderByPaymentIntent(ctx context.Context, intentID string) (*models.Order, error)
+	MarkOrderPaid(ctx context.Context, orderID string, version int, lt *models.LedgerTransaction) error
+	GetExpiredPendingOrderIDs(ctx context.Context, now time.Time, limit int) ([]string, error)
+	GetOrderEvents(ctx context.Context, orderID string) ([]*models.OrderEvent, error)
 }
 
-func NewOrderService(repo OrderRepository) *OrderService {
-	return &OrderService{repo: repo}
+func NewOrderService(repo OrderRepository, payments PaymentProvider, fees FeeSchedule, fraud FraudScreen, refunds RefundCompleter) *OrderService {
+	return &OrderService{repo: repo, payments: payments, fees: fees, fraud: fraud, refunds: refunds, now: time.Now}
 }
 
 func (s *OrderService) CreateOrder(ctx context.Context, buyerID string, req *models.Order) (*models.Order, error) {
@@ -36,7 +60,19 @@ func (s *OrderService) CreateOrder(ctx context.Context, buyerID string, req *mod
 		return nil, ErrQuantityInvalid
 	}
 
-	err := s.repo.CreateOrder(ctx, req.ListingID, func(l *models.Listing) (*models.Order, error) {
+	// The policy depends only on the listing and the time, so it is picked before the
+	// listing is locked
+	policy, err := s.fees.PolicyFor(ctx, req.ListingID)
+	if err != nil {
+		return nil, err
+	}
+
+	// Velocity checks read the buyer's recent orders, so they run before any lock is taken
+	if err := s.fraud.CheckOrder(ctx, buyerID, req); err != nil {
+		return nil, err
+	}
+
+	err = s.repo.CreateOrder(ctx, req.ListingID, func(l *models.Listing) (*models.Order, error) {
 		if buyerID == l.SellerID {
 			return nil, ErrBuyOwnListing
 		}
@@ -45,6 +81,29 @@ func (s *OrderService) CreateOrder(ctx context.Context, buyerID string, req *mod
 			return nil, ErrListingNotActive
 		}
 
+		// Listings with variants are bought per variant; the listing quantity is the variant total
+		price := l.Price
+		req.VariantLabel = ""
+		if len(l.Variants) > 0 {
+			if req.VariantID == "" {
+				return nil, ErrVariantRequired
+			}
+			v := findVariant(l, req.VariantID)
+			if v == nil {
+				return nil, ErrVariantNotFound
+			}
+			if v.Quantity < req.Quantity {
+				return nil, ErrInsufficientStock
+			}
+			v.Quantity -= req.Quantity
+			if v.Price > 0 {
+				price = v.Price
+			}
+			req.VariantLabel = v.Label
+		} else if req.VariantID != "" {
+			return nil, ErrVariantNotFound
+		}
+
 		if l.Quantity < req.Quantity {
 			return nil, ErrInsufficientStock
 		}
@@ -57,18 +116,45 @@ func (s *OrderService) CreateOrder(ctx context.Context, buyerID string, req *mod
 		req.ID = "ord_" + ulid.Make().String()
 		req.BuyerID = buyerID
 		req.SellerID = l.SellerID
+		req.SellerInvoiceNumber = l.SellerInvoiceNumber
 		req.ListingTitle = l.Title
-		req.ListingPrice = l.Price
+		req.ListingPrice = price
+		req.ItemTaxRate = l.TaxRate
+		if req.ItemTaxRate == "" {
+			req.ItemTaxRate = models.TaxRateStandard
+		}
 		if len(l.Images) > 0 {
 			req.ListingMainImage = l.Images[0].URL
 		}
-		req.Status = models.OrderStatusPaid
-		req.CreatedAt = time.Now()
-		req.UpdatedAt = time.Now()
+		// Stock is held until the payment completes or the window closes
+		now := s.now()
+		expiresAt := now.Add(PaymentWindow)
+		req.Status = models.OrderStatusPendingPayment
+		req.PaymentIntentID = ""
+		req.PaymentExpiresAt = &expiresAt
+		req.PaymentSecret = ""
+		req.CreatedAt = now
+		req.UpdatedAt = now
+
+		// One package per order; buyer-paid (送料購入者負担) listings add shipping to the order total at checkout
+		req.ShippingPayer = l.ShippingPayer
+		req.ShippingMethod = l.ShippingMethod
+		req.ShippingSize = l.ShippingSize
+		req.ShippingFee = 0
+		if l.ShippingPayer == models.ShippingPayerBuyer {
+			fee, err := ShippingFee(l.ShippingMethod, l.ShippingSize)
+			if err != nil {
+				return nil, err
+			}
+			req.ShippingFee = fee
+		}
 
-		req.TotalPrice = l.Price * req.Quantity
-		// 10% fee
-		req.PlatformFee = (req.TotalPrice + 9) / 10
+		subtotal := price * req.Quantity
+		req.TotalPrice = subtotal + req.ShippingFee
+		req.Tax = orderTax(req.ItemTaxRate, subtotal, req.ShippingFee)
+		// The fee is charged on the item subtotal; the shipping fee is passed through to the seller
+		req.PlatformFee = PlatformFee(policy, subtotal)
+		req.FeePolicyID = policy.ID
 		req.NetPayout = req.TotalPrice - req.PlatformFee
 
 		return req, nil
@@ -78,6 +164,10 @@ func (s *OrderService) CreateOrder(ctx context.Context, buyerID string, req *mod
 		return nil, err
 	}
 
+	if err := s.startPayment(ctx, req); err != nil {
+		return nil, err
+	}
+
 	return req, nil
 }
 
@@ -95,6 +185,26 @@ func (s *OrderService) GetOrder(ctx context.Context, userID, orderID string) (*m
 	return order, nil
 }
 
-func (s *OrderService) GetOrdersByUser(ctx context.Context, userID string) ([]*models.Order, error) {
-	return s.repo.GetOrdersByUserID(ctx, userID)
+// GetOrderEvents returns the order's timeline, oldest first, to its buyer or seller. Each
+// event says whether the buyer, the seller, an admin or the platform acted.
+func (s *OrderService) GetOrderEvents(ctx context.Context, userID, orderID string) ([]*models.OrderEvent, error) {
+	o, err := s.GetOrder(ctx, userID, orderID)
+	if err != nil {
+		return nil, err
+	}
+	events, err := s.repo.GetOrderEvents(ctx, o.ID)
+	if err != nil {
+		return nil, err
+	}
+	for _, ev := range events {
+		switch {
+		case ev.ActorID == "":
+			ev.ActorRole = models.ActorRoleSystem
+		case orderRole(o, ev.ActorID) != "":
+			ev.ActorRole = string(orderRole(o, ev.ActorID))
+		default:
+			ev.ActorRole = models.ActorRoleAdmin
+		}
+	}
+	return events, nil
 }
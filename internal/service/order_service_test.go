@@ -3,6 +3,7 @@ package service
 import (
 	"context"
 	"testing"
+	"time"
 
 	"uttc-hackathon-backend/internal/models"
 
@@ -31,14 +32,76 @@ func (m *MockOrderRepository) GetOrder(ctx context.Context, orderID string) (*mo
 	return args.Get(0).(*models.Order), args.Error(1)
 }
 
-func (m *MockOrderRepository) GetOrdersByUserID(ctx context.Context, userID string) ([]*models.Order, error) {
-	args := m.Called(ctx, userID)
+func (m *MockOrderRepository) GetOrdersByUserID(ctx context.Context, userID string, f models.OrderFilter) ([]*models.Order, error) {
+	args := m.Called(ctx, userID, f)
 	if args.Get(0) == nil {
 		return nil, args.Error(1)
 	}
 	return args.Get(0).([]*models.Order), args.Error(1)
 }
 
+func (m *MockOrderRepository) CountOrdersByStatus(ctx context.Context, userID string, role models.OrderRole) (map[models.OrderStatus]int, error) {
+	args := m.Called(ctx, userID, role)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(map[models.OrderStatus]int), args.Error(1)
+}
+
+func (m *MockOrderRepository) UpdateOrderStatus(ctx context.Context, orderID string, version int, status models.OrderStatus, actorID string, lt *models.LedgerTransaction) error {
+	args := m.Called(ctx, orderID, version, status, actorID, lt)
+	return args.Error(0)
+}
+
+func (m *MockOrderRepository) CancelOrder(ctx context.Context, orderID string, fn func(*models.Order, *models.Listing) (*models.Refund, *models.LedgerTransaction, error)) error {
+	args := m.Called(ctx, orderID, fn)
+	return args.Error(0)
+}
+
+func (m *MockOrderRepository) SetPaymentIntent(ctx context.Context, orderID, intentID string) error {
+	args := m.Called(ctx, orderID, intentID)
+	return args.Error(0)
+}
+
+func (m *MockOrderRepository) GetOrderByPaymentIntent(ctx context.Context, intentID string) (*models.Order, error) {
+	args := m.Called(ctx, intentID)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*models.Order), args.Error(1)
+}
+
+func (m *MockOrderRepository) MarkOrderPaid(ctx context.Context, orderID string, version int, lt *models.LedgerTransaction) error {
+	args := m.Called(ctx, orderID, version, lt)
+	return args.Error(0)
+}
+
+func (m *MockOrderRepository) GetExpiredPendingOrderIDs(ctx context.Context, now time.Time, limit int) ([]string, error) {
+	args := m.Called(ctx, now, limit)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]string), args.Error(1)
+}
+
+func (m *MockOrderRepository) GetOrderEvents(ctx context.Context, orderID string) ([]*models.OrderEvent, error) {
+	args := m.Called(ctx, orderID)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]*models.OrderEvent), args.Error(1)
+}
+
+// newTestOrderService returns an OrderService whose payment provider accepts every new order
+// and whose fee schedule charges the standard 10%.
+func newTestOrderService(repo *MockOrderRepository) *OrderService {
+	payments := new(MockPaymentProvider)
+	payments.On("CreateIntent", mock.Anything, mock.Anything, mock.Anything).
+		Return(&models.PaymentIntent{ID: "pi_1", ClientSecret: "pi_1_secret"}, nil).Maybe()
+	repo.On("SetPaymentIntent", mock.Anything, mock.Anything, "pi_1").Return(nil).Maybe()
+	return NewOrderService(repo, payments, newTestFeeSchedule(), newTestFraudScreen(), new(MockRefundRepository))
+}
+
 func TestOrderService_CreateOrder(t *testing.T) {
 	tests := []struct {
 		name      string
@@ -131,7 +194,7 @@ func TestOrderService_CreateOrder(t *testing.T) {
 				tt.mockSetup(repo, tt.req, tt.listing)
 			}
 
-			s := NewOrderService(repo)
+			s := newTestOrderService(repo)
 			got, err := s.CreateOrder(context.Background(), tt.buyerID, tt.req)
 
 			if tt.wantErr {
@@ -145,7 +208,7 @@ func TestOrderService_CreateOrder(t *testing.T) {
 				// Check calculated fields
 				assert.Equal(t, tt.listing.Price*tt.req.Quantity, got.TotalPrice)
 				assert.NotEmpty(t, got.ID)
-				assert.Equal(t, models.OrderStatusPaid, got.Status)
+				assert.Equal(t, models.OrderStatusPendingPayment, got.Status)
 			}
 			repo.AssertExpectations(t)
 		})
@@ -213,7 +276,7 @@ func TestOrderService_GetOrder(t *testing.T) {
 			repo := new(MockOrderRepository)
 			tt.mockSetup(repo)
 
-			s := NewOrderService(repo)
+			s := newTestOrderService(repo)
 			got, err := s.GetOrder(context.Background(), tt.userID, tt.orderID)
 
 			if tt.wantErr {
@@ -231,14 +294,385 @@ func TestOrderService_GetOrder(t *testing.T) {
 }
 
 func TestOrderService_GetOrdersByUser(t *testing.T) {
-	orders := []*models.Order{{ID: "ord1"}, {ID: "ord2"}}
+	from := time.Date(2024, 9, 1, 0, 0, 0, 0, time.UTC)
+	to := time.Date(2024, 10, 1, 0, 0, 0, 0, time.UTC)
+	tests := []struct {
+		name       string
+		filter     models.OrderFilter
+		wantFilter models.OrderFilter
+		wantErr    error
+	}{
+		{
+			name:       "Defaults",
+			filter:     models.OrderFilter{},
+			wantFilter: models.OrderFilter{Limit: DefaultOrderListLimit},
+		},
+		{
+			name: "Seller Filtered Page",
+			filter: models.OrderFilter{
+				Role:        models.OrderRoleSeller,
+				Statuses:    []models.OrderStatus{models.OrderStatusPaid, models.OrderStatusShipped},
+				CreatedFrom: from,
+				CreatedTo:   to,
+				Limit:       20,
+				Offset:      40,
+			},
+			wantFilter: models.OrderFilter{
+				Role:        models.OrderRoleSeller,
+				Statuses:    []models.OrderStatus{models.OrderStatusPaid, models.OrderStatusShipped},
+				CreatedFrom: from,
+				CreatedTo:   to,
+				Limit:       20,
+				Offset:      40,
+			},
+		},
+		{
+			name:       "Limit Capped",
+			filter:     models.OrderFilter{Role: models.OrderRoleBuyer, Limit: 1000, Offset: -5},
+			wantFilter: models.OrderFilter{Role: models.OrderRoleBuyer, Limit: MaxOrderListLimit},
+		},
+		{
+			name:    "Invalid Role",
+			filter:  models.OrderFilter{Role: "admin"},
+			wantErr: ErrInvalidOrderRole,
+		},
+		{
+			name:    "Unknown Status",
+			filter:  models.OrderFilter{Statuses: []models.OrderStatus{"lost"}},
+			wantErr: ErrInvalidOrderStatus,
+		},
+		{
+			name:    "Reversed Date Range",
+			filter:  models.OrderFilter{CreatedFrom: to, CreatedTo: from},
+			wantErr: ErrInvalidDateRange,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			orders := []*models.Order{{ID: "ord1"}, {ID: "ord2"}}
+			repo := new(MockOrderRepository)
+			if tt.wantErr == nil {
+				repo.On("GetOrdersByUserID", mock.Anything, "user1", tt.wantFilter).Return(orders, nil)
+			}
+
+			s := newTestOrderService(repo)
+			got, err := s.GetOrdersByUser(context.Background(), "user1", tt.filter)
+
+			if tt.wantErr != nil {
+				assert.ErrorIs(t, err, tt.wantErr)
+				repo.AssertNotCalled(t, "GetOrdersByUserID", mock.Anything, mock.Anything, mock.Anything)
+				return
+			}
+			assert.NoError(t, err)
+			assert.Equal(t, orders, got)
+			repo.AssertExpectations(t)
+		})
+	}
+}
+
+func TestOrderService_GetOrderSummary(t *testing.T) {
 	repo := new(MockOrderRepository)
-	repo.On("GetOrdersByUserID", mock.Anything, "user1").Return(orders, nil)
+	repo.On("CountOrdersByStatus", mock.Anything, "seller1", models.OrderRoleSeller).
+		Return(map[models.OrderStatus]int{models.OrderStatusPaid: 3, models.OrderStatusCompleted: 12}, nil)
 
-	s := NewOrderService(repo)
-	got, err := s.GetOrdersByUser(context.Background(), "user1")
+	s := newTestOrderService(repo)
+	got, err := s.GetOrderSummary(context.Background(), "seller1", models.OrderRoleSeller)
 
 	assert.NoError(t, err)
-	assert.Equal(t, orders, got)
+	assert.Equal(t, models.OrderRoleSeller, got.Role)
+	assert.Equal(t, 15, got.Total)
+	assert.Len(t, got.Counts, 7)
+	assert.Equal(t, 3, got.Counts[models.OrderStatusPaid])
+	assert.Equal(t, 0, got.Counts[models.OrderStatusShipped])
 	repo.AssertExpectations(t)
+
+	_, err = s.GetOrderSummary(context.Background(), "seller1", "admin")
+	assert.ErrorIs(t, err, ErrInvalidOrderRole)
+}
+
+func TestOrderService_CreateOrder_ShippingAndFees(t *testing.T) {
+	tests := []struct {
+		name            string
+		listing         *models.Listing
+		quantity        int
+		wantShippingFee int
+		wantTotal       int
+		wantFee         int
+		wantPayout      int
+		wantErr         error
+	}{
+		{
+			name: "Seller Pays Shipping",
+			listing: &models.Listing{
+				Price: 1000, ShippingPayer: models.ShippingPayerSeller,
+				ShippingMethod: models.ShippingMethodTakkyubin, ShippingSize: models.ShippingSize60,
+			},
+			quantity:        1,
+			wantShippingFee: 0,
+			wantTotal:       1000,
+			wantFee:         100,
+			wantPayout:      900,
+		},
+		{
+			name: "Buyer Pays Shipping",
+			listing: &models.Listing{
+				Price: 1000, ShippingPayer: models.ShippingPayerBuyer,
+				ShippingMethod: models.ShippingMethodTakkyubin, ShippingSize: models.ShippingSize80,
+			},
+			quantity:        1,
+			wantShippingFee: 850,
+			wantTotal:       1850,
+			wantFee:         100, // fee is on the item subtotal only
+			wantPayout:      1750,
+		},
+		{
+			name: "Buyer Pays One Package For Several Items",
+			listing: &models.Listing{
+				Price: 333, ShippingPayer: models.ShippingPayerBuyer,
+				ShippingMethod: models.ShippingMethodNekopos, ShippingSize: models.ShippingSizeSmall,
+			},
+			quantity:        3,
+			wantShippingFee: 210,
+			wantTotal:       999 + 210,
+			wantFee:         100, // 10% of 999 rounded up
+			wantPayout:      999 + 210 - 100,
+		},
+		{
+			name:            "Legacy Listing Without Shipping Options",
+			listing:         &models.Listing{Price: 500},
+			quantity:        2,
+			wantShippingFee: 0,
+			wantTotal:       1000,
+			wantFee:         100,
+			wantPayout:      900,
+		},
+		{
+			name: "Buyer Pays With Unknown Size",
+			listing: &models.Listing{
+				Price: 1000, ShippingPayer: models.ShippingPayerBuyer,
+				ShippingMethod: models.ShippingMethodNekopos, ShippingSize: models.ShippingSize160,
+			},
+			quantity: 1,
+			wantErr:  ErrInvalidShippingMethod,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tt.listing.ID = "lst1"
+			tt.listing.SellerID = "seller1"
+			tt.listing.Status = models.ListingStatusActive
+			tt.listing.Quantity = 10
+
+			repo := new(MockOrderRepository)
+			repo.On("CreateOrder", mock.Anything, "lst1", mock.Anything).Return(tt.wantErr).Run(func(args mock.Arguments) {
+				fn := args.Get(2).(func(*models.Listing) (*models.Order, error))
+				_, err := fn(tt.listing)
+				assert.Equal(t, tt.wantErr, err)
+			})
+
+			s := newTestOrderService(repo)
+			got, err := s.CreateOrder(context.Background(), "buyer1", &models.Order{ListingID: "lst1", Quantity: tt.quantity})
+
+			if tt.wantErr != nil {
+				assert.Equal(t, tt.wantErr, err)
+				return
+			}
+			assert.NoError(t, err)
+			assert.Equal(t, tt.wantShippingFee, got.ShippingFee)
+			assert.Equal(t, tt.wantTotal, got.TotalPrice)
+			assert.Equal(t, tt.wantFee, got.PlatformFee)
+			assert.Equal(t, "fee_default", got.FeePolicyID)
+			assert.Equal(t, tt.wantPayout, got.NetPayout)
+			assert.Equal(t, got.TotalPrice, got.PlatformFee+got.NetPayout)
+			repo.AssertExpectations(t)
+		})
+	}
+}
+
+func TestOrderService_CreateOrder_Tax(t *testing.T) {
+	listing := &models.Listing{
+		ID: "lst1", SellerID: "seller1", SellerInvoiceNumber: "T7000012050002", Status: models.ListingStatusActive,
+		Price: 1000, TaxRate: models.TaxRateReduced, Quantity: 10,
+		ShippingPayer: models.ShippingPayerBuyer, ShippingMethod: models.ShippingMethodNekopos, ShippingSize: models.ShippingSizeSmall,
+	}
+	repo := new(MockOrderRepository)
+	repo.On("CreateOrder", mock.Anything, "lst1", mock.Anything).Return(nil).Run(func(args mock.Arguments) {
+		fn := args.Get(2).(func(*models.Listing) (*models.Order, error))
+		_, err := fn(listing)
+		assert.NoError(t, err)
+	})
+
+	got, err := newTestOrderService(repo).CreateOrder(context.Background(), "buyer1", &models.Order{ListingID: "lst1", Quantity: 2})
+
+	assert.NoError(t, err)
+	assert.Equal(t, models.TaxRateReduced, got.ItemTaxRate)
+	assert.Equal(t, "T7000012050002", got.SellerInvoiceNumber)
+	// The food is taxed at 8% and the shipping at 10%
+	assert.Equal(t, models.TaxBreakdown{StandardRateTotal: 210, StandardRateTax: 19, ReducedRateTotal: 2000, ReducedRateTax: 148}, got.Tax)
+	assert.Equal(t, got.TotalPrice, got.Tax.StandardRateTotal+got.Tax.ReducedRateTotal)
+}
+
+func TestOrderService_CreateOrder_Variants(t *testing.T) {
+	newListing := func() *models.Listing {
+		return &models.Listing{
+			ID: "lst1", SellerID: "seller1", Status: models.ListingStatusActive, Price: 3000, Quantity: 3,
+			Variants: []models.ListingVariant{
+				{ID: "var_s", Label: "S", Quantity: 1},
+				{ID: "var_m", Label: "M", Price: 3500, Quantity: 2},
+			},
+		}
+	}
+
+	tests := []struct {
+		name         string
+		listing      *models.Listing
+		req          *models.Order
+		wantErr      error
+		wantPrice    int
+		wantLabel    string
+		wantVariants []int
+		wantQuantity int
+		wantStatus   models.ListingStatus
+	}{
+		{
+			name:         "Base Price Variant",
+			listing:      newListing(),
+			req:          &models.Order{ListingID: "lst1", VariantID: "var_s", Quantity: 1},
+			wantPrice:    3000,
+			wantLabel:    "S",
+			wantVariants: []int{0, 2},
+			wantQuantity: 2,
+			wantStatus:   models.ListingStatusActive,
+		},
+		{
+			name:         "Price Override",
+			listing:      newListing(),
+			req:          &models.Order{ListingID: "lst1", VariantID: "var_m", Quantity: 2},
+			wantPrice:    3500,
+			wantLabel:    "M",
+			wantVariants: []int{1, 0},
+			wantQuantity: 1,
+			wantStatus:   models.ListingStatusActive,
+		},
+		{
+			name: "Last Variant Sells Out Listing",
+			listing: func() *models.Listing {
+				l := newListing()
+				l.Variants[1].Quantity = 0
+				l.Quantity = 1
+				return l
+			}(),
+			req:          &models.Order{ListingID: "lst1", VariantID: "var_s", Quantity: 1},
+			wantPrice:    3000,
+			wantLabel:    "S",
+			wantVariants: []int{0, 0},
+			wantQuantity: 0,
+			wantStatus:   models.ListingStatusSold,
+		},
+		{
+			name:    "Variant Required",
+			listing: newListing(),
+			req:     &models.Order{ListingID: "lst1", Quantity: 1},
+			wantErr: ErrVariantRequired,
+		},
+		{
+			name:    "Unknown Variant",
+			listing: newListing(),
+			req:     &models.Order{ListingID: "lst1", VariantID: "var_x", Quantity: 1},
+			wantErr: ErrVariantNotFound,
+		},
+		{
+			name:    "Variant On Listing Without Variants",
+			listing: &models.Listing{ID: "lst1", SellerID: "seller1", Status: models.ListingStatusActive, Price: 3000, Quantity: 3},
+			req:     &models.Order{ListingID: "lst1", VariantID: "var_s", Quantity: 1},
+			wantErr: ErrVariantNotFound,
+		},
+		{
+			name:    "Variant Out Of Stock",
+			listing: newListing(),
+			req:     &models.Order{ListingID: "lst1", VariantID: "var_s", Quantity: 2},
+			wantErr: ErrInsufficientStock,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			repo := new(MockOrderRepository)
+			repo.On("CreateOrder", mock.Anything, "lst1", mock.Anything).Return(tt.wantErr).Run(func(args mock.Arguments) {
+				fn := args.Get(2).(func(*models.Listing) (*models.Order, error))
+				_, err := fn(tt.listing)
+				assert.Equal(t, tt.wantErr, err)
+			})
+
+			s := newTestOrderService(repo)
+			got, err := s.CreateOrder(context.Background(), "buyer1", tt.req)
+
+			if tt.wantErr != nil {
+				assert.Equal(t, tt.wantErr, err)
+				return
+			}
+			assert.NoError(t, err)
+			assert.Equal(t, tt.wantPrice, got.ListingPrice)
+			assert.Equal(t, tt.wantPrice*tt.req.Quantity, got.TotalPrice)
+			assert.Equal(t, tt.wantLabel, got.VariantLabel)
+			for i, q := range tt.wantVariants {
+				assert.Equal(t, q, tt.listing.Variants[i].Quantity)
+			}
+			assert.Equal(t, tt.wantQuantity, tt.listing.Quantity)
+			assert.Equal(t, tt.wantStatus, tt.listing.Status)
+			repo.AssertExpectations(t)
+		})
+	}
+}
+
+func TestOrderService_GetOrderEvents(t *testing.T) {
+	order := &models.Order{ID: "ord1", BuyerID: "buyer", SellerID: "seller"}
+	events := []*models.OrderEvent{
+		{ID: 1, OrderID: "ord1", Type: models.OrderEventCreated, ActorID: "buyer", ToStatus: models.OrderStatusPendingPayment},
+		{ID: 2, OrderID: "ord1", Type: models.OrderEventPaid, FromStatus: models.OrderStatusPendingPayment, ToStatus: models.OrderStatusPaid},
+		{ID: 3, OrderID: "ord1", Type: models.OrderEventShipped, ActorID: "seller", FromStatus: models.OrderStatusPaid, ToStatus: models.OrderStatusShipped},
+		{ID: 4, OrderID: "ord1", Type: models.OrderEventCompleted, ActorID: "admin1", FromStatus: models.OrderStatusDisputed, ToStatus: models.OrderStatusCompleted},
+	}
+
+	t.Run("Participant", func(t *testing.T) {
+		repo := new(MockOrderRepository)
+		repo.On("GetOrder", mock.Anything, "ord1").Return(order, nil)
+		repo.On("GetOrderEvents", mock.Anything, "ord1").Return(events, nil)
+		s := newTestOrderService(repo)
+
+		got, err := s.GetOrderEvents(context.Background(), "seller", "ord1")
+
+		assert.NoError(t, err)
+		var roles []string
+		for _, ev := range got {
+			roles = append(roles, ev.ActorRole)
+		}
+		assert.Equal(t, []string{"buyer", models.ActorRoleSystem, "seller", models.ActorRoleAdmin}, roles)
+	})
+
+	t.Run("Stranger", func(t *testing.T) {
+		repo := new(MockOrderRepository)
+		repo.On("GetOrder", mock.Anything, "ord1").Return(order, nil)
+		s := newTestOrderService(repo)
+
+		_, err := s.GetOrderEvents(context.Background(), "stranger", "ord1")
+
+		assert.ErrorIs(t, err, ErrUnauthorized)
+		repo.AssertNotCalled(t, "GetOrderEvents", mock.Anything, mock.Anything)
+	})
+}
+
+func TestOrderService_CreateOrder_BlockedByFraudRule(t *testing.T) {
+	repo := new(MockOrderRepository)
+	fraud := new(MockFraudScreen)
+	violation := &FraudViolationError{Rule: models.FraudRuleOrderVelocity, ReviewID: "frv_1"}
+	fraud.On("CheckOrder", mock.Anything, "buyer1", mock.Anything).Return(violation)
+
+	s := NewOrderService(repo, new(MockPaymentProvider), newTestFeeSchedule(), fraud, new(MockRefundRepository))
+	_, err := s.CreateOrder(context.Background(), "buyer1", &models.Order{ListingID: "lst1", Quantity: 1})
+
+	assert.ErrorIs(t, err, ErrOrderBlocked)
+	repo.AssertNotCalled(t, "CreateOrder", mock.Anything, mock.Anything, mock.Anything)
 }
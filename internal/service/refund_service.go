@@ -0,0 +1,229 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log"
+	"strings"
+	"time"
+	"unicode/utf8"
+	"uttc-hackathon-backend/internal/models"
+
+	"github.com/oklog/ulid/v2"
+)
+
+const (
+	MaxRefundNote = 1000
+	// refundRetryDelay leaves a new refund to the request that recorded it before
+	// RetryPendingRefunds takes it over.
+	refundRetryDelay     = time.Minute
+	refundRetryBatchSize = 100
+)
+
+var (
+	ErrInvalidRefundReason    = errors.New("invalid refund reason")
+	ErrRefundAmountRequired   = errors.New("refund a positive amount or number of items")
+	ErrRefundExceedsTotal     = errors.New("refunds cannot exceed the order total")
+	ErrRefundQuantityExceeded = errors.New("cannot refund more items than were ordered")
+	ErrRefundNoteLong         = errors.New("note must be 1000 characters or fewer")
+	ErrOrderNotRefundable     = errors.New("only paid orders that were not cancelled can be refunded")
+)
+
+type RefundRepository interface {
+	RefundOrder(ctx context.Context, orderID string, fn func(*models.Order) (*models.Refund, *models.LedgerTransaction, error)) error
+	GetRefunds(ctx context.Context, orderID string) ([]*models.Refund, error)
+	GetPendingRefunds(ctx context.Context, createdBefore time.Time, limit int) ([]*models.Refund, error)
+	RefundCompleter
+}
+
+// RefundCompleter records that the payment provider has returned a refund to the buyer.
+type RefundCompleter interface {
+	CompleteRefund(ctx context.Context, refundID string, at time.Time) error
+}
+
+// settleRefund asks the payment provider to return a committed refund to the buyer and marks
+// it completed. The refund ID is the provider's idempotency key, so a refund retried after an
+// error or a lost response is paid out only once. A refund left pending is retried by
+// RetryPendingRefunds.
+func settleRefund(ctx context.Context, payments PaymentProvider, refunds RefundCompleter, rf *models.Refund, at time.Time) error {
+	if err := payments.Refund(ctx, rf.PaymentIntentID, rf.Amount, rf.ID); err != nil {
+		return fmt.Errorf("refund %s through the payment provider: %w", rf.ID, err)
+	}
+	if err := refunds.CompleteRefund(ctx, rf.ID, at); err != nil {
+		return err
+	}
+	rf.Status = models.RefundStatusCompleted
+	rf.CompletedAt = &at
+	return nil
+}
+
+// RefundService returns money to buyers. Sellers refund their own orders and admins any
+// order; the platform fee is given back in proportion to the refund and the rest comes out
+// of the seller's payout.
+type RefundService struct {
+	repo     RefundRepository
+	orders   OrderReader
+	payments PaymentProvider
+	now      func() time.Time
+}
+
+func NewRefundService(repo RefundRepository, orders OrderReader, payments PaymentProvider) *RefundService {
+	return &RefundService{repo: repo, orders: orders, payments: payments, now: time.Now}
+}
+
+// RefundOrder lets the seller refund their order. See refund for the amount.
+func (s *RefundService) RefundOrder(ctx context.Context, sellerID, orderID string, amount, quantity int, reason models.RefundReason, note string) (*models.Refund, error) {
+	return s.refund(ctx, sellerID, string(models.OrderRoleSeller), orderID, amount, quantity, reason, note)
+}
+
+// AdminRefundOrder refunds any order on an admin's decision. See refund for the amount.
+func (s *RefundService) AdminRefundOrder(ctx context.Context, adminID, orderID string, amount, quantity int, reason models.RefundReason, note string) (*models.Refund, error) {
+	return s.refund(ctx, adminID, models.ActorRoleAdmin, orderID, amount, quantity, reason, note)
+}
+
+// applyRefund builds a pending refund of amount, at most the order's unrefunded total, and
+// takes it out of the locked order's effective fee, payout and refunded totals. Every refund
+// of an order, whether made directly or by resolving a dispute, goes through here.
+func applyRefund(o *models.Order, amount, quantity int, reason models.RefundReason, note, actorID, actorRole string, at time.Time) *models.Refund {
+	// The fee shrinks with what is left of the order; rounding down leaves the seller
+	// whole-yen deltas that still sum to the refund
+	feeDelta := o.PlatformFee * amount / (o.TotalPrice - o.RefundedAmount)
+	payoutDelta := amount - feeDelta
+
+	o.PlatformFee -= feeDelta
+	o.NetPayout -= payoutDelta
+	o.RefundedAmount += amount
+	o.RefundedQuantity += quantity
+	return &models.Refund{
+		ID:               "rfd_" + ulid.Make().String(),
+		OrderID:          o.ID,
+		Amount:           amount,
+		Quantity:         quantity,
+		PlatformFeeDelta: feeDelta,
+		NetPayoutDelta:   payoutDelta,
+		Reason:           reason,
+		Note:             note,
+		InitiatedBy:      actorID,
+		InitiatorRole:    actorRole,
+		Status:           models.RefundStatusPending,
+		PaymentIntentID:  o.PaymentIntentID,
+		CreatedAt:        at,
+	}
+}
+
+// refund returns amount to the buyer, or the item price times quantity when amount is 0.
+// Quantity itemizes the refund and is capped by the items ordered; the total of all refunds
+// is capped by the order total.
+//
+// The refund is committed as pending together with the order's new totals, and only then
+// sent to the payment provider, so no money moves while the order is locked. If the provider
+// fails the pending refund is still returned; RetryPendingRefunds completes it later.
+func (s *RefundService) refund(ctx context.Context, actorID, actorRole, orderID string, amount, quantity int, reason models.RefundReason, note string) (*models.Refund, error) {
+	switch reason {
+	case models.RefundReasonDamaged, models.RefundReasonNotAsDescribed, models.RefundReasonMissingItem,
+		models.RefundReasonGoodwill, models.RefundReasonOther:
+	default:
+		return nil, ErrInvalidRefundReason
+	}
+	if amount < 0 || quantity < 0 || (amount == 0 && quantity == 0) {
+		return nil, ErrRefundAmountRequired
+	}
+	note = strings.TrimSpace(note)
+	if utf8.RuneCountInString(note) > MaxRefundNote {
+		return nil, ErrRefundNoteLong
+	}
+
+	var created *models.Refund
+	err := s.repo.RefundOrder(ctx, orderID, func(o *models.Order) (*models.Refund, *models.LedgerTransaction, error) {
+		if actorRole == string(models.OrderRoleSeller) {
+			switch orderRole(o, actorID) {
+			case models.OrderRoleSeller:
+			case models.OrderRoleBuyer:
+				return nil, nil, ErrOrderActionForbidden
+			default:
+				return nil, nil, ErrUnauthorized
+			}
+		}
+		switch o.Status {
+		case models.OrderStatusPaid, models.OrderStatusShipped, models.OrderStatusDelivered,
+			models.OrderStatusDisputed, models.OrderStatusCompleted:
+		default:
+			return nil, nil, ErrOrderNotRefundable
+		}
+
+		refundAmount := amount
+		if quantity > 0 {
+			if o.RefundedQuantity+quantity > o.Quantity {
+				return nil, nil, ErrRefundQuantityExceeded
+			}
+			if refundAmount == 0 {
+				refundAmount = o.ListingPrice * quantity
+			}
+		}
+		if refundAmount > o.TotalPrice-o.RefundedAmount {
+			return nil, nil, ErrRefundExceedsTotal
+		}
+
+		now := s.now()
+		rf := applyRefund(o, refundAmount, quantity, reason, note, actorID, actorRole, now)
+		created = rf
+		return rf, orderRefundedLedger(o, rf, now), nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	if err := settleRefund(ctx, s.payments, s.repo, created, s.now()); err != nil {
+		log.Printf("settle refund %s: %v", created.ID, err)
+	}
+	return created, nil
+}
+
+// RetryPendingRefunds sends refunds the payment provider has not confirmed yet, for example
+// because it was down or the server stopped right after recording them. It returns how many
+// were completed.
+func (s *RefundService) RetryPendingRefunds(ctx context.Context) (int, error) {
+	pending, err := s.repo.GetPendingRefunds(ctx, s.now().Add(-refundRetryDelay), refundRetryBatchSize)
+	if err != nil {
+		return 0, err
+	}
+
+	completed := 0
+	for _, rf := range pending {
+		if ctx.Err() != nil {
+			return completed, nil
+		}
+		if err := settleRefund(ctx, s.payments, s.repo, rf, s.now()); err != nil {
+			log.Printf("retry refund %s: %v", rf.ID, err)
+			continue
+		}
+		completed++
+	}
+	return completed, nil
+}
+
+// RunRefundRetryLoop calls RetryPendingRefunds every interval until ctx is cancelled.
+func (s *RefundService) RunRefundRetryLoop(ctx context.Context, interval time.Duration) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			if _, err := s.RetryPendingRefunds(ctx); err != nil {
+				log.Printf("retry pending refunds: %v", err)
+			}
+		}
+	}
+}
+
+// GetRefunds returns the order's refunds to its buyer or seller, oldest first.
+func (s *RefundService) GetRefunds(ctx context.Context, userID, orderID string) ([]*models.Refund, error) {
+	o, err := s.orders.GetOrder(ctx, userID, orderID)
+	if err != nil {
+		return nil, err
+	}
+	return s.repo.GetRefunds(ctx, o.ID)
+}
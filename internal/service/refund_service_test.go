@@ -0,0 +1,300 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"uttc-hackathon-backend/internal/models"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+type MockRefundRepository struct {
+	mock.Mock
+}
+
+func (m *MockRefundRepository) RefundOrder(ctx context.Context, orderID string, fn func(*models.Order) (*models.Refund, *models.LedgerTransaction, error)) error {
+	args := m.Called(ctx, orderID, fn)
+	return args.Error(0)
+}
+
+func (m *MockRefundRepository) GetRefunds(ctx context.Context, orderID string) ([]*models.Refund, error) {
+	args := m.Called(ctx, orderID)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]*models.Refund), args.Error(1)
+}
+
+func (m *MockRefundRepository) GetPendingRefunds(ctx context.Context, createdBefore time.Time, limit int) ([]*models.Refund, error) {
+	args := m.Called(ctx, createdBefore, limit)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]*models.Refund), args.Error(1)
+}
+
+func (m *MockRefundRepository) CompleteRefund(ctx context.Context, refundID string, at time.Time) error {
+	args := m.Called(ctx, refundID, at)
+	return args.Error(0)
+}
+
+var refundTestNow = time.Date(2024, 10, 1, 12, 0, 0, 0, time.UTC)
+
+func TestRefundService_RefundOrder(t *testing.T) {
+	// 3 items at 1000 yen with 500 yen shipping; 10% fee on 3500
+	paid := func() *models.Order {
+		return &models.Order{
+			ID: "ord_1", BuyerID: "buyer1", SellerID: "seller1", Status: models.OrderStatusShipped,
+			ListingPrice: 1000, Quantity: 3, TotalPrice: 3500, PlatformFee: 350, NetPayout: 3150,
+			PaymentIntentID: "pi_1",
+		}
+	}
+
+	tests := []struct {
+		name          string
+		userID        string
+		amount        int
+		quantity      int
+		reason        models.RefundReason
+		order         func() *models.Order
+		providerErr   error
+		wantAmount    int
+		wantFeeDelta  int
+		wantAccount   models.LedgerAccount
+		wantNoLedger  bool
+		wantFee       int
+		wantNetPayout int
+		wantErr       error
+	}{
+		{
+			name:          "Partial Amount Split Proportionally",
+			userID:        "seller1",
+			amount:        700,
+			reason:        models.RefundReasonDamaged,
+			order:         paid,
+			wantAmount:    700,
+			wantFeeDelta:  70,
+			wantAccount:   models.LedgerAccountSellerPending,
+			wantFee:       280,
+			wantNetPayout: 2520,
+		},
+		{
+			name:          "Quantity Defaults To Item Price",
+			userID:        "seller1",
+			quantity:      2,
+			reason:        models.RefundReasonMissingItem,
+			order:         paid,
+			wantAmount:    2000,
+			wantFeeDelta:  200,
+			wantAccount:   models.LedgerAccountSellerPending,
+			wantFee:       150,
+			wantNetPayout: 1350,
+		},
+		{
+			name:   "Rest Of A Partly Refunded Order",
+			userID: "seller1",
+			amount: 2800,
+			reason: models.RefundReasonGoodwill,
+			order: func() *models.Order {
+				o := paid()
+				o.Status = models.OrderStatusCompleted
+				o.PlatformFee, o.NetPayout, o.RefundedAmount = 280, 2520, 700
+				return o
+			},
+			wantAmount:    2800,
+			wantFeeDelta:  280,
+			wantAccount:   models.LedgerAccountSellerAvailable,
+			wantFee:       0,
+			wantNetPayout: 0,
+		},
+		{
+			name:          "Fee Only Refund Posts No Ledger",
+			userID:        "seller1",
+			amount:        1,
+			reason:        models.RefundReasonOther,
+			order:         func() *models.Order { o := paid(); o.NetPayout, o.PlatformFee = 0, 3500; return o },
+			wantAmount:    1,
+			wantFeeDelta:  1,
+			wantNoLedger:  true,
+			wantFee:       3499,
+			wantNetPayout: 0,
+		},
+		{
+			name:    "Exceeds Order Total",
+			userID:  "seller1",
+			amount:  3000,
+			reason:  models.RefundReasonDamaged,
+			order:   func() *models.Order { o := paid(); o.RefundedAmount = 700; return o },
+			wantErr: ErrRefundExceedsTotal,
+		},
+		{
+			name:     "Exceeds Ordered Quantity",
+			userID:   "seller1",
+			quantity: 2,
+			reason:   models.RefundReasonMissingItem,
+			order:    func() *models.Order { o := paid(); o.RefundedQuantity = 2; return o },
+			wantErr:  ErrRefundQuantityExceeded,
+		},
+		{
+			name:    "Buyer Cannot Refund",
+			userID:  "buyer1",
+			amount:  100,
+			reason:  models.RefundReasonDamaged,
+			order:   paid,
+			wantErr: ErrOrderActionForbidden,
+		},
+		{
+			name:    "Stranger Cannot Refund",
+			userID:  "other",
+			amount:  100,
+			reason:  models.RefundReasonDamaged,
+			order:   paid,
+			wantErr: ErrUnauthorized,
+		},
+		{
+			name:    "Cancelled Order",
+			userID:  "seller1",
+			amount:  100,
+			reason:  models.RefundReasonDamaged,
+			order:   func() *models.Order { o := paid(); o.Status = models.OrderStatusCancelled; return o },
+			wantErr: ErrOrderNotRefundable,
+		},
+		{
+			name:          "Provider Failure Leaves Refund Pending",
+			userID:        "seller1",
+			amount:        100,
+			reason:        models.RefundReasonDamaged,
+			order:         paid,
+			providerErr:   errors.New("provider down"),
+			wantAmount:    100,
+			wantFeeDelta:  10,
+			wantAccount:   models.LedgerAccountSellerPending,
+			wantFee:       340,
+			wantNetPayout: 3060,
+		},
+		{
+			name:    "Invalid Reason",
+			userID:  "seller1",
+			amount:  100,
+			reason:  "because",
+			wantErr: ErrInvalidRefundReason,
+		},
+		{
+			name:    "Nothing To Refund",
+			userID:  "seller1",
+			reason:  models.RefundReasonDamaged,
+			wantErr: ErrRefundAmountRequired,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			repo := new(MockRefundRepository)
+			payments := new(MockPaymentProvider)
+			s := NewRefundService(repo, nil, payments)
+			s.now = func() time.Time { return refundTestNow }
+
+			var committed bool
+			payments.On("Refund", mock.Anything, "pi_1", mock.Anything, mock.Anything).Return(tt.providerErr).Run(func(args mock.Arguments) {
+				// No money moves while the order is locked
+				assert.True(t, committed)
+			}).Maybe()
+			repo.On("CompleteRefund", mock.Anything, mock.Anything, refundTestNow).Return(nil).Maybe()
+
+			var order *models.Order
+			var lt *models.LedgerTransaction
+			repo.On("RefundOrder", mock.Anything, "ord_1", mock.Anything).Return(tt.wantErr).Run(func(args mock.Arguments) {
+				fn := args.Get(2).(func(*models.Order) (*models.Refund, *models.LedgerTransaction, error))
+				order = tt.order()
+				var err error
+				_, lt, err = fn(order)
+				assert.Equal(t, tt.wantErr, err)
+				committed = err == nil
+			}).Maybe()
+
+			rf, err := s.RefundOrder(context.Background(), tt.userID, "ord_1", tt.amount, tt.quantity, tt.reason, "")
+			if tt.wantErr != nil {
+				assert.ErrorIs(t, err, tt.wantErr)
+				return
+			}
+			assert.NoError(t, err)
+			assert.Equal(t, tt.wantAmount, rf.Amount)
+			assert.Equal(t, tt.wantFeeDelta, rf.PlatformFeeDelta)
+			assert.Equal(t, tt.wantAmount, rf.PlatformFeeDelta+rf.NetPayoutDelta)
+			assert.Equal(t, "seller1", rf.InitiatedBy)
+			assert.Equal(t, "seller", rf.InitiatorRole)
+			assert.Equal(t, tt.wantFee, order.PlatformFee)
+			assert.Equal(t, tt.wantNetPayout, order.NetPayout)
+			payments.AssertCalled(t, "Refund", mock.Anything, "pi_1", tt.wantAmount, rf.ID)
+			if tt.providerErr != nil {
+				assert.Equal(t, models.RefundStatusPending, rf.Status)
+				repo.AssertNotCalled(t, "CompleteRefund", mock.Anything, mock.Anything, mock.Anything)
+			} else {
+				assert.Equal(t, models.RefundStatusCompleted, rf.Status)
+				repo.AssertCalled(t, "CompleteRefund", mock.Anything, rf.ID, refundTestNow)
+			}
+
+			if tt.wantNoLedger {
+				assert.Nil(t, lt)
+				return
+			}
+			assert.Equal(t, models.LedgerTxOrderRefunded, lt.Type)
+			assert.Equal(t, rf.ID, lt.RefundID)
+			assert.Equal(t, tt.wantAccount, lt.Entries[0].Account)
+			assert.Equal(t, -rf.NetPayoutDelta, lt.Entries[0].Amount)
+		})
+	}
+}
+
+func TestRefundService_AdminRefundOrder(t *testing.T) {
+	repo := new(MockRefundRepository)
+	payments := new(MockPaymentProvider)
+	s := NewRefundService(repo, nil, payments)
+	s.now = func() time.Time { return refundTestNow }
+
+	o := &models.Order{ID: "ord_1", BuyerID: "buyer1", SellerID: "seller1", Status: models.OrderStatusDisputed,
+		ListingPrice: 1000, Quantity: 1, TotalPrice: 1000, PlatformFee: 100, NetPayout: 900, PaymentIntentID: "pi_1"}
+	payments.On("Refund", mock.Anything, "pi_1", 1000, mock.Anything).Return(nil)
+	repo.On("CompleteRefund", mock.Anything, mock.Anything, refundTestNow).Return(nil)
+	repo.On("RefundOrder", mock.Anything, "ord_1", mock.Anything).Return(nil).Run(func(args mock.Arguments) {
+		fn := args.Get(2).(func(*models.Order) (*models.Refund, *models.LedgerTransaction, error))
+		_, _, err := fn(o)
+		assert.NoError(t, err)
+	})
+
+	rf, err := s.AdminRefundOrder(context.Background(), "admin1", "ord_1", 0, 1, models.RefundReasonNotAsDescribed, "  refunded after review ")
+	assert.NoError(t, err)
+	assert.Equal(t, models.ActorRoleAdmin, rf.InitiatorRole)
+	assert.Equal(t, "refunded after review", rf.Note)
+	assert.Equal(t, 1000, o.RefundedAmount)
+	assert.Equal(t, 1, o.RefundedQuantity)
+	assert.Equal(t, 0, o.PlatformFee)
+	assert.Equal(t, 0, o.NetPayout)
+}
+
+func TestRefundService_RetryPendingRefunds(t *testing.T) {
+	repo := new(MockRefundRepository)
+	payments := new(MockPaymentProvider)
+	s := NewRefundService(repo, nil, payments)
+	s.now = func() time.Time { return refundTestNow }
+
+	failing := &models.Refund{ID: "rfd_1", OrderID: "ord_1", Amount: 500, Status: models.RefundStatusPending, PaymentIntentID: "pi_1"}
+	pending := &models.Refund{ID: "rfd_2", OrderID: "ord_2", Amount: 700, Status: models.RefundStatusPending, PaymentIntentID: "pi_2"}
+	repo.On("GetPendingRefunds", mock.Anything, refundTestNow.Add(-refundRetryDelay), refundRetryBatchSize).
+		Return([]*models.Refund{failing, pending}, nil)
+	payments.On("Refund", mock.Anything, "pi_1", 500, "rfd_1").Return(errors.New("provider down"))
+	payments.On("Refund", mock.Anything, "pi_2", 700, "rfd_2").Return(nil)
+	repo.On("CompleteRefund", mock.Anything, "rfd_2", refundTestNow).Return(nil)
+
+	completed, err := s.RetryPendingRefunds(context.Background())
+	assert.NoError(t, err)
+	assert.Equal(t, 1, completed)
+	assert.Equal(t, models.RefundStatusPending, failing.Status)
+	assert.Equal(t, models.RefundStatusCompleted, pending.Status)
+	repo.AssertNotCalled(t, "CompleteRefund", mock.Anything, "rfd_1", mock.Anything)
+	payments.AssertExpectations(t)
+}
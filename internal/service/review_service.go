@@ -0,0 +1,123 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"time"
+	"unicode/utf8"
+	"uttc-hackathon-backend/internal/models"
+	"uttc-hackathon-backend/internal/repository"
+
+	"github.com/oklog/ulid/v2"
+)
+
+const (
+	// ReviewWindow is how long after completion both sides may review the order. Reviews
+	// left unanswered become visible when it closes.
+	ReviewWindow        = 14 * 24 * time.Hour
+	MaxReviewComment    = 1000
+	DefaultReviewsLimit = 20
+	MaxReviewsLimit     = 100
+)
+
+var (
+	ErrInvalidReviewRating = errors.New("rating must be good, normal or bad")
+	ErrReviewCommentLong   = errors.New("comment must be 1000 characters or fewer")
+	ErrOrderNotReviewable  = errors.New("only completed orders can be reviewed")
+	ErrReviewWindowClosed  = errors.New("the review period for this order has ended")
+)
+
+type ReviewRepository interface {
+	CreateReview(ctx context.Context, orderID string, fn func(o *models.Order, existing *models.Review) (*models.Review, error)) error
+	GetReviews(ctx context.Context, revieweeID string, now time.Time, limit, offset int) ([]*models.Review, error)
+	GetRatingSummary(ctx context.Context, revieweeID string, now time.Time) (*models.RatingSummary, error)
+}
+
+// ReviewService lets buyer and seller rate each other once per completed order. Neither
+// side sees the other's review before submitting their own, so ratings cannot retaliate.
+type ReviewService struct {
+	repo ReviewRepository
+	now  func() time.Time
+}
+
+func NewReviewService(repo ReviewRepository) *ReviewService {
+	return &ReviewService{repo: repo, now: time.Now}
+}
+
+// SubmitReview rates the other side of a completed order. The review stays hidden until the
+// other side has reviewed too or the review window closes.
+func (s *ReviewService) SubmitReview(ctx context.Context, userID, orderID string, rating models.ReviewRating, comment string) (*models.Review, error) {
+	switch rating {
+	case models.ReviewRatingGood, models.ReviewRatingNormal, models.ReviewRatingBad:
+	default:
+		return nil, ErrInvalidReviewRating
+	}
+	comment = strings.TrimSpace(comment)
+	if utf8.RuneCountInString(comment) > MaxReviewComment {
+		return nil, ErrReviewCommentLong
+	}
+
+	var created *models.Review
+	err := s.repo.CreateReview(ctx, orderID, func(o *models.Order, existing *models.Review) (*models.Review, error) {
+		role := orderRole(o, userID)
+		if role == "" {
+			return nil, ErrUnauthorized
+		}
+		if o.Status != models.OrderStatusCompleted || o.CompletedAt == nil {
+			return nil, ErrOrderNotReviewable
+		}
+		now := s.now()
+		closesAt := o.CompletedAt.Add(ReviewWindow)
+		if !now.Before(closesAt) {
+			return nil, ErrReviewWindowClosed
+		}
+		if existing != nil && existing.ReviewerRole == role {
+			return nil, repository.ErrAlreadyReviewed
+		}
+
+		rv := &models.Review{
+			ID:           "rev_" + ulid.Make().String(),
+			OrderID:      o.ID,
+			ReviewerID:   userID,
+			RevieweeID:   o.SellerID,
+			ReviewerRole: role,
+			Rating:       rating,
+			Comment:      comment,
+			VisibleAt:    closesAt,
+			CreatedAt:    now,
+		}
+		if role == models.OrderRoleSeller {
+			rv.RevieweeID = o.BuyerID
+		}
+		// The second review reveals both
+		if existing != nil {
+			rv.VisibleAt = now
+		}
+		created = rv
+		return rv, nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return created, nil
+}
+
+// GetReviews returns the visible reviews the user received, newest first.
+func (s *ReviewService) GetReviews(ctx context.Context, userID string, limit, offset int) ([]*models.Review, error) {
+	if limit <= 0 {
+		limit = DefaultReviewsLimit
+	}
+	if limit > MaxReviewsLimit {
+		limit = MaxReviewsLimit
+	}
+	if offset < 0 {
+		offset = 0
+	}
+	return s.repo.GetReviews(ctx, userID, s.now(), limit, offset)
+}
+
+// GetRatingSummary counts the visible ratings the user received.
+func (s *ReviewService) GetRatingSummary(ctx context.Context, userID string) (*models.RatingSummary, error) {
+	return s.repo.GetRatingSummary(ctx, userID, s.now())
+}
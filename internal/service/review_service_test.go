@@ -0,0 +1,176 @@
+package service
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"uttc-hackathon-backend/internal/models"
+	"uttc-hackathon-backend/internal/repository"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+type MockReviewRepository struct {
+	mock.Mock
+}
+
+func (m *MockReviewRepository) CreateReview(ctx context.Context, orderID string, fn func(o *models.Order, existing *models.Review) (*models.Review, error)) error {
+	args := m.Called(ctx, orderID, fn)
+	return args.Error(0)
+}
+
+func (m *MockReviewRepository) GetReviews(ctx context.Context, revieweeID string, now time.Time, limit, offset int) ([]*models.Review, error) {
+	args := m.Called(ctx, revieweeID, now, limit, offset)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]*models.Review), args.Error(1)
+}
+
+func (m *MockReviewRepository) GetRatingSummary(ctx context.Context, revieweeID string, now time.Time) (*models.RatingSummary, error) {
+	args := m.Called(ctx, revieweeID, now)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*models.RatingSummary), args.Error(1)
+}
+
+var reviewTestNow = time.Date(2024, 9, 10, 12, 0, 0, 0, time.UTC)
+
+func newTestReviewService(repo *MockReviewRepository) *ReviewService {
+	s := NewReviewService(repo)
+	s.now = func() time.Time { return reviewTestNow }
+	return s
+}
+
+func TestReviewService_SubmitReview(t *testing.T) {
+	completedAt := reviewTestNow.Add(-3 * 24 * time.Hour)
+	completed := func() *models.Order {
+		at := completedAt
+		return &models.Order{ID: "ord_1", BuyerID: "buyer1", SellerID: "seller1", Status: models.OrderStatusCompleted, CompletedAt: &at}
+	}
+	buyerReview := &models.Review{ID: "rev_1", OrderID: "ord_1", ReviewerID: "buyer1", ReviewerRole: models.OrderRoleBuyer}
+
+	tests := []struct {
+		name          string
+		userID        string
+		rating        models.ReviewRating
+		comment       string
+		wantComment   string
+		order         func() *models.Order
+		existing      *models.Review
+		wantReviewee  string
+		wantVisibleAt time.Time
+		wantErr       error
+	}{
+		{
+			name:          "Buyer First Stays Hidden",
+			userID:        "buyer1",
+			rating:        models.ReviewRatingGood,
+			comment:       "  Fast shipping  ",
+			wantComment:   "Fast shipping",
+			order:         completed,
+			wantReviewee:  "seller1",
+			wantVisibleAt: completedAt.Add(ReviewWindow),
+		},
+		{
+			name:          "Seller Second Reveals Both",
+			userID:        "seller1",
+			rating:        models.ReviewRatingNormal,
+			order:         completed,
+			existing:      buyerReview,
+			wantReviewee:  "buyer1",
+			wantVisibleAt: reviewTestNow,
+		},
+		{
+			name:     "Already Reviewed",
+			userID:   "buyer1",
+			rating:   models.ReviewRatingBad,
+			order:    completed,
+			existing: buyerReview,
+			wantErr:  repository.ErrAlreadyReviewed,
+		},
+		{
+			name:    "Invalid Rating",
+			userID:  "buyer1",
+			rating:  "excellent",
+			order:   completed,
+			wantErr: ErrInvalidReviewRating,
+		},
+		{
+			name:    "Not Part Of Order",
+			userID:  "other",
+			rating:  models.ReviewRatingGood,
+			order:   completed,
+			wantErr: ErrUnauthorized,
+		},
+		{
+			name:   "Not Completed",
+			userID: "buyer1",
+			rating: models.ReviewRatingGood,
+			order: func() *models.Order {
+				return &models.Order{ID: "ord_1", BuyerID: "buyer1", SellerID: "seller1", Status: models.OrderStatusDelivered}
+			},
+			wantErr: ErrOrderNotReviewable,
+		},
+		{
+			name:   "Window Closed",
+			userID: "buyer1",
+			rating: models.ReviewRatingGood,
+			order: func() *models.Order {
+				o := completed()
+				at := reviewTestNow.Add(-ReviewWindow)
+				o.CompletedAt = &at
+				return o
+			},
+			wantErr: ErrReviewWindowClosed,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			repo := new(MockReviewRepository)
+			var stored *models.Review
+			repo.On("CreateReview", mock.Anything, "ord_1", mock.Anything).
+				Return(tt.wantErr).
+				Run(func(args mock.Arguments) {
+					fn := args.Get(2).(func(*models.Order, *models.Review) (*models.Review, error))
+					rv, err := fn(tt.order(), tt.existing)
+					assert.Equal(t, tt.wantErr, err)
+					stored = rv
+				}).
+				Maybe() // not reached when the request itself is invalid
+			s := newTestReviewService(repo)
+
+			got, err := s.SubmitReview(context.Background(), tt.userID, "ord_1", tt.rating, tt.comment)
+
+			if tt.wantErr != nil {
+				assert.ErrorIs(t, err, tt.wantErr)
+				assert.Nil(t, got)
+				return
+			}
+			assert.NoError(t, err)
+			assert.Equal(t, stored.ID, got.ID)
+			assert.Equal(t, tt.userID, got.ReviewerID)
+			assert.Equal(t, tt.wantReviewee, got.RevieweeID)
+			assert.Equal(t, tt.rating, got.Rating)
+			assert.Equal(t, tt.wantComment, got.Comment)
+			assert.Equal(t, tt.wantVisibleAt, got.VisibleAt)
+		})
+	}
+}
+
+func TestReviewService_GetReviews(t *testing.T) {
+	repo := new(MockReviewRepository)
+	reviews := []*models.Review{{ID: "rev_1"}}
+	repo.On("GetReviews", mock.Anything, "seller1", reviewTestNow, MaxReviewsLimit, 0).Return(reviews, nil)
+	s := newTestReviewService(repo)
+
+	got, err := s.GetReviews(context.Background(), "seller1", 500, -1)
+
+	assert.NoError(t, err)
+	assert.Equal(t, reviews, got)
+	repo.AssertExpectations(t)
+}
@@ -0,0 +1,137 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"time"
+	"uttc-hackathon-backend/internal/models"
+)
+
+const (
+	// MaxSalesReportBuckets bounds the report to a year of days, or longer in weeks and months.
+	MaxSalesReportBuckets = 366
+	TopListingsLimit      = 5
+)
+
+var (
+	ErrInvalidGranularity = errors.New("granularity must be day, week or month")
+	ErrSalesRangeTooLong  = errors.New("the date range has too many periods for this granularity")
+)
+
+// jst is the time zone sales are reported in.
+var jst = time.FixedZone("JST", 9*60*60)
+
+type SalesReportRepository interface {
+	GetSalesBuckets(ctx context.Context, sellerID string, g models.SalesGranularity, from, to time.Time) ([]models.SalesBucket, error)
+	GetTopListings(ctx context.Context, sellerID string, from, to time.Time, limit int) ([]models.ListingSales, error)
+}
+
+// SalesReportService aggregates a seller's orders into revenue over time. Orders count from
+// the day they were placed once paid, unless cancelled.
+type SalesReportService struct {
+	repo SalesReportRepository
+	now  func() time.Time
+}
+
+func NewSalesReportService(repo SalesReportRepository) *SalesReportService {
+	return &SalesReportService{repo: repo, now: time.Now}
+}
+
+// bucketStart returns the start of the JST period containing t.
+func bucketStart(t time.Time, g models.SalesGranularity) time.Time {
+	t = t.In(jst)
+	day := time.Date(t.Year(), t.Month(), t.Day(), 0, 0, 0, 0, jst)
+	switch g {
+	case models.SalesGranularityWeek:
+		// Weekday counts from Sunday; weeks start on Monday
+		return day.AddDate(0, 0, -(int(day.Weekday())+6)%7)
+	case models.SalesGranularityMonth:
+		return day.AddDate(0, 0, 1-day.Day())
+	default:
+		return day
+	}
+}
+
+func nextBucket(t time.Time, g models.SalesGranularity) time.Time {
+	switch g {
+	case models.SalesGranularityWeek:
+		return t.AddDate(0, 0, 7)
+	case models.SalesGranularityMonth:
+		return t.AddDate(0, 1, 0)
+	default:
+		return t.AddDate(0, 0, 1)
+	}
+}
+
+// GetSalesReport reports the seller's sales for orders created in [from, to), both JST
+// midnights. The granularity defaults to month. Without to the report runs through today;
+// without from it covers the last 30 days, 12 weeks or 12 months. The first and last periods
+// can be partial.
+func (s *SalesReportService) GetSalesReport(ctx context.Context, sellerID string, g models.SalesGranularity, from, to time.Time) (*models.SalesReport, error) {
+	switch g {
+	case "":
+		g = models.SalesGranularityMonth
+	case models.SalesGranularityDay, models.SalesGranularityWeek, models.SalesGranularityMonth:
+	default:
+		return nil, ErrInvalidGranularity
+	}
+	if to.IsZero() {
+		to = bucketStart(s.now(), models.SalesGranularityDay).AddDate(0, 0, 1)
+	}
+	if from.IsZero() {
+		switch g {
+		case models.SalesGranularityDay:
+			from = to.AddDate(0, 0, -30)
+		case models.SalesGranularityWeek:
+			from = bucketStart(to.AddDate(0, 0, -7*12), g)
+		default:
+			from = bucketStart(to.AddDate(0, -12, 0), g)
+		}
+	}
+	if !from.Before(to) {
+		return nil, ErrInvalidDateRange
+	}
+
+	var starts []time.Time
+	for t := bucketStart(from, g); t.Before(to); t = nextBucket(t, g) {
+		if len(starts) == MaxSalesReportBuckets {
+			return nil, ErrSalesRangeTooLong
+		}
+		starts = append(starts, t)
+	}
+
+	found, err := s.repo.GetSalesBuckets(ctx, sellerID, g, from, to)
+	if err != nil {
+		return nil, err
+	}
+	byStart := make(map[string]models.SalesTotals, len(found))
+	for _, b := range found {
+		byStart[b.Start] = b.SalesTotals
+	}
+
+	report := &models.SalesReport{
+		Granularity: g,
+		From:        from.In(jst).Format(time.DateOnly),
+		To:          to.In(jst).AddDate(0, 0, -1).Format(time.DateOnly),
+		Buckets:     make([]models.SalesBucket, 0, len(starts)),
+	}
+	for _, t := range starts {
+		b := models.SalesBucket{Start: t.Format(time.DateOnly), SalesTotals: byStart[t.Format(time.DateOnly)]}
+		report.Totals.Orders += b.Orders
+		report.Totals.Quantity += b.Quantity
+		report.Totals.GrossSales += b.GrossSales
+		report.Totals.Refunds += b.Refunds
+		report.Totals.PlatformFees += b.PlatformFees
+		report.Totals.NetPayouts += b.NetPayouts
+		report.Buckets = append(report.Buckets, b)
+	}
+
+	report.TopListings, err = s.repo.GetTopListings(ctx, sellerID, from, to, TopListingsLimit)
+	if err != nil {
+		return nil, err
+	}
+	if report.TopListings == nil {
+		report.TopListings = []models.ListingSales{}
+	}
+	return report, nil
+}
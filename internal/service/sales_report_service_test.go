@@ -0,0 +1,218 @@
+package service
+
+import (
+	"context"
+	"sort"
+	"testing"
+	"time"
+
+	"uttc-hackathon-backend/internal/models"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// fakeSale is an order with the JST period starts SalesReportRepo would bucket it into,
+// written out by hand so that the test does not share bucketStart with the service.
+type fakeSale struct {
+	*models.Order
+	starts map[models.SalesGranularity]string
+}
+
+// fakeSalesReportRepo aggregates orders in memory the way SalesReportRepo does in SQL.
+type fakeSalesReportRepo struct {
+	orders []fakeSale
+}
+
+func (f *fakeSalesReportRepo) sales(sellerID string, from, to time.Time) []fakeSale {
+	var sales []fakeSale
+	for _, o := range f.orders {
+		if o.SellerID != sellerID || o.CreatedAt.Before(from) || !o.CreatedAt.Before(to) {
+			continue
+		}
+		if o.Status == models.OrderStatusPendingPayment || o.Status == models.OrderStatusCancelled {
+			continue
+		}
+		sales = append(sales, o)
+	}
+	return sales
+}
+
+func addSale(t *models.SalesTotals, o *models.Order) {
+	t.Orders++
+	t.Quantity += o.Quantity
+	t.GrossSales += o.TotalPrice
+	t.Refunds += o.RefundedAmount
+	t.PlatformFees += o.PlatformFee
+	t.NetPayouts += o.NetPayout
+}
+
+func (f *fakeSalesReportRepo) GetSalesBuckets(ctx context.Context, sellerID string, g models.SalesGranularity, from, to time.Time) ([]models.SalesBucket, error) {
+	byStart := map[string]*models.SalesBucket{}
+	for _, o := range f.sales(sellerID, from, to) {
+		start := o.starts[g]
+		if byStart[start] == nil {
+			byStart[start] = &models.SalesBucket{Start: start}
+		}
+		addSale(&byStart[start].SalesTotals, o.Order)
+	}
+	var buckets []models.SalesBucket
+	for _, b := range byStart {
+		buckets = append(buckets, *b)
+	}
+	sort.Slice(buckets, func(i, j int) bool { return buckets[i].Start < buckets[j].Start })
+	return buckets, nil
+}
+
+func (f *fakeSalesReportRepo) GetTopListings(ctx context.Context, sellerID string, from, to time.Time, limit int) ([]models.ListingSales, error) {
+	byListing := map[string]*models.ListingSales{}
+	for _, o := range f.sales(sellerID, from, to) {
+		if byListing[o.ListingID] == nil {
+			byListing[o.ListingID] = &models.ListingSales{ListingID: o.ListingID, ListingTitle: o.ListingTitle}
+		}
+		addSale(&byListing[o.ListingID].SalesTotals, o.Order)
+	}
+	var listings []models.ListingSales
+	for _, l := range byListing {
+		listings = append(listings, *l)
+	}
+	sort.Slice(listings, func(i, j int) bool {
+		a, b := listings[i], listings[j]
+		if a.GrossSales-a.Refunds != b.GrossSales-b.Refunds {
+			return a.GrossSales-a.Refunds > b.GrossSales-b.Refunds
+		}
+		return a.ListingID < b.ListingID
+	})
+	if len(listings) > limit {
+		listings = listings[:limit]
+	}
+	return listings, nil
+}
+
+// salesTestNow is Wednesday 2024-07-10 in JST.
+var salesTestNow = time.Date(2024, 7, 10, 3, 0, 0, 0, time.UTC)
+
+// sale returns a seller1 order created at createdAt that falls on the given JST day, week and month.
+func sale(listingID string, createdAt time.Time, total int, status models.OrderStatus, day, week, month string) fakeSale {
+	return fakeSale{
+		Order: &models.Order{
+			ID: "ord_" + listingID + createdAt.Format("0102150405"), SellerID: "seller1", ListingID: listingID, ListingTitle: "Item " + listingID,
+			Quantity: 1, TotalPrice: total, PlatformFee: total / 10, NetPayout: total - total/10, Status: status, CreatedAt: createdAt,
+		},
+		starts: map[models.SalesGranularity]string{
+			models.SalesGranularityDay:   day,
+			models.SalesGranularityWeek:  week,
+			models.SalesGranularityMonth: month,
+		},
+	}
+}
+
+func newTestSalesReportService() *SalesReportService {
+	// Thursday June 20 in Japan
+	refunded := sale("lst_b", time.Date(2024, 6, 20, 1, 0, 0, 0, time.UTC), 3000, models.OrderStatusCompleted, "2024-06-20", "2024-06-17", "2024-06-01")
+	refunded.RefundedAmount, refunded.PlatformFee, refunded.NetPayout = 1000, 200, 1800
+	other := sale("lst_x", time.Date(2024, 7, 1, 0, 0, 0, 0, time.UTC), 5000, models.OrderStatusPaid, "2024-07-01", "2024-07-01", "2024-07-01")
+	other.ID, other.SellerID = "ord_other", "seller2"
+
+	repo := &fakeSalesReportRepo{orders: []fakeSale{
+		// 00:30 on Saturday June 1 in Japan, still May in UTC
+		sale("lst_a", time.Date(2024, 5, 31, 15, 30, 0, 0, time.UTC), 1000, models.OrderStatusCompleted, "2024-06-01", "2024-05-27", "2024-06-01"),
+		// 23:59 on Friday May 31 in Japan
+		sale("lst_a", time.Date(2024, 5, 31, 14, 59, 0, 0, time.UTC), 1000, models.OrderStatusShipped, "2024-05-31", "2024-05-27", "2024-05-01"),
+		refunded,
+		// Monday July 8 in Japan
+		sale("lst_c", time.Date(2024, 7, 8, 0, 0, 0, 0, time.UTC), 500, models.OrderStatusPaid, "2024-07-08", "2024-07-08", "2024-07-01"),
+		sale("lst_c", time.Date(2024, 7, 9, 0, 0, 0, 0, time.UTC), 9000, models.OrderStatusCancelled, "2024-07-09", "2024-07-08", "2024-07-01"),
+		sale("lst_c", time.Date(2024, 7, 9, 0, 0, 0, 0, time.UTC), 9000, models.OrderStatusPendingPayment, "2024-07-09", "2024-07-08", "2024-07-01"),
+		other,
+	}}
+	s := NewSalesReportService(repo)
+	s.now = func() time.Time { return salesTestNow }
+	return s
+}
+
+func jstDate(y int, m time.Month, d int) time.Time {
+	return time.Date(y, m, d, 0, 0, 0, 0, jst)
+}
+
+func TestSalesReportService_GetSalesReport_Monthly(t *testing.T) {
+	s := newTestSalesReportService()
+
+	report, err := s.GetSalesReport(context.Background(), "seller1", models.SalesGranularityMonth, jstDate(2024, 5, 1), jstDate(2024, 8, 1))
+	require.NoError(t, err)
+
+	assert.Equal(t, "2024-05-01", report.From)
+	assert.Equal(t, "2024-07-31", report.To)
+	require.Len(t, report.Buckets, 3)
+	assert.Equal(t, "2024-05-01", report.Buckets[0].Start)
+	assert.Equal(t, 1, report.Buckets[0].Orders)
+	assert.Equal(t, "2024-06-01", report.Buckets[1].Start)
+	assert.Equal(t, models.SalesTotals{Orders: 2, Quantity: 2, GrossSales: 4000, Refunds: 1000, PlatformFees: 300, NetPayouts: 2700}, report.Buckets[1].SalesTotals)
+	assert.Equal(t, "2024-07-01", report.Buckets[2].Start)
+	assert.Equal(t, 500, report.Buckets[2].GrossSales)
+
+	assert.Equal(t, models.SalesTotals{Orders: 4, Quantity: 4, GrossSales: 5500, Refunds: 1000, PlatformFees: 450, NetPayouts: 4050}, report.Totals)
+	assert.Equal(t, report.Totals.GrossSales-report.Totals.Refunds, report.Totals.PlatformFees+report.Totals.NetPayouts)
+
+	require.Len(t, report.TopListings, 3)
+	assert.Equal(t, []string{"lst_a", "lst_b", "lst_c"}, []string{report.TopListings[0].ListingID, report.TopListings[1].ListingID, report.TopListings[2].ListingID})
+	assert.Equal(t, 2, report.TopListings[0].Orders)
+}
+
+func TestSalesReportService_GetSalesReport_WeeklyFillsEmptyWeeks(t *testing.T) {
+	s := newTestSalesReportService()
+
+	// Wednesday 2024-06-19 through Wednesday 2024-07-10
+	report, err := s.GetSalesReport(context.Background(), "seller1", models.SalesGranularityWeek, jstDate(2024, 6, 19), jstDate(2024, 7, 11))
+	require.NoError(t, err)
+
+	var starts []string
+	for _, b := range report.Buckets {
+		starts = append(starts, b.Start)
+	}
+	assert.Equal(t, []string{"2024-06-17", "2024-06-24", "2024-07-01", "2024-07-08"}, starts)
+	assert.Equal(t, 3000, report.Buckets[0].GrossSales)
+	assert.Equal(t, models.SalesTotals{}, report.Buckets[1].SalesTotals)
+	assert.Equal(t, models.SalesTotals{}, report.Buckets[2].SalesTotals)
+	assert.Equal(t, 500, report.Buckets[3].GrossSales)
+}
+
+func TestSalesReportService_GetSalesReport_Defaults(t *testing.T) {
+	s := newTestSalesReportService()
+
+	report, err := s.GetSalesReport(context.Background(), "seller1", "", time.Time{}, time.Time{})
+	require.NoError(t, err)
+	assert.Equal(t, models.SalesGranularityMonth, report.Granularity)
+	assert.Equal(t, "2023-07-01", report.From)
+	assert.Equal(t, "2024-07-10", report.To)
+	assert.Len(t, report.Buckets, 13)
+
+	report, err = s.GetSalesReport(context.Background(), "seller1", models.SalesGranularityDay, time.Time{}, time.Time{})
+	require.NoError(t, err)
+	assert.Equal(t, "2024-06-11", report.From)
+	assert.Len(t, report.Buckets, 30)
+	assert.Equal(t, "2024-07-10", report.Buckets[29].Start)
+	assert.Equal(t, "2024-07-08", report.Buckets[27].Start)
+	assert.Equal(t, 500, report.Buckets[27].GrossSales)
+}
+
+func TestSalesReportService_GetSalesReport_Invalid(t *testing.T) {
+	s := newTestSalesReportService()
+
+	tests := []struct {
+		name     string
+		g        models.SalesGranularity
+		from, to time.Time
+		wantErr  error
+	}{
+		{name: "Unknown Granularity", g: "year", wantErr: ErrInvalidGranularity},
+		{name: "Reversed Range", g: models.SalesGranularityDay, from: jstDate(2024, 7, 2), to: jstDate(2024, 7, 1), wantErr: ErrInvalidDateRange},
+		{name: "Too Many Days", g: models.SalesGranularityDay, from: jstDate(2023, 1, 1), to: jstDate(2024, 7, 1), wantErr: ErrSalesRangeTooLong},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := s.GetSalesReport(context.Background(), "seller1", tt.g, tt.from, tt.to)
+			assert.ErrorIs(t, err, tt.wantErr)
+		})
+	}
+}
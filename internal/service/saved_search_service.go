@@ -0,0 +1,208 @@
+package service
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"log"
+	"time"
+	"unicode/utf8"
+	"uttc-hackathon-backend/internal/models"
+
+	"github.com/oklog/ulid/v2"
+)
+
+const (
+	MaxSavedSearches     = 20
+	MaxSavedSearchQuery  = 100
+	SavedSearchDigestGap = time.Hour // at most one digest per user per hour
+	// digestBatchSize bounds how many users one FlushDigests pass handles.
+	digestBatchSize = 500
+)
+
+var (
+	ErrSavedSearchQueryRequired = errors.New("search query is required")
+	ErrSavedSearchQueryLong     = errors.New("search query must be 100 characters or fewer")
+	ErrSavedSearchPriceRange    = errors.New("min_price must not exceed max_price")
+	ErrInvalidItemCondition     = errors.New("invalid item condition")
+	ErrTooManySavedSearches     = errors.New("saved search limit reached")
+)
+
+type SavedSearchService struct {
+	repo        SavedSearchRepository
+	listingRepo SavedSearchListingRepo
+	now         func() time.Time
+}
+
+type SavedSearchRepository interface {
+	CreateSavedSearch(ctx context.Context, ss *models.SavedSearch) error
+	CountSavedSearches(ctx context.Context, userID string) (int, error)
+	GetSavedSearches(ctx context.Context, userID string) ([]*models.SavedSearch, error)
+	DeleteSavedSearch(ctx context.Context, id, userID string) error
+	GetSavedSearchCandidates(ctx context.Context, terms []string) ([]*models.SavedSearch, error)
+	RecordMatches(ctx context.Context, matches []models.SavedSearchMatch) error
+	GetUsersWithPendingMatches(ctx context.Context, limit int) ([]string, error)
+	CreateDigest(ctx context.Context, userID string, notBefore time.Time, fn func([]models.SavedSearchMatch) (*models.Notification, error)) (*models.Notification, error)
+}
+
+type SavedSearchListingRepo interface {
+	GetListing(ctx context.Context, id string) (*models.Listing, error)
+}
+
+func NewSavedSearchService(repo SavedSearchRepository, listingRepo SavedSearchListingRepo) *SavedSearchService {
+	return &SavedSearchService{repo: repo, listingRepo: listingRepo, now: time.Now}
+}
+
+func (s *SavedSearchService) CreateSavedSearch(ctx context.Context, userID string, req *models.SavedSearch) (*models.SavedSearch, error) {
+	if utf8.RuneCountInString(req.Query) > MaxSavedSearchQuery {
+		return nil, ErrSavedSearchQueryLong
+	}
+	terms := searchTerms(req.Query)
+	if len(terms) == 0 {
+		return nil, ErrSavedSearchQueryRequired
+	}
+	if req.MinPrice < 0 || req.MaxPrice < 0 || (req.MaxPrice > 0 && req.MinPrice > req.MaxPrice) {
+		return nil, ErrSavedSearchPriceRange
+	}
+	switch req.Condition {
+	case "", models.ItemConditionNew, models.ItemConditionExcellent, models.ItemConditionGood,
+		models.ItemConditionNotGood, models.ItemConditionBad:
+	default:
+		return nil, ErrInvalidItemCondition
+	}
+
+	n, err := s.repo.CountSavedSearches(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+	if n >= MaxSavedSearches {
+		return nil, ErrTooManySavedSearches
+	}
+
+	ss := &models.SavedSearch{
+		ID:        "srh_" + ulid.Make().String(),
+		UserID:    userID,
+		Query:     req.Query,
+		Terms:     terms,
+		MinPrice:  req.MinPrice,
+		MaxPrice:  req.MaxPrice,
+		Condition: req.Condition,
+		CreatedAt: s.now(),
+	}
+	if err := s.repo.CreateSavedSearch(ctx, ss); err != nil {
+		return nil, err
+	}
+	return ss, nil
+}
+
+func (s *SavedSearchService) GetSavedSearches(ctx context.Context, userID string) ([]*models.SavedSearch, error) {
+	return s.repo.GetSavedSearches(ctx, userID)
+}
+
+func (s *SavedSearchService) DeleteSavedSearch(ctx context.Context, userID, id string) error {
+	return s.repo.DeleteSavedSearch(ctx, id, userID)
+}
+
+// MatchListing records a match for every saved search the listing satisfies and sends digests
+// to users who have not had one recently. Call it after a listing becomes active.
+func (s *SavedSearchService) MatchListing(ctx context.Context, listingID string) error {
+	l, err := s.listingRepo.GetListing(ctx, listingID)
+	if err != nil {
+		return err
+	}
+	if l == nil || l.Status != models.ListingStatusActive {
+		return nil
+	}
+
+	candidates, err := s.repo.GetSavedSearchCandidates(ctx, listingTerms(l.Title, l.Description))
+	if err != nil {
+		return err
+	}
+
+	var matches []models.SavedSearchMatch
+	var users []string
+	seenUser := make(map[string]bool)
+	for _, ss := range candidates {
+		if ss.UserID == l.SellerID || !matchesFilters(ss, l) {
+			continue
+		}
+		matches = append(matches, models.SavedSearchMatch{SavedSearchID: ss.ID, ListingID: l.ID, UserID: ss.UserID})
+		if !seenUser[ss.UserID] {
+			seenUser[ss.UserID] = true
+			users = append(users, ss.UserID)
+		}
+	}
+	if len(matches) == 0 {
+		return nil
+	}
+
+	if err := s.repo.RecordMatches(ctx, matches); err != nil {
+		return err
+	}
+	for _, userID := range users {
+		if _, err := s.sendDigest(ctx, userID); err != nil {
+			log.Printf("send saved search digest to %s: %v", userID, err)
+		}
+	}
+	return nil
+}
+
+// FlushDigests sends digests for matches held back by the throttle. Run it periodically.
+func (s *SavedSearchService) FlushDigests(ctx context.Context) error {
+	users, err := s.repo.GetUsersWithPendingMatches(ctx, digestBatchSize)
+	if err != nil {
+		return err
+	}
+	for _, userID := range users {
+		if _, err := s.sendDigest(ctx, userID); err != nil {
+			log.Printf("send saved search digest to %s: %v", userID, err)
+		}
+	}
+	return nil
+}
+
+// RunDigestLoop calls FlushDigests every interval until ctx is cancelled.
+func (s *SavedSearchService) RunDigestLoop(ctx context.Context, interval time.Duration) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			if err := s.FlushDigests(ctx); err != nil {
+				log.Printf("flush saved search digests: %v", err)
+			}
+		}
+	}
+}
+
+func (s *SavedSearchService) sendDigest(ctx context.Context, userID string) (*models.Notification, error) {
+	now := s.now()
+	return s.repo.CreateDigest(ctx, userID, now.Add(-SavedSearchDigestGap), func(matches []models.SavedSearchMatch) (*models.Notification, error) {
+		payload, err := json.Marshal(models.SavedSearchDigest{Count: len(matches), Matches: matches})
+		if err != nil {
+			return nil, err
+		}
+		return &models.Notification{
+			ID:        "ntf_" + ulid.Make().String(),
+			UserID:    userID,
+			Type:      models.NotificationTypeSavedSearchDigest,
+			Payload:   payload,
+			CreatedAt: now,
+		}, nil
+	})
+}
+
+func matchesFilters(ss *models.SavedSearch, l *models.Listing) bool {
+	if ss.MinPrice > 0 && l.Price < ss.MinPrice {
+		return false
+	}
+	if ss.MaxPrice > 0 && l.Price > ss.MaxPrice {
+		return false
+	}
+	if ss.Condition != "" && l.ItemCondition != ss.Condition {
+		return false
+	}
+	return true
+}
@@ -0,0 +1,281 @@
+package service
+
+import (
+	"context"
+	"encoding/json"
+	"testing"
+	"time"
+
+	"uttc-hackathon-backend/internal/models"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+type MockSavedSearchRepository struct {
+	mock.Mock
+}
+
+func (m *MockSavedSearchRepository) CreateSavedSearch(ctx context.Context, ss *models.SavedSearch) error {
+	args := m.Called(ctx, ss)
+	return args.Error(0)
+}
+
+func (m *MockSavedSearchRepository) CountSavedSearches(ctx context.Context, userID string) (int, error) {
+	args := m.Called(ctx, userID)
+	return args.Int(0), args.Error(1)
+}
+
+func (m *MockSavedSearchRepository) GetSavedSearches(ctx context.Context, userID string) ([]*models.SavedSearch, error) {
+	args := m.Called(ctx, userID)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]*models.SavedSearch), args.Error(1)
+}
+
+func (m *MockSavedSearchRepository) DeleteSavedSearch(ctx context.Context, id, userID string) error {
+	args := m.Called(ctx, id, userID)
+	return args.Error(0)
+}
+
+func (m *MockSavedSearchRepository) GetSavedSearchCandidates(ctx context.Context, terms []string) ([]*models.SavedSearch, error) {
+	args := m.Called(ctx, terms)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]*models.SavedSearch), args.Error(1)
+}
+
+func (m *MockSavedSearchRepository) RecordMatches(ctx context.Context, matches []models.SavedSearchMatch) error {
+	args := m.Called(ctx, matches)
+	return args.Error(0)
+}
+
+func (m *MockSavedSearchRepository) GetUsersWithPendingMatches(ctx context.Context, limit int) ([]string, error) {
+	args := m.Called(ctx, limit)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]string), args.Error(1)
+}
+
+func (m *MockSavedSearchRepository) CreateDigest(ctx context.Context, userID string, notBefore time.Time, fn func([]models.SavedSearchMatch) (*models.Notification, error)) (*models.Notification, error) {
+	args := m.Called(ctx, userID, notBefore, fn)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*models.Notification), args.Error(1)
+}
+
+type MockSavedSearchListingRepo struct {
+	mock.Mock
+}
+
+func (m *MockSavedSearchListingRepo) GetListing(ctx context.Context, id string) (*models.Listing, error) {
+	args := m.Called(ctx, id)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*models.Listing), args.Error(1)
+}
+
+func TestSearchTerms(t *testing.T) {
+	tests := []struct {
+		name  string
+		query string
+		want  []string
+	}{
+		{name: "Latin Words", query: "iPhone 15  Pro", want: []string{"iphone", "15", "pro"}},
+		{name: "Full Width", query: "ｉＰｈｏｎｅ１５", want: []string{"iphone15"}},
+		{name: "Katakana Bigrams", query: "スニーカー", want: []string{"スニ", "ニー", "ーカ", "カー"}},
+		{name: "Single Kanji", query: "靴", want: []string{"靴"}},
+		{name: "Mixed Script", query: "Nikeのスニーカー", want: []string{"nike", "のス", "スニ", "ニー", "ーカ", "カー"}},
+		{name: "Duplicates", query: "red red", want: []string{"red"}},
+		{name: "Only Punctuation", query: "!!! ...", want: nil},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.want, searchTerms(tt.query))
+		})
+	}
+}
+
+func TestListingTerms_ContainQueryTerms(t *testing.T) {
+	listing := listingTerms("NIKE エアマックス 靴", "サイズ27cm。箱付き")
+	for _, q := range []string{"nike", "エアマックス", "靴", "27cm", "箱"} {
+		for _, term := range searchTerms(q) {
+			assert.Contains(t, listing, term, "query %q", q)
+		}
+	}
+}
+
+func TestSavedSearchService_CreateSavedSearch(t *testing.T) {
+	tests := []struct {
+		name      string
+		req       *models.SavedSearch
+		mockSetup func(*MockSavedSearchRepository)
+		wantErr   error
+	}{
+		{
+			name: "Success",
+			req:  &models.SavedSearch{Query: "Nintendo Switch", MinPrice: 10000, MaxPrice: 30000, Condition: models.ItemConditionGood},
+			mockSetup: func(m *MockSavedSearchRepository) {
+				m.On("CountSavedSearches", mock.Anything, "u1").Return(3, nil)
+				m.On("CreateSavedSearch", mock.Anything, mock.MatchedBy(func(ss *models.SavedSearch) bool {
+					return ss.UserID == "u1" && len(ss.ID) == 30 && assert.ObjectsAreEqual([]string{"nintendo", "switch"}, ss.Terms)
+				})).Return(nil)
+			},
+		},
+		{
+			name:      "Empty Query",
+			req:       &models.SavedSearch{Query: "  !! "},
+			mockSetup: func(m *MockSavedSearchRepository) {},
+			wantErr:   ErrSavedSearchQueryRequired,
+		},
+		{
+			name:      "Query Too Long",
+			req:       &models.SavedSearch{Query: string(make([]rune, MaxSavedSearchQuery+1))},
+			mockSetup: func(m *MockSavedSearchRepository) {},
+			wantErr:   ErrSavedSearchQueryLong,
+		},
+		{
+			name:      "Inverted Price Range",
+			req:       &models.SavedSearch{Query: "switch", MinPrice: 5000, MaxPrice: 1000},
+			mockSetup: func(m *MockSavedSearchRepository) {},
+			wantErr:   ErrSavedSearchPriceRange,
+		},
+		{
+			name:      "Invalid Condition",
+			req:       &models.SavedSearch{Query: "switch", Condition: "mint"},
+			mockSetup: func(m *MockSavedSearchRepository) {},
+			wantErr:   ErrInvalidItemCondition,
+		},
+		{
+			name: "Limit Reached",
+			req:  &models.SavedSearch{Query: "switch"},
+			mockSetup: func(m *MockSavedSearchRepository) {
+				m.On("CountSavedSearches", mock.Anything, "u1").Return(MaxSavedSearches, nil)
+			},
+			wantErr: ErrTooManySavedSearches,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			repo := new(MockSavedSearchRepository)
+			tt.mockSetup(repo)
+
+			s := NewSavedSearchService(repo, new(MockSavedSearchListingRepo))
+			got, err := s.CreateSavedSearch(context.Background(), "u1", tt.req)
+
+			if tt.wantErr != nil {
+				assert.Equal(t, tt.wantErr, err)
+				assert.Nil(t, got)
+			} else {
+				assert.NoError(t, err)
+				assert.NotNil(t, got)
+			}
+			repo.AssertExpectations(t)
+		})
+	}
+}
+
+func TestSavedSearchService_MatchListing(t *testing.T) {
+	now := time.Date(2025, 6, 1, 12, 0, 0, 0, time.UTC)
+	listing := &models.Listing{
+		ID: "lst1", SellerID: "seller", Title: "Nintendo Switch 本体", Description: "good",
+		Price: 20000, ItemCondition: models.ItemConditionGood, Status: models.ListingStatusActive,
+	}
+
+	tests := []struct {
+		name        string
+		listing     *models.Listing
+		candidates  []*models.SavedSearch
+		wantMatches []models.SavedSearchMatch
+		wantDigests []string
+	}{
+		{
+			name:    "Filters And Seller Excluded",
+			listing: listing,
+			candidates: []*models.SavedSearch{
+				{ID: "s1", UserID: "a"},
+				{ID: "s2", UserID: "b", MinPrice: 10000, MaxPrice: 25000, Condition: models.ItemConditionGood},
+				{ID: "s3", UserID: "c", MaxPrice: 15000},
+				{ID: "s4", UserID: "d", Condition: models.ItemConditionNew},
+				{ID: "s5", UserID: "seller"},
+				{ID: "s6", UserID: "a", MinPrice: 1000},
+			},
+			wantMatches: []models.SavedSearchMatch{
+				{SavedSearchID: "s1", ListingID: "lst1", UserID: "a"},
+				{SavedSearchID: "s2", ListingID: "lst1", UserID: "b"},
+				{SavedSearchID: "s6", ListingID: "lst1", UserID: "a"},
+			},
+			wantDigests: []string{"a", "b"},
+		},
+		{
+			name:       "No Candidates",
+			listing:    listing,
+			candidates: []*models.SavedSearch{},
+		},
+		{
+			name:    "Listing Not Active",
+			listing: &models.Listing{ID: "lst1", Status: models.ListingStatusPendingReview},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			repo := new(MockSavedSearchRepository)
+			listingRepo := new(MockSavedSearchListingRepo)
+			listingRepo.On("GetListing", mock.Anything, "lst1").Return(tt.listing, nil)
+			if tt.candidates != nil {
+				repo.On("GetSavedSearchCandidates", mock.Anything, mock.Anything).Return(tt.candidates, nil)
+			}
+			if tt.wantMatches != nil {
+				repo.On("RecordMatches", mock.Anything, tt.wantMatches).Return(nil)
+			}
+			for _, u := range tt.wantDigests {
+				repo.On("CreateDigest", mock.Anything, u, now.Add(-SavedSearchDigestGap), mock.Anything).Return(nil, nil).Once()
+			}
+
+			s := NewSavedSearchService(repo, listingRepo)
+			s.now = func() time.Time { return now }
+			err := s.MatchListing(context.Background(), "lst1")
+
+			assert.NoError(t, err)
+			repo.AssertExpectations(t)
+			listingRepo.AssertExpectations(t)
+		})
+	}
+}
+
+func TestSavedSearchService_DigestPayload(t *testing.T) {
+	repo := new(MockSavedSearchRepository)
+	repo.On("GetUsersWithPendingMatches", mock.Anything, digestBatchSize).Return([]string{"a"}, nil)
+
+	var built *models.Notification
+	repo.On("CreateDigest", mock.Anything, "a", mock.Anything, mock.Anything).Return(nil, nil).Run(func(args mock.Arguments) {
+		fn := args.Get(3).(func([]models.SavedSearchMatch) (*models.Notification, error))
+		n, err := fn([]models.SavedSearchMatch{
+			{SavedSearchID: "s1", ListingID: "lst1", UserID: "a"},
+			{SavedSearchID: "s1", ListingID: "lst2", UserID: "a"},
+		})
+		assert.NoError(t, err)
+		built = n
+	})
+
+	s := NewSavedSearchService(repo, new(MockSavedSearchListingRepo))
+	assert.NoError(t, s.FlushDigests(context.Background()))
+
+	if assert.NotNil(t, built) {
+		assert.Equal(t, "a", built.UserID)
+		assert.Equal(t, models.NotificationTypeSavedSearchDigest, built.Type)
+		var digest models.SavedSearchDigest
+		assert.NoError(t, json.Unmarshal(built.Payload, &digest))
+		assert.Equal(t, 2, digest.Count)
+		assert.Equal(t, "lst2", digest.Matches[1].ListingID)
+	}
+	repo.AssertExpectations(t)
+}
@@ -0,0 +1,187 @@
+package service
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"log"
+	"slices"
+	"strings"
+	"uttc-hackathon-backend/internal/models"
+	"uttc-hackathon-backend/internal/repository"
+)
+
+// MinScreeningConfidence is the AI confidence at or above which a listing is held for review.
+const MinScreeningConfidence = 0.5
+
+type keywordRule struct {
+	category string
+	keywords []string
+}
+
+// prohibitedKeywordRules are matched case-insensitively against the title and description.
+var prohibitedKeywordRules = []keywordRule{
+	{
+		category: models.ProhibitedCategoryWeapons,
+		keywords: []string{"拳銃", "実弾", "銃刀", "スタンガン", "firearm", "handgun", "ammunition", "taser"},
+	},
+	{
+		category: models.ProhibitedCategoryCounterfeit,
+		keywords: []string{"スーパーコピー", "コピー品", "偽物", "偽ブランド", "super copy", "counterfeit", "fake brand"},
+	},
+	{
+		category: models.ProhibitedCategoryTicketResale,
+		keywords: []string{"チケット転売", "転売チケット", "定価以上", "ticket resale", "resale ticket"},
+	},
+	{
+		category: models.ProhibitedCategoryPersonalData,
+		keywords: []string{"マイナンバー", "個人情報", "名簿", "住所録", "personal data", "leaked data", "social security number"},
+	},
+}
+
+var prohibitedCategories = []string{
+	models.ProhibitedCategoryWeapons,
+	models.ProhibitedCategoryCounterfeit,
+	models.ProhibitedCategoryTicketResale,
+	models.ProhibitedCategoryPersonalData,
+}
+
+type ScreeningService struct {
+	vertexRepo VertexGenerativeClient
+}
+
+func NewScreeningService(vertexRepo VertexGenerativeClient) *ScreeningService {
+	return &ScreeningService{
+		vertexRepo: vertexRepo,
+	}
+}
+
+type screeningVerdict struct {
+	Prohibited bool     `json:"prohibited"`
+	Categories []string `json:"categories"`
+	Confidence float64  `json:"confidence"`
+	Reason     string   `json:"reason"`
+}
+
+// Screen checks a listing for prohibited content, first with keyword rules and then with the AI.
+// It never returns an error: if the AI is unavailable or answers with an invalid verdict,
+// the listing is flagged so that nothing unchecked goes active.
+func (s *ScreeningService) Screen(ctx context.Context, l *models.Listing) *models.ListingScreening {
+	result := &models.ListingScreening{ListingID: l.ID, Categories: []string{}}
+
+	if categories := matchKeywordRules(l.Title + "\n" + l.Description); len(categories) > 0 {
+		result.Flagged = true
+		result.Source = models.ScreeningSourceKeyword
+		result.Categories = categories
+		result.Reason = "matched prohibited keywords"
+		return result
+	}
+
+	verdict, err := s.classify(ctx, l.Title, l.Description)
+	if err != nil {
+		log.Printf("listing screening unavailable for %s: %v", l.ID, err)
+		result.Flagged = true
+		result.Source = models.ScreeningSourceUnavailable
+		result.Reason = "automatic screening unavailable"
+		return result
+	}
+
+	result.Source = models.ScreeningSourceAI
+	result.Flagged = verdict.Prohibited && verdict.Confidence >= MinScreeningConfidence
+	result.Categories = verdict.Categories
+	result.Reason = verdict.Reason
+	return result
+}
+
+func matchKeywordRules(text string) []string {
+	text = strings.ToLower(text)
+	var categories []string
+	for _, rule := range prohibitedKeywordRules {
+		for _, kw := range rule.keywords {
+			if strings.Contains(text, kw) {
+				categories = append(categories, rule.category)
+				break
+			}
+		}
+	}
+	return categories
+}
+
+func (s *ScreeningService) classify(ctx context.Context, title, description string) (*screeningVerdict, error) {
+	instruction := `You are a trust and safety classifier for a C2C flea market app in Japan. Decide whether a listing offers a prohibited item.
+
+### PROHIBITED CATEGORIES
+- "weapons": firearms, ammunition, stun guns, swords or knives sold as weapons.
+- "counterfeit": fake or replica branded goods, unauthorized copies.
+- "ticket_resale": event tickets resold above face value or by scalpers.
+- "personal_data": personal information, customer lists, ID documents, account credentials.
+
+### OUTPUT
+Output valid JSON only, with exactly these fields:
+{
+  "prohibited": boolean,
+  "categories": array of category strings from the list above (empty if not prohibited),
+  "confidence": number between 0 and 1,
+  "reason": short explanation in English
+}
+
+Do not output any other text.`
+
+	input, err := json.Marshal(map[string]string{"title": title, "description": description})
+	if err != nil {
+		return nil, fmt.Errorf("marshal screening input: %w", err)
+	}
+	prompt := "### LISTING\n" + string(input)
+
+	temperature := float32(0)
+	config := repository.GenerationConfig{
+		SystemInstruction: instruction,
+		Temperature:       &temperature,
+		JsonResponse:      true,
+	}
+
+	respStr, err := s.vertexRepo.GenerateContent(ctx, "gemini-2.0-flash-lite", prompt, config)
+	if err != nil {
+		return nil, fmt.Errorf("generate screening verdict: %w", err)
+	}
+	return parseScreeningVerdict(respStr)
+}
+
+// parseScreeningVerdict strictly decodes the AI response, rejecting unknown fields and categories.
+func parseScreeningVerdict(respStr string) (*screeningVerdict, error) {
+	dec := json.NewDecoder(bytes.NewReader([]byte(respStr)))
+	dec.DisallowUnknownFields()
+
+	var raw struct {
+		Prohibited *bool    `json:"prohibited"`
+		Categories []string `json:"categories"`
+		Confidence *float64 `json:"confidence"`
+		Reason     string   `json:"reason"`
+	}
+	if err := dec.Decode(&raw); err != nil {
+		return nil, fmt.Errorf("decode screening verdict: %w", err)
+	}
+	if raw.Prohibited == nil || raw.Confidence == nil {
+		return nil, fmt.Errorf("screening verdict missing required fields: %s", respStr)
+	}
+	if *raw.Confidence < 0 || *raw.Confidence > 1 {
+		return nil, fmt.Errorf("screening confidence out of range: %v", *raw.Confidence)
+	}
+	for _, c := range raw.Categories {
+		if !slices.Contains(prohibitedCategories, c) {
+			return nil, fmt.Errorf("unknown screening category: %q", c)
+		}
+	}
+
+	v := &screeningVerdict{
+		Prohibited: *raw.Prohibited,
+		Categories: raw.Categories,
+		Confidence: *raw.Confidence,
+		Reason:     raw.Reason,
+	}
+	if v.Categories == nil {
+		v.Categories = []string{}
+	}
+	return v, nil
+}
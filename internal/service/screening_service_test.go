@@ -0,0 +1,134 @@
+package service
+
+import (
+	"context"
+	"testing"
+
+	"uttc-hackathon-backend/internal/models"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+func TestScreeningService_Screen(t *testing.T) {
+	tests := []struct {
+		name           string
+		listing        *models.Listing
+		mockSetup      func(*MockVertexGenerativeClient)
+		wantFlagged    bool
+		wantSource     models.ScreeningSource
+		wantCategories []string
+	}{
+		{
+			name:    "Keyword Match Skips AI",
+			listing: &models.Listing{Title: "ブランドバッグ スーパーコピー", Description: "新品"},
+			mockSetup: func(m *MockVertexGenerativeClient) {
+				// Not called
+			},
+			wantFlagged:    true,
+			wantSource:     models.ScreeningSourceKeyword,
+			wantCategories: []string{models.ProhibitedCategoryCounterfeit},
+		},
+		{
+			name:    "Keyword Match Is Case Insensitive",
+			listing: &models.Listing{Title: "Old Handgun holster", Description: "Leather"},
+			mockSetup: func(m *MockVertexGenerativeClient) {
+				// Not called
+			},
+			wantFlagged:    true,
+			wantSource:     models.ScreeningSourceKeyword,
+			wantCategories: []string{models.ProhibitedCategoryWeapons},
+		},
+		{
+			name:    "AI Clean",
+			listing: &models.Listing{Title: "Leather Backpack", Description: "Good condition"},
+			mockSetup: func(m *MockVertexGenerativeClient) {
+				m.On("GenerateContent", mock.Anything, "gemini-2.0-flash-lite", mock.Anything, mock.Anything).
+					Return(`{"prohibited": false, "categories": [], "confidence": 0.95, "reason": "ordinary bag"}`, nil)
+			},
+			wantFlagged:    false,
+			wantSource:     models.ScreeningSourceAI,
+			wantCategories: []string{},
+		},
+		{
+			name:    "AI Flags",
+			listing: &models.Listing{Title: "Concert seats", Description: "Sold at 3x price"},
+			mockSetup: func(m *MockVertexGenerativeClient) {
+				m.On("GenerateContent", mock.Anything, mock.Anything, mock.Anything, mock.Anything).
+					Return(`{"prohibited": true, "categories": ["ticket_resale"], "confidence": 0.8, "reason": "scalped tickets"}`, nil)
+			},
+			wantFlagged:    true,
+			wantSource:     models.ScreeningSourceAI,
+			wantCategories: []string{models.ProhibitedCategoryTicketResale},
+		},
+		{
+			name:    "AI Low Confidence",
+			listing: &models.Listing{Title: "Kitchen knife set", Description: "Stainless"},
+			mockSetup: func(m *MockVertexGenerativeClient) {
+				m.On("GenerateContent", mock.Anything, mock.Anything, mock.Anything, mock.Anything).
+					Return(`{"prohibited": true, "categories": ["weapons"], "confidence": 0.2, "reason": "probably cookware"}`, nil)
+			},
+			wantFlagged:    false,
+			wantSource:     models.ScreeningSourceAI,
+			wantCategories: []string{models.ProhibitedCategoryWeapons},
+		},
+		{
+			name:    "AI Error Holds Listing",
+			listing: &models.Listing{Title: "Lamp", Description: "Desk lamp"},
+			mockSetup: func(m *MockVertexGenerativeClient) {
+				m.On("GenerateContent", mock.Anything, mock.Anything, mock.Anything, mock.Anything).Return("", assert.AnError)
+			},
+			wantFlagged:    true,
+			wantSource:     models.ScreeningSourceUnavailable,
+			wantCategories: []string{},
+		},
+		{
+			name:    "Unknown Field Rejected",
+			listing: &models.Listing{Title: "Lamp", Description: "Desk lamp"},
+			mockSetup: func(m *MockVertexGenerativeClient) {
+				m.On("GenerateContent", mock.Anything, mock.Anything, mock.Anything, mock.Anything).
+					Return(`{"prohibited": false, "categories": [], "confidence": 0.9, "reason": "", "note": "x"}`, nil)
+			},
+			wantFlagged:    true,
+			wantSource:     models.ScreeningSourceUnavailable,
+			wantCategories: []string{},
+		},
+		{
+			name:    "Unknown Category Rejected",
+			listing: &models.Listing{Title: "Lamp", Description: "Desk lamp"},
+			mockSetup: func(m *MockVertexGenerativeClient) {
+				m.On("GenerateContent", mock.Anything, mock.Anything, mock.Anything, mock.Anything).
+					Return(`{"prohibited": true, "categories": ["drugs"], "confidence": 0.9, "reason": ""}`, nil)
+			},
+			wantFlagged:    true,
+			wantSource:     models.ScreeningSourceUnavailable,
+			wantCategories: []string{},
+		},
+		{
+			name:    "Missing Confidence Rejected",
+			listing: &models.Listing{Title: "Lamp", Description: "Desk lamp"},
+			mockSetup: func(m *MockVertexGenerativeClient) {
+				m.On("GenerateContent", mock.Anything, mock.Anything, mock.Anything, mock.Anything).
+					Return(`{"prohibited": false, "categories": []}`, nil)
+			},
+			wantFlagged:    true,
+			wantSource:     models.ScreeningSourceUnavailable,
+			wantCategories: []string{},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			client := new(MockVertexGenerativeClient)
+			tt.mockSetup(client)
+
+			s := NewScreeningService(client)
+			got := s.Screen(context.Background(), tt.listing)
+
+			assert.Equal(t, tt.wantFlagged, got.Flagged)
+			assert.Equal(t, tt.wantSource, got.Source)
+			assert.Equal(t, tt.wantCategories, got.Categories)
+			client.AssertExpectations(t)
+		})
+	}
+}
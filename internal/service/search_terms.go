@@ -0,0 +1,91 @@
+package service
+
+import (
+	"strings"
+	"unicode"
+)
+
+const (
+	maxTermRunes = 32
+	// maxListingTerms bounds the IN list used to look up saved searches for one listing.
+	maxListingTerms = 2000
+)
+
+// searchTerms splits a saved search query into index terms. Latin words and numbers become
+// whole lower-cased words; Japanese has no spaces, so CJK runs become character bigrams
+// (a single character stays a unigram). Duplicates are removed.
+func searchTerms(text string) []string {
+	return tokenize(text, false, 0)
+}
+
+// listingTerms returns every term a saved search could be looking for in the listing.
+// CJK runs contribute both unigrams and bigrams so that one-character queries match too.
+func listingTerms(title, description string) []string {
+	return tokenize(title+"\n"+description, true, maxListingTerms)
+}
+
+func tokenize(text string, withUnigrams bool, limit int) []string {
+	seen := make(map[string]struct{})
+	var terms []string
+	add := func(t string) {
+		if _, ok := seen[t]; ok || (limit > 0 && len(terms) >= limit) {
+			return
+		}
+		seen[t] = struct{}{}
+		terms = append(terms, t)
+	}
+
+	var run []rune
+	runCJK := false
+	flush := func() {
+		switch {
+		case len(run) == 0:
+		case !runCJK:
+			if len(run) > maxTermRunes {
+				run = run[:maxTermRunes]
+			}
+			add(string(run))
+		case len(run) == 1:
+			add(string(run))
+		default:
+			for i := 0; i+1 < len(run); i++ {
+				if withUnigrams {
+					add(string(run[i]))
+				}
+				add(string(run[i : i+2]))
+			}
+			if withUnigrams {
+				add(string(run[len(run)-1]))
+			}
+		}
+		run = run[:0]
+	}
+
+	for _, r := range strings.ToLower(text) {
+		r = unicode.ToLower(narrowRune(r))
+		cjk := isCJK(r)
+		if !cjk && !unicode.IsLetter(r) && !unicode.IsDigit(r) {
+			flush()
+			continue
+		}
+		if len(run) > 0 && cjk != runCJK {
+			flush()
+		}
+		runCJK = cjk
+		run = append(run, r)
+	}
+	flush()
+	return terms
+}
+
+func isCJK(r rune) bool {
+	return unicode.In(r, unicode.Han, unicode.Hiragana, unicode.Katakana) || r == 'ー'
+}
+
+// narrowRune maps full-width ASCII (Ａ, １, ...) to its half-width form.
+func narrowRune(r rune) rune {
+	if r >= '！' && r <= '～' {
+		return r - 0xFEE0
+	}
+	return r
+}
@@ -0,0 +1,221 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"log"
+	"strconv"
+	"strings"
+	"time"
+	"uttc-hackathon-backend/internal/models"
+)
+
+const (
+	// TrackingPollInterval is the least time between two checks of the same package.
+	TrackingPollInterval = 30 * time.Minute
+	// trackingBatchSize bounds how many packages one SyncTracking pass checks.
+	trackingBatchSize = 100
+)
+
+var (
+	ErrInvalidCarrier        = errors.New("carrier must be yamato, japan_post or sagawa")
+	ErrInvalidTrackingNumber = errors.New("tracking number is not valid for the carrier")
+	ErrTrackingIncomplete    = errors.New("carrier and tracking_number must be given together")
+	ErrCarrierMismatch       = errors.New("the carrier does not handle the order's shipping method")
+)
+
+// Carrier looks up packages with a delivery company.
+type Carrier interface {
+	Track(ctx context.Context, trackingNumber string) (*models.TrackingUpdate, error)
+}
+
+type ShipmentRepository interface {
+	ShipOrder(ctx context.Context, orderID string, fn func(*models.Order) (*models.Shipment, error)) error
+	GetShipment(ctx context.Context, orderID string) (*models.Shipment, error)
+	GetShipmentsToTrack(ctx context.Context, checkedBefore time.Time, limit int) ([]*models.Shipment, error)
+	UpdateShipment(ctx context.Context, orderID string, fn func(*models.Shipment, *models.Order) error) error
+}
+
+// methodCarriers is the carrier that runs each shipping method.
+var methodCarriers = map[models.ShippingMethod]models.Carrier{
+	models.ShippingMethodNekopos:   models.CarrierYamato,
+	models.ShippingMethodTakkyubin: models.CarrierYamato,
+	models.ShippingMethodYuPacket:  models.CarrierJapanPost,
+	models.ShippingMethodYuPack:    models.CarrierJapanPost,
+}
+
+// ShipmentService records the packages sellers send and follows them with the carriers
+// until they are delivered.
+type ShipmentService struct {
+	repo     ShipmentRepository
+	orders   OrderReader
+	carriers map[models.Carrier]Carrier
+	now      func() time.Time
+}
+
+// NewShipmentService tracks packages with the given carriers; shipments with other carriers
+// are recorded but not tracked.
+func NewShipmentService(repo ShipmentRepository, orders OrderReader, carriers map[models.Carrier]Carrier) *ShipmentService {
+	return &ShipmentService{repo: repo, orders: orders, carriers: carriers, now: time.Now}
+}
+
+// normalizeTrackingNumber drops the hyphens and spaces printed on shipping labels.
+func normalizeTrackingNumber(n string) string {
+	return strings.NewReplacer("-", "", " ", "").Replace(n)
+}
+
+// validateTrackingNumber checks a normalized tracking number. Yamato, Japan Post and Sagawa
+// all issue 12-digit domestic numbers whose last digit is the first 11, read as a number,
+// modulo 7.
+func validateTrackingNumber(carrier models.Carrier, n string) error {
+	switch carrier {
+	case models.CarrierYamato, models.CarrierJapanPost, models.CarrierSagawa:
+	default:
+		return ErrInvalidCarrier
+	}
+	if !isDigits(n, 12) {
+		return ErrInvalidTrackingNumber
+	}
+	body, _ := strconv.ParseInt(n[:11], 10, 64)
+	if int(n[11]-'0') != int(body%7) {
+		return ErrInvalidTrackingNumber
+	}
+	return nil
+}
+
+// ShipOrder marks a paid order shipped for its seller, recording the package's tracking
+// number when one is given. Packages with a tracking number are then followed by SyncTracking.
+func (s *ShipmentService) ShipOrder(ctx context.Context, userID, orderID string, carrier models.Carrier, trackingNumber string) (*models.Order, error) {
+	trackingNumber = normalizeTrackingNumber(trackingNumber)
+	if (carrier == "") != (trackingNumber == "") {
+		return nil, ErrTrackingIncomplete
+	}
+	if carrier != "" {
+		if err := validateTrackingNumber(carrier, trackingNumber); err != nil {
+			return nil, err
+		}
+	}
+
+	var shipped *models.Order
+	err := s.repo.ShipOrder(ctx, orderID, func(o *models.Order) (*models.Shipment, error) {
+		role := orderRole(o, userID)
+		if role == "" {
+			return nil, ErrUnauthorized
+		}
+		next, err := nextOrderStatus(o, role, OrderActionShip)
+		if err != nil {
+			return nil, err
+		}
+		if want, ok := methodCarriers[o.ShippingMethod]; ok && carrier != "" && carrier != want {
+			return nil, ErrCarrierMismatch
+		}
+
+		o.Status = next
+		o.Version++
+		shipped = o
+		if carrier == "" {
+			return nil, nil
+		}
+		return &models.Shipment{
+			OrderID:        o.ID,
+			Carrier:        carrier,
+			TrackingNumber: trackingNumber,
+			TrackingStatus: models.TrackingStatusInTransit,
+			ShippedAt:      s.now(),
+		}, nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return shipped, nil
+}
+
+// GetShipment returns the order's shipment to its buyer or seller.
+func (s *ShipmentService) GetShipment(ctx context.Context, userID, orderID string) (*models.Shipment, error) {
+	if _, err := s.orders.GetOrder(ctx, userID, orderID); err != nil {
+		return nil, err
+	}
+	return s.repo.GetShipment(ctx, orderID)
+}
+
+// SyncTracking checks the packages in transit with their carriers and records what they
+// report. A package the carrier has delivered moves its order to delivered unless the buyer
+// already confirmed receipt or opened a dispute. It returns how many orders were delivered.
+func (s *ShipmentService) SyncTracking(ctx context.Context) (int, error) {
+	shipments, err := s.repo.GetShipmentsToTrack(ctx, s.now().Add(-TrackingPollInterval), trackingBatchSize)
+	if err != nil {
+		return 0, err
+	}
+
+	delivered := 0
+	for _, sh := range shipments {
+		c, ok := s.carriers[sh.Carrier]
+		if !ok {
+			continue
+		}
+		update, err := c.Track(ctx, sh.TrackingNumber)
+		if err != nil {
+			log.Printf("track %s package %s for order %s: %v", sh.Carrier, sh.TrackingNumber, sh.OrderID, err)
+			continue
+		}
+		moved, err := s.applyTracking(ctx, sh.OrderID, update)
+		if err != nil {
+			log.Printf("apply tracking for order %s: %v", sh.OrderID, err)
+			continue
+		}
+		if moved {
+			delivered++
+		}
+	}
+	return delivered, nil
+}
+
+// applyTracking records a carrier's report on the order's package and reports whether it
+// moved the order to delivered.
+func (s *ShipmentService) applyTracking(ctx context.Context, orderID string, update *models.TrackingUpdate) (bool, error) {
+	delivered := false
+	err := s.repo.UpdateShipment(ctx, orderID, func(sh *models.Shipment, o *models.Order) error {
+		now := s.now()
+		sh.LastCheckedAt = &now
+		if sh.TrackingStatus == models.TrackingStatusDelivered {
+			return nil
+		}
+
+		sh.TrackingStatus = update.Status
+		if update.Status != models.TrackingStatusDelivered {
+			return nil
+		}
+		deliveredAt := now
+		if update.DeliveredAt != nil {
+			deliveredAt = *update.DeliveredAt
+		}
+		sh.DeliveredAt = &deliveredAt
+
+		if o.Status == models.OrderStatusShipped {
+			o.Status = models.OrderStatusDelivered
+			o.Version++
+			delivered = true
+		}
+		return nil
+	})
+	if err != nil {
+		return false, err
+	}
+	return delivered, nil
+}
+
+// RunTrackingLoop calls SyncTracking every interval until ctx is cancelled.
+func (s *ShipmentService) RunTrackingLoop(ctx context.Context, interval time.Duration) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			if _, err := s.SyncTracking(ctx); err != nil {
+				log.Printf("sync shipment tracking: %v", err)
+			}
+		}
+	}
+}
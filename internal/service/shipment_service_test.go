@@ -0,0 +1,218 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"uttc-hackathon-backend/internal/models"
+	"uttc-hackathon-backend/internal/repository"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+type MockShipmentRepository struct {
+	mock.Mock
+}
+
+func (m *MockShipmentRepository) ShipOrder(ctx context.Context, orderID string, fn func(*models.Order) (*models.Shipment, error)) error {
+	args := m.Called(ctx, orderID, fn)
+	return args.Error(0)
+}
+
+func (m *MockShipmentRepository) GetShipment(ctx context.Context, orderID string) (*models.Shipment, error) {
+	args := m.Called(ctx, orderID)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*models.Shipment), args.Error(1)
+}
+
+func (m *MockShipmentRepository) GetShipmentsToTrack(ctx context.Context, checkedBefore time.Time, limit int) ([]*models.Shipment, error) {
+	args := m.Called(ctx, checkedBefore, limit)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]*models.Shipment), args.Error(1)
+}
+
+func (m *MockShipmentRepository) UpdateShipment(ctx context.Context, orderID string, fn func(*models.Shipment, *models.Order) error) error {
+	args := m.Called(ctx, orderID, fn)
+	return args.Error(0)
+}
+
+var shipmentTestNow = time.Date(2024, 9, 1, 12, 0, 0, 0, time.UTC)
+
+func TestValidateTrackingNumber(t *testing.T) {
+	tests := []struct {
+		name    string
+		carrier models.Carrier
+		number  string
+		wantErr error
+	}{
+		{name: "Yamato", carrier: models.CarrierYamato, number: "123456789013"},
+		{name: "Japan Post", carrier: models.CarrierJapanPost, number: "400123456784"},
+		{name: "Sagawa", carrier: models.CarrierSagawa, number: "987654321091"},
+		{name: "Wrong Check Digit", carrier: models.CarrierYamato, number: "123456789014", wantErr: ErrInvalidTrackingNumber},
+		{name: "Too Short", carrier: models.CarrierYamato, number: "12345678901", wantErr: ErrInvalidTrackingNumber},
+		{name: "Letters", carrier: models.CarrierJapanPost, number: "AB123456789J", wantErr: ErrInvalidTrackingNumber},
+		{name: "Unknown Carrier", carrier: "fedex", number: "123456789013", wantErr: ErrInvalidCarrier},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.wantErr, validateTrackingNumber(tt.carrier, tt.number))
+		})
+	}
+}
+
+func TestShipmentService_ShipOrder(t *testing.T) {
+	tests := []struct {
+		name         string
+		userID       string
+		method       models.ShippingMethod
+		status       models.OrderStatus
+		carrier      models.Carrier
+		tracking     string
+		wantShipment *models.Shipment
+		wantErr      error
+	}{
+		{
+			name: "With Tracking Number", userID: "seller1", method: models.ShippingMethodTakkyubin, status: models.OrderStatusPaid,
+			carrier: models.CarrierYamato, tracking: "1234-5678-9013",
+			wantShipment: &models.Shipment{
+				OrderID: "ord1", Carrier: models.CarrierYamato, TrackingNumber: "123456789013",
+				TrackingStatus: models.TrackingStatusInTransit, ShippedAt: shipmentTestNow,
+			},
+		},
+		{name: "Without Tracking Number", userID: "seller1", method: models.ShippingMethodTakkyubin, status: models.OrderStatusPaid},
+		{
+			name: "Any Carrier Without Shipping Method", userID: "seller1", status: models.OrderStatusPaid,
+			carrier: models.CarrierSagawa, tracking: "987654321091",
+			wantShipment: &models.Shipment{
+				OrderID: "ord1", Carrier: models.CarrierSagawa, TrackingNumber: "987654321091",
+				TrackingStatus: models.TrackingStatusInTransit, ShippedAt: shipmentTestNow,
+			},
+		},
+		{
+			name: "Carrier Does Not Run Method", userID: "seller1", method: models.ShippingMethodYuPacket, status: models.OrderStatusPaid,
+			carrier: models.CarrierYamato, tracking: "123456789013", wantErr: ErrCarrierMismatch,
+		},
+		{name: "Carrier Only", userID: "seller1", status: models.OrderStatusPaid, carrier: models.CarrierYamato, wantErr: ErrTrackingIncomplete},
+		{name: "Invalid Number", userID: "seller1", status: models.OrderStatusPaid, carrier: models.CarrierYamato, tracking: "123456789014", wantErr: ErrInvalidTrackingNumber},
+		{name: "Buyer", userID: "buyer1", status: models.OrderStatusPaid, wantErr: ErrOrderActionForbidden},
+		{name: "Stranger", userID: "stranger", status: models.OrderStatusPaid, wantErr: ErrUnauthorized},
+		{name: "Already Shipped", userID: "seller1", status: models.OrderStatusShipped, wantErr: ErrInvalidOrderTransition},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			o := &models.Order{ID: "ord1", BuyerID: "buyer1", SellerID: "seller1", ShippingMethod: tt.method, Status: tt.status, Version: 2}
+			repo := new(MockShipmentRepository)
+			repo.On("ShipOrder", mock.Anything, "ord1", mock.Anything).Return(tt.wantErr).Run(func(args mock.Arguments) {
+				fn := args.Get(2).(func(*models.Order) (*models.Shipment, error))
+				sh, err := fn(o)
+				assert.Equal(t, tt.wantErr, err)
+				assert.Equal(t, tt.wantShipment, sh)
+			}).Maybe()
+
+			s := NewShipmentService(repo, nil, nil)
+			s.now = func() time.Time { return shipmentTestNow }
+			got, err := s.ShipOrder(context.Background(), tt.userID, "ord1", tt.carrier, tt.tracking)
+
+			if tt.wantErr != nil {
+				assert.Equal(t, tt.wantErr, err)
+				return
+			}
+			assert.NoError(t, err)
+			assert.Equal(t, models.OrderStatusShipped, got.Status)
+			assert.Equal(t, 3, got.Version)
+		})
+	}
+}
+
+func TestShipmentService_SyncTracking(t *testing.T) {
+	deliveredAt := shipmentTestNow.Add(-2 * time.Hour)
+	yamato := repository.NewFakeCarrier()
+	yamato.Deliver("123456789013", deliveredAt)
+	yamato.Deliver("400123456784", deliveredAt)
+	yamato.Return("987654321091")
+
+	type tracked struct {
+		shipment *models.Shipment
+		order    *models.Order
+	}
+	packages := map[string]*tracked{
+		"ord_delivered": {
+			shipment: &models.Shipment{OrderID: "ord_delivered", Carrier: models.CarrierYamato, TrackingNumber: "123456789013", TrackingStatus: models.TrackingStatusInTransit},
+			order:    &models.Order{ID: "ord_delivered", Status: models.OrderStatusShipped, Version: 3},
+		},
+		"ord_disputed": {
+			shipment: &models.Shipment{OrderID: "ord_disputed", Carrier: models.CarrierYamato, TrackingNumber: "400123456784", TrackingStatus: models.TrackingStatusInTransit},
+			order:    &models.Order{ID: "ord_disputed", Status: models.OrderStatusDisputed, Version: 4},
+		},
+		"ord_returned": {
+			shipment: &models.Shipment{OrderID: "ord_returned", Carrier: models.CarrierYamato, TrackingNumber: "987654321091", TrackingStatus: models.TrackingStatusInTransit},
+			order:    &models.Order{ID: "ord_returned", Status: models.OrderStatusShipped, Version: 3},
+		},
+		"ord_untracked": {
+			shipment: &models.Shipment{OrderID: "ord_untracked", Carrier: models.CarrierSagawa, TrackingNumber: "123456789013", TrackingStatus: models.TrackingStatusInTransit},
+			order:    &models.Order{ID: "ord_untracked", Status: models.OrderStatusShipped, Version: 3},
+		},
+	}
+
+	repo := new(MockShipmentRepository)
+	var toTrack []*models.Shipment
+	for _, id := range []string{"ord_delivered", "ord_disputed", "ord_returned", "ord_untracked"} {
+		toTrack = append(toTrack, packages[id].shipment)
+	}
+	repo.On("GetShipmentsToTrack", mock.Anything, shipmentTestNow.Add(-TrackingPollInterval), trackingBatchSize).Return(toTrack, nil)
+	repo.On("UpdateShipment", mock.Anything, mock.Anything, mock.Anything).Return(nil).Run(func(args mock.Arguments) {
+		p := packages[args.String(1)]
+		fn := args.Get(2).(func(*models.Shipment, *models.Order) error)
+		assert.NoError(t, fn(p.shipment, p.order))
+	})
+
+	s := NewShipmentService(repo, nil, map[models.Carrier]Carrier{models.CarrierYamato: yamato})
+	s.now = func() time.Time { return shipmentTestNow }
+	n, err := s.SyncTracking(context.Background())
+
+	assert.NoError(t, err)
+	assert.Equal(t, 1, n)
+
+	assert.Equal(t, models.TrackingStatusDelivered, packages["ord_delivered"].shipment.TrackingStatus)
+	assert.Equal(t, &deliveredAt, packages["ord_delivered"].shipment.DeliveredAt)
+	assert.Equal(t, models.OrderStatusDelivered, packages["ord_delivered"].order.Status)
+	assert.Equal(t, 4, packages["ord_delivered"].order.Version)
+
+	// A disputed order stays with the admins even though the package arrived
+	assert.Equal(t, models.TrackingStatusDelivered, packages["ord_disputed"].shipment.TrackingStatus)
+	assert.Equal(t, models.OrderStatusDisputed, packages["ord_disputed"].order.Status)
+
+	assert.Equal(t, models.TrackingStatusReturned, packages["ord_returned"].shipment.TrackingStatus)
+	assert.Equal(t, models.OrderStatusShipped, packages["ord_returned"].order.Status)
+	assert.Equal(t, &shipmentTestNow, packages["ord_returned"].shipment.LastCheckedAt)
+
+	// Carriers without an integration are never polled
+	assert.Nil(t, packages["ord_untracked"].shipment.LastCheckedAt)
+	repo.AssertNumberOfCalls(t, "UpdateShipment", 3)
+}
+
+func TestShipmentService_GetShipment(t *testing.T) {
+	orderRepo := new(MockOrderRepository)
+	orderRepo.On("GetOrder", mock.Anything, "ord1").Return(&models.Order{ID: "ord1", BuyerID: "buyer1", SellerID: "seller1"}, nil)
+	repo := new(MockShipmentRepository)
+	shipment := &models.Shipment{OrderID: "ord1", Carrier: models.CarrierYamato, TrackingNumber: "123456789013"}
+	repo.On("GetShipment", mock.Anything, "ord1").Return(shipment, nil)
+
+	s := NewShipmentService(repo, newTestOrderService(orderRepo), nil)
+
+	got, err := s.GetShipment(context.Background(), "buyer1", "ord1")
+	assert.NoError(t, err)
+	assert.Equal(t, shipment, got)
+
+	_, err = s.GetShipment(context.Background(), "stranger", "ord1")
+	assert.True(t, errors.Is(err, ErrUnauthorized))
+}
@@ -0,0 +1,79 @@
+package service
+
+import (
+	"errors"
+	"uttc-hackathon-backend/internal/models"
+)
+
+var (
+	ErrInvalidShippingPayer   = errors.New("shipping_payer must be seller or buyer")
+	ErrInvalidShippingMethod  = errors.New("unsupported shipping method and size")
+	ErrShippingMethodRequired = errors.New("shipping_method and shipping_size are required when the buyer pays shipping")
+	ErrInvalidPrefecture      = errors.New("ship_from_prefecture must be a prefecture code between 1 and 47")
+	ErrInvalidDaysToShip      = errors.New("days_to_ship must be 1_2, 2_3 or 4_7")
+)
+
+// shippingFeeTable holds the shipping fee in yen by method and package size.
+var shippingFeeTable = map[models.ShippingMethod]map[models.ShippingSize]int{
+	models.ShippingMethodNekopos: {
+		models.ShippingSizeSmall: 210,
+	},
+	models.ShippingMethodYuPacket: {
+		models.ShippingSizeSmall: 230,
+	},
+	models.ShippingMethodTakkyubin: {
+		models.ShippingSize60:  750,
+		models.ShippingSize80:  850,
+		models.ShippingSize100: 1050,
+		models.ShippingSize120: 1200,
+		models.ShippingSize140: 1450,
+		models.ShippingSize160: 1700,
+	},
+	models.ShippingMethodYuPack: {
+		models.ShippingSize60:  770,
+		models.ShippingSize80:  870,
+		models.ShippingSize100: 1070,
+		models.ShippingSize120: 1200,
+		models.ShippingSize140: 1450,
+		models.ShippingSize160: 1700,
+	},
+}
+
+// ShippingFee returns the fee for one package of the given method and size.
+func ShippingFee(method models.ShippingMethod, size models.ShippingSize) (int, error) {
+	fee, ok := shippingFeeTable[method][size]
+	if !ok {
+		return 0, ErrInvalidShippingMethod
+	}
+	return fee, nil
+}
+
+// validateShipping normalizes and validates the shipping options of a listing.
+func validateShipping(l *models.Listing) error {
+	switch l.ShippingPayer {
+	case "":
+		l.ShippingPayer = models.ShippingPayerSeller
+	case models.ShippingPayerSeller, models.ShippingPayerBuyer:
+	default:
+		return ErrInvalidShippingPayer
+	}
+
+	if l.ShippingMethod == "" && l.ShippingSize == "" {
+		if l.ShippingPayer == models.ShippingPayerBuyer {
+			return ErrShippingMethodRequired
+		}
+	} else if _, err := ShippingFee(l.ShippingMethod, l.ShippingSize); err != nil {
+		return err
+	}
+
+	if l.ShipFromPrefecture < 0 || l.ShipFromPrefecture > 47 {
+		return ErrInvalidPrefecture
+	}
+
+	switch l.DaysToShip {
+	case "", models.DaysToShip1To2, models.DaysToShip2To3, models.DaysToShip4To7:
+	default:
+		return ErrInvalidDaysToShip
+	}
+	return nil
+}
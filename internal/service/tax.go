@@ -0,0 +1,71 @@
+package service
+
+import (
+	"errors"
+	"strings"
+	"uttc-hackathon-backend/internal/models"
+)
+
+var (
+	ErrInvalidTaxRate                   = errors.New("tax_rate must be standard or reduced")
+	ErrInvalidInvoiceRegistrationNumber = errors.New("invoice registration number must be T followed by a valid 13-digit number")
+)
+
+// TaxRatePercent returns the consumption tax rate in percent.
+func TaxRatePercent(rate models.TaxRate) int {
+	if rate == models.TaxRateReduced {
+		return 8
+	}
+	return 10
+}
+
+// includedTax returns the consumption tax included in a tax-inclusive total, rounded down.
+func includedTax(total int, rate models.TaxRate) int {
+	p := TaxRatePercent(rate)
+	return total * p / (100 + p)
+}
+
+// orderTax splits an order total by tax rate. Shipping is a service and always taxed at the
+// standard rate, whatever the item's rate.
+func orderTax(itemRate models.TaxRate, subtotal, shippingFee int) models.TaxBreakdown {
+	var t models.TaxBreakdown
+	if itemRate == models.TaxRateReduced {
+		t.ReducedRateTotal = subtotal
+		t.StandardRateTotal = shippingFee
+	} else {
+		t.StandardRateTotal = subtotal + shippingFee
+	}
+	t.StandardRateTax = includedTax(t.StandardRateTotal, models.TaxRateStandard)
+	t.ReducedRateTax = includedTax(t.ReducedRateTotal, models.TaxRateReduced)
+	return t
+}
+
+// validateTaxRate defaults the listing to the standard rate.
+func validateTaxRate(l *models.Listing) error {
+	switch l.TaxRate {
+	case "":
+		l.TaxRate = models.TaxRateStandard
+	case models.TaxRateStandard, models.TaxRateReduced:
+	default:
+		return ErrInvalidTaxRate
+	}
+	return nil
+}
+
+// validInvoiceRegistrationNumber checks the "T" prefix and the check digit, which is the
+// leading digit of the 13-digit corporate or personal number: 9 minus the weighted sum of
+// the other 12 digits mod 9, weighting digits 1 and 2 alternately from the rightmost.
+func validInvoiceRegistrationNumber(s string) bool {
+	if !strings.HasPrefix(s, "T") || !isDigits(s[1:], 13) {
+		return false
+	}
+	sum := 0
+	for i := 0; i < 12; i++ {
+		d := int(s[13-i] - '0')
+		if i%2 == 1 {
+			d *= 2
+		}
+		sum += d
+	}
+	return int(s[1]-'0') == 9-sum%9
+}
@@ -0,0 +1,73 @@
+package service
+
+import (
+	"testing"
+
+	"uttc-hackathon-backend/internal/models"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestOrderTax(t *testing.T) {
+	tests := []struct {
+		name        string
+		rate        models.TaxRate
+		subtotal    int
+		shippingFee int
+		want        models.TaxBreakdown
+	}{
+		{
+			name:     "Standard Rate",
+			rate:     models.TaxRateStandard,
+			subtotal: 1000,
+			want:     models.TaxBreakdown{StandardRateTotal: 1000, StandardRateTax: 90},
+		},
+		{
+			name:        "Standard Rate With Shipping",
+			rate:        models.TaxRateStandard,
+			subtotal:    1000,
+			shippingFee: 210,
+			want:        models.TaxBreakdown{StandardRateTotal: 1210, StandardRateTax: 110},
+		},
+		{
+			name:     "Reduced Rate",
+			rate:     models.TaxRateReduced,
+			subtotal: 1080,
+			want:     models.TaxBreakdown{ReducedRateTotal: 1080, ReducedRateTax: 80},
+		},
+		{
+			name:        "Reduced Rate Item With Standard Rate Shipping",
+			rate:        models.TaxRateReduced,
+			subtotal:    1000,
+			shippingFee: 210,
+			want:        models.TaxBreakdown{StandardRateTotal: 210, StandardRateTax: 19, ReducedRateTotal: 1000, ReducedRateTax: 74},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.want, orderTax(tt.rate, tt.subtotal, tt.shippingFee))
+		})
+	}
+}
+
+func TestValidInvoiceRegistrationNumber(t *testing.T) {
+	tests := []struct {
+		number string
+		want   bool
+	}{
+		{number: "T7000012050002", want: true},
+		{number: "T1000012050002", want: false}, // wrong check digit
+		{number: "7000012050002", want: false},
+		{number: "T700001205000", want: false},
+		{number: "T70000120500021", want: false},
+		{number: "T700001205000A", want: false},
+		{number: "t7000012050002", want: false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.number, func(t *testing.T) {
+			assert.Equal(t, tt.want, validInvoiceRegistrationNumber(tt.number))
+		})
+	}
+}
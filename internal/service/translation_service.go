@@ -2,20 +2,40 @@ package service
 
 import (
 	"context"
+	"crypto/sha256"
+	"encoding/hex"
 	"encoding/json"
 	"fmt"
 	"log"
+	"slices"
 	"strings"
+	"sync"
+	"uttc-hackathon-backend/internal/models"
 	"uttc-hackathon-backend/internal/repository"
 )
 
+// SupportedLanguages are the languages listings are localized into.
+var SupportedLanguages = []string{"ja", "en"}
+
 type TranslationService struct {
 	vertexRepo VertexGenerativeClient
+	repo       TranslationRepository
+
+	mu       sync.Mutex
+	inflight map[string]struct{}
+}
+
+type TranslationRepository interface {
+	GetListingTranslation(ctx context.Context, listingID, language string) (*models.ListingTranslation, error)
+	GetListingTranslations(ctx context.Context, listingIDs []string, language string) (map[string]*models.ListingTranslation, error)
+	UpsertListingTranslation(ctx context.Context, t *models.ListingTranslation) error
 }
 
-func NewTranslationService(vertexRepo VertexGenerativeClient) *TranslationService {
+func NewTranslationService(vertexRepo VertexGenerativeClient, repo TranslationRepository) *TranslationService {
 	return &TranslationService{
 		vertexRepo: vertexRepo,
+		repo:       repo,
+		inflight:   make(map[string]struct{}),
 	}
 }
 
@@ -93,3 +113,144 @@ You will receive:
 
 	return &response, nil
 }
+
+// IsSupportedLanguage reports whether listings can be localized into the language.
+func IsSupportedLanguage(language string) bool {
+	return slices.Contains(SupportedLanguages, language)
+}
+
+// listingSourceHash identifies the listing text a translation was made from,
+// so that translations become stale as soon as the title or description changes.
+func listingSourceHash(l *models.Listing) string {
+	sum := sha256.Sum256([]byte(l.Title + "\x00" + l.Description))
+	return hex.EncodeToString(sum[:])
+}
+
+// GetListingTranslation returns the cached translation of the listing,
+// translating and storing it when missing or stale.
+func (s *TranslationService) GetListingTranslation(ctx context.Context, l *models.Listing, language string) (*models.ListingTranslation, error) {
+	hash := listingSourceHash(l)
+
+	cached, err := s.repo.GetListingTranslation(ctx, l.ID, language)
+	if err != nil {
+		return nil, err
+	}
+	if cached != nil && cached.SourceHash == hash {
+		return cached, nil
+	}
+
+	resp, err := s.TranslateContent(ctx, l.Title, l.Description, language)
+	if err != nil {
+		return nil, err
+	}
+
+	t := &models.ListingTranslation{
+		ListingID:      l.ID,
+		Language:       language,
+		Title:          resp.TranslatedTitle,
+		Description:    resp.TranslatedDescription,
+		SourceLanguage: resp.DetectedSourceLanguage,
+		SourceHash:     hash,
+	}
+	if err := s.repo.UpsertListingTranslation(ctx, t); err != nil {
+		return nil, err
+	}
+	return t, nil
+}
+
+// PrefetchListingTranslations translates a newly published listing into every supported language.
+func (s *TranslationService) PrefetchListingTranslations(ctx context.Context, l *models.Listing) {
+	for _, language := range SupportedLanguages {
+		if _, err := s.GetListingTranslation(ctx, l, language); err != nil {
+			log.Printf("failed to prefetch %s translation for listing %s: %v", language, l.ID, err)
+		}
+	}
+}
+
+// LocalizeListing returns the listing in the requested language using the cached translation only,
+// so that a listing page never waits on the AI. The original text is returned unchanged if the
+// language is empty or no fresh translation is cached; pending reports that the caller should
+// translate the listing in the background with TranslateListing.
+func (s *TranslationService) LocalizeListing(ctx context.Context, l *models.Listing, language string) (localized *models.LocalizedListing, pending bool) {
+	if language == "" {
+		return &models.LocalizedListing{Listing: l}, false
+	}
+
+	t, err := s.repo.GetListingTranslation(ctx, l.ID, language)
+	if err != nil {
+		log.Printf("failed to get cached %s translation for listing %s: %v", language, l.ID, err)
+		return &models.LocalizedListing{Listing: l}, false
+	}
+	if t == nil || t.SourceHash != listingSourceHash(l) {
+		return &models.LocalizedListing{Listing: l}, true
+	}
+	return applyTranslation(l, t), false
+}
+
+// TranslateListing translates and caches the listing for a later request.
+// Concurrent calls for the same listing and language share a single translation.
+func (s *TranslationService) TranslateListing(ctx context.Context, l *models.Listing, language string) {
+	key := l.ID + "/" + language
+	s.mu.Lock()
+	if _, ok := s.inflight[key]; ok {
+		s.mu.Unlock()
+		return
+	}
+	s.inflight[key] = struct{}{}
+	s.mu.Unlock()
+
+	defer func() {
+		s.mu.Lock()
+		delete(s.inflight, key)
+		s.mu.Unlock()
+	}()
+
+	if _, err := s.GetListingTranslation(ctx, l, language); err != nil {
+		log.Printf("failed to translate listing %s into %s: %v", l.ID, language, err)
+	}
+}
+
+// LocalizeListings localizes a page of listings using cached translations only,
+// so that a feed request never waits on the AI. Listings without a fresh translation keep their original text.
+func (s *TranslationService) LocalizeListings(ctx context.Context, listings []*models.Listing, language string) []*models.LocalizedListing {
+	result := make([]*models.LocalizedListing, 0, len(listings))
+
+	var cached map[string]*models.ListingTranslation
+	if language != "" && len(listings) > 0 {
+		ids := make([]string, 0, len(listings))
+		for _, l := range listings {
+			ids = append(ids, l.ID)
+		}
+		var err error
+		cached, err = s.repo.GetListingTranslations(ctx, ids, language)
+		if err != nil {
+			log.Printf("failed to get cached translations: %v", err)
+		}
+	}
+
+	for _, l := range listings {
+		t, ok := cached[l.ID]
+		if !ok || t.SourceHash != listingSourceHash(l) {
+			result = append(result, &models.LocalizedListing{Listing: l})
+			continue
+		}
+		result = append(result, applyTranslation(l, t))
+	}
+	return result
+}
+
+func applyTranslation(l *models.Listing, t *models.ListingTranslation) *models.LocalizedListing {
+	if t.SourceLanguage == t.Language {
+		return &models.LocalizedListing{Listing: l, Language: t.Language, TranslatedAt: t.UpdatedAt}
+	}
+
+	localized := *l
+	localized.Title = t.Title
+	localized.Description = t.Description
+	return &models.LocalizedListing{
+		Listing:      &localized,
+		Language:     t.Language,
+		Original:     &models.ListingText{Title: l.Title, Description: l.Description},
+		TranslatedAt: t.UpdatedAt,
+	}
+}
@@ -0,0 +1,226 @@
+package service
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"uttc-hackathon-backend/internal/models"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+type MockTranslationRepository struct {
+	mock.Mock
+}
+
+func (m *MockTranslationRepository) GetListingTranslation(ctx context.Context, listingID, language string) (*models.ListingTranslation, error) {
+	args := m.Called(ctx, listingID, language)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*models.ListingTranslation), args.Error(1)
+}
+
+func (m *MockTranslationRepository) GetListingTranslations(ctx context.Context, listingIDs []string, language string) (map[string]*models.ListingTranslation, error) {
+	args := m.Called(ctx, listingIDs, language)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(map[string]*models.ListingTranslation), args.Error(1)
+}
+
+func (m *MockTranslationRepository) UpsertListingTranslation(ctx context.Context, t *models.ListingTranslation) error {
+	args := m.Called(ctx, t)
+	return args.Error(0)
+}
+
+func TestTranslationService_LocalizeListing(t *testing.T) {
+	listing := &models.Listing{ID: "lst1", Title: "革のリュック", Description: "美品です"}
+	translatedAt := time.Date(2024, 5, 1, 12, 0, 0, 0, time.UTC)
+	fresh := &models.ListingTranslation{
+		ListingID: "lst1", Language: "en", Title: "Leather Backpack", Description: "In great condition",
+		SourceLanguage: "ja", SourceHash: listingSourceHash(listing), UpdatedAt: translatedAt,
+	}
+	stale := &models.ListingTranslation{
+		ListingID: "lst1", Language: "en", Title: "Old Title", Description: "Old",
+		SourceLanguage: "ja", SourceHash: "outdated",
+	}
+	sameLanguage := &models.ListingTranslation{
+		ListingID: "lst1", Language: "ja", Title: "革のリュック", Description: "美品です",
+		SourceLanguage: "ja", SourceHash: listingSourceHash(listing),
+	}
+
+	tests := []struct {
+		name         string
+		language     string
+		mockSetup    func(*MockTranslationRepository)
+		wantTitle    string
+		wantLanguage string
+		wantOriginal bool
+		wantPending  bool
+	}{
+		{
+			name:         "No Language",
+			language:     "",
+			mockSetup:    func(r *MockTranslationRepository) {},
+			wantTitle:    "革のリュック",
+			wantLanguage: "",
+			wantOriginal: false,
+			wantPending:  false,
+		},
+		{
+			name:     "Cache Hit",
+			language: "en",
+			mockSetup: func(r *MockTranslationRepository) {
+				r.On("GetListingTranslation", mock.Anything, "lst1", "en").Return(fresh, nil)
+			},
+			wantTitle:    "Leather Backpack",
+			wantLanguage: "en",
+			wantOriginal: true,
+			wantPending:  false,
+		},
+		{
+			name:     "Stale Cache Returns Original",
+			language: "en",
+			mockSetup: func(r *MockTranslationRepository) {
+				r.On("GetListingTranslation", mock.Anything, "lst1", "en").Return(stale, nil)
+			},
+			wantTitle:    "革のリュック",
+			wantLanguage: "",
+			wantOriginal: false,
+			wantPending:  true,
+		},
+		{
+			name:     "Cache Miss Returns Original",
+			language: "en",
+			mockSetup: func(r *MockTranslationRepository) {
+				r.On("GetListingTranslation", mock.Anything, "lst1", "en").Return(nil, nil)
+			},
+			wantTitle:    "革のリュック",
+			wantLanguage: "",
+			wantOriginal: false,
+			wantPending:  true,
+		},
+		{
+			name:     "Same Language Keeps Original",
+			language: "ja",
+			mockSetup: func(r *MockTranslationRepository) {
+				r.On("GetListingTranslation", mock.Anything, "lst1", "ja").Return(sameLanguage, nil)
+			},
+			wantTitle:    "革のリュック",
+			wantLanguage: "ja",
+			wantOriginal: false,
+			wantPending:  false,
+		},
+		{
+			name:     "Repository Error Falls Back To Original",
+			language: "en",
+			mockSetup: func(r *MockTranslationRepository) {
+				r.On("GetListingTranslation", mock.Anything, "lst1", "en").Return(nil, assert.AnError)
+			},
+			wantTitle:    "革のリュック",
+			wantLanguage: "",
+			wantOriginal: false,
+			wantPending:  false,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			repo := new(MockTranslationRepository)
+			client := new(MockVertexGenerativeClient)
+			tt.mockSetup(repo)
+
+			s := NewTranslationService(client, repo)
+			got, pending := s.LocalizeListing(context.Background(), listing, tt.language)
+
+			assert.Equal(t, tt.wantTitle, got.Title)
+			assert.Equal(t, tt.wantLanguage, got.Language)
+			assert.Equal(t, tt.wantPending, pending)
+			if tt.wantOriginal {
+				assert.Equal(t, &models.ListingText{Title: listing.Title, Description: listing.Description}, got.Original)
+				assert.Equal(t, translatedAt, got.TranslatedAt)
+			} else {
+				assert.Nil(t, got.Original)
+			}
+			// The stored listing is never modified
+			assert.Equal(t, "革のリュック", listing.Title)
+			repo.AssertExpectations(t)
+			// The AI is never called while serving a request
+			client.AssertNotCalled(t, "GenerateContent", mock.Anything, mock.Anything, mock.Anything, mock.Anything)
+		})
+	}
+}
+
+func TestTranslationService_TranslateListing(t *testing.T) {
+	listing := &models.Listing{ID: "lst1", Title: "革のリュック", Description: "美品です"}
+	stale := &models.ListingTranslation{
+		ListingID: "lst1", Language: "en", Title: "Old Title", Description: "Old",
+		SourceLanguage: "ja", SourceHash: "outdated",
+	}
+
+	tests := []struct {
+		name      string
+		mockSetup func(*MockTranslationRepository, *MockVertexGenerativeClient)
+	}{
+		{
+			name: "Stale Cache Is Regenerated",
+			mockSetup: func(r *MockTranslationRepository, v *MockVertexGenerativeClient) {
+				r.On("GetListingTranslation", mock.Anything, "lst1", "en").Return(stale, nil)
+				v.On("GenerateContent", mock.Anything, "gemini-2.0-flash-lite", mock.Anything, mock.Anything).
+					Return(`{"translated_title": "Leather Backpack", "translated_description": "In great condition", "detected_source_language": "JA"}`, nil)
+				r.On("UpsertListingTranslation", mock.Anything, mock.MatchedBy(func(tr *models.ListingTranslation) bool {
+					return tr.SourceHash == listingSourceHash(listing) && tr.SourceLanguage == "ja" && tr.Language == "en"
+				})).Return(nil)
+			},
+		},
+		{
+			name: "AI Error Stores Nothing",
+			mockSetup: func(r *MockTranslationRepository, v *MockVertexGenerativeClient) {
+				r.On("GetListingTranslation", mock.Anything, "lst1", "en").Return(nil, nil)
+				v.On("GenerateContent", mock.Anything, mock.Anything, mock.Anything, mock.Anything).Return("", assert.AnError)
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			repo := new(MockTranslationRepository)
+			client := new(MockVertexGenerativeClient)
+			tt.mockSetup(repo, client)
+
+			s := NewTranslationService(client, repo)
+			s.TranslateListing(context.Background(), listing, "en")
+
+			repo.AssertExpectations(t)
+			client.AssertExpectations(t)
+			assert.Empty(t, s.inflight)
+		})
+	}
+}
+
+func TestTranslationService_LocalizeListings(t *testing.T) {
+	l1 := &models.Listing{ID: "lst1", Title: "時計", Description: "動作品"}
+	l2 := &models.Listing{ID: "lst2", Title: "カメラ", Description: "中古"}
+	cached := map[string]*models.ListingTranslation{
+		"lst1": {ListingID: "lst1", Language: "en", Title: "Watch", Description: "Works", SourceLanguage: "ja", SourceHash: listingSourceHash(l1)},
+	}
+
+	repo := new(MockTranslationRepository)
+	client := new(MockVertexGenerativeClient)
+	repo.On("GetListingTranslations", mock.Anything, []string{"lst1", "lst2"}, "en").Return(cached, nil)
+
+	s := NewTranslationService(client, repo)
+	got := s.LocalizeListings(context.Background(), []*models.Listing{l1, l2}, "en")
+
+	assert.Len(t, got, 2)
+	assert.Equal(t, "Watch", got[0].Title)
+	assert.Equal(t, "時計", got[0].Original.Title)
+	assert.Equal(t, "カメラ", got[1].Title)
+	assert.Nil(t, got[1].Original)
+	// The feed never calls the AI
+	client.AssertNotCalled(t, "GenerateContent", mock.Anything, mock.Anything, mock.Anything, mock.Anything)
+	repo.AssertExpectations(t)
+}
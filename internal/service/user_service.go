@@ -19,6 +19,8 @@ type UserRepository interface {
 	GetUser(ctx context.Context, id string) (*models.User, error)
 	GetUserProfile(ctx context.Context, id string) (*models.UserProfile, error)
 	CreateUser(ctx context.Context, user *models.User) error
+	GetUserStatus(ctx context.Context, id string) (models.UserStatus, error)
+	UpdateInvoiceRegistrationNumber(ctx context.Context, id, number string) error
 }
 
 type FirebaseRepository interface {
@@ -56,6 +58,24 @@ func (s *UserService) VerifyToken(ctx context.Context, idToken string) (string,
 	return s.firebaseAuth.VerifyIDToken(ctx, idToken)
 }
 
+// IsSuspended reports whether the account is suspended. Users without an account yet are not.
+func (s *UserService) IsSuspended(ctx context.Context, userID string) (bool, error) {
+	status, err := s.repo.GetUserStatus(ctx, userID)
+	if err != nil {
+		return false, err
+	}
+	return status == models.UserStatusSuspended, nil
+}
+
+// IsAdmin reports whether the user has the admin role.
+func (s *UserService) IsAdmin(ctx context.Context, userID string) (bool, error) {
+	u, err := s.repo.GetUser(ctx, userID)
+	if err != nil {
+		return false, err
+	}
+	return u.Role == models.UserRoleAdmin, nil
+}
+
 // GetUser returns the user from DB by ID.
 func (s *UserService) GetUser(ctx context.Context, id string) (*models.User, error) {
 	return s.repo.GetUser(ctx, id)
@@ -71,3 +91,15 @@ func (s *UserService) GetUserProfile(ctx context.Context, id string) (*models.Us
 	}
 	return p, nil
 }
+
+// SetInvoiceRegistrationNumber registers the user as a qualified invoice issuer, or clears the
+// registration when number is empty. Orders placed afterwards carry the new number.
+func (s *UserService) SetInvoiceRegistrationNumber(ctx context.Context, userID, number string) (*models.User, error) {
+	if number != "" && !validInvoiceRegistrationNumber(number) {
+		return nil, ErrInvalidInvoiceRegistrationNumber
+	}
+	if err := s.repo.UpdateInvoiceRegistrationNumber(ctx, userID, number); err != nil {
+		return nil, err
+	}
+	return s.repo.GetUser(ctx, userID)
+}
@@ -36,6 +36,16 @@ func (m *MockUserRepository) CreateUser(ctx context.Context, user *models.User)
 	return args.Error(0)
 }
 
+func (m *MockUserRepository) GetUserStatus(ctx context.Context, id string) (models.UserStatus, error) {
+	args := m.Called(ctx, id)
+	return args.Get(0).(models.UserStatus), args.Error(1)
+}
+
+func (m *MockUserRepository) UpdateInvoiceRegistrationNumber(ctx context.Context, id, number string) error {
+	args := m.Called(ctx, id, number)
+	return args.Error(0)
+}
+
 type MockFirebaseRepository struct {
 	mock.Mock
 }
@@ -207,3 +217,115 @@ func TestUserService_GetUserProfile(t *testing.T) {
 		})
 	}
 }
+
+func TestUserService_VerifyToken(t *testing.T) {
+	tests := []struct {
+		name      string
+		mockSetup func(*MockUserRepository, *MockFirebaseRepository)
+		want      string
+		wantErr   bool
+		errType   error
+	}{
+		{
+			name: "Success",
+			mockSetup: func(userRepo *MockUserRepository, fbRepo *MockFirebaseRepository) {
+				fbRepo.On("VerifyIDToken", mock.Anything, "token").Return("uid123", nil)
+			},
+			want:    "uid123",
+			wantErr: false,
+		},
+		{
+			name: "Invalid token",
+			mockSetup: func(userRepo *MockUserRepository, fbRepo *MockFirebaseRepository) {
+				fbRepo.On("VerifyIDToken", mock.Anything, "token").Return("", assert.AnError)
+			},
+			wantErr: true,
+			errType: assert.AnError,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			userRepo := new(MockUserRepository)
+			fbRepo := new(MockFirebaseRepository)
+			tt.mockSetup(userRepo, fbRepo)
+
+			s := NewUserService(userRepo, fbRepo)
+			got, err := s.VerifyToken(context.Background(), "token")
+
+			if tt.wantErr {
+				assert.Error(t, err)
+				if tt.errType != nil {
+					assert.Equal(t, tt.errType, err)
+				}
+			} else {
+				assert.NoError(t, err)
+				assert.Equal(t, tt.want, got)
+			}
+			userRepo.AssertExpectations(t)
+			fbRepo.AssertExpectations(t)
+		})
+	}
+}
+
+func TestUserService_IsSuspended(t *testing.T) {
+	tests := []struct {
+		name    string
+		status  models.UserStatus
+		err     error
+		want    bool
+		wantErr error
+	}{
+		{name: "Active", status: models.UserStatusActive, want: false},
+		{name: "Suspended", status: models.UserStatusSuspended, want: true},
+		{name: "No Account Yet", status: "", want: false},
+		{name: "Repo Error", err: assert.AnError, wantErr: assert.AnError},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			userRepo := new(MockUserRepository)
+			userRepo.On("GetUserStatus", mock.Anything, "uid123").Return(tt.status, tt.err)
+
+			s := NewUserService(userRepo, new(MockFirebaseRepository))
+			got, err := s.IsSuspended(context.Background(), "uid123")
+
+			assert.Equal(t, tt.wantErr, err)
+			assert.Equal(t, tt.want, got)
+		})
+	}
+}
+
+func TestUserService_SetInvoiceRegistrationNumber(t *testing.T) {
+	tests := []struct {
+		name    string
+		number  string
+		wantErr error
+	}{
+		{name: "Register", number: "T7000012050002"},
+		{name: "Clear", number: ""},
+		{name: "Invalid Check Digit", number: "T1000012050002", wantErr: ErrInvalidInvoiceRegistrationNumber},
+		{name: "Missing Prefix", number: "7000012050002", wantErr: ErrInvalidInvoiceRegistrationNumber},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			userRepo := new(MockUserRepository)
+			if tt.wantErr == nil {
+				userRepo.On("UpdateInvoiceRegistrationNumber", mock.Anything, "uid123", tt.number).Return(nil)
+				userRepo.On("GetUser", mock.Anything, "uid123").Return(&models.User{ID: "uid123", InvoiceRegistrationNumber: tt.number}, nil)
+			}
+
+			s := NewUserService(userRepo, new(MockFirebaseRepository))
+			got, err := s.SetInvoiceRegistrationNumber(context.Background(), "uid123", tt.number)
+
+			if tt.wantErr != nil {
+				assert.Equal(t, tt.wantErr, err)
+			} else {
+				assert.NoError(t, err)
+				assert.Equal(t, tt.number, got.InvoiceRegistrationNumber)
+			}
+			userRepo.AssertExpectations(t)
+		})
+	}
+}
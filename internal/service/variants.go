@@ -0,0 +1,74 @@
+package service
+
+import (
+	"errors"
+	"strings"
+	"unicode/utf8"
+	"uttc-hackathon-backend/internal/models"
+
+	"github.com/oklog/ulid/v2"
+)
+
+const (
+	MaxListingVariants = 20
+	MaxVariantLabel    = 50
+)
+
+var (
+	ErrTooManyVariants        = errors.New("a listing can have at most 20 variants")
+	ErrVariantLabelRequired   = errors.New("variant label is required")
+	ErrVariantLabelLong       = errors.New("variant label must be 50 characters or fewer")
+	ErrDuplicateVariantLabel  = errors.New("variant labels must be unique")
+	ErrVariantPriceInvalid    = errors.New("variant price must be at least 100")
+	ErrVariantQuantityInvalid = errors.New("variant quantity must not be negative")
+	ErrVariantRequired        = errors.New("variant_id is required for this listing")
+	ErrVariantNotFound        = errors.New("variant not found")
+)
+
+// prepareVariants validates the listing's variants, assigns their IDs and sets the listing
+// quantity to the total variant stock. Listings without variants are left untouched.
+func prepareVariants(l *models.Listing) error {
+	if len(l.Variants) == 0 {
+		return nil
+	}
+	if len(l.Variants) > MaxListingVariants {
+		return ErrTooManyVariants
+	}
+
+	seen := make(map[string]bool, len(l.Variants))
+	total := 0
+	for i := range l.Variants {
+		v := &l.Variants[i]
+		v.Label = strings.TrimSpace(v.Label)
+		if v.Label == "" {
+			return ErrVariantLabelRequired
+		}
+		if utf8.RuneCountInString(v.Label) > MaxVariantLabel {
+			return ErrVariantLabelLong
+		}
+		if seen[v.Label] {
+			return ErrDuplicateVariantLabel
+		}
+		seen[v.Label] = true
+		if v.Price != 0 && v.Price < MinListingPrice {
+			return ErrVariantPriceInvalid
+		}
+		if v.Quantity < 0 {
+			return ErrVariantQuantityInvalid
+		}
+		v.ID = "var_" + ulid.Make().String()
+		total += v.Quantity
+	}
+	l.Quantity = total
+	return nil
+}
+
+// findVariant returns the variant with the given ID, or nil.
+func findVariant(l *models.Listing, id string) *models.ListingVariant {
+	for i := range l.Variants {
+		if l.Variants[i].ID == id {
+			return &l.Variants[i]
+		}
+	}
+	return nil
+}
@@ -7,11 +7,14 @@ import (
 	"net/http"
 	"os"
 	"os/signal"
+	"strconv"
 	"syscall"
 	"time"
 	"uttc-hackathon-backend/internal/app"
 	"uttc-hackathon-backend/internal/client"
 	"uttc-hackathon-backend/internal/middleware"
+	"uttc-hackathon-backend/internal/repository"
+	"uttc-hackathon-backend/internal/service"
 )
 
 func main() {
@@ -26,6 +29,29 @@ func main() {
 	googleCredentials := os.Getenv("GOOGLE_CREDENTIALS_JSON")
 	gcpProjectID := os.Getenv("GOOGLE_CLOUD_PROJECT")
 	gcpLocation := os.Getenv("GOOGLE_CLOUD_LOCATION")
+	siteURL := os.Getenv("SITE_URL") // frontend origin used in sitemaps and feeds
+	paymentWebhookSecret := os.Getenv("PAYMENT_WEBHOOK_SECRET")
+	if paymentWebhookSecret == "" {
+		log.Fatal("PAYMENT_WEBHOOK_SECRET must be set to verify payment webhooks")
+	}
+	// No payment processor is integrated yet; the in-memory fake must be opted into explicitly
+	var paymentProvider service.PaymentProvider
+	switch v := os.Getenv("PAYMENT_PROVIDER"); v {
+	case "fake":
+		log.Println("Using the fake payment provider; payments are not charged")
+		paymentProvider = repository.NewFakePaymentProvider(paymentWebhookSecret)
+	default:
+		log.Fatalf("PAYMENT_PROVIDER must name a supported provider (only \"fake\" for development and tests), got %q", v)
+	}
+	// Days a delivered order waits for the buyer before it is completed automatically
+	autoCompleteAfter := service.DefaultAutoCompleteAfter
+	if v := os.Getenv("ORDER_AUTO_COMPLETE_DAYS"); v != "" {
+		days, err := strconv.Atoi(v)
+		if err != nil || days < 1 {
+			log.Fatalf("ORDER_AUTO_COMPLETE_DAYS must be a positive number of days, got %q", v)
+		}
+		autoCompleteAfter = time.Duration(days) * 24 * time.Hour
+	}
 
 	db := client.InitDB(mysqlUser, mysqlUserPwd, mysqlDatabase, mysqlHost, mysqlConnectionParms)
 	defer func() {
@@ -36,9 +62,14 @@ func main() {
 	fbAuth := client.InitFirebaseAuth(googleCredentials)
 	vertexClient := client.InitVertexAI(gcpProjectID, gcpLocation, googleCredentials)
 
-	routes := app.NewApp(db, fbAuth, vertexClient).Routes()
+	a := app.NewApp(db, fbAuth, vertexClient, siteURL, paymentProvider, autoCompleteAfter)
+	routes := a.Routes()
 	handlerWithCors := middleware.CorsMiddleware(routes, corsAllowOrigin)
 
+	jobsCtx, stopJobs := context.WithCancel(context.Background())
+	defer stopJobs()
+	a.RunBackgroundJobs(jobsCtx)
+
 	srv := &http.Server{Addr: ":8080", Handler: handlerWithCors}
 	go func() {
 		log.Println("Starting server on :8080")
@@ -51,6 +82,7 @@ func main() {
 	signal.Notify(quit, syscall.SIGINT, syscall.SIGTERM)
 	<-quit // BLOCKING WAIT
 	log.Println("Shutting down server...")
+	stopJobs()
 
 	// Graceful Shutdown
 	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
@@ -59,6 +91,10 @@ func main() {
 	if err := srv.Shutdown(ctx); err != nil {
 		log.Fatal("Server forced to shutdown:", err)
 	}
+	// Let running jobs finish their current order before the DB is closed
+	if err := a.WaitBackgroundJobs(ctx); err != nil {
+		log.Printf("Background jobs did not stop in time: %v", err)
+	}
 
 	log.Println("Server exiting")
 }
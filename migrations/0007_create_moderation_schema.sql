@@ -0,0 +1,45 @@
+-- Reports, moderation queue and admin actions
+-- Dialect: MySQL (InnoDB, utf8mb4)
+
+ALTER TABLE users
+    ADD role   ENUM ('user', 'admin')       NOT NULL DEFAULT 'user',
+    ADD status ENUM ('active', 'suspended') NOT NULL DEFAULT 'active';
+
+-- 'removed' is set when an admin takes a listing down
+ALTER TABLE listings
+    MODIFY COLUMN status ENUM ('draft','active','sold','removed') NOT NULL;
+
+CREATE TABLE reports
+(
+    id          CHAR(30)                                  NOT NULL PRIMARY KEY,
+    reporter_id VARCHAR(128)                              NOT NULL,
+    target_type ENUM ('listing', 'user')                  NOT NULL,
+    target_id   VARCHAR(128)                              NOT NULL, -- listings.id or users.id
+    reason      ENUM (
+        'scam',
+        'prohibited_item',
+        'counterfeit',
+        'inappropriate',
+        'spam',
+        'harassment',
+        'other'
+        )                                                 NOT NULL,
+    comment     TEXT                                      NOT NULL,
+    status      ENUM ('open','in_review','actioned','dismissed') NOT NULL DEFAULT 'open',
+    handled_by  VARCHAR(128)                              NULL,
+    created_at  TIMESTAMP                                 NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    updated_at  TIMESTAMP                                 NOT NULL DEFAULT CURRENT_TIMESTAMP ON UPDATE CURRENT_TIMESTAMP,
+
+    CONSTRAINT chk_reports_id CHECK (id LIKE 'rpt_%'),
+    CONSTRAINT fk_reports_reporter FOREIGN KEY (reporter_id) REFERENCES users (id)
+        ON UPDATE RESTRICT ON DELETE RESTRICT,
+    CONSTRAINT fk_reports_handler FOREIGN KEY (handled_by) REFERENCES users (id)
+        ON UPDATE RESTRICT ON DELETE RESTRICT,
+    -- One report per reporter and target
+    UNIQUE INDEX uq_reports_reporter_target (reporter_id, target_type, target_id),
+    INDEX idx_reports_status (status, created_at),
+    INDEX idx_reports_target (target_type, target_id),
+    INDEX idx_reports_reporter_created (reporter_id, created_at)
+) ENGINE = InnoDB
+  DEFAULT CHARSET = utf8mb4
+  COLLATE = utf8mb4_unicode_ci;
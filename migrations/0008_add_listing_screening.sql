@@ -0,0 +1,24 @@
+-- Prohibited-content screening for new listings
+-- Dialect: MySQL (InnoDB, utf8mb4)
+
+-- 'pending_review' holds listings flagged by screening until an admin approves them
+ALTER TABLE listings
+    MODIFY COLUMN status ENUM ('draft','active','sold','removed','pending_review') NOT NULL;
+
+CREATE TABLE listing_screenings
+(
+    id         CHAR(30)                               NOT NULL PRIMARY KEY,
+    listing_id CHAR(30)                               NOT NULL,
+    flagged    BOOLEAN                                NOT NULL,
+    source     ENUM ('keyword', 'ai', 'unavailable') NOT NULL,
+    categories JSON                                   NOT NULL,
+    reason     TEXT                                   NOT NULL,
+    created_at TIMESTAMP                              NOT NULL DEFAULT CURRENT_TIMESTAMP,
+
+    CONSTRAINT chk_listing_screenings_id CHECK (id LIKE 'scr_%'),
+    CONSTRAINT fk_listing_screenings_listing FOREIGN KEY (listing_id) REFERENCES listings (id)
+        ON UPDATE CASCADE ON DELETE CASCADE,
+    INDEX idx_listing_screenings_listing (listing_id, created_at)
+) ENGINE = InnoDB
+  DEFAULT CHARSET = utf8mb4
+  COLLATE = utf8mb4_unicode_ci;
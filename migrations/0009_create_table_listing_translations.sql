@@ -0,0 +1,20 @@
+-- Cached listing translations
+-- Dialect: MySQL (InnoDB, utf8mb4)
+
+CREATE TABLE listing_translations
+(
+    listing_id      CHAR(30)     NOT NULL,
+    language        CHAR(2)      NOT NULL, -- ISO 639-1, lower case
+    title           VARCHAR(400) NOT NULL,
+    description     TEXT         NOT NULL,
+    source_language CHAR(2)      NOT NULL,
+    source_hash     CHAR(64)     NOT NULL, -- SHA-256 of the original title and description
+    created_at      TIMESTAMP    NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    updated_at      TIMESTAMP    NOT NULL DEFAULT CURRENT_TIMESTAMP ON UPDATE CURRENT_TIMESTAMP,
+
+    PRIMARY KEY (listing_id, language),
+    CONSTRAINT fk_listing_translations_listing FOREIGN KEY (listing_id) REFERENCES listings (id)
+        ON UPDATE CASCADE ON DELETE CASCADE
+) ENGINE = InnoDB
+  DEFAULT CHARSET = utf8mb4
+  COLLATE = utf8mb4_unicode_ci;
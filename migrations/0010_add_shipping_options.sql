@@ -0,0 +1,20 @@
+-- Shipping options on listings and shipping fee on orders
+-- Dialect: MySQL (InnoDB, utf8mb4)
+
+ALTER TABLE listings
+    ADD shipping_payer       ENUM ('seller', 'buyer')                             NOT NULL DEFAULT 'seller' AFTER item_condition,
+    ADD shipping_method      ENUM ('nekopos', 'yu_packet', 'takkyubin', 'yu_pack') NULL AFTER shipping_payer,
+    ADD shipping_size        ENUM ('small', '60', '80', '100', '120', '140', '160') NULL AFTER shipping_method,
+    ADD ship_from_prefecture TINYINT UNSIGNED                                     NULL AFTER shipping_size, -- JIS X 0401
+    ADD days_to_ship         ENUM ('1_2', '2_3', '4_7')                           NULL AFTER ship_from_prefecture,
+    ADD CONSTRAINT chk_listings_prefecture CHECK (ship_from_prefecture BETWEEN 1 AND 47),
+    -- Buyers can only pay shipping if there is a method and size to price it
+    ADD CONSTRAINT chk_listings_buyer_shipping CHECK (
+        shipping_payer = 'seller' OR (shipping_method IS NOT NULL AND shipping_size IS NOT NULL)
+        );
+
+ALTER TABLE orders
+    ADD shipping_payer  ENUM ('seller', 'buyer')                             NOT NULL DEFAULT 'seller' AFTER quantity,
+    ADD shipping_method ENUM ('nekopos', 'yu_packet', 'takkyubin', 'yu_pack') NULL AFTER shipping_payer,
+    ADD shipping_size   ENUM ('small', '60', '80', '100', '120', '140', '160') NULL AFTER shipping_method,
+    ADD shipping_fee    INT UNSIGNED                                         NOT NULL DEFAULT 0 AFTER shipping_size;
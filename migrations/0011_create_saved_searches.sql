@@ -0,0 +1,73 @@
+-- Saved searches, their inverted term index, and user notifications
+-- Dialect: MySQL (InnoDB, utf8mb4)
+
+CREATE TABLE saved_searches
+(
+    id             CHAR(30)                                             NOT NULL PRIMARY KEY,
+    user_id        VARCHAR(128)                                         NOT NULL,
+    query          VARCHAR(100)                                         NOT NULL,
+    term_count     SMALLINT UNSIGNED                                    NOT NULL, -- rows in saved_search_terms
+    min_price      INT UNSIGNED                                         NULL,
+    max_price      INT UNSIGNED                                         NULL,
+    item_condition ENUM ('new', 'excellent', 'good', 'not_good', 'bad') NULL,
+    created_at     TIMESTAMP                                            NOT NULL DEFAULT CURRENT_TIMESTAMP,
+
+    CONSTRAINT chk_saved_searches_id CHECK (id LIKE 'srh_%'),
+    CONSTRAINT chk_saved_searches_price CHECK (min_price IS NULL OR max_price IS NULL OR min_price <= max_price),
+    CONSTRAINT fk_saved_searches_user FOREIGN KEY (user_id) REFERENCES users (id)
+        ON UPDATE CASCADE ON DELETE CASCADE,
+    INDEX idx_saved_searches_user (user_id, created_at)
+) ENGINE = InnoDB
+  DEFAULT CHARSET = utf8mb4
+  COLLATE = utf8mb4_unicode_ci;
+
+-- Inverted index: a listing's terms find candidate searches without scanning every saved search
+CREATE TABLE saved_search_terms
+(
+    term            VARCHAR(64) NOT NULL,
+    saved_search_id CHAR(30)    NOT NULL,
+
+    PRIMARY KEY (term, saved_search_id),
+    CONSTRAINT fk_saved_search_terms_search FOREIGN KEY (saved_search_id) REFERENCES saved_searches (id)
+        ON UPDATE CASCADE ON DELETE CASCADE
+) ENGINE = InnoDB
+  DEFAULT CHARSET = utf8mb4
+  COLLATE = utf8mb4_bin;
+
+CREATE TABLE notifications
+(
+    id         CHAR(30)                       NOT NULL PRIMARY KEY,
+    user_id    VARCHAR(128)                   NOT NULL,
+    type       ENUM ('saved_search_digest')   NOT NULL,
+    payload    JSON                           NOT NULL,
+    read_at    TIMESTAMP                      NULL,
+    created_at TIMESTAMP                      NOT NULL DEFAULT CURRENT_TIMESTAMP,
+
+    CONSTRAINT chk_notifications_id CHECK (id LIKE 'ntf_%'),
+    CONSTRAINT fk_notifications_user FOREIGN KEY (user_id) REFERENCES users (id)
+        ON UPDATE CASCADE ON DELETE CASCADE,
+    INDEX idx_notifications_user (user_id, type, created_at)
+) ENGINE = InnoDB
+  DEFAULT CHARSET = utf8mb4
+  COLLATE = utf8mb4_unicode_ci;
+
+-- Matches waiting for (or included in) a digest notification
+CREATE TABLE saved_search_matches
+(
+    saved_search_id CHAR(30)     NOT NULL,
+    listing_id      CHAR(30)     NOT NULL,
+    user_id         VARCHAR(128) NOT NULL,
+    notification_id CHAR(30)     NULL, -- NULL until sent in a digest
+    created_at      TIMESTAMP    NOT NULL DEFAULT CURRENT_TIMESTAMP,
+
+    PRIMARY KEY (saved_search_id, listing_id),
+    CONSTRAINT fk_saved_search_matches_search FOREIGN KEY (saved_search_id) REFERENCES saved_searches (id)
+        ON UPDATE CASCADE ON DELETE CASCADE,
+    CONSTRAINT fk_saved_search_matches_listing FOREIGN KEY (listing_id) REFERENCES listings (id)
+        ON UPDATE CASCADE ON DELETE CASCADE,
+    CONSTRAINT fk_saved_search_matches_notification FOREIGN KEY (notification_id) REFERENCES notifications (id)
+        ON UPDATE CASCADE ON DELETE SET NULL,
+    INDEX idx_saved_search_matches_pending (user_id, notification_id)
+) ENGINE = InnoDB
+  DEFAULT CHARSET = utf8mb4
+  COLLATE = utf8mb4_unicode_ci;
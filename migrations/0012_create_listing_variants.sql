@@ -0,0 +1,25 @@
+-- Listing variants (size/color SKUs) with their own stock
+-- Dialect: MySQL (InnoDB, utf8mb4)
+
+CREATE TABLE listing_variants
+(
+    id         CHAR(30)         NOT NULL PRIMARY KEY,
+    listing_id CHAR(30)         NOT NULL,
+    label      VARCHAR(50)      NOT NULL,
+    price      INT UNSIGNED     NULL, -- overrides listings.price when set
+    quantity   INT UNSIGNED     NOT NULL,
+    position   TINYINT UNSIGNED NOT NULL, -- display order
+
+    CONSTRAINT chk_listing_variants_id CHECK (id LIKE 'var_%'),
+    CONSTRAINT fk_listing_variants_listing FOREIGN KEY (listing_id) REFERENCES listings (id)
+        ON UPDATE CASCADE ON DELETE CASCADE,
+    UNIQUE KEY uq_listing_variants_label (listing_id, label),
+    INDEX idx_listing_variants_listing (listing_id, position)
+) ENGINE = InnoDB
+  DEFAULT CHARSET = utf8mb4
+  COLLATE = utf8mb4_unicode_ci;
+
+-- Orders snapshot the variant they were placed for
+ALTER TABLE orders
+    ADD variant_id    CHAR(30)    NULL AFTER listing_id,
+    ADD variant_label VARCHAR(50) NULL AFTER variant_id;
@@ -0,0 +1,5 @@
+-- Optimistic locking for order status changes
+-- Dialect: MySQL (InnoDB, utf8mb4)
+
+ALTER TABLE orders
+    ADD version INT UNSIGNED NOT NULL DEFAULT 0 AFTER status; -- incremented on every status change
@@ -0,0 +1,12 @@
+-- Who cancelled an order and why
+-- Dialect: MySQL (InnoDB, utf8mb4)
+
+ALTER TABLE orders
+    ADD cancelled_by  VARCHAR(128) NULL AFTER version,
+    ADD cancel_reason ENUM ('changed_mind', 'out_of_stock', 'buyer_unresponsive', 'returned_to_sender',
+                            'lost_in_transit', 'other')                        NULL AFTER cancelled_by,
+    ADD cancel_note   VARCHAR(500) NULL AFTER cancel_reason,
+    ADD cancelled_at  TIMESTAMP    NULL AFTER cancel_note,
+    ADD CONSTRAINT chk_orders_cancellation CHECK (
+        (status = 'cancelled') = (cancelled_by IS NOT NULL AND cancel_reason IS NOT NULL AND cancelled_at IS NOT NULL)
+        );
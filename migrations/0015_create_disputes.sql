@@ -0,0 +1,58 @@
+-- Order disputes and their timeline
+-- Dialect: MySQL (InnoDB, utf8mb4)
+
+CREATE TABLE disputes
+(
+    id              CHAR(30)                                                                      NOT NULL PRIMARY KEY,
+    order_id        CHAR(30)                                                                      NOT NULL,
+    buyer_id        VARCHAR(128)                                                                  NOT NULL,
+    seller_id       VARCHAR(128)                                                                  NOT NULL,
+    reason          ENUM ('not_received', 'not_as_described', 'damaged', 'counterfeit', 'other') NOT NULL,
+    status          ENUM ('awaiting_seller', 'awaiting_admin', 'resolved')                        NOT NULL DEFAULT 'awaiting_seller',
+    escalated       BOOLEAN                                                                       NOT NULL DEFAULT FALSE, -- the seller missed respond_by
+    respond_by      TIMESTAMP                                                                     NULL,                   -- seller deadline while awaiting_seller
+    outcome         ENUM ('full_refund', 'partial_refund', 'release_to_seller')                  NULL,
+    refund_amount   INT UNSIGNED                                                                  NULL,
+    resolution_note VARCHAR(1000)                                                                 NULL,
+    resolved_by     VARCHAR(128)                                                                  NULL,
+    resolved_at     TIMESTAMP                                                                     NULL,
+    created_at      TIMESTAMP                                                                     NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    updated_at      TIMESTAMP                                                                     NOT NULL DEFAULT CURRENT_TIMESTAMP ON UPDATE CURRENT_TIMESTAMP,
+
+    CONSTRAINT chk_disputes_id CHECK (id LIKE 'dsp_%'),
+    CONSTRAINT chk_disputes_deadline CHECK ((status = 'awaiting_seller') = (respond_by IS NOT NULL)),
+    CONSTRAINT chk_disputes_resolution CHECK (
+        (status = 'resolved') = (outcome IS NOT NULL AND resolved_by IS NOT NULL AND resolved_at IS NOT NULL)
+        ),
+    CONSTRAINT uq_disputes_order UNIQUE (order_id),
+    CONSTRAINT fk_disputes_order FOREIGN KEY (order_id) REFERENCES orders (id)
+        ON UPDATE CASCADE ON DELETE CASCADE,
+    INDEX idx_disputes_queue (status, escalated, created_at),
+    INDEX idx_disputes_deadline (status, respond_by)
+) ENGINE = InnoDB
+  DEFAULT CHARSET = utf8mb4
+  COLLATE = utf8mb4_unicode_ci;
+
+-- Every step of a dispute, with who took it; actor_id is NULL for automatic escalation
+CREATE TABLE dispute_events
+(
+    id         CHAR(30)                                                       NOT NULL PRIMARY KEY,
+    dispute_id CHAR(30)                                                       NOT NULL,
+    actor_id   VARCHAR(128)                                                   NULL,
+    type       ENUM ('opened', 'seller_responded', 'escalated', 'resolved')  NOT NULL,
+    message    TEXT                                                           NOT NULL,
+    images     JSON                                                           NOT NULL, -- evidence image URLs
+    created_at TIMESTAMP                                                      NOT NULL DEFAULT CURRENT_TIMESTAMP,
+
+    CONSTRAINT chk_dispute_events_id CHECK (id LIKE 'dev_%'),
+    CONSTRAINT fk_dispute_events_dispute FOREIGN KEY (dispute_id) REFERENCES disputes (id)
+        ON UPDATE CASCADE ON DELETE CASCADE,
+    INDEX idx_dispute_events_dispute (dispute_id, created_at)
+) ENGINE = InnoDB
+  DEFAULT CHARSET = utf8mb4
+  COLLATE = utf8mb4_unicode_ci;
+
+-- A dispute resolved with a full refund cancels the order
+ALTER TABLE orders
+    MODIFY cancel_reason ENUM ('changed_mind', 'out_of_stock', 'buyer_unresponsive', 'returned_to_sender',
+                               'lost_in_transit', 'other', 'dispute_refunded') NULL;
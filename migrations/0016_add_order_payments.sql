@@ -0,0 +1,20 @@
+-- Orders wait for the payment provider before they are paid
+-- Dialect: MySQL (InnoDB, utf8mb4)
+
+ALTER TABLE orders
+    MODIFY COLUMN status ENUM (
+        'pending_payment',
+        'paid',
+        'shipped',
+        'delivered',
+        'completed',
+        'cancelled',
+        'disputed'
+        ) NOT NULL,
+    MODIFY cancel_reason ENUM ('changed_mind', 'out_of_stock', 'buyer_unresponsive', 'returned_to_sender',
+                               'lost_in_transit', 'other', 'dispute_refunded',
+                               'payment_failed', 'payment_expired') NULL,
+    ADD payment_intent_id  VARCHAR(64) NULL AFTER version,
+    ADD payment_expires_at TIMESTAMP   NULL AFTER payment_intent_id, -- stock is released after this
+    ADD CONSTRAINT uq_orders_payment_intent UNIQUE (payment_intent_id),
+    ADD INDEX idx_orders_payment_expiry (status, payment_expires_at);
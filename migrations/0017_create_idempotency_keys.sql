@@ -0,0 +1,22 @@
+-- Stored responses for requests sent with an Idempotency-Key header
+-- Dialect: MySQL (InnoDB, utf8mb4)
+
+CREATE TABLE idempotency_keys
+(
+    user_id               VARCHAR(128)                     NOT NULL,
+    idem_key              VARCHAR(255)                     NOT NULL,
+    fingerprint           CHAR(64)                         NOT NULL, -- sha256 of method, path and body
+    status                ENUM ('in_flight', 'completed')  NOT NULL DEFAULT 'in_flight',
+    response_status       SMALLINT UNSIGNED                NULL,
+    response_content_type VARCHAR(100)                     NULL,
+    response_body         MEDIUMBLOB                       NULL,
+    created_at            TIMESTAMP                        NOT NULL DEFAULT CURRENT_TIMESTAMP,
+
+    PRIMARY KEY (user_id, idem_key),
+    CONSTRAINT chk_idempotency_keys_response CHECK ((status = 'completed') = (response_status IS NOT NULL)),
+    CONSTRAINT fk_idempotency_keys_user FOREIGN KEY (user_id) REFERENCES users (id)
+        ON UPDATE CASCADE ON DELETE CASCADE,
+    INDEX idx_idempotency_keys_created (created_at)
+) ENGINE = InnoDB
+  DEFAULT CHARSET = utf8mb4
+  COLLATE = utf8mb4_bin;
@@ -0,0 +1,129 @@
+-- Double-entry ledger of what the platform owes sellers, bank accounts and payouts
+-- Dialect: MySQL (InnoDB, utf8mb4)
+
+CREATE TABLE bank_accounts
+(
+    user_id        VARCHAR(128)                  NOT NULL PRIMARY KEY,
+    bank_code      CHAR(4)                       NOT NULL,
+    branch_code    CHAR(3)                       NOT NULL,
+    account_type   ENUM ('ordinary', 'current')  NOT NULL,
+    account_number CHAR(7)                       NOT NULL,
+    account_holder VARCHAR(100)                  NOT NULL,
+    updated_at     TIMESTAMP                     NOT NULL DEFAULT CURRENT_TIMESTAMP ON UPDATE CURRENT_TIMESTAMP,
+
+    CONSTRAINT fk_bank_accounts_user FOREIGN KEY (user_id) REFERENCES users (id)
+        ON UPDATE CASCADE ON DELETE CASCADE
+) ENGINE = InnoDB
+  DEFAULT CHARSET = utf8mb4
+  COLLATE = utf8mb4_unicode_ci;
+
+CREATE TABLE payouts
+(
+    id             CHAR(30)                               NOT NULL PRIMARY KEY,
+    user_id        VARCHAR(128)                           NOT NULL,
+    amount         INT UNSIGNED                           NOT NULL,
+    status         ENUM ('requested', 'paid', 'failed')   NOT NULL DEFAULT 'requested',
+    bank_code      CHAR(4)                                NOT NULL, -- bank account snapshot at request time
+    branch_code    CHAR(3)                                NOT NULL,
+    account_type   ENUM ('ordinary', 'current')           NOT NULL,
+    account_number CHAR(7)                                NOT NULL,
+    account_holder VARCHAR(100)                           NOT NULL,
+    processed_by   VARCHAR(128)                           NULL,
+    processed_at   TIMESTAMP                              NULL,
+    created_at     TIMESTAMP                              NOT NULL DEFAULT CURRENT_TIMESTAMP,
+
+    CONSTRAINT chk_payouts_id CHECK (id LIKE 'pyo_%'),
+    CONSTRAINT chk_payouts_amount CHECK (amount > 0),
+    CONSTRAINT chk_payouts_processed CHECK ((status = 'requested') = (processed_at IS NULL)),
+    CONSTRAINT fk_payouts_user FOREIGN KEY (user_id) REFERENCES users (id)
+        ON UPDATE CASCADE ON DELETE RESTRICT,
+    INDEX idx_payouts_user (user_id, created_at),
+    INDEX idx_payouts_status (status, created_at)
+) ENGINE = InnoDB
+  DEFAULT CHARSET = utf8mb4
+  COLLATE = utf8mb4_unicode_ci;
+
+CREATE TABLE ledger_transactions
+(
+    id         BIGINT UNSIGNED                                                       NOT NULL AUTO_INCREMENT PRIMARY KEY,
+    type       ENUM ('order_paid', 'order_completed', 'order_cancelled',
+                     'payout_requested', 'payout_paid', 'payout_failed')             NOT NULL,
+    order_id   CHAR(30)                                                              NULL,
+    payout_id  CHAR(30)                                                              NULL,
+    created_at TIMESTAMP                                                             NOT NULL DEFAULT CURRENT_TIMESTAMP,
+
+    -- Each order or payout event is posted once
+    CONSTRAINT uq_ledger_transactions_order UNIQUE (type, order_id),
+    CONSTRAINT uq_ledger_transactions_payout UNIQUE (type, payout_id),
+    CONSTRAINT chk_ledger_transactions_reference CHECK ((order_id IS NULL) <> (payout_id IS NULL)),
+    CONSTRAINT fk_ledger_transactions_order FOREIGN KEY (order_id) REFERENCES orders (id)
+        ON UPDATE CASCADE ON DELETE RESTRICT,
+    CONSTRAINT fk_ledger_transactions_payout FOREIGN KEY (payout_id) REFERENCES payouts (id)
+        ON UPDATE CASCADE ON DELETE RESTRICT
+) ENGINE = InnoDB
+  DEFAULT CHARSET = utf8mb4
+  COLLATE = utf8mb4_unicode_ci;
+
+-- Entries of a transaction sum to zero; balances are sums of entries per account
+CREATE TABLE ledger_entries
+(
+    id             BIGINT UNSIGNED                                                NOT NULL AUTO_INCREMENT PRIMARY KEY,
+    transaction_id BIGINT UNSIGNED                                                NOT NULL,
+    account        ENUM ('seller_pending', 'seller_available', 'seller_payout',
+                         'seller_paid_out', 'platform_clearing')                  NOT NULL,
+    user_id        VARCHAR(128)                                                   NULL,
+    amount         INT                                                            NOT NULL,
+    created_at     TIMESTAMP                                                      NOT NULL DEFAULT CURRENT_TIMESTAMP,
+
+    CONSTRAINT chk_ledger_entries_owner CHECK ((account = 'platform_clearing') = (user_id IS NULL)),
+    CONSTRAINT fk_ledger_entries_transaction FOREIGN KEY (transaction_id) REFERENCES ledger_transactions (id)
+        ON UPDATE CASCADE ON DELETE RESTRICT,
+    INDEX idx_ledger_entries_user (user_id, account),
+    INDEX idx_ledger_entries_user_created (user_id, created_at, id)
+) ENGINE = InnoDB
+  DEFAULT CHARSET = utf8mb4
+  COLLATE = utf8mb4_unicode_ci;
+
+-- Backfill: orders paid before the ledger existed are credited as pending, completed ones as
+-- available (less any partial refund from a dispute). Cancelled orders net to zero and are skipped.
+INSERT INTO ledger_transactions (type, order_id, created_at)
+SELECT 'order_paid', o.id, o.created_at
+FROM orders o
+WHERE o.status IN ('paid', 'shipped', 'delivered', 'completed', 'disputed');
+
+INSERT INTO ledger_entries (transaction_id, account, user_id, amount, created_at)
+SELECT t.id, 'seller_pending', o.seller_id, o.net_payout, t.created_at
+FROM ledger_transactions t
+         JOIN orders o ON o.id = t.order_id
+WHERE t.type = 'order_paid';
+
+INSERT INTO ledger_entries (transaction_id, account, user_id, amount, created_at)
+SELECT t.id, 'platform_clearing', NULL, -o.net_payout, t.created_at
+FROM ledger_transactions t
+         JOIN orders o ON o.id = t.order_id
+WHERE t.type = 'order_paid';
+
+INSERT INTO ledger_transactions (type, order_id, created_at)
+SELECT 'order_completed', o.id, o.updated_at
+FROM orders o
+WHERE o.status = 'completed';
+
+INSERT INTO ledger_entries (transaction_id, account, user_id, amount, created_at)
+SELECT t.id, 'seller_pending', o.seller_id, -o.net_payout, t.created_at
+FROM ledger_transactions t
+         JOIN orders o ON o.id = t.order_id
+WHERE t.type = 'order_completed';
+
+INSERT INTO ledger_entries (transaction_id, account, user_id, amount, created_at)
+SELECT t.id, 'seller_available', o.seller_id, o.net_payout - LEAST(COALESCE(d.refund_amount, 0), o.net_payout), t.created_at
+FROM ledger_transactions t
+         JOIN orders o ON o.id = t.order_id
+         LEFT JOIN disputes d ON d.order_id = o.id AND d.outcome = 'partial_refund'
+WHERE t.type = 'order_completed';
+
+INSERT INTO ledger_entries (transaction_id, account, user_id, amount, created_at)
+SELECT t.id, 'platform_clearing', NULL, LEAST(d.refund_amount, o.net_payout), t.created_at
+FROM ledger_transactions t
+         JOIN orders o ON o.id = t.order_id
+         JOIN disputes d ON d.order_id = o.id AND d.outcome = 'partial_refund'
+WHERE t.type = 'order_completed';
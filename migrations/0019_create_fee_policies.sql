@@ -0,0 +1,45 @@
+-- Platform fee schedule: per-category rates, promotions and seller tiers
+-- Dialect: MySQL (InnoDB, utf8mb4)
+
+-- Policies are never edited once orders reference them; to change a rate, add a new policy
+-- and deactivate the old one so past orders still explain their fee.
+CREATE TABLE fee_policies
+(
+    id          CHAR(30)                               NOT NULL PRIMARY KEY,
+    name        VARCHAR(100)                           NOT NULL,
+    category_id CHAR(30)                               NULL, -- also applies to subcategories
+    seller_tier ENUM ('standard', 'silver', 'gold')    NULL,
+    rate_bps    INT UNSIGNED                           NOT NULL, -- 1000 = 10%
+    min_fee     INT UNSIGNED                           NOT NULL DEFAULT 0,
+    max_fee     INT UNSIGNED                           NULL,
+    rounding    ENUM ('up', 'down', 'half_up')         NOT NULL DEFAULT 'up',
+    priority    INT                                    NOT NULL DEFAULT 0, -- highest matching policy wins
+    starts_at   TIMESTAMP                              NULL,
+    ends_at     TIMESTAMP                              NULL,
+    active      BOOLEAN                                NOT NULL DEFAULT TRUE,
+    created_at  TIMESTAMP                              NOT NULL DEFAULT CURRENT_TIMESTAMP,
+
+    CONSTRAINT chk_fee_policies_id CHECK (id LIKE 'fee_%'),
+    CONSTRAINT chk_fee_policies_rate CHECK (rate_bps <= 10000),
+    CONSTRAINT chk_fee_policies_caps CHECK (max_fee IS NULL OR max_fee >= min_fee),
+    CONSTRAINT chk_fee_policies_period CHECK (starts_at IS NULL OR ends_at IS NULL OR starts_at < ends_at),
+    CONSTRAINT fk_fee_policies_category FOREIGN KEY (category_id) REFERENCES categories (id)
+        ON UPDATE CASCADE ON DELETE RESTRICT
+) ENGINE = InnoDB
+  DEFAULT CHARSET = utf8mb4
+  COLLATE = utf8mb4_unicode_ci;
+
+-- The fee every order paid so far: 10% of the item subtotal, rounded up
+INSERT INTO fee_policies (id, name, rate_bps, rounding)
+VALUES ('fee_default', 'Standard 10%', 1000, 'up');
+
+ALTER TABLE users
+    ADD seller_tier ENUM ('standard', 'silver', 'gold') NOT NULL DEFAULT 'standard';
+
+ALTER TABLE orders
+    ADD fee_policy_id CHAR(30) NULL AFTER net_payout,
+    ADD CONSTRAINT fk_orders_fee_policy FOREIGN KEY (fee_policy_id) REFERENCES fee_policies (id)
+        ON UPDATE CASCADE ON DELETE RESTRICT;
+
+UPDATE orders
+SET fee_policy_id = 'fee_default';
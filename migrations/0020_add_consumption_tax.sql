@@ -0,0 +1,27 @@
+-- Consumption tax breakdown on orders and qualified invoice registration for sellers
+-- Dialect: MySQL (InnoDB, utf8mb4)
+
+-- 適格請求書発行事業者登録番号: "T" followed by the 13-digit corporate or personal number
+ALTER TABLE users
+    ADD invoice_registration_number CHAR(14) NULL,
+    ADD CONSTRAINT chk_users_invoice_registration_number CHECK (invoice_registration_number REGEXP '^T[0-9]{13}$');
+
+-- Prices stay tax-inclusive; reduced (8%) is for food, beverages and newspapers
+ALTER TABLE listings
+    ADD tax_rate ENUM ('standard', 'reduced') NOT NULL DEFAULT 'standard' AFTER price;
+
+ALTER TABLE orders
+    ADD item_tax_rate         ENUM ('standard', 'reduced') NOT NULL DEFAULT 'standard' AFTER listing_price,
+    ADD standard_rate_total   INT UNSIGNED                 NOT NULL DEFAULT 0 AFTER total_price, -- tax-inclusive
+    ADD standard_rate_tax     INT UNSIGNED                 NOT NULL DEFAULT 0 AFTER standard_rate_total,
+    ADD reduced_rate_total    INT UNSIGNED                 NOT NULL DEFAULT 0 AFTER standard_rate_tax,  -- tax-inclusive
+    ADD reduced_rate_tax      INT UNSIGNED                 NOT NULL DEFAULT 0 AFTER reduced_rate_total,
+    ADD seller_invoice_number CHAR(14)                     NULL AFTER seller_id; -- snapshot at purchase time
+
+-- Every order so far was taxed at the standard rate; tax is the included 10/110, rounded down
+UPDATE orders
+SET standard_rate_total = total_price,
+    standard_rate_tax   = FLOOR(total_price * 10 / 110);
+
+ALTER TABLE orders
+    ADD CONSTRAINT chk_orders_tax_totals CHECK (standard_rate_total + reduced_rate_total = total_price);
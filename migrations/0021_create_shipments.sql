@@ -0,0 +1,23 @@
+-- Shipments with carrier tracking numbers
+-- Dialect: MySQL (InnoDB, utf8mb4)
+
+-- One package per order; orders shipped without a tracking number have no shipment
+CREATE TABLE shipments
+(
+    order_id        CHAR(30)                                      NOT NULL PRIMARY KEY,
+    carrier         ENUM ('yamato', 'japan_post', 'sagawa')       NOT NULL,
+    tracking_number CHAR(12)                                      NOT NULL, -- digits only, without hyphens
+    tracking_status ENUM ('in_transit', 'delivered', 'returned')  NOT NULL DEFAULT 'in_transit',
+    shipped_at      TIMESTAMP                                     NOT NULL,
+    delivered_at    TIMESTAMP                                     NULL, -- as reported by the carrier
+    last_checked_at TIMESTAMP                                     NULL, -- last poll of the carrier
+
+    CONSTRAINT chk_shipments_tracking_number CHECK (tracking_number REGEXP '^[0-9]{12}$'),
+    CONSTRAINT chk_shipments_delivered CHECK ((tracking_status = 'delivered') = (delivered_at IS NOT NULL)),
+    CONSTRAINT uq_shipments_tracking UNIQUE (carrier, tracking_number),
+    CONSTRAINT fk_shipments_order FOREIGN KEY (order_id) REFERENCES orders (id)
+        ON UPDATE CASCADE ON DELETE CASCADE,
+    INDEX idx_shipments_polling (tracking_status, last_checked_at)
+) ENGINE = InnoDB
+  DEFAULT CHARSET = utf8mb4
+  COLLATE = utf8mb4_unicode_ci;
@@ -0,0 +1,25 @@
+-- Automatic completion of delivered orders, completion reminders and job leases
+-- Dialect: MySQL (InnoDB, utf8mb4)
+
+ALTER TABLE orders
+    ADD delivered_at           TIMESTAMP NULL AFTER payment_expires_at, -- when the order became delivered
+    ADD completion_reminded_at TIMESTAMP NULL AFTER delivered_at,       -- when the buyer was reminded to complete it
+    ADD INDEX idx_orders_delivered (status, delivered_at);
+
+-- Orders delivered before this migration start their grace period from their last change
+UPDATE orders
+SET delivered_at = updated_at
+WHERE status = 'delivered';
+
+ALTER TABLE notifications
+    MODIFY type ENUM ('saved_search_digest', 'order_completion_reminder') NOT NULL;
+
+-- A lease lets one instance at a time run a background job; an expired lease can be taken over
+CREATE TABLE job_leases
+(
+    name       VARCHAR(64)  NOT NULL PRIMARY KEY,
+    holder     VARCHAR(64)  NOT NULL,
+    expires_at TIMESTAMP(3) NOT NULL
+) ENGINE = InnoDB
+  DEFAULT CHARSET = utf8mb4
+  COLLATE = utf8mb4_unicode_ci;
@@ -0,0 +1,10 @@
+-- Composite indexes for the filtered, paginated order list and the per-status summary
+-- Dialect: MySQL (InnoDB, utf8mb4)
+
+-- Each side of a user's orders is read separately so both can use an index; created_at and
+-- id give a stable page order, status serves the status filter and the summary counts
+ALTER TABLE orders
+    ADD INDEX idx_orders_buyer_created (buyer_id, created_at, id),
+    ADD INDEX idx_orders_seller_created (seller_id, created_at, id),
+    ADD INDEX idx_orders_buyer_status (buyer_id, status, created_at),
+    ADD INDEX idx_orders_seller_status (seller_id, status, created_at);
@@ -0,0 +1,37 @@
+-- Mutual ratings between buyer and seller after an order completes
+-- Dialect: MySQL (InnoDB, utf8mb4)
+
+ALTER TABLE orders
+    ADD completed_at TIMESTAMP NULL AFTER completion_reminded_at; -- when the order became completed; opens the review window
+
+-- Orders completed before this migration start their review window from their last change
+UPDATE orders
+SET completed_at = updated_at
+WHERE status = 'completed';
+
+-- Each side rates the other once per order. A review stays hidden until the other side has
+-- reviewed too or the review window closes; visible_at holds whichever comes first.
+CREATE TABLE reviews
+(
+    id            CHAR(30)                       NOT NULL PRIMARY KEY,
+    order_id      CHAR(30)                       NOT NULL,
+    reviewer_id   VARCHAR(128)                   NOT NULL,
+    reviewee_id   VARCHAR(128)                   NOT NULL,
+    reviewer_role ENUM ('buyer', 'seller')       NOT NULL,
+    rating        ENUM ('good', 'normal', 'bad') NOT NULL,
+    comment       VARCHAR(1000)                  NOT NULL DEFAULT '',
+    visible_at    TIMESTAMP                      NOT NULL,
+    created_at    TIMESTAMP                      NOT NULL DEFAULT CURRENT_TIMESTAMP,
+
+    CONSTRAINT chk_reviews_id CHECK (id LIKE 'rev_%'),
+    CONSTRAINT chk_reviews_not_self CHECK (reviewer_id <> reviewee_id),
+    CONSTRAINT uq_reviews_order_reviewer UNIQUE (order_id, reviewer_role),
+    CONSTRAINT fk_reviews_order FOREIGN KEY (order_id) REFERENCES orders (id)
+        ON UPDATE CASCADE ON DELETE CASCADE,
+    CONSTRAINT fk_reviews_reviewer FOREIGN KEY (reviewer_id) REFERENCES users (id),
+    CONSTRAINT fk_reviews_reviewee FOREIGN KEY (reviewee_id) REFERENCES users (id),
+    INDEX idx_reviews_reviewee (reviewee_id, created_at),
+    INDEX idx_reviews_rating (reviewee_id, rating, visible_at)
+) ENGINE = InnoDB
+  DEFAULT CHARSET = utf8mb4
+  COLLATE = utf8mb4_unicode_ci;
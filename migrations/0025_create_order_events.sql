@@ -0,0 +1,23 @@
+-- Append-only timeline of order status changes
+-- Dialect: MySQL (InnoDB, utf8mb4)
+
+-- One row per change, written in the transaction that changed the order. actor_id is NULL
+-- when the platform acted (payment webhook, expiry, carrier tracking, auto-completion).
+-- Orders placed before this migration have no events; their timeline starts with the next change.
+CREATE TABLE order_events
+(
+    id          BIGINT UNSIGNED                                                                         NOT NULL AUTO_INCREMENT PRIMARY KEY,
+    order_id    CHAR(30)                                                                                NOT NULL,
+    type        ENUM ('created', 'paid', 'shipped', 'delivered', 'completed', 'cancelled', 'disputed') NOT NULL,
+    actor_id    VARCHAR(128)                                                                            NULL,
+    from_status ENUM ('pending_payment', 'paid', 'shipped', 'delivered', 'completed', 'cancelled', 'disputed') NULL, -- NULL for created
+    to_status   ENUM ('pending_payment', 'paid', 'shipped', 'delivered', 'completed', 'cancelled', 'disputed') NOT NULL,
+    metadata    JSON                                                                                    NULL,
+    created_at  TIMESTAMP(3)                                                                            NOT NULL DEFAULT CURRENT_TIMESTAMP(3),
+
+    CONSTRAINT fk_order_events_order FOREIGN KEY (order_id) REFERENCES orders (id)
+        ON UPDATE CASCADE ON DELETE CASCADE,
+    INDEX idx_order_events_order (order_id, id)
+) ENGINE = InnoDB
+  DEFAULT CHARSET = utf8mb4
+  COLLATE = utf8mb4_unicode_ci;
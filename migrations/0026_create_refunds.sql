@@ -0,0 +1,50 @@
+-- Full and partial refunds initiated by sellers or admins
+-- Dialect: MySQL (InnoDB, utf8mb4)
+
+-- platform_fee and net_payout become the effective amounts after refunds; refunded_amount
+-- and refunded_quantity are running totals
+ALTER TABLE orders
+    ADD refunded_amount   INT UNSIGNED NOT NULL DEFAULT 0 AFTER net_payout,
+    ADD refunded_quantity INT UNSIGNED NOT NULL DEFAULT 0 AFTER refunded_amount,
+    ADD CONSTRAINT chk_orders_refunded_amount CHECK (refunded_amount <= total_price),
+    ADD CONSTRAINT chk_orders_refunded_quantity CHECK (refunded_quantity <= quantity);
+
+CREATE TABLE refunds
+(
+    id                 CHAR(30)                                                                    NOT NULL PRIMARY KEY,
+    order_id           CHAR(30)                                                                    NOT NULL,
+    amount             INT UNSIGNED                                                                NOT NULL, -- returned to the buyer
+    quantity           INT UNSIGNED                                                                NOT NULL DEFAULT 0, -- items refunded, 0 for an amount-only refund
+    platform_fee_delta INT UNSIGNED                                                                NOT NULL, -- fee the platform gives back
+    net_payout_delta   INT UNSIGNED                                                                NOT NULL, -- taken from the seller
+    reason             ENUM ('damaged', 'not_as_described', 'missing_item', 'goodwill', 'other') NOT NULL,
+    note               VARCHAR(1000)                                                               NOT NULL DEFAULT '',
+    initiated_by       VARCHAR(128)                                                                NOT NULL,
+    initiator_role     ENUM ('seller', 'admin')                                                    NOT NULL,
+    created_at         TIMESTAMP                                                                   NOT NULL DEFAULT CURRENT_TIMESTAMP,
+
+    CONSTRAINT chk_refunds_id CHECK (id LIKE 'rfd_%'),
+    CONSTRAINT chk_refunds_amount CHECK (amount > 0 AND amount = platform_fee_delta + net_payout_delta),
+    CONSTRAINT fk_refunds_order FOREIGN KEY (order_id) REFERENCES orders (id)
+        ON UPDATE CASCADE ON DELETE RESTRICT,
+    INDEX idx_refunds_order (order_id, created_at)
+) ENGINE = InnoDB
+  DEFAULT CHARSET = utf8mb4
+  COLLATE = utf8mb4_unicode_ci;
+
+-- An order can be refunded more than once, so refund postings reference the refund instead
+-- of the order
+ALTER TABLE ledger_transactions
+    MODIFY type ENUM ('order_paid', 'order_completed', 'order_cancelled', 'order_refunded',
+        'payout_requested', 'payout_paid', 'payout_failed') NOT NULL,
+    ADD refund_id CHAR(30) NULL AFTER payout_id,
+    DROP CHECK chk_ledger_transactions_reference,
+    ADD CONSTRAINT chk_ledger_transactions_reference CHECK (
+        (order_id IS NOT NULL) + (payout_id IS NOT NULL) + (refund_id IS NOT NULL) = 1
+        ),
+    ADD CONSTRAINT uq_ledger_transactions_refund UNIQUE (type, refund_id),
+    ADD CONSTRAINT fk_ledger_transactions_refund FOREIGN KEY (refund_id) REFERENCES refunds (id)
+        ON UPDATE CASCADE ON DELETE RESTRICT;
+
+ALTER TABLE order_events
+    MODIFY type ENUM ('created', 'paid', 'shipped', 'delivered', 'completed', 'cancelled', 'disputed', 'refunded') NOT NULL;
@@ -0,0 +1,13 @@
+-- Messages tied to an order or a listing, so each gets its own thread between the two users
+-- Dialect: MySQL (InnoDB, utf8mb4)
+
+-- Messages with neither are the free-form direct messages between the two users
+ALTER TABLE messages
+    ADD order_id   CHAR(30) NULL AFTER receiver_id,
+    ADD listing_id CHAR(30) NULL AFTER order_id,
+    ADD CONSTRAINT chk_messages_context CHECK (order_id IS NULL OR listing_id IS NULL),
+    ADD CONSTRAINT fk_messages_order FOREIGN KEY (order_id) REFERENCES orders (id)
+        ON UPDATE CASCADE ON DELETE CASCADE,
+    ADD CONSTRAINT fk_messages_listing FOREIGN KEY (listing_id) REFERENCES listings (id)
+        ON UPDATE CASCADE ON DELETE SET NULL,
+    ADD INDEX idx_messages_order_created (order_id, created_at);
@@ -0,0 +1,55 @@
+-- Velocity checks on new orders and the review queue for orders they block
+-- Dialect: MySQL (InnoDB, utf8mb4)
+
+-- One row per rule; limits take effect within a few minutes of being changed. Windows are
+-- rolling, counted back from the order being placed.
+CREATE TABLE fraud_rules
+(
+    rule                  ENUM ('order_velocity', 'spend_limit', 'new_account_limit', 'same_seller_velocity') NOT NULL PRIMARY KEY,
+    limit_value           INT UNSIGNED NOT NULL, -- orders, or yen for the spend rules
+    window_minutes        INT UNSIGNED NOT NULL,
+    max_account_age_hours INT UNSIGNED NULL,     -- new_account_limit only: accounts younger than this
+    active                BOOLEAN      NOT NULL DEFAULT TRUE,
+    updated_at            TIMESTAMP    NOT NULL DEFAULT CURRENT_TIMESTAMP ON UPDATE CURRENT_TIMESTAMP,
+
+    CONSTRAINT chk_fraud_rules_window CHECK (window_minutes > 0),
+    CONSTRAINT chk_fraud_rules_account_age CHECK ((rule = 'new_account_limit') = (max_account_age_hours IS NOT NULL))
+) ENGINE = InnoDB
+  DEFAULT CHARSET = utf8mb4
+  COLLATE = utf8mb4_unicode_ci;
+
+INSERT INTO fraud_rules (rule, limit_value, window_minutes, max_account_age_hours)
+VALUES ('order_velocity', 10, 60, NULL),          -- 10 orders an hour
+       ('spend_limit', 500000, 1440, NULL),       -- 500,000 yen a day
+       ('new_account_limit', 30000, 1440, 72),    -- 30,000 yen a day for the first 3 days
+       ('same_seller_velocity', 5, 10080, NULL);  -- 5 orders from one seller a week
+
+-- Repeated blocks for the same buyer and rule count as attempts on the open review
+CREATE TABLE fraud_reviews
+(
+    id              CHAR(30)                                                                          NOT NULL PRIMARY KEY,
+    buyer_id        VARCHAR(128)                                                                      NOT NULL,
+    seller_id       VARCHAR(128)                                                                      NOT NULL, -- of the latest attempt
+    listing_id      CHAR(30)                                                                          NOT NULL,
+    rule            ENUM ('order_velocity', 'spend_limit', 'new_account_limit', 'same_seller_velocity') NOT NULL,
+    limit_value     INT UNSIGNED                                                                      NOT NULL,
+    observed_value  INT UNSIGNED                                                                      NOT NULL, -- including the blocked order
+    attempts        INT UNSIGNED                                                                      NOT NULL DEFAULT 1,
+    status          ENUM ('open', 'cleared', 'confirmed')                                             NOT NULL DEFAULT 'open',
+    reviewed_by     VARCHAR(128)                                                                      NULL,
+    note            VARCHAR(1000)                                                                     NOT NULL DEFAULT '',
+    created_at      TIMESTAMP                                                                         NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    last_attempt_at TIMESTAMP                                                                         NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    reviewed_at     TIMESTAMP                                                                         NULL,
+
+    CONSTRAINT chk_fraud_reviews_id CHECK (id LIKE 'frv_%'),
+    CONSTRAINT chk_fraud_reviews_reviewed CHECK ((status = 'open') = (reviewed_at IS NULL)),
+    CONSTRAINT fk_fraud_reviews_buyer FOREIGN KEY (buyer_id) REFERENCES users (id)
+        ON UPDATE CASCADE ON DELETE CASCADE,
+    CONSTRAINT fk_fraud_reviews_listing FOREIGN KEY (listing_id) REFERENCES listings (id)
+        ON UPDATE CASCADE ON DELETE CASCADE,
+    INDEX idx_fraud_reviews_buyer_rule (buyer_id, rule, status),
+    INDEX idx_fraud_reviews_status_created (status, created_at)
+) ENGINE = InnoDB
+  DEFAULT CHARSET = utf8mb4
+  COLLATE = utf8mb4_unicode_ci;
@@ -0,0 +1,14 @@
+-- Refunds are committed before the payment provider returns the money
+-- Dialect: MySQL (InnoDB, utf8mb4)
+
+-- A refund is pending from the moment it is recorded until the provider confirms it;
+-- pending refunds are retried with the refund ID as the provider's idempotency key
+ALTER TABLE refunds
+    ADD status       ENUM ('pending', 'completed') NOT NULL DEFAULT 'pending' AFTER initiator_role,
+    ADD completed_at TIMESTAMP                     NULL AFTER created_at,
+    ADD INDEX idx_refunds_pending (status, created_at);
+
+-- Earlier refunds were recorded only after the provider accepted them
+UPDATE refunds
+SET status       = 'completed',
+    completed_at = created_at;
@@ -0,0 +1,5 @@
+-- Dispute refunds are recorded as refunds
+-- Dialect: MySQL (InnoDB, utf8mb4)
+
+ALTER TABLE refunds
+    MODIFY reason ENUM ('damaged', 'not_as_described', 'missing_item', 'goodwill', 'other', 'dispute') NOT NULL;
@@ -0,0 +1,8 @@
+-- Cancelling a paid order refunds the buyer
+-- Dialect: MySQL (InnoDB, utf8mb4)
+
+-- Either party may cancel before shipping, so buyers can initiate refunds too
+ALTER TABLE refunds
+    MODIFY reason ENUM ('damaged', 'not_as_described', 'missing_item', 'goodwill', 'other', 'dispute',
+        'order_cancelled') NOT NULL,
+    MODIFY initiator_role ENUM ('buyer', 'seller', 'admin') NOT NULL;